@@ -4,6 +4,8 @@ import (
 	"context"
 	"os/signal"
 	"syscall"
+	// Часовые пояса мест проверяются по встроенной базе IANA, даже если в образе её нет.
+	_ "time/tzdata"
 
 	"github.com/NailUsmanov/api_organization/internal/app"
 	"github.com/NailUsmanov/api_organization/internal/config"
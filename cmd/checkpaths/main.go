@@ -0,0 +1,64 @@
+// Команда checkpaths сверяет материализованные пути подразделений с иерархией parent_id
+// и при флаге -fix пересчитывает расходящиеся пути.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/NailUsmanov/api_organization/internal/config"
+	"github.com/NailUsmanov/api_organization/internal/db"
+	"github.com/NailUsmanov/api_organization/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "rebuild inconsistent paths")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+
+	gormDB, err := db.InitDB(cfg.DSN())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	ctx := context.Background()
+	deptRepo := repository.NewDepartmentRepository(gormDB)
+
+	mismatches, err := deptRepo.CheckPaths(ctx)
+	if err != nil {
+		logger.Fatalf("Failed to check paths: %v", err)
+	}
+	for _, m := range mismatches {
+		logger.WithFields(logrus.Fields{
+			"id":       m.ID,
+			"stored":   m.Stored,
+			"expected": m.Expected,
+		}).Warn("inconsistent department path")
+	}
+	if len(mismatches) == 0 {
+		logger.Info("All department paths are consistent")
+		return
+	}
+
+	if !*fix {
+		logger.Errorf("Found %d inconsistent paths, run with -fix to rebuild", len(mismatches))
+		os.Exit(1)
+	}
+
+	fixed, err := deptRepo.RebuildPaths(ctx)
+	if err != nil {
+		logger.Fatalf("Failed to rebuild paths: %v", err)
+	}
+	logger.Infof("Rebuilt %d department paths", fixed)
+}
@@ -0,0 +1,86 @@
+// Команда orglint проверяет дерево подразделений на признаки неудачной организации
+// и выводит замечания. Код выхода 1, если есть замечания уровня -fail-on и серьёзнее.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/NailUsmanov/api_organization/internal/config"
+	"github.com/NailUsmanov/api_organization/internal/db"
+	"github.com/NailUsmanov/api_organization/internal/repository"
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	maxDepth := flag.Int("max-depth", 0, "maximum department depth (default LINT_MAX_DEPTH)")
+	maxSpan := flag.Int("max-span", 0, "maximum span of control (default LINT_MAX_SPAN)")
+	failOn := flag.String("fail-on", service.SeverityError, "exit with code 1 on findings of this severity or higher")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	if !service.ValidSeverity(*failOn) {
+		logger.Fatalf("Invalid -fail-on %q: must be error, warning or info", *failOn)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("Failed to load config: %v", err)
+	}
+	limits := service.LintLimits{MaxDepth: cfg.LintMaxDepth, MaxSpan: cfg.LintMaxSpan}
+	if *maxDepth > 0 {
+		limits.MaxDepth = *maxDepth
+	}
+	if *maxSpan > 0 {
+		limits.MaxSpan = *maxSpan
+	}
+
+	gormDB, err := db.InitDB(cfg.DSN())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	lintService := service.NewLintService(repository.NewDepartmentRepository(gormDB), repository.NewEmployeeRepo(gormDB), limits)
+	report, err := lintService.Report(context.Background())
+	if err != nil {
+		logger.Fatalf("Failed to build report: %v", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			logger.Fatalf("Failed to write report: %v", err)
+		}
+	} else {
+		for _, f := range report.Findings {
+			entry := logger.WithFields(logrus.Fields{
+				"rule":       f.Rule,
+				"department": f.DepartmentID,
+				"name":       f.DepartmentName,
+			})
+			switch f.Severity {
+			case service.SeverityError:
+				entry.Error(f.Message)
+			case service.SeverityWarning:
+				entry.Warn(f.Message)
+			default:
+				entry.Info(f.Message)
+			}
+		}
+		logger.Infof("Found %d errors, %d warnings, %d info", report.Summary[service.SeverityError],
+			report.Summary[service.SeverityWarning], report.Summary[service.SeverityInfo])
+	}
+
+	if len(report.FindingsAtLeast(*failOn)) > 0 {
+		os.Exit(1)
+	}
+}
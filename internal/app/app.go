@@ -18,10 +18,12 @@ import (
 
 // App - состоит из маршуртизатора, логгера, базы данных, конфига.
 type App struct {
-	router *http.ServeMux
-	logger *logrus.Logger
-	db     *gorm.DB
-	cfg    *config.Config
+	router   *http.ServeMux
+	logger   *logrus.Logger
+	db       *gorm.DB
+	cfg      *config.Config
+	versions *service.VerService
+	reorgs   *service.ReorgSvc
 }
 
 // NewApp создаёт экземпляр приложения, инициализирует зависимости и регистрирует маршруты.
@@ -41,22 +43,161 @@ func NewApp(logger *logrus.Logger, db *gorm.DB, cfg *config.Config) *App {
 func (a *App) setupRoutes() {
 	deptRepo := repository.NewDepartmentRepository(a.db)
 	empRepo := repository.NewEmployeeRepo(a.db)
+	vacancyRepo := repository.NewVacancyRepo(a.db)
+	historyRepo := repository.NewHistoryRepo(a.db)
+	versionRepo := repository.NewVersionRepo(a.db)
+	attributeRepo := repository.NewAttributeRepo(a.db)
+	reorgRepo := repository.NewReorgRepo(a.db)
+	changeRequestRepo := repository.NewChangeRequestRepo(a.db)
+	snapshotRepo := repository.NewSnapshotRepo(a.db)
+	sandboxRepo := repository.NewSandboxRepo(a.db)
+	searchRepo := repository.NewSearchRepo(a.db)
+	employeeMergeRepo := repository.NewEmployeeMergeRepo(a.db)
+	positionRepo := repository.NewPositionRepo(a.db)
+	assignmentRepo := repository.NewAssignmentRepo(a.db)
+	departmentTypeRepo := repository.NewDepartmentTypeRepo(a.db)
+	locationRepo := repository.NewLocationRepo(a.db)
+	txManager := repository.NewTxManager(a.db)
+	names := service.NamingPolicy{
+		MinLength:      a.cfg.DepartmentNameMinLength,
+		MaxLength:      a.cfg.DepartmentNameMaxLength,
+		AllowedChars:   a.cfg.DepartmentNameAllowedChars,
+		ForbiddenWords: a.cfg.DepartmentNameForbiddenWords,
+	}
 
-	deptService := service.NewDepartmentService(deptRepo, empRepo)
-	empService := service.NewEmpService(empRepo, deptRepo)
+	attributeService := service.NewAttributeService(attributeRepo, txManager)
+	departmentTypeService := service.NewDepartmentTypeService(departmentTypeRepo, txManager)
+	deptService := service.NewDepartmentService(deptRepo, empRepo, vacancyRepo, assignmentRepo, historyRepo, attributeService,
+		departmentTypeService, names, txManager, a.cfg.HeadcountLimitMode, a.cfg.ChangeApprovalMode)
+	empService := service.NewEmpService(empRepo, deptRepo, positionRepo, vacancyRepo, attributeService, txManager,
+		a.cfg.HeadcountLimitMode)
+	importService := service.NewImportService(deptRepo, empRepo, vacancyRepo, attributeService, departmentTypeService,
+		names, txManager, a.cfg.HeadcountLimitMode, a.cfg.ChangeApprovalMode)
+	a.versions = service.NewVersionService(deptRepo, empRepo, vacancyRepo, versionRepo, historyRepo,
+		departmentTypeService, names, txManager, a.cfg.HeadcountLimitMode, a.cfg.ChangeApprovalMode)
+	changeRequestService := service.NewChangeRequestService(changeRequestRepo, deptRepo, empRepo, historyRepo, deptService,
+		departmentTypeService, txManager)
+	snapshotService := service.NewSnapshotService(snapshotRepo, deptRepo, empRepo, txManager)
+	sandboxService := service.NewSandboxService(sandboxRepo, txManager, a.cfg.ChangeApprovalMode)
+	searchService := service.NewSearchService(searchRepo)
+	duplicateService := service.NewDuplicateService(empRepo, deptRepo, employeeMergeRepo, historyRepo, txManager)
+	positionService := service.NewPositionService(positionRepo, deptRepo, txManager)
+	assignmentService := service.NewAssignmentService(assignmentRepo, empRepo, deptRepo, txManager)
+	locationService := service.NewLocationService(locationRepo, deptRepo, empRepo, historyRepo, txManager, time.Now)
+	vacancyService := service.NewVacancyService(vacancyRepo, deptRepo, empRepo, positionRepo, txManager, a.cfg.HeadcountLimitMode)
+	lintService := service.NewLintService(deptRepo, empRepo, service.LintLimits{MaxDepth: a.cfg.LintMaxDepth, MaxSpan: a.cfg.LintMaxSpan})
+	a.reorgs = service.NewReorgService(reorgRepo, deptRepo, empRepo, vacancyRepo, deptService, departmentTypeService,
+		names, txManager, time.Now, a.cfg.HeadcountLimitMode, a.cfg.ChangeApprovalMode)
 
 	deptHandler := handlers.NewDepartmentHandler(deptService)
 	empHandler := handlers.NewEmployeeHandler(empService)
+	importHandler := handlers.NewImportHandler(importService)
+	attributeHandler := handlers.NewAttributeHandler(attributeService)
+	versionHandler := handlers.NewVersionHandler(a.versions)
+	reorgHandler := handlers.NewReorgHandler(a.reorgs)
+	changeRequestHandler := handlers.NewChangeRequestHandler(changeRequestService)
+	snapshotHandler := handlers.NewSnapshotHandler(snapshotService)
+	sandboxHandler := handlers.NewSandboxHandler(sandboxService)
+	lintHandler := handlers.NewLintHandler(lintService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	positionHandler := handlers.NewPositionHandler(positionService)
+	vacancyHandler := handlers.NewVacancyHandler(vacancyService)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
+	departmentTypeHandler := handlers.NewDepartmentTypeHandler(departmentTypeService)
+	locationHandler := handlers.NewLocationHandler(locationService)
+
+	// Запросы к подразделениям и сотрудникам с заголовком X-Sandbox-ID выполняются в песочнице.
+	sandboxed := middleware.Sandbox(sandboxService, a.logger)
+	handleSandboxed := func(pattern string, handler http.HandlerFunc) {
+		a.router.Handle(pattern, sandboxed(handler))
+	}
 
-	a.router.HandleFunc("POST /departments", deptHandler.CreateDepartment)
-	a.router.HandleFunc("GET /departments/{id}", deptHandler.GetDepartment)
-	a.router.HandleFunc("PATCH /departments/{id}", deptHandler.UpdateDepartment)
-	a.router.HandleFunc("DELETE /departments/{id}", deptHandler.DeleteDepartment)
-	a.router.HandleFunc("POST /departments/{id}/employees", empHandler.CreateEmployee)
+	handleSandboxed("POST /departments", deptHandler.CreateDepartment)
+	handleSandboxed("GET /departments", deptHandler.ListDepartments)
+	handleSandboxed("GET /departments/{id}", deptHandler.GetDepartment)
+	handleSandboxed("GET /departments/{id}/{view}", deptHandler.GetDepartmentView)
+	handleSandboxed("GET /departments/by-code/{code}", deptHandler.GetDepartmentByCode)
+	handleSandboxed("GET /departments/by-external-id/{system}/{id}", deptHandler.GetDepartmentByExternalID)
+	handleSandboxed("PATCH /departments/{id}", deptHandler.UpdateDepartment)
+	handleSandboxed("DELETE /departments/{id}", deptHandler.DeleteDepartment)
+	handleSandboxed("POST /departments/{id}/merge-into/{targetId}", deptHandler.MergeDepartment)
+	handleSandboxed("POST /departments/{id}/clone", deptHandler.CloneDepartment)
+	handleSandboxed("POST /departments/{id}/reorder", deptHandler.ReorderDepartment)
+	handleSandboxed("PUT /departments/{id}/identifiers", deptHandler.SetDepartmentIdentifiers)
+	handleSandboxed("PUT /departments/{id}/head", deptHandler.SetDepartmentHead)
+	handleSandboxed("PUT /departments/{id}/headcount-limit", deptHandler.SetDepartmentHeadcountLimit)
+	handleSandboxed("PUT /departments/{id}/location", locationHandler.SetDepartmentLocation)
+	handleSandboxed("POST /departments/{id}/scheduled-changes", versionHandler.ScheduleDepartmentChange)
+	handleSandboxed("POST /departments/{id}/employees", empHandler.CreateEmployee)
+	handleSandboxed("POST /departments/{id}/vacancies", vacancyHandler.CreateVacancy)
+	handleSandboxed("GET /vacancies", vacancyHandler.ListVacancies)
+	handleSandboxed("GET /vacancies/{id}", vacancyHandler.GetVacancy)
+	handleSandboxed("POST /vacancies/{id}/cancel", vacancyHandler.CancelVacancy)
+	handleSandboxed("GET /employees", empHandler.ListEmployees)
+	handleSandboxed("PUT /employees/{id}/identifiers", empHandler.SetEmployeeIdentifiers)
+	handleSandboxed("POST /employees/{id}/transfer", versionHandler.TransferEmployee)
+	handleSandboxed("POST /employees/{id}/terminate", empHandler.TerminateEmployee)
+	handleSandboxed("POST /employees/{id}/rehire", empHandler.RehireEmployee)
+	handleSandboxed("PUT /employees/{id}/status", empHandler.SetEmployeeStatus)
+	handleSandboxed("GET /employees/{id}/location", locationHandler.GetEmployeeLocation)
+	handleSandboxed("PUT /employees/{id}/location", locationHandler.SetEmployeeLocation)
+	handleSandboxed("GET /employees/{id}/assignments", assignmentHandler.ListAssignments)
+	handleSandboxed("PUT /employees/{id}/assignments/{departmentId}", assignmentHandler.SetAssignment)
+	handleSandboxed("DELETE /employees/{id}/assignments/{departmentId}", assignmentHandler.DeleteAssignment)
+	handleSandboxed("GET /employees/duplicates", duplicateHandler.ListDuplicates)
+	handleSandboxed("POST /employees/{id}/merge", duplicateHandler.MergeEmployees)
+	handleSandboxed("POST /import", importHandler.Import)
+	handleSandboxed("GET /org-health", lintHandler.GetOrgHealth)
+	handleSandboxed("GET /search", searchHandler.Search)
+	handleSandboxed("GET /job-families", positionHandler.GetJobFamilies)
+	handleSandboxed("GET /locations/headcount", locationHandler.GetLocationHeadcount)
+	handleSandboxed("GET /locations/{id}/employees", locationHandler.ListLocationEmployees)
+	a.router.HandleFunc("POST /attribute-definitions", attributeHandler.CreateDefinition)
+	a.router.HandleFunc("GET /attribute-definitions", attributeHandler.ListDefinitions)
+	a.router.HandleFunc("DELETE /attribute-definitions/{id}", attributeHandler.DeleteDefinition)
+	a.router.HandleFunc("POST /department-types", departmentTypeHandler.CreateDepartmentType)
+	a.router.HandleFunc("GET /department-types", departmentTypeHandler.ListDepartmentTypes)
+	a.router.HandleFunc("PUT /department-types/{name}", departmentTypeHandler.UpdateDepartmentType)
+	a.router.HandleFunc("DELETE /department-types/{name}", departmentTypeHandler.DeleteDepartmentType)
+	a.router.HandleFunc("POST /positions", positionHandler.CreatePosition)
+	a.router.HandleFunc("GET /positions", positionHandler.ListPositions)
+	a.router.HandleFunc("GET /positions/{id}", positionHandler.GetPosition)
+	a.router.HandleFunc("PUT /positions/{id}", positionHandler.UpdatePosition)
+	a.router.HandleFunc("DELETE /positions/{id}", positionHandler.DeletePosition)
+	a.router.HandleFunc("POST /positions/{id}/merge-into/{targetId}", positionHandler.MergePosition)
+	a.router.HandleFunc("POST /locations", locationHandler.CreateLocation)
+	a.router.HandleFunc("GET /locations", locationHandler.ListLocations)
+	a.router.HandleFunc("GET /locations/{id}", locationHandler.GetLocation)
+	a.router.HandleFunc("PUT /locations/{id}", locationHandler.UpdateLocation)
+	a.router.HandleFunc("DELETE /locations/{id}", locationHandler.DeleteLocation)
+	a.router.HandleFunc("POST /change-requests", changeRequestHandler.CreateChangeRequest)
+	a.router.HandleFunc("GET /change-requests", changeRequestHandler.ListChangeRequests)
+	a.router.HandleFunc("GET /change-requests/{id}", changeRequestHandler.GetChangeRequest)
+	a.router.HandleFunc("POST /change-requests/{id}/approve", changeRequestHandler.ApproveChangeRequest)
+	a.router.HandleFunc("POST /change-requests/{id}/reject", changeRequestHandler.RejectChangeRequest)
+	a.router.HandleFunc("POST /snapshots", snapshotHandler.CreateSnapshot)
+	a.router.HandleFunc("GET /snapshots", snapshotHandler.ListSnapshots)
+	a.router.HandleFunc("GET /snapshots/{id}", snapshotHandler.GetSnapshot)
+	a.router.HandleFunc("GET /snapshots/{a}/diff/{b}", snapshotHandler.DiffSnapshots)
+	a.router.HandleFunc("POST /sandboxes", sandboxHandler.CreateSandbox)
+	a.router.HandleFunc("GET /sandboxes", sandboxHandler.ListSandboxes)
+	a.router.HandleFunc("GET /sandboxes/{id}", sandboxHandler.GetSandbox)
+	a.router.HandleFunc("GET /sandboxes/{id}/changes", sandboxHandler.GetSandboxChanges)
+	a.router.HandleFunc("POST /sandboxes/{id}/promote", sandboxHandler.PromoteSandbox)
+	a.router.HandleFunc("DELETE /sandboxes/{id}", sandboxHandler.DiscardSandbox)
+	a.router.HandleFunc("POST /reorganizations", reorgHandler.CreateReorganization)
+	a.router.HandleFunc("GET /reorganizations", reorgHandler.ListReorganizations)
+	a.router.HandleFunc("GET /reorganizations/{id}", reorgHandler.GetReorganization)
+	a.router.HandleFunc("POST /reorganizations/{id}/cancel", reorgHandler.CancelReorganization)
 }
 
 // Run запускает HTTP-сервер и корректно завершает его при получении сигнала.
 func (a *App) Run(ctx context.Context, addr string) error {
+	// Фоновые задания и ожидание сигнала завершаются и при ошибке запуска сервера.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	handler := middleware.Logger(a.logger)(a.router)
 
 	srv := &http.Server{
@@ -77,9 +218,50 @@ func (a *App) Run(ctx context.Context, addr string) error {
 		_ = srv.Shutdown(shutdownCtx)
 	}()
 
+	a.startJobs(ctx)
+
 	a.logger.Infof("Starting server on %s", addr)
 	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	return nil
 }
+
+// startJobs запускает фоновые задания с периодом JobsInterval: применение изменений структуры,
+// дата вступления в силу которых наступила (изменение, вступившее в силу в полночь, применяется
+// в пределах периода), и выполнение пакетов реорганизаций, время которых наступило.
+// Задания не запускаются, если период нулевой или база данных не подключена.
+func (a *App) startJobs(ctx context.Context) {
+	if a.cfg.JobsInterval <= 0 {
+		return
+	}
+	if a.db == nil || a.db.Config == nil {
+		a.logger.Warn("Database is not configured, background jobs are disabled")
+		return
+	}
+	go a.runPeriodically(ctx, a.cfg.JobsInterval, "scheduled changes", a.versions.ApplyDue)
+	go a.runPeriodically(ctx, a.cfg.JobsInterval, "reorganization batches", a.reorgs.ExecuteDue)
+}
+
+// runPeriodically запускает фоновое задание job с периодом interval до отмены ctx.
+// Задание возвращает количество обработанных записей и ошибки необработанных.
+func (a *App) runPeriodically(ctx context.Context, interval time.Duration, name string,
+	job func(ctx context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			done, err := job(ctx)
+			if err != nil {
+				a.logger.WithError(err).Warnf("Some %s could not be applied", name)
+			}
+			if done > 0 {
+				a.logger.Infof("Applied %d %s", done, name)
+			}
+		}
+	}
+}
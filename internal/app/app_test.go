@@ -106,6 +106,69 @@ func TestRun_ContextCancelledBeforeStart(t *testing.T) {
 	assert.NoError(t, err) // ListenAndServe не вызывается, ошибки нет
 }
 
+// TestRun_JobsWithoutDatabase проверяет, что фоновые задания не обращаются к неподключённой БД
+func TestRun_JobsWithoutDatabase(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	db := &gorm.DB{}
+	cfg := &config.Config{JobsInterval: 10 * time.Millisecond}
+
+	app := NewApp(logger, db, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- app.Run(ctx, "localhost:0")
+	}()
+
+	// Несколько периодов заданий: обращение к пустому gorm.DB завершило бы тест паникой.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not shut down in time")
+	}
+}
+
+// TestRunPeriodically проверяет, что задание выполняется с заданным периодом до отмены контекста
+func TestRunPeriodically(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	app := NewApp(logger, &gorm.DB{}, &config.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		app.runPeriodically(ctx, 5*time.Millisecond, "test jobs", func(ctx context.Context) (int, error) {
+			select {
+			case calls <- struct{}{}:
+			default:
+			}
+			return 0, nil
+		})
+		close(done)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatal("job was not run")
+		}
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runPeriodically did not stop after cancel")
+	}
+}
+
 // Benchmark для проверки создания приложения
 func BenchmarkNewApp(b *testing.B) {
 	logger := logrus.New()
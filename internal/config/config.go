@@ -5,8 +5,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// nameCharClasses - классы символов, которые можно разрешить в названиях подразделений.
+var nameCharClasses = []string{"letters", "digits", "spaces", "punctuation", "symbols"}
+
 // Config хранит все настройки приложения, необходимые для его работы.
 // Значения загружаются из переменных окружения или используются значения по умолчанию.
 type Config struct {
@@ -16,6 +23,26 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	Port       string
+	// LintMaxDepth - наибольшая допустимая глубина подразделения в отчёте о состоянии структуры.
+	LintMaxDepth int
+	// LintMaxSpan - наибольший допустимый охват управления подразделения в отчёте о состоянии структуры.
+	LintMaxSpan int
+	// HeadcountLimitMode - реакция на превышение утверждённой численности подразделения:
+	// soft - принять с предупреждением, hard - отклонить.
+	HeadcountLimitMode string
+	// ChangeApprovalMode - согласование переносов и удалений подразделений: required - только
+	// по согласованной заявке, optional - заявки не обязательны.
+	ChangeApprovalMode string
+	// DepartmentNameMinLength и DepartmentNameMaxLength - допустимая длина названия подразделения в символах.
+	DepartmentNameMinLength int
+	DepartmentNameMaxLength int
+	// DepartmentNameAllowedChars - разрешённые классы символов названий; пустой список разрешает любые.
+	DepartmentNameAllowedChars []string
+	// DepartmentNameForbiddenWords - слова, запрещённые в названиях подразделений.
+	DepartmentNameForbiddenWords []string
+	// JobsInterval - период фоновых заданий: применения запланированных изменений структуры
+	// и выполнения пакетов реорганизаций. Нулевое значение отключает задания.
+	JobsInterval time.Duration
 }
 
 // Load загружает конфигурацию из переменных окружения.
@@ -27,6 +54,44 @@ func Load() (*Config, error) {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "organization"),
 		Port:       getEnv("PORT", "8080"),
+
+		HeadcountLimitMode: getEnv("HEADCOUNT_LIMIT_MODE", "soft"),
+		ChangeApprovalMode: getEnv("CHANGE_APPROVAL_MODE", "required"),
+
+		DepartmentNameAllowedChars:   getEnvList("DEPARTMENT_NAME_ALLOWED_CHARS"),
+		DepartmentNameForbiddenWords: getEnvList("DEPARTMENT_NAME_FORBIDDEN_WORDS"),
+	}
+
+	var err error
+	if cfg.LintMaxDepth, err = getEnvInt("LINT_MAX_DEPTH", 6); err != nil {
+		return nil, err
+	}
+	if cfg.LintMaxSpan, err = getEnvInt("LINT_MAX_SPAN", 10); err != nil {
+		return nil, err
+	}
+	if cfg.HeadcountLimitMode != "soft" && cfg.HeadcountLimitMode != "hard" {
+		return nil, fmt.Errorf("HEADCOUNT_LIMIT_MODE must be soft or hard, got %q", cfg.HeadcountLimitMode)
+	}
+	if cfg.ChangeApprovalMode != "required" && cfg.ChangeApprovalMode != "optional" {
+		return nil, fmt.Errorf("CHANGE_APPROVAL_MODE must be required or optional, got %q", cfg.ChangeApprovalMode)
+	}
+	if cfg.DepartmentNameMinLength, err = getEnvInt("DEPARTMENT_NAME_MIN_LENGTH", 1); err != nil {
+		return nil, err
+	}
+	if cfg.DepartmentNameMaxLength, err = getEnvInt("DEPARTMENT_NAME_MAX_LENGTH", 200); err != nil {
+		return nil, err
+	}
+	if cfg.DepartmentNameMaxLength > 200 || cfg.DepartmentNameMinLength > cfg.DepartmentNameMaxLength {
+		return nil, fmt.Errorf("DEPARTMENT_NAME_MIN_LENGTH and DEPARTMENT_NAME_MAX_LENGTH must satisfy min <= max <= 200, got %d and %d",
+			cfg.DepartmentNameMinLength, cfg.DepartmentNameMaxLength)
+	}
+	if cfg.JobsInterval, err = getEnvDuration("JOBS_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	for _, class := range cfg.DepartmentNameAllowedChars {
+		if !slices.Contains(nameCharClasses, class) {
+			return nil, fmt.Errorf("DEPARTMENT_NAME_ALLOWED_CHARS must list %s, got %q", strings.Join(nameCharClasses, ", "), class)
+		}
 	}
 	return cfg, nil
 }
@@ -40,6 +105,44 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt возвращает положительное целое значение переменной окружения с заданным ключом.
+func getEnvInt(key string, defaultValue int) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer, got %q", key, val)
+	}
+	return n, nil
+}
+
+// getEnvDuration возвращает неотрицательную длительность из переменной окружения с заданным ключом
+// в формате time.ParseDuration, например 30s или 5m.
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration such as 30s or 5m, got %q", key, val)
+	}
+	return d, nil
+}
+
+// getEnvList возвращает непустые элементы списка через запятую из переменной окружения с заданным ключом.
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // DSN формирует строку подключения к базе данных PostgreSQL (Data Source Name).
 func (c *Config) DSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
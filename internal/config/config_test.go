@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +23,8 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, "postgres", cfg.DBPassword)
 	assert.Equal(t, "organization", cfg.DBName)
 	assert.Equal(t, "8080", cfg.Port)
+	assert.Equal(t, 6, cfg.LintMaxDepth)
+	assert.Equal(t, 10, cfg.LintMaxSpan)
 }
 
 func TestLoad_WithEnvVars(t *testing.T) {
@@ -65,6 +68,80 @@ func TestLoad_PartialEnvVars(t *testing.T) {
 	assert.Equal(t, "8080", cfg.Port)           // значение по умолчанию
 }
 
+func TestLoad_LintLimits(t *testing.T) {
+	os.Setenv("LINT_MAX_DEPTH", "4")
+	os.Setenv("LINT_MAX_SPAN", "15")
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 4, cfg.LintMaxDepth)
+	assert.Equal(t, 15, cfg.LintMaxSpan)
+
+	os.Setenv("LINT_MAX_SPAN", "wide")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_HeadcountLimitMode(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "soft", cfg.HeadcountLimitMode)
+
+	os.Setenv("HEADCOUNT_LIMIT_MODE", "hard")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "hard", cfg.HeadcountLimitMode)
+
+	os.Setenv("HEADCOUNT_LIMIT_MODE", "strict")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_ChangeApprovalMode(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "required", cfg.ChangeApprovalMode)
+
+	os.Setenv("CHANGE_APPROVAL_MODE", "optional")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "optional", cfg.ChangeApprovalMode)
+
+	os.Setenv("CHANGE_APPROVAL_MODE", "never")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
+func TestLoad_JobsInterval(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.JobsInterval)
+
+	os.Setenv("JOBS_INTERVAL", "30s")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.JobsInterval)
+
+	os.Setenv("JOBS_INTERVAL", "0")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Zero(t, cfg.JobsInterval)
+
+	os.Setenv("JOBS_INTERVAL", "-1m")
+	_, err = Load()
+	assert.Error(t, err)
+}
+
 func TestDSN_Format(t *testing.T) {
 	cfg := &Config{
 		DBHost:     "localhost",
@@ -105,3 +182,32 @@ func TestGetEnv_Existing(t *testing.T) {
 	result := getEnv("EXISTENT", "default")
 	assert.Equal(t, "value", result)
 }
+
+func TestLoad_DepartmentNamePolicy(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cfg.DepartmentNameMinLength)
+	assert.Equal(t, 200, cfg.DepartmentNameMaxLength)
+	assert.Empty(t, cfg.DepartmentNameAllowedChars)
+
+	os.Setenv("DEPARTMENT_NAME_MAX_LENGTH", "80")
+	os.Setenv("DEPARTMENT_NAME_ALLOWED_CHARS", "letters, digits,spaces")
+	os.Setenv("DEPARTMENT_NAME_FORBIDDEN_WORDS", "test,tmp")
+	cfg, err = Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 80, cfg.DepartmentNameMaxLength)
+	assert.Equal(t, []string{"letters", "digits", "spaces"}, cfg.DepartmentNameAllowedChars)
+	assert.Equal(t, []string{"test", "tmp"}, cfg.DepartmentNameForbiddenWords)
+
+	os.Setenv("DEPARTMENT_NAME_ALLOWED_CHARS", "emoji")
+	_, err = Load()
+	assert.Error(t, err)
+
+	os.Setenv("DEPARTMENT_NAME_ALLOWED_CHARS", "")
+	os.Setenv("DEPARTMENT_NAME_MAX_LENGTH", "250")
+	_, err = Load()
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/NailUsmanov/api_organization/internal/repository"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose"
 	"gorm.io/driver/postgres"
@@ -14,11 +15,15 @@ import (
 )
 
 // InitDB инициализирует подключение к базе данных PostgreSQL через GORM.
+// Ошибки PostgreSQL всех запросов переводятся плагином repository.ErrorTranslator.
 func InitDB(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	if err := db.Use(repository.ErrorTranslator{}); err != nil {
+		return nil, fmt.Errorf("failed to register error translator: %w", err)
+	}
 	return db, nil
 }
 
@@ -1,10 +1,14 @@
 // Package apperrors содержит централизованное определение всех бизнес-ошибок, используемых в приложении.
 package apperrors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// Department errors
+	ErrInvalidDepartmentName    = errors.New("invalid department name")
 	ErrDepartmentNotFound       = errors.New("department not found")
 	ErrDepartmentNameConflict   = errors.New("department with this name already exists under the same parent")
 	ErrParentNotFound           = errors.New("parent department not found")
@@ -14,9 +18,131 @@ var (
 	ErrInvalidMode              = errors.New("invalid mode, must be 'cascade' or 'reassign'")
 	ErrReassignTargetRequired   = errors.New("reassign_to_department_id is required for reassign mode")
 	ErrTargetDepartmentNotFound = errors.New("target department not found")
+	ErrMergeIntoSelf            = errors.New("cannot merge department into itself")
+	ErrMergeIntoDescendant      = errors.New("cannot merge department into its own descendant")
+	ErrInvalidSortPosition      = errors.New("position must be a non-negative integer")
+	ErrInvalidChildrenOrder     = errors.New("child_ids must list every child department exactly once")
+	ErrInvalidStatsWindow       = errors.New("months must be an integer between 1 and 60")
+
+	// Identifier errors
+	ErrInvalidCode            = errors.New("code must be 1-50 characters: latin letters and digits separated by single dashes")
+	ErrInvalidExternalID      = errors.New("external_ids must map system names (lowercase latin letters, digits, underscores) to non-empty ids")
+	ErrDepartmentCodeConflict = errors.New("department with this code already exists")
+	ErrEmployeeCodeConflict   = errors.New("employee with this code already exists")
+	ErrExternalIDConflict     = errors.New("external id is already assigned to another record")
+	ErrImportKeyRequired      = errors.New("each import record requires code or external_ids")
+	ErrImportParentAmbiguous  = errors.New("parent_code and move_to_root cannot be set together")
+
+	// Effective dating errors
+	ErrInvalidEffectiveDate = errors.New("effective date must not be in the past")
+	ErrEmptyScheduledChange = errors.New("scheduled change must set name or parent_id")
+
+	// Reorganization batch errors
+	ErrInvalidReorgBatch    = errors.New("invalid reorganization batch")
+	ErrInvalidExecutionTime = errors.New("execute_at must not be in the past")
+	ErrReorgBatchNotFound   = errors.New("reorganization batch not found")
+	ErrReorgBatchNotPending = errors.New("reorganization batch is not pending")
+
+	// Change request errors
+	ErrInvalidChangeRequest    = errors.New("invalid change request")
+	ErrChangeRequestNotFound   = errors.New("change request not found")
+	ErrChangeRequestNotPending = errors.New("change request is already decided")
+	ErrNoApprover              = errors.New("no department head is assigned to approve this change")
+	ErrNotApprover             = errors.New("employee is not the approver of this change request")
+	ErrApprovalRequired        = errors.New("moves and deletes of departments require an approved change request")
+
+	// Snapshot errors
+	ErrSnapshotNotFound     = errors.New("snapshot not found")
+	ErrInvalidSnapshotLabel = errors.New("snapshot label must be max 200 characters")
+
+	// Search errors
+	ErrInvalidSearchQuery = errors.New("invalid search query")
+
+	// Sandbox errors
+	ErrInvalidSandboxName = errors.New("sandbox name must be non-empty and max 200 characters")
+	ErrInvalidSandboxID   = errors.New("X-Sandbox-ID must be a positive integer")
+	ErrSandboxNotFound    = errors.New("sandbox not found")
+	ErrSandboxClosed      = errors.New("sandbox is already promoted or discarded")
+	ErrSandboxConflict    = errors.New("sandbox changes conflict with changes made after the sandbox was created")
+
+	// Attribute errors
+	ErrInvalidAttributeDefinition  = errors.New("invalid attribute definition")
+	ErrAttributeDefinitionConflict = errors.New("attribute with this name is already defined for the entity")
+	ErrAttributeDefinitionNotFound = errors.New("attribute definition not found")
+	ErrInvalidAttributes           = errors.New("invalid attributes")
 
 	// Employee errors
+	ErrEmployeeNotFound           = errors.New("employee not found")
 	ErrEmployeeDepartmentNotFound = errors.New("department not found")
 	ErrInvalidFullName            = errors.New("full_name must be non-empty and max 200 characters")
 	ErrInvalidPosition            = errors.New("position must be non-empty and max 200 characters")
+	ErrInvalidEmployeeStatus      = errors.New("invalid employee status")
+	ErrInvalidEmploymentDate      = errors.New("invalid employment date")
+	ErrInvalidTerminationReason   = errors.New("invalid termination reason")
+	ErrEmployeeTerminated         = errors.New("employee is terminated")
+	ErrEmployeeNotTerminated      = errors.New("employee is not terminated")
+	ErrInvalidDuplicateQuery      = errors.New("invalid duplicate search parameters")
+	ErrInvalidEmployeeMerge       = errors.New("invalid employee merge")
+	ErrEmployeeMergeConflict      = errors.New("employees have conflicting data and cannot be merged")
+
+	// Position catalog errors
+	ErrInvalidPositionEntry = errors.New("invalid position")
+	ErrPositionNotFound     = errors.New("position not found")
+	ErrPositionConflict     = errors.New("position with this title already exists")
+	ErrPositionInUse        = errors.New("position is assigned to employees or vacancies")
+
+	// Headcount budget errors
+	ErrInvalidHeadcountLimit  = errors.New("headcount_limit must be a non-negative integer or null")
+	ErrHeadcountLimitExceeded = errors.New("department headcount limit exceeded")
+	ErrInvalidVacancy         = errors.New("invalid vacancy")
+	ErrVacancyNotFound        = errors.New("vacancy not found")
+	ErrVacancyNotOpen         = errors.New("vacancy is already filled or cancelled")
+
+	// Secondary assignment errors
+	ErrInvalidAssignment  = errors.New("invalid assignment")
+	ErrAssignmentNotFound = errors.New("assignment not found")
+	ErrAllocationExceeded = errors.New("total allocation of secondary assignments exceeds 100%")
+
+	// Department type errors
+	ErrInvalidDepartmentType  = errors.New("invalid department type")
+	ErrDepartmentTypeNotFound = errors.New("department type not found")
+	ErrDepartmentTypeConflict = errors.New("department type with this name already exists")
+	ErrDepartmentTypeInUse    = errors.New("department type is in use")
+	ErrDepartmentTypeRule     = errors.New("department type is not allowed at this place in the structure")
+
+	// Location errors
+	ErrInvalidLocation  = errors.New("invalid location")
+	ErrLocationNotFound = errors.New("location not found")
+	ErrLocationConflict = errors.New("location with this name already exists")
+	ErrLocationInUse    = errors.New("location is assigned to departments or employees")
+
+	// Database errors
+	ErrUniqueViolation      = errors.New("record conflicts with an existing one")
+	ErrForeignKeyViolation  = errors.New("referenced record does not exist or is still referenced")
+	ErrCheckViolation       = errors.New("record violates a database constraint")
+	ErrSerializationFailure = errors.New("concurrent update conflict, retry the request")
+	ErrDeadlock             = errors.New("deadlock detected, retry the request")
+	ErrStatementTimeout     = errors.New("database statement timed out")
+	ErrLockNotAvailable     = errors.New("database lock could not be acquired in time")
 )
+
+// DBError - ошибка PostgreSQL, переведённая репозиторием по коду SQLSTATE. errors.Is
+// сопоставляет её с Kind (одной из ошибок базы данных выше), а Constraint и Table
+// указывают нарушенное ограничение, если оно известно.
+type DBError struct {
+	Kind       error
+	Constraint string
+	Table      string
+	Err        error
+}
+
+func (e *DBError) Error() string {
+	if e.Constraint != "" {
+		return fmt.Sprintf("%s (constraint %s)", e.Kind, e.Constraint)
+	}
+	return e.Kind.Error()
+}
+
+func (e *DBError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
@@ -0,0 +1,111 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// AssignmentHandler обрабатывает HTTP-запросы, связанные с дополнительными назначениями сотрудников.
+type AssignmentHandler struct {
+	assignmentService service.AssignmentService
+}
+
+// NewAssignmentHandler создаёт новый экземпляр обработчика дополнительных назначений.
+func NewAssignmentHandler(assignmentService service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{assignmentService: assignmentService}
+}
+
+// ListAssignments обрабатывает GET /employees/{id}/assignments - дополнительные назначения сотрудника.
+func (h *AssignmentHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	employeeID, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	assignments, err := h.assignmentService.List(r.Context(), uint(employeeID))
+	if err != nil {
+		writeAssignmentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignments)
+}
+
+// SetAssignment обрабатывает PUT /employees/{id}/assignments/{departmentId} - назначение
+// сотрудника в подразделение с ролью и долей занятости или замена существующего назначения.
+func (h *AssignmentHandler) SetAssignment(w http.ResponseWriter, r *http.Request) {
+	employeeID, departmentID, ok := parseAssignmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req assignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.assignmentService.Set(r.Context(), employeeID, departmentID, req.Role, req.Allocation)
+	if err != nil {
+		writeAssignmentError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// DeleteAssignment обрабатывает DELETE /employees/{id}/assignments/{departmentId} - снятие назначения.
+func (h *AssignmentHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	employeeID, departmentID, ok := parseAssignmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.assignmentService.Delete(r.Context(), employeeID, departmentID); err != nil {
+		writeAssignmentError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseAssignmentPath разбирает идентификаторы сотрудника и подразделения из пути запроса.
+func parseAssignmentPath(w http.ResponseWriter, r *http.Request) (employeeID, departmentID uint, ok bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	deptID, err := strconv.ParseUint(r.PathValue("departmentId"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return uint(id), uint(deptID), true
+}
+
+// writeAssignmentError отвечает кодом, соответствующим ошибке дополнительного назначения.
+func writeAssignmentError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidAssignment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrEmployeeNotFound),
+		errors.Is(err, apperrors.ErrDepartmentNotFound),
+		errors.Is(err, apperrors.ErrAssignmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrAllocationExceeded),
+		errors.Is(err, apperrors.ErrEmployeeTerminated):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAssignmentService — мок для AssignmentService
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) List(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EmployeeAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) Set(ctx context.Context, employeeID, departmentID uint, role string, allocation int) (*models.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeID, departmentID, role, allocation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmployeeAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentService) Delete(ctx context.Context, employeeID, departmentID uint) error {
+	args := m.Called(ctx, employeeID, departmentID)
+	return args.Error(0)
+}
+
+func setupAssignmentTest(t *testing.T) (*MockAssignmentService, *http.ServeMux) {
+	mockSvc := new(MockAssignmentService)
+	handler := NewAssignmentHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /employees/{id}/assignments", handler.ListAssignments)
+	mux.HandleFunc("PUT /employees/{id}/assignments/{departmentId}", handler.SetAssignment)
+	mux.HandleFunc("DELETE /employees/{id}/assignments/{departmentId}", handler.DeleteAssignment)
+
+	return mockSvc, mux
+}
+
+func TestSetAssignment(t *testing.T) {
+	mockSvc, mux := setupAssignmentTest(t)
+	mockSvc.On("Set", mock.Anything, uint(1), uint(20), "guild lead", 30).
+		Return(&models.EmployeeAssignment{ID: 5, EmployeeID: 1, DepartmentID: 20, Role: "guild lead", Allocation: 30}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/employees/1/assignments/20", bytes.NewBufferString(`{"role":"guild lead","allocation":30}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.EmployeeAssignment
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 30, got.Allocation)
+}
+
+func TestSetAssignment_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrInvalidAssignment, http.StatusBadRequest},
+		{apperrors.ErrEmployeeNotFound, http.StatusNotFound},
+		{apperrors.ErrDepartmentNotFound, http.StatusNotFound},
+		{apperrors.ErrAllocationExceeded, http.StatusConflict},
+		{apperrors.ErrEmployeeTerminated, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupAssignmentTest(t)
+		mockSvc.On("Set", mock.Anything, uint(1), uint(20), "", 50).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPut, "/employees/1/assignments/20", bytes.NewBufferString(`{"allocation":50}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
+func TestListAssignments(t *testing.T) {
+	mockSvc, mux := setupAssignmentTest(t)
+	mockSvc.On("List", mock.Anything, uint(1)).Return([]models.EmployeeAssignment{{ID: 5, DepartmentID: 20, Allocation: 30}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/1/assignments", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.EmployeeAssignment
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 1)
+}
+
+func TestDeleteAssignment(t *testing.T) {
+	mockSvc, mux := setupAssignmentTest(t)
+	mockSvc.On("Delete", mock.Anything, uint(1), uint(20)).Return(nil)
+	mockSvc.On("Delete", mock.Anything, uint(1), uint(30)).Return(apperrors.ErrAssignmentNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/employees/1/assignments/20", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/employees/1/assignments/30", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/employees/1/assignments/abc", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
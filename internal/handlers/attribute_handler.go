@@ -0,0 +1,109 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// attributeFilterPrefix - префикс параметров запроса, задающих фильтр по атрибутам.
+const attributeFilterPrefix = "attr."
+
+// AttributeHandler обрабатывает HTTP-запросы, связанные с определениями пользовательских атрибутов.
+type AttributeHandler struct {
+	attrService service.AttributeService
+}
+
+// NewAttributeHandler создаёт новый экземпляр обработчика определений атрибутов.
+func NewAttributeHandler(attrService service.AttributeService) *AttributeHandler {
+	return &AttributeHandler{attrService: attrService}
+}
+
+// CreateDefinition обрабатывает POST /attribute-definitions - создание определения атрибута.
+func (h *AttributeHandler) CreateDefinition(w http.ResponseWriter, r *http.Request) {
+	var req attributeDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	def, err := h.attrService.CreateDefinition(r.Context(), models.AttributeDefinition{
+		Entity:     req.Entity,
+		Name:       req.Name,
+		Type:       req.Type,
+		Required:   req.Required,
+		EnumValues: req.EnumValues,
+		Pattern:    req.Pattern,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidAttributeDefinition):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrAttributeDefinitionConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(def)
+}
+
+// ListDefinitions обрабатывает GET /attribute-definitions - список определений атрибутов,
+// необязательно отфильтрованный по сущности (entity).
+func (h *AttributeHandler) ListDefinitions(w http.ResponseWriter, r *http.Request) {
+	defs, err := h.attrService.ListDefinitions(r.Context(), r.URL.Query().Get("entity"))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidAttributeDefinition) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(defs)
+}
+
+// DeleteDefinition обрабатывает DELETE /attribute-definitions/{id} - удаление определения
+// атрибута вместе с его значениями.
+func (h *AttributeHandler) DeleteDefinition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid attribute definition id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.attrService.DeleteDefinition(r.Context(), uint(id)); err != nil {
+		if errors.Is(err, apperrors.ErrAttributeDefinitionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attributeFilter собирает фильтр по атрибутам из параметров запроса вида attr.<name>=<value>.
+func attributeFilter(r *http.Request) map[string]string {
+	filter := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(key, attributeFilterPrefix); ok && len(values) > 0 {
+			filter[name] = values[0]
+		}
+	}
+	return filter
+}
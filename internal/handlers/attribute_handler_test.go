@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttributeService — мок для AttributeService
+type MockAttributeService struct {
+	mock.Mock
+}
+
+func (m *MockAttributeService) CreateDefinition(ctx context.Context, def models.AttributeDefinition) (*models.AttributeDefinition, error) {
+	args := m.Called(ctx, def)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeService) ListDefinitions(ctx context.Context, entity string) ([]models.AttributeDefinition, error) {
+	args := m.Called(ctx, entity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeService) DeleteDefinition(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupAttributeTest(t *testing.T) (*MockAttributeService, *http.ServeMux) {
+	mockSvc := new(MockAttributeService)
+	handler := NewAttributeHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /attribute-definitions", handler.CreateDefinition)
+	mux.HandleFunc("GET /attribute-definitions", handler.ListDefinitions)
+	mux.HandleFunc("DELETE /attribute-definitions/{id}", handler.DeleteDefinition)
+
+	return mockSvc, mux
+}
+
+func TestCreateAttributeDefinition_Success(t *testing.T) {
+	mockSvc, mux := setupAttributeTest(t)
+
+	expected := &models.AttributeDefinition{ID: 1, Entity: "employee", Name: "grade", Type: "enum",
+		EnumValues: models.StringList{"junior", "senior"}}
+	mockSvc.On("CreateDefinition", mock.Anything, mock.MatchedBy(func(d models.AttributeDefinition) bool {
+		return d.Entity == "employee" && d.Name == "grade" && len(d.EnumValues) == 2
+	})).Return(expected, nil)
+
+	body := []byte(`{"entity": "employee", "name": "grade", "type": "enum", "enum_values": ["junior", "senior"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/attribute-definitions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp models.AttributeDefinition
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "grade", resp.Name)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateAttributeDefinition_Conflict(t *testing.T) {
+	mockSvc, mux := setupAttributeTest(t)
+
+	mockSvc.On("CreateDefinition", mock.Anything, mock.Anything).Return(nil, apperrors.ErrAttributeDefinitionConflict)
+
+	body := []byte(`{"entity": "employee", "name": "grade", "type": "string"}`)
+	req := httptest.NewRequest(http.MethodPost, "/attribute-definitions", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteAttributeDefinition_NotFound(t *testing.T) {
+	mockSvc, mux := setupAttributeTest(t)
+
+	mockSvc.On("DeleteDefinition", mock.Anything, uint(7)).Return(apperrors.ErrAttributeDefinitionNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/attribute-definitions/7", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListDepartments_AttributeFilter(t *testing.T) {
+	mockSvc := new(MockDepartmentService)
+	handler := NewDepartmentHandler(mockSvc)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /departments", handler.ListDepartments)
+
+	mockSvc.On("List", mock.Anything, map[string]string{"tier": "gold"}, (*time.Time)(nil)).
+		Return([]models.Department{{ID: 1, Name: "IT", Attributes: models.Attributes{"tier": "gold"}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments?attr.tier=gold&depth=2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []models.Department
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "gold", resp[0].Attributes["tier"])
+
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,148 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// ChangeRequestHandler обрабатывает HTTP-запросы заявок на перенос и удаление подразделений.
+type ChangeRequestHandler struct {
+	requestService service.ChangeRequestService
+}
+
+// NewChangeRequestHandler создаёт новый экземпляр обработчика заявок.
+func NewChangeRequestHandler(requestService service.ChangeRequestService) *ChangeRequestHandler {
+	return &ChangeRequestHandler{requestService: requestService}
+}
+
+// CreateChangeRequest обрабатывает POST /change-requests - создание заявки на перенос
+// или удаление подразделения.
+func (h *ChangeRequestHandler) CreateChangeRequest(w http.ResponseWriter, r *http.Request) {
+	var req changeRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.requestService.Submit(r.Context(), models.ChangeRequest{
+		Operation:    req.Operation,
+		DepartmentID: req.DepartmentID,
+		Payload:      req.Payload,
+		RequestedBy:  req.RequestedBy,
+		ApproverRule: req.ApproverRule,
+	})
+	if err != nil {
+		writeChangeRequestError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// ListChangeRequests обрабатывает GET /change-requests - список заявок,
+// необязательно отфильтрованный по статусу (status).
+func (h *ChangeRequestHandler) ListChangeRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.requestService.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		writeChangeRequestError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// GetChangeRequest обрабатывает GET /change-requests/{id} - получение заявки.
+func (h *ChangeRequestHandler) GetChangeRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid change request id", http.StatusBadRequest)
+		return
+	}
+
+	req, err := h.requestService.Get(r.Context(), uint(id))
+	if err != nil {
+		writeChangeRequestError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(req)
+}
+
+// ApproveChangeRequest обрабатывает POST /change-requests/{id}/approve - согласование
+// и выполнение заявки.
+func (h *ChangeRequestHandler) ApproveChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.requestService.Approve)
+}
+
+// RejectChangeRequest обрабатывает POST /change-requests/{id}/reject - отклонение заявки.
+func (h *ChangeRequestHandler) RejectChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.requestService.Reject)
+}
+
+// decide разбирает запрос решения по заявке и передаёт его в decision.
+func (h *ChangeRequestHandler) decide(w http.ResponseWriter, r *http.Request,
+	decision func(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error)) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid change request id", http.StatusBadRequest)
+		return
+	}
+
+	var req decisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ApproverID == 0 {
+		http.Error(w, "approver_id is required", http.StatusBadRequest)
+		return
+	}
+
+	decided, err := decision(r.Context(), uint(id), req.ApproverID, req.Comment)
+	if err != nil {
+		writeChangeRequestError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decided)
+}
+
+// writeChangeRequestError отправляет ответ с HTTP-статусом, соответствующим ошибке заявки.
+func writeChangeRequestError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrChangeRequestNotFound),
+		errors.Is(err, apperrors.ErrDepartmentNotFound),
+		errors.Is(err, apperrors.ErrEmployeeNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrInvalidChangeRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrNotApprover):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, apperrors.ErrChangeRequestNotPending),
+		errors.Is(err, apperrors.ErrNoApprover),
+		errors.Is(err, apperrors.ErrParentNotFound),
+		errors.Is(err, apperrors.ErrTargetDepartmentNotFound),
+		errors.Is(err, apperrors.ErrReassignWithChildren),
+		errors.Is(err, apperrors.ErrDepartmentNameConflict),
+		errors.Is(err, apperrors.ErrSelfParent),
+		errors.Is(err, apperrors.ErrCycleDetected),
+		errors.Is(err, apperrors.ErrHeadcountLimitExceeded),
+		errors.Is(err, apperrors.ErrDepartmentTypeRule):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
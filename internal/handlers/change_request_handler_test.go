@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockChangeRequestService — мок для ChangeRequestService
+type MockChangeRequestService struct {
+	mock.Mock
+}
+
+func (m *MockChangeRequestService) Submit(ctx context.Context, req models.ChangeRequest) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestService) Get(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestService) List(ctx context.Context, status string) ([]models.ChangeRequest, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestService) Approve(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, id, approverID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestService) Reject(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, id, approverID, comment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func setupChangeRequestTest(t *testing.T) (*MockChangeRequestService, *http.ServeMux) {
+	mockSvc := new(MockChangeRequestService)
+	handler := NewChangeRequestHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /change-requests", handler.CreateChangeRequest)
+	mux.HandleFunc("GET /change-requests", handler.ListChangeRequests)
+	mux.HandleFunc("GET /change-requests/{id}", handler.GetChangeRequest)
+	mux.HandleFunc("POST /change-requests/{id}/approve", handler.ApproveChangeRequest)
+	mux.HandleFunc("POST /change-requests/{id}/reject", handler.RejectChangeRequest)
+
+	return mockSvc, mux
+}
+
+func TestCreateChangeRequest_Success(t *testing.T) {
+	mockSvc, mux := setupChangeRequestTest(t)
+
+	approver := 100
+	mockSvc.On("Submit", mock.Anything, mock.MatchedBy(func(r models.ChangeRequest) bool {
+		return r.Operation == "move" && r.DepartmentID == 4 && *r.Payload.ParentID == 3 && r.RequestedBy == 42
+	})).Return(&models.ChangeRequest{ID: 1, Operation: "move", DepartmentID: 4, Status: models.ChangeRequestPending,
+		ApproverID: &approver}, nil)
+
+	body := []byte(`{"operation": "move", "department_id": 4, "payload": {"parent_id": 3}, "requested_by": 42}`)
+	req := httptest.NewRequest(http.MethodPost, "/change-requests", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp models.ChangeRequest
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestPending, resp.Status)
+	assert.Equal(t, 100, *resp.ApproverID)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateChangeRequest_NoApprover(t *testing.T) {
+	mockSvc, mux := setupChangeRequestTest(t)
+
+	mockSvc.On("Submit", mock.Anything, mock.Anything).Return(nil, apperrors.ErrNoApprover)
+
+	body := []byte(`{"operation": "delete", "department_id": 1, "payload": {"mode": "cascade"}, "requested_by": 42}`)
+	req := httptest.NewRequest(http.MethodPost, "/change-requests", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestApproveChangeRequest_NotApprover(t *testing.T) {
+	mockSvc, mux := setupChangeRequestTest(t)
+
+	mockSvc.On("Approve", mock.Anything, uint(1), uint(300), "").Return(nil, apperrors.ErrNotApprover)
+
+	body := []byte(`{"approver_id": 300}`)
+	req := httptest.NewRequest(http.MethodPost, "/change-requests/1/approve", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestApproveChangeRequest_Conflict(t *testing.T) {
+	mockSvc, mux := setupChangeRequestTest(t)
+
+	mockSvc.On("Approve", mock.Anything, uint(1), uint(100), "ok").Return(nil, apperrors.ErrDepartmentNameConflict)
+
+	body := []byte(`{"approver_id": 100, "comment": "ok"}`)
+	req := httptest.NewRequest(http.MethodPost, "/change-requests/1/approve", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRejectChangeRequest_MissingApprover(t *testing.T) {
+	mockSvc, mux := setupChangeRequestTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/change-requests/1/reject", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "Reject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
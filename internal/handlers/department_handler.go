@@ -6,8 +6,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
 	"github.com/NailUsmanov/api_organization/internal/service"
 )
 
@@ -29,15 +31,20 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	dept, err := h.depService.Create(r.Context(), req.Name, req.ParentID)
+	dept, err := h.depService.Create(r.Context(), req.Name, req.ParentID, req.Type, req.Attributes)
 	if err != nil {
 		switch {
-		case errors.Is(err, apperrors.ErrDepartmentNameConflict):
+		case errors.Is(err, apperrors.ErrDepartmentNameConflict),
+			errors.Is(err, apperrors.ErrDepartmentTypeRule):
 			http.Error(w, err.Error(), http.StatusConflict)
 		case errors.Is(err, apperrors.ErrParentNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrInvalidAttributes),
+			errors.Is(err, apperrors.ErrInvalidDepartmentName),
+			errors.Is(err, apperrors.ErrDepartmentTypeNotFound):
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServerError(w, err)
 		}
 		return
 	}
@@ -47,6 +54,28 @@ func (h *DepartmentHandler) CreateDepartment(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(dept)
 }
 
+// ListDepartments обрабатывает GET /departments - список подразделений
+// с фильтрацией по атрибутам (attr.<name>=<value>) на дату as_of.
+func (h *DepartmentHandler) ListDepartments(w http.ResponseWriter, r *http.Request) {
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	depts, err := h.depService.List(r.Context(), attributeFilter(r), asOf)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidAttributes) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(depts)
+}
+
 // GetDepartment обрабатывает GET /departments/{id} - получение информации об отделе.
 func (h *DepartmentHandler) GetDepartment(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
@@ -56,33 +85,94 @@ func (h *DepartmentHandler) GetDepartment(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	depth := 1
+	depth, includeEmployees, asOf, ok := parseTreeParams(w, r)
+	if !ok {
+		return
+	}
+
+	dept, err := h.depService.GetByID(r.Context(), uint(id), depth, includeEmployees, asOf)
+	writeDepartmentTree(w, dept, err)
+}
+
+// GetDepartmentByCode обрабатывает GET /departments/by-code/{code} - получение подразделения по коду.
+func (h *DepartmentHandler) GetDepartmentByCode(w http.ResponseWriter, r *http.Request) {
+	depth, includeEmployees, asOf, ok := parseTreeParams(w, r)
+	if !ok {
+		return
+	}
+
+	dept, err := h.depService.GetByCode(r.Context(), r.PathValue("code"), depth, includeEmployees, asOf)
+	writeDepartmentTree(w, dept, err)
+}
+
+// GetDepartmentByExternalID обрабатывает GET /departments/by-external-id/{system}/{id} -
+// получение подразделения по идентификатору во внешней системе.
+func (h *DepartmentHandler) GetDepartmentByExternalID(w http.ResponseWriter, r *http.Request) {
+	depth, includeEmployees, asOf, ok := parseTreeParams(w, r)
+	if !ok {
+		return
+	}
+
+	dept, err := h.depService.GetByExternalID(r.Context(), r.PathValue("system"), r.PathValue("id"), depth, includeEmployees, asOf)
+	writeDepartmentTree(w, dept, err)
+}
+
+// parseTreeParams разбирает параметры depth, include_employees и as_of запроса подразделения.
+// При ошибке пишет ответ 400 и возвращает ok = false.
+func parseTreeParams(w http.ResponseWriter, r *http.Request) (depth int, includeEmployees bool, asOf *time.Time, ok bool) {
+	depth = 1
 	if d := r.URL.Query().Get("depth"); d != "" {
 		if val, err := strconv.Atoi(d); err == nil && val >= 1 && val <= 5 {
 			depth = val
 		} else {
 			http.Error(w, "depth must be integer between 1 and 5", http.StatusBadRequest)
-			return
+			return 0, false, nil, false
 		}
 	}
 
-	includeEmployees := true
+	includeEmployees = true
 	if ie := r.URL.Query().Get("include_employees"); ie != "" {
 		if val, err := strconv.ParseBool(ie); err == nil {
 			includeEmployees = val
 		} else {
 			http.Error(w, "invalid include_employees value", http.StatusBadRequest)
-			return
+			return 0, false, nil, false
 		}
 	}
 
-	dept, err := h.depService.GetByID(r.Context(), uint(id), depth, includeEmployees)
+	asOf, ok = parseAsOf(w, r)
+	if !ok {
+		return 0, false, nil, false
+	}
+	return depth, includeEmployees, asOf, true
+}
+
+// parseAsOf разбирает необязательный параметр as_of в формате YYYY-MM-DD.
+// При ошибке пишет ответ 400 и возвращает ok = false.
+func parseAsOf(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	raw := r.URL.Query().Get("as_of")
+	if raw == "" {
+		return nil, true
+	}
+	asOf, err := time.Parse(time.DateOnly, raw)
 	if err != nil {
-		if errors.Is(err, apperrors.ErrDepartmentNotFound) {
+		http.Error(w, "as_of must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+		return nil, false
+	}
+	return &asOf, true
+}
+
+// writeDepartmentTree пишет ответ с деревом подразделения либо соответствующую ошибку.
+func writeDepartmentTree(w http.ResponseWriter, dept *models.Department, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
-			return
+		case errors.Is(err, apperrors.ErrInvalidCode):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -105,17 +195,24 @@ func (h *DepartmentHandler) UpdateDepartment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	dept, err := h.depService.Update(r.Context(), uint(id), req.Name, req.ParentID)
+	dept, err := h.depService.Update(r.Context(), uint(id), req.Name, req.ParentID, req.Type, req.Attributes)
 	if err != nil {
 		switch {
 		case errors.Is(err, apperrors.ErrDepartmentNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
 		case errors.Is(err, apperrors.ErrDepartmentNameConflict),
 			errors.Is(err, apperrors.ErrSelfParent),
-			errors.Is(err, apperrors.ErrCycleDetected):
+			errors.Is(err, apperrors.ErrCycleDetected),
+			errors.Is(err, apperrors.ErrDepartmentTypeRule):
 			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidAttributes),
+			errors.Is(err, apperrors.ErrInvalidDepartmentName),
+			errors.Is(err, apperrors.ErrDepartmentTypeNotFound):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServerError(w, err)
 		}
 		return
 	}
@@ -159,17 +256,316 @@ func (h *DepartmentHandler) DeleteDepartment(w http.ResponseWriter, r *http.Requ
 		switch {
 		case errors.Is(err, apperrors.ErrDepartmentNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
-		case errors.Is(err, apperrors.ErrReassignWithChildren):
+		case errors.Is(err, apperrors.ErrReassignWithChildren),
+			errors.Is(err, apperrors.ErrHeadcountLimitExceeded):
 			http.Error(w, err.Error(), http.StatusConflict)
 		case errors.Is(err, apperrors.ErrInvalidMode),
 			errors.Is(err, apperrors.ErrReassignTargetRequired),
 			errors.Is(err, apperrors.ErrTargetDepartmentNotFound):
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServerError(w, err)
 		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// MergeDepartment обрабатывает POST /departments/{id}/merge-into/{targetId} - слияние подразделения с целевым.
+func (h *DepartmentHandler) MergeDepartment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+	targetID, err := strconv.ParseUint(r.PathValue("targetId"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid target department id", http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if dr := r.URL.Query().Get("dry_run"); dr != "" {
+		if val, err := strconv.ParseBool(dr); err == nil {
+			dryRun = val
+		} else {
+			http.Error(w, "invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.depService.Merge(r.Context(), uint(id), uint(targetID), dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound),
+			errors.Is(err, apperrors.ErrTargetDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrMergeIntoSelf),
+			errors.Is(err, apperrors.ErrMergeIntoDescendant),
+			errors.Is(err, apperrors.ErrHeadcountLimitExceeded),
+			errors.Is(err, apperrors.ErrDepartmentTypeRule):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// CloneDepartment обрабатывает POST /departments/{id}/clone - копирование структуры поддерева.
+func (h *DepartmentHandler) CloneDepartment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req cloneDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.depService.Clone(r.Context(), uint(id), req.Name, req.ParentID, req.IncludeVacancies)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound),
+			errors.Is(err, apperrors.ErrParentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrDepartmentNameConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidDepartmentName):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dept)
+}
+
+// ReorderDepartment обрабатывает POST /departments/{id}/reorder - изменение порядка среди соседей
+// (поле position) либо порядка дочерних подразделений (поле child_ids).
+func (h *DepartmentHandler) ReorderDepartment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req reorderDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if (req.Position == nil) == (req.ChildIDs == nil) {
+		http.Error(w, "exactly one of position or child_ids is required", http.StatusBadRequest)
+		return
+	}
+
+	var depts []models.Department
+	if req.Position != nil {
+		depts, err = h.depService.Reorder(r.Context(), uint(id), *req.Position)
+	} else {
+		depts, err = h.depService.ReorderChildren(r.Context(), uint(id), req.ChildIDs)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrInvalidSortPosition),
+			errors.Is(err, apperrors.ErrInvalidChildrenOrder):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(depts)
+}
+
+// SetDepartmentIdentifiers обрабатывает PUT /departments/{id}/identifiers - замена кода
+// и идентификаторов внешних систем подразделения.
+func (h *DepartmentHandler) SetDepartmentIdentifiers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req identifiersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.depService.SetIdentifiers(r.Context(), uint(id), req.Code, req.ExternalIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrDepartmentCodeConflict),
+			errors.Is(err, apperrors.ErrExternalIDConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidCode),
+			errors.Is(err, apperrors.ErrInvalidExternalID):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dept)
+}
+
+// SetDepartmentHead обрабатывает PUT /departments/{id}/head - назначение или снятие
+// руководителя подразделения.
+func (h *DepartmentHandler) SetDepartmentHead(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req setHeadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.depService.SetHead(r.Context(), uint(id), req.EmployeeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound),
+			errors.Is(err, apperrors.ErrEmployeeNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrEmployeeTerminated):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dept)
+}
+
+// SetDepartmentHeadcountLimit обрабатывает PUT /departments/{id}/headcount-limit - утверждённая
+// численность подразделения; headcount_limit = null снимает ограничение.
+func (h *DepartmentHandler) SetDepartmentHeadcountLimit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req setHeadcountLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.depService.SetHeadcountLimit(r.Context(), uint(id), req.HeadcountLimit)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidHeadcountLimit):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dept)
+}
+
+// GetDepartmentView обрабатывает GET /departments/{id}/{view} - дополнительные представления
+// подразделения. Один маршрут с переменным последним сегментом нужен потому, что маршрут
+// с фиксированным сегментом (например, /departments/{id}/stats) конфликтует
+// с GET /departments/by-code/{code}.
+func (h *DepartmentHandler) GetDepartmentView(w http.ResponseWriter, r *http.Request) {
+	switch r.PathValue("view") {
+	case "stats":
+		h.GetDepartmentStats(w, r)
+	case "headcount":
+		h.GetDepartmentHeadcount(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// GetDepartmentStats обрабатывает GET /departments/{id}/stats - показатели численности
+// и структуры поддерева подразделения с помесячным наймом за months месяцев (по умолчанию 12).
+func (h *DepartmentHandler) GetDepartmentStats(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	months := 12
+	if raw := r.URL.Query().Get("months"); raw != "" {
+		if months, err = strconv.Atoi(raw); err != nil {
+			http.Error(w, apperrors.ErrInvalidStatsWindow.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	stats, err := h.depService.Stats(r.Context(), uint(id), months)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidStatsWindow):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetDepartmentHeadcount обрабатывает GET /departments/{id}/headcount - утверждённая, фактическая
+// и открытая (вакансии) численность подразделения и его поддерева с итогами по поддеревьям.
+func (h *DepartmentHandler) GetDepartmentHeadcount(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.depService.HeadcountBudget(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrDepartmentNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
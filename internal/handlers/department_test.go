@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,6 +13,7 @@ import (
 
 	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,24 +22,24 @@ type MockDepartmentService struct {
 	mock.Mock
 }
 
-func (m *MockDepartmentService) Create(ctx context.Context, name string, parentID *uint) (*models.Department, error) {
-	args := m.Called(ctx, name, parentID)
+func (m *MockDepartmentService) Create(ctx context.Context, name string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error) {
+	args := m.Called(ctx, name, parentID, deptType, attrs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Department), args.Error(1)
 }
 
-func (m *MockDepartmentService) GetByID(ctx context.Context, id uint, depth int, includeEmployees bool) (*models.Department, error) {
-	args := m.Called(ctx, id, depth, includeEmployees)
+func (m *MockDepartmentService) GetByID(ctx context.Context, id uint, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	args := m.Called(ctx, id, depth, includeEmployees, asOf)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Department), args.Error(1)
 }
 
-func (m *MockDepartmentService) Update(ctx context.Context, id uint, name *string, parentID *uint) (*models.Department, error) {
-	args := m.Called(ctx, id, name, parentID)
+func (m *MockDepartmentService) Update(ctx context.Context, id uint, name *string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error) {
+	args := m.Called(ctx, id, name, parentID, deptType, attrs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -49,6 +51,102 @@ func (m *MockDepartmentService) Delete(ctx context.Context, id uint, mode string
 	return args.Error(0)
 }
 
+func (m *MockDepartmentService) Merge(ctx context.Context, sourceID, targetID uint, dryRun bool) (*service.MergeResult, error) {
+	args := m.Called(ctx, sourceID, targetID, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.MergeResult), args.Error(1)
+}
+
+func (m *MockDepartmentService) Clone(ctx context.Context, id uint, name string, parentID *uint, withVacancies bool) (*models.Department, error) {
+	args := m.Called(ctx, id, name, parentID, withVacancies)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) Reorder(ctx context.Context, id uint, position int) ([]models.Department, error) {
+	args := m.Called(ctx, id, position)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) ReorderChildren(ctx context.Context, parentID uint, childIDs []uint) ([]models.Department, error) {
+	args := m.Called(ctx, parentID, childIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) GetByCode(ctx context.Context, code string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	args := m.Called(ctx, code, depth, includeEmployees, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) List(ctx context.Context, attrFilter map[string]string, asOf *time.Time) ([]models.Department, error) {
+	args := m.Called(ctx, attrFilter, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) GetByExternalID(ctx context.Context, system, externalID string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	args := m.Called(ctx, system, externalID, depth, includeEmployees, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Department, error) {
+	args := m.Called(ctx, id, code, externalIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) SetHead(ctx context.Context, id uint, employeeID *uint) (*models.Department, error) {
+	args := m.Called(ctx, id, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) Stats(ctx context.Context, id uint, months int) (*models.DepartmentStats, error) {
+	args := m.Called(ctx, id, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentStats), args.Error(1)
+}
+
+func (m *MockDepartmentService) SetHeadcountLimit(ctx context.Context, id uint, limit *int) (*models.Department, error) {
+	args := m.Called(ctx, id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentService) HeadcountBudget(ctx context.Context, id uint) (*models.HeadcountBudget, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HeadcountBudget), args.Error(1)
+}
+
 func setupDepartmentTest(t *testing.T) (*MockDepartmentService, *DepartmentHandler, *http.ServeMux) {
 	mockSvc := new(MockDepartmentService)
 	handler := NewDepartmentHandler(mockSvc)
@@ -56,8 +154,17 @@ func setupDepartmentTest(t *testing.T) (*MockDepartmentService, *DepartmentHandl
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /departments", handler.CreateDepartment)
 	mux.HandleFunc("GET /departments/{id}", handler.GetDepartment)
+	mux.HandleFunc("GET /departments/{id}/{view}", handler.GetDepartmentView)
+	mux.HandleFunc("GET /departments/by-code/{code}", handler.GetDepartmentByCode)
+	mux.HandleFunc("GET /departments/by-external-id/{system}/{id}", handler.GetDepartmentByExternalID)
 	mux.HandleFunc("PATCH /departments/{id}", handler.UpdateDepartment)
 	mux.HandleFunc("DELETE /departments/{id}", handler.DeleteDepartment)
+	mux.HandleFunc("POST /departments/{id}/merge-into/{targetId}", handler.MergeDepartment)
+	mux.HandleFunc("POST /departments/{id}/clone", handler.CloneDepartment)
+	mux.HandleFunc("POST /departments/{id}/reorder", handler.ReorderDepartment)
+	mux.HandleFunc("PUT /departments/{id}/identifiers", handler.SetDepartmentIdentifiers)
+	mux.HandleFunc("PUT /departments/{id}/head", handler.SetDepartmentHead)
+	mux.HandleFunc("PUT /departments/{id}/headcount-limit", handler.SetDepartmentHeadcountLimit)
 
 	return mockSvc, handler, mux
 }
@@ -76,7 +183,7 @@ func TestCreateDepartment_Success(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	mockSvc.On("Create", mock.Anything, "IT", (*uint)(nil)).Return(expectedDept, nil)
+	mockSvc.On("Create", mock.Anything, "IT", (*uint)(nil), (*string)(nil), models.Attributes(nil)).Return(expectedDept, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -100,7 +207,7 @@ func TestCreateDepartment_Conflict(t *testing.T) {
 	reqBody := createDepartmentRequest{Name: "IT", ParentID: nil}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Create", mock.Anything, "IT", (*uint)(nil)).Return(nil, apperrors.ErrDepartmentNameConflict)
+	mockSvc.On("Create", mock.Anything, "IT", (*uint)(nil), (*string)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrDepartmentNameConflict)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -121,7 +228,7 @@ func TestCreateDepartment_ParentNotFound(t *testing.T) {
 	reqBody := createDepartmentRequest{Name: "Backend", ParentID: &parentID}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Create", mock.Anything, "Backend", &parentID).Return(nil, apperrors.ErrParentNotFound)
+	mockSvc.On("Create", mock.Anything, "Backend", &parentID, (*string)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrParentNotFound)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -135,6 +242,42 @@ func TestCreateDepartment_ParentNotFound(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestCreateDepartment_InvalidName(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+	mockSvc.On("Create", mock.Anything, "Sales test", (*uint)(nil), (*string)(nil), models.Attributes(nil)).
+		Return(nil, fmt.Errorf("%w: name contains forbidden word", apperrors.ErrInvalidDepartmentName))
+
+	req := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewBufferString(`{"name":"Sales test"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateDepartment_TypeRule(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrDepartmentTypeRule, http.StatusConflict},
+		{apperrors.ErrDepartmentTypeNotFound, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		mockSvc, _, mux := setupDepartmentTest(t)
+		parentID := uint(2)
+		deptType := "division"
+		mockSvc.On("Create", mock.Anything, "Retail", &parentID, &deptType, models.Attributes(nil)).Return(nil, tt.err)
+
+		body := `{"name":"Retail","parent_id":2,"type":"division"}`
+		req := httptest.NewRequest(http.MethodPost, "/departments", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+		mockSvc.AssertExpectations(t)
+	}
+}
+
 // --- GET ---
 func TestGetDepartment_Success(t *testing.T) {
 	mockSvc, _, mux := setupDepartmentTest(t)
@@ -148,7 +291,7 @@ func TestGetDepartment_Success(t *testing.T) {
 		Children:  []models.Department{},
 	}
 
-	mockSvc.On("GetByID", mock.Anything, uint(1), 1, true).Return(expectedDept, nil)
+	mockSvc.On("GetByID", mock.Anything, uint(1), 1, true, (*time.Time)(nil)).Return(expectedDept, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
 	w := httptest.NewRecorder()
@@ -165,10 +308,37 @@ func TestGetDepartment_Success(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestGetDepartment_AsOf(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	asOf := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("GetByID", mock.Anything, uint(1), 1, true, &asOf).Return(&models.Department{ID: 1, Name: "Old IT"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1?as_of=2025-12-31", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetDepartment_InvalidAsOf(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1?as_of=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetDepartment_NotFound(t *testing.T) {
 	mockSvc, _, mux := setupDepartmentTest(t)
 
-	mockSvc.On("GetByID", mock.Anything, uint(999), 1, true).Return(nil, apperrors.ErrDepartmentNotFound)
+	mockSvc.On("GetByID", mock.Anything, uint(999), 1, true, (*time.Time)(nil)).Return(nil, apperrors.ErrDepartmentNotFound)
 
 	req := httptest.NewRequest(http.MethodGet, "/departments/999", nil)
 	w := httptest.NewRecorder()
@@ -196,7 +366,7 @@ func TestUpdateDepartment_Success(t *testing.T) {
 		CreatedAt: time.Now(),
 	}
 
-	mockSvc.On("Update", mock.Anything, uint(1), &newName, (*uint)(nil)).Return(updatedDept, nil)
+	mockSvc.On("Update", mock.Anything, uint(1), &newName, (*uint)(nil), (*string)(nil), models.Attributes(nil)).Return(updatedDept, nil)
 
 	req := httptest.NewRequest(http.MethodPatch, "/departments/1", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -221,7 +391,7 @@ func TestUpdateDepartment_NotFound(t *testing.T) {
 	reqBody := updateDepartmentRequest{Name: &newName}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Update", mock.Anything, uint(999), &newName, (*uint)(nil)).Return(nil, apperrors.ErrDepartmentNotFound)
+	mockSvc.On("Update", mock.Anything, uint(999), &newName, (*uint)(nil), (*string)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrDepartmentNotFound)
 
 	req := httptest.NewRequest(http.MethodPatch, "/departments/999", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -276,3 +446,340 @@ func TestDeleteDepartment_MissingMode(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "mode query parameter is required")
 }
+
+func TestDeleteDepartment_ApprovalRequired(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("Delete", mock.Anything, uint(1), "cascade", (*uint)(nil)).
+		Return(fmt.Errorf("%w: delete of department 1", apperrors.ErrApprovalRequired))
+
+	req := httptest.NewRequest(http.MethodDelete, "/departments/1?mode=cascade", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+// --- MERGE ---
+func TestMergeDepartment_DryRun(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	expected := &service.MergeResult{SourceID: 1, TargetID: 2, DryRun: true, MovedEmployees: 4}
+	mockSvc.On("Merge", mock.Anything, uint(1), uint(2), true).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/merge-into/2?dry_run=true", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp service.MergeResult
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.True(t, resp.DryRun)
+	assert.Equal(t, int64(4), resp.MovedEmployees)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestMergeDepartment_IntoDescendant(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("Merge", mock.Anything, uint(1), uint(3), false).Return(nil, apperrors.ErrMergeIntoDescendant)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/merge-into/3", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestMergeDepartment_HeadcountLimit(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("Merge", mock.Anything, uint(1), uint(3), false).Return(nil, apperrors.ErrHeadcountLimitExceeded)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/merge-into/3", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+// --- CLONE ---
+func TestCloneDepartment_Success(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	parentID := uint(10)
+	body, _ := json.Marshal(cloneDepartmentRequest{Name: "Kazakhstan", ParentID: &parentID, IncludeVacancies: true})
+
+	expected := &models.Department{
+		ID:       100,
+		Name:     "Kazakhstan",
+		ParentID: &parentID,
+		Children: []models.Department{{ID: 101, Name: "Sales"}},
+	}
+	mockSvc.On("Clone", mock.Anything, uint(1), "Kazakhstan", &parentID, true).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/clone", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp models.Department
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Children, 1)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCloneDepartment_InvalidName(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	body, _ := json.Marshal(cloneDepartmentRequest{Name: ""})
+	mockSvc.On("Clone", mock.Anything, uint(1), "", (*uint)(nil), false).Return(nil, apperrors.ErrInvalidDepartmentName)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/clone", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+// --- REORDER ---
+func TestReorderDepartment_Position(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	expected := []models.Department{{ID: 4, Name: "Finance", SortOrder: 1}, {ID: 2, Name: "IT", SortOrder: 2}}
+	mockSvc.On("Reorder", mock.Anything, uint(4), 0).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/4/reorder", bytes.NewReader([]byte(`{"position":0}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp []models.Department
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Finance", resp[0].Name)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestReorderDepartment_Children(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("ReorderChildren", mock.Anything, uint(1), []uint{3, 2}).Return(nil, apperrors.ErrInvalidChildrenOrder)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/reorder", bytes.NewReader([]byte(`{"child_ids":[3,2]}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestReorderDepartment_BothFields(t *testing.T) {
+	_, _, mux := setupDepartmentTest(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/reorder", bytes.NewReader([]byte(`{"position":1,"child_ids":[3,2]}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "exactly one of position or child_ids")
+}
+
+// --- IDENTIFIERS ---
+func TestGetDepartmentByCode_Success(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	code := "ENG-PLT-01"
+	expected := &models.Department{ID: 5, Name: "Platform", Code: &code}
+	mockSvc.On("GetByCode", mock.Anything, "ENG-PLT-01", 2, false, (*time.Time)(nil)).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/by-code/ENG-PLT-01?depth=2&include_employees=false", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp models.Department
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, code, *resp.Code)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetDepartmentByExternalID_NotFound(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("GetByExternalID", mock.Anything, "sap", "1001", 1, true, (*time.Time)(nil)).Return(nil, apperrors.ErrDepartmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/by-external-id/sap/1001", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetDepartmentIdentifiers_Conflict(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	code := "ENG-PLT-01"
+	mockSvc.On("SetIdentifiers", mock.Anything, uint(5), &code, map[string]string{"sap": "1001"}).
+		Return(nil, apperrors.ErrDepartmentCodeConflict)
+
+	body := []byte(`{"code":"ENG-PLT-01","external_ids":{"sap":"1001"}}`)
+	req := httptest.NewRequest(http.MethodPut, "/departments/5/identifiers", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetDepartmentHead_Success(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	headID := uint(7)
+	mockSvc.On("SetHead", mock.Anything, uint(1), &headID).Return(&models.Department{ID: 1, Name: "IT", HeadID: &headID}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/departments/1/head", bytes.NewReader([]byte(`{"employee_id": 7}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetDepartmentHead_EmployeeNotFound(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("SetHead", mock.Anything, uint(1), mock.Anything).Return(nil, apperrors.ErrEmployeeNotFound)
+
+	req := httptest.NewRequest(http.MethodPut, "/departments/1/head", bytes.NewReader([]byte(`{"employee_id": 99}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetDepartmentStats(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("Stats", mock.Anything, uint(1), 12).Return(&models.DepartmentStats{
+		DepartmentID: 1, DirectHeadcount: 2, TotalHeadcount: 9, SubDepartments: 3, MaxDepth: 2, AvgSpanOfControl: 2.75,
+		HiresByMonth: []models.MonthlyHires{{Month: "2026-03", Hires: 1}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1/stats", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.DepartmentStats
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 9, got.TotalHeadcount)
+	assert.Len(t, got.HiresByMonth, 1)
+}
+
+func TestSetDepartmentHeadcountLimit(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	limit := 5
+	mockSvc.On("SetHeadcountLimit", mock.Anything, uint(1), &limit).Return(&models.Department{ID: 1, HeadcountLimit: &limit}, nil)
+	mockSvc.On("SetHeadcountLimit", mock.Anything, uint(1), (*int)(nil)).Return(&models.Department{ID: 1}, nil)
+	mockSvc.On("SetHeadcountLimit", mock.Anything, uint(2), mock.Anything).Return(nil, apperrors.ErrInvalidHeadcountLimit)
+
+	tests := []struct {
+		id   string
+		body string
+		code int
+	}{
+		{"1", `{"headcount_limit":5}`, http.StatusOK},
+		{"1", `{"headcount_limit":null}`, http.StatusOK},
+		{"2", `{"headcount_limit":-1}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/departments/"+tt.id+"/headcount-limit", bytes.NewBufferString(tt.body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.body)
+	}
+}
+
+func TestGetDepartmentHeadcount(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	budgeted := 4
+	mockSvc.On("HeadcountBudget", mock.Anything, uint(1)).Return(&models.HeadcountBudget{
+		DepartmentID: 1, Budgeted: &budgeted, Actual: 3, Open: 1, TotalBudgeted: 4, TotalActual: 3, TotalOpen: 1,
+	}, nil)
+	mockSvc.On("HeadcountBudget", mock.Anything, uint(2)).Return(nil, apperrors.ErrDepartmentNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1/headcount", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.HeadcountBudget
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 1, got.TotalOpen)
+
+	req = httptest.NewRequest(http.MethodGet, "/departments/2/headcount", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetDepartmentStats_InvalidWindow(t *testing.T) {
+	mockSvc, _, mux := setupDepartmentTest(t)
+
+	mockSvc.On("Stats", mock.Anything, uint(1), 100).Return(nil, apperrors.ErrInvalidStatsWindow)
+
+	for _, query := range []string{"months=abc", "months=100"} {
+		req := httptest.NewRequest(http.MethodGet, "/departments/1/stats?"+query, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetDepartmentView_Unknown(t *testing.T) {
+	_, _, mux := setupDepartmentTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1/unknown", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
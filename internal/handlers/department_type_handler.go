@@ -0,0 +1,107 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// DepartmentTypeHandler обрабатывает HTTP-запросы, связанные с типами подразделений.
+type DepartmentTypeHandler struct {
+	typeService service.DepartmentTypeService
+}
+
+// NewDepartmentTypeHandler создаёт новый экземпляр обработчика типов подразделений.
+func NewDepartmentTypeHandler(typeService service.DepartmentTypeService) *DepartmentTypeHandler {
+	return &DepartmentTypeHandler{typeService: typeService}
+}
+
+// CreateDepartmentType обрабатывает POST /department-types - создание типа подразделения
+// с правилами вложенности.
+func (h *DepartmentTypeHandler) CreateDepartmentType(w http.ResponseWriter, r *http.Request) {
+	var req departmentTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.typeService.Create(r.Context(), models.DepartmentType{
+		Name:        req.Name,
+		AllowRoot:   req.AllowRoot,
+		ParentTypes: req.ParentTypes,
+	})
+	if err != nil {
+		writeDepartmentTypeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(t)
+}
+
+// ListDepartmentTypes обрабатывает GET /department-types - список типов подразделений.
+func (h *DepartmentTypeHandler) ListDepartmentTypes(w http.ResponseWriter, r *http.Request) {
+	types, err := h.typeService.List(r.Context())
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types)
+}
+
+// UpdateDepartmentType обрабатывает PUT /department-types/{name} - замену правил вложенности типа.
+// Правила, которым не соответствуют существующие подразделения, отклоняются кодом 409.
+func (h *DepartmentTypeHandler) UpdateDepartmentType(w http.ResponseWriter, r *http.Request) {
+	var req departmentTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	t, err := h.typeService.Update(r.Context(), r.PathValue("name"), models.DepartmentType{
+		AllowRoot:   req.AllowRoot,
+		ParentTypes: req.ParentTypes,
+	})
+	if err != nil {
+		writeDepartmentTypeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(t)
+}
+
+// DeleteDepartmentType обрабатывает DELETE /department-types/{name} - удаление типа,
+// не назначенного ни одному подразделению.
+func (h *DepartmentTypeHandler) DeleteDepartmentType(w http.ResponseWriter, r *http.Request) {
+	if err := h.typeService.Delete(r.Context(), r.PathValue("name")); err != nil {
+		writeDepartmentTypeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeDepartmentTypeError отвечает кодом, соответствующим ошибке справочника типов подразделений.
+func writeDepartmentTypeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidDepartmentType):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrDepartmentTypeNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrDepartmentTypeConflict),
+		errors.Is(err, apperrors.ErrDepartmentTypeInUse),
+		errors.Is(err, apperrors.ErrDepartmentTypeRule):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
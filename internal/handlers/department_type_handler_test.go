@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDepartmentTypeService — мок для DepartmentTypeService
+type MockDepartmentTypeService struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentTypeService) Create(ctx context.Context, t models.DepartmentType) (*models.DepartmentType, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentType), args.Error(1)
+}
+
+func (m *MockDepartmentTypeService) List(ctx context.Context) ([]models.DepartmentType, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DepartmentType), args.Error(1)
+}
+
+func (m *MockDepartmentTypeService) Update(ctx context.Context, name string, t models.DepartmentType) (*models.DepartmentType, error) {
+	args := m.Called(ctx, name, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentType), args.Error(1)
+}
+
+func (m *MockDepartmentTypeService) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func setupDepartmentTypeTest(t *testing.T) (*MockDepartmentTypeService, *http.ServeMux) {
+	mockSvc := new(MockDepartmentTypeService)
+	handler := NewDepartmentTypeHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /department-types", handler.CreateDepartmentType)
+	mux.HandleFunc("GET /department-types", handler.ListDepartmentTypes)
+	mux.HandleFunc("PUT /department-types/{name}", handler.UpdateDepartmentType)
+	mux.HandleFunc("DELETE /department-types/{name}", handler.DeleteDepartmentType)
+
+	return mockSvc, mux
+}
+
+func TestCreateDepartmentType(t *testing.T) {
+	mockSvc, mux := setupDepartmentTypeTest(t)
+	want := models.DepartmentType{Name: "squad", ParentTypes: models.StringList{"team"}}
+	mockSvc.On("Create", mock.Anything, want).Return(&want, nil)
+	mockSvc.On("Create", mock.Anything, models.DepartmentType{Name: "team", ParentTypes: models.StringList{"department"}}).
+		Return(nil, apperrors.ErrDepartmentTypeConflict)
+
+	req := httptest.NewRequest(http.MethodPost, "/department-types", bytes.NewBufferString(`{"name":"squad","parent_types":["team"]}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got models.DepartmentType
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, "squad", got.Name)
+
+	req = httptest.NewRequest(http.MethodPost, "/department-types", bytes.NewBufferString(`{"name":"team","parent_types":["department"]}`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestUpdateDepartmentType_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrInvalidDepartmentType, http.StatusBadRequest},
+		{apperrors.ErrDepartmentTypeNotFound, http.StatusNotFound},
+		{apperrors.ErrDepartmentTypeRule, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupDepartmentTypeTest(t)
+		mockSvc.On("Update", mock.Anything, "team", models.DepartmentType{ParentTypes: models.StringList{"department"}}).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPut, "/department-types/team", bytes.NewBufferString(`{"parent_types":["department"]}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
+func TestDeleteDepartmentType(t *testing.T) {
+	mockSvc, mux := setupDepartmentTypeTest(t)
+	mockSvc.On("Delete", mock.Anything, "squad").Return(nil)
+	mockSvc.On("Delete", mock.Anything, "team").Return(apperrors.ErrDepartmentTypeInUse)
+
+	req := httptest.NewRequest(http.MethodDelete, "/department-types/squad", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/department-types/team", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
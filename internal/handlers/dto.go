@@ -1,22 +1,183 @@
 package handlers
 
-import "time"
+import (
+	"time"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
 
 // createDepartmentRequest представляет структуру JSON-запроса для создания нового подразделения.
 type createDepartmentRequest struct {
-	Name     string `json:"name"`
-	ParentID *uint  `json:"parent_id"`
+	Name       string            `json:"name"`
+	ParentID   *uint             `json:"parent_id"`
+	Type       *string           `json:"type"`
+	Attributes models.Attributes `json:"attributes"`
 }
 
 // updateDepartmentRequest представляет структуру JSON-запроса для обновления подразделения.
 type updateDepartmentRequest struct {
-	Name     *string `json:"name,omitempty"`
-	ParentID *uint   `json:"parent_id,omitempty"`
+	Name       *string           `json:"name,omitempty"`
+	ParentID   *uint             `json:"parent_id,omitempty"`
+	Type       *string           `json:"type,omitempty"`
+	Attributes models.Attributes `json:"attributes,omitempty"`
+}
+
+// cloneDepartmentRequest представляет структуру JSON-запроса для копирования поддерева подразделения.
+type cloneDepartmentRequest struct {
+	Name             string `json:"name"`
+	ParentID         *uint  `json:"parent_id"`
+	IncludeVacancies bool   `json:"include_vacancies"`
+}
+
+// reorderDepartmentRequest представляет структуру JSON-запроса для изменения порядка подразделений.
+type reorderDepartmentRequest struct {
+	Position *int   `json:"position,omitempty"`
+	ChildIDs []uint `json:"child_ids,omitempty"`
+}
+
+// identifiersRequest представляет структуру JSON-запроса для замены кода и внешних идентификаторов.
+type identifiersRequest struct {
+	Code        *string           `json:"code"`
+	ExternalIDs map[string]string `json:"external_ids"`
+}
+
+// importRequest представляет структуру JSON-запроса импорта подразделений и сотрудников.
+type importRequest struct {
+	Departments []service.ImportDepartment `json:"departments"`
+	Employees   []service.ImportEmployee   `json:"employees"`
 }
 
 // createEmployeeRequest представляет структуру JSON-запроса для создания нового сотрудника.
 type createEmployeeRequest struct {
-	FullName string     `json:"full_name"`
-	Position string     `json:"position"`
-	HiredAt  *time.Time `json:"hired_at"`
+	FullName   string            `json:"full_name"`
+	Position   string            `json:"position"`
+	PositionID *uint             `json:"position_id"`
+	VacancyID  *uint             `json:"vacancy_id"`
+	HiredAt    *time.Time        `json:"hired_at"`
+	Attributes models.Attributes `json:"attributes"`
+}
+
+// terminateEmployeeRequest представляет структуру JSON-запроса увольнения сотрудника.
+// Дата в формате YYYY-MM-DD, по умолчанию - сегодня.
+type terminateEmployeeRequest struct {
+	TerminatedAt string `json:"terminated_at,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+// rehireEmployeeRequest представляет структуру JSON-запроса повторного приёма сотрудника.
+// Дата в формате YYYY-MM-DD, по умолчанию - сегодня; без department_id сотрудник
+// возвращается в прежнее подразделение.
+type rehireEmployeeRequest struct {
+	HiredAt      string `json:"hired_at,omitempty"`
+	DepartmentID *uint  `json:"department_id,omitempty"`
+}
+
+// employeeStatusRequest представляет структуру JSON-запроса смены статуса сотрудника.
+type employeeStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// attributeDefinitionRequest представляет структуру JSON-запроса для создания определения атрибута.
+type attributeDefinitionRequest struct {
+	Entity     string   `json:"entity"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Required   bool     `json:"required"`
+	EnumValues []string `json:"enum_values"`
+	Pattern    string   `json:"pattern"`
+}
+
+// positionRequest представляет структуру JSON-запроса создания и изменения должности справочника.
+type positionRequest struct {
+	Title     string `json:"title"`
+	JobFamily string `json:"job_family"`
+	Grade     *int   `json:"grade"`
+}
+
+// departmentTypeRequest представляет структуру JSON-запроса создания и изменения типа подразделения.
+type departmentTypeRequest struct {
+	Name        string   `json:"name"`
+	AllowRoot   bool     `json:"allow_root"`
+	ParentTypes []string `json:"parent_types"`
+}
+
+// locationRequest представляет структуру JSON-запроса создания и изменения места.
+type locationRequest struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Country  string `json:"country"`
+	Timezone string `json:"timezone"`
+}
+
+// scheduleChangeRequest представляет структуру JSON-запроса для изменения подразделения с даты вступления в силу.
+type scheduleChangeRequest struct {
+	Name          *string `json:"name,omitempty"`
+	ParentID      *uint   `json:"parent_id,omitempty"`
+	EffectiveFrom string  `json:"effective_from"`
+}
+
+// transferEmployeeRequest представляет структуру JSON-запроса для перевода сотрудника в другой отдел.
+type transferEmployeeRequest struct {
+	DepartmentID  uint   `json:"department_id"`
+	EffectiveFrom string `json:"effective_from,omitempty"`
+}
+
+// reorgBatchRequest представляет структуру JSON-запроса для создания пакета запланированных изменений.
+type reorgBatchRequest struct {
+	ExecuteAt  time.Time               `json:"execute_at"`
+	Operations []models.ReorgOperation `json:"operations"`
+}
+
+// setHeadRequest представляет структуру JSON-запроса для назначения руководителя подразделения.
+type setHeadRequest struct {
+	EmployeeID *uint `json:"employee_id"`
+}
+
+// setHeadcountLimitRequest представляет структуру JSON-запроса утверждённой численности подразделения.
+type setHeadcountLimitRequest struct {
+	HeadcountLimit *int `json:"headcount_limit"`
+}
+
+// setLocationRequest представляет структуру JSON-запроса места подразделения или сотрудника.
+type setLocationRequest struct {
+	LocationID *uint `json:"location_id"`
+}
+
+// assignmentRequest представляет структуру JSON-запроса дополнительного назначения сотрудника.
+type assignmentRequest struct {
+	Role       string `json:"role"`
+	Allocation int    `json:"allocation"`
+}
+
+// createVacancyRequest представляет структуру JSON-запроса открытия вакансии.
+type createVacancyRequest struct {
+	Position        string     `json:"position"`
+	PositionID      *uint      `json:"position_id"`
+	TargetStartDate *time.Time `json:"target_start_date"`
+}
+
+// changeRequestRequest представляет структуру JSON-запроса для создания заявки на структурное изменение.
+type changeRequestRequest struct {
+	Operation    string                      `json:"operation"`
+	DepartmentID int                         `json:"department_id"`
+	Payload      models.ChangeRequestPayload `json:"payload"`
+	RequestedBy  int                         `json:"requested_by"`
+	ApproverRule string                      `json:"approver_rule"`
+}
+
+// decisionRequest представляет структуру JSON-запроса для согласования или отклонения заявки.
+type decisionRequest struct {
+	ApproverID uint   `json:"approver_id"`
+	Comment    string `json:"comment"`
+}
+
+// createSnapshotRequest представляет структуру JSON-запроса для создания снимка структуры.
+type createSnapshotRequest struct {
+	Label string `json:"label"`
+}
+
+// createSandboxRequest представляет структуру JSON-запроса для создания песочницы.
+type createSandboxRequest struct {
+	Name string `json:"name"`
 }
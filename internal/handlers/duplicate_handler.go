@@ -0,0 +1,88 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// DuplicateHandler обрабатывает HTTP-запросы поиска и слияния дубликатов сотрудников.
+type DuplicateHandler struct {
+	duplicateService service.DuplicateService
+}
+
+// NewDuplicateHandler создаёт новый экземпляр обработчика дубликатов сотрудников.
+func NewDuplicateHandler(duplicateService service.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{duplicateService: duplicateService}
+}
+
+// mergeEmployeesRequest - тело запроса слияния дубликатов с сотрудником.
+type mergeEmployeesRequest struct {
+	DuplicateIDs []uint `json:"duplicate_ids"`
+}
+
+// ListDuplicates обрабатывает GET /employees/duplicates - группы вероятных дубликатов
+// сотрудников. Параметр min_confidence (0..1] задаёт минимальную уверенность пары.
+func (h *DuplicateHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	minConfidence := service.DefaultDuplicateConfidence
+	if v := r.URL.Query().Get("min_confidence"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "invalid min_confidence", http.StatusBadRequest)
+			return
+		}
+		minConfidence = parsed
+	}
+
+	clusters, err := h.duplicateService.Find(r.Context(), minConfidence)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidDuplicateQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+// MergeEmployees обрабатывает POST /employees/{id}/merge - слияние дубликатов
+// duplicate_ids с сотрудником id, который остаётся основной записью.
+func (h *DuplicateHandler) MergeEmployees(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req mergeEmployeesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.duplicateService.Merge(r.Context(), uint(id), req.DuplicateIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrEmployeeNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrInvalidEmployeeMerge):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrEmployeeMergeConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDuplicateService — мок для DuplicateService
+type MockDuplicateService struct {
+	mock.Mock
+}
+
+func (m *MockDuplicateService) Find(ctx context.Context, minConfidence float64) ([]service.DuplicateCluster, error) {
+	args := m.Called(ctx, minConfidence)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]service.DuplicateCluster), args.Error(1)
+}
+
+func (m *MockDuplicateService) Merge(ctx context.Context, targetID uint, sourceIDs []uint) (*service.EmployeeMergeResult, error) {
+	args := m.Called(ctx, targetID, sourceIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.EmployeeMergeResult), args.Error(1)
+}
+
+func setupDuplicateTest(t *testing.T) (*MockDuplicateService, *http.ServeMux) {
+	mockSvc := new(MockDuplicateService)
+	handler := NewDuplicateHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /employees/duplicates", handler.ListDuplicates)
+	mux.HandleFunc("POST /employees/{id}/merge", handler.MergeEmployees)
+
+	return mockSvc, mux
+}
+
+func TestListDuplicates(t *testing.T) {
+	mockSvc, mux := setupDuplicateTest(t)
+
+	mockSvc.On("Find", mock.Anything, 0.8).Return([]service.DuplicateCluster{{
+		Confidence: 1,
+		Employees:  []models.Employee{{ID: 10}, {ID: 11}},
+		Pairs:      []service.DuplicatePair{{EmployeeIDs: [2]int{10, 11}, Confidence: 1}},
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/duplicates?min_confidence=0.8", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []service.DuplicateCluster
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, [2]int{10, 11}, got[0].Pairs[0].EmployeeIDs)
+}
+
+func TestListDuplicates_InvalidConfidence(t *testing.T) {
+	mockSvc, mux := setupDuplicateTest(t)
+
+	mockSvc.On("Find", mock.Anything, 2.0).Return(nil, apperrors.ErrInvalidDuplicateQuery)
+
+	for _, query := range []string{"abc", "2"} {
+		req := httptest.NewRequest(http.MethodGet, "/employees/duplicates?min_confidence="+query, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMergeEmployees(t *testing.T) {
+	mockSvc, mux := setupDuplicateTest(t)
+
+	mockSvc.On("Merge", mock.Anything, uint(10), []uint{11, 12}).Return(&service.EmployeeMergeResult{
+		Employee: &models.Employee{ID: 10},
+		Merges:   []models.EmployeeMerge{{SourceID: 11, TargetID: 10}, {SourceID: 12, TargetID: 10}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/employees/10/merge", bytes.NewBufferString(`{"duplicate_ids":[11,12]}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got service.EmployeeMergeResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got.Merges, 2)
+}
+
+func TestMergeEmployees_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrEmployeeNotFound, http.StatusNotFound},
+		{apperrors.ErrInvalidEmployeeMerge, http.StatusBadRequest},
+		{apperrors.ErrEmployeeMergeConflict, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupDuplicateTest(t)
+		mockSvc.On("Merge", mock.Anything, uint(10), []uint{11}).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/employees/10/merge", bytes.NewBufferString(`{"duplicate_ids":[11]}`))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
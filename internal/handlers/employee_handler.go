@@ -6,8 +6,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
 	"github.com/NailUsmanov/api_organization/internal/service"
 )
 
@@ -22,6 +24,9 @@ func NewEmployeeHandler(empService service.EmployeeService) *EmployeeHandler {
 }
 
 // CreateEmployee обрабатывает POST /departments/{id}/employees - создание нового сотрудника в отделе.
+// Должность задаётся идентификатором справочника (position_id) либо названием (position).
+// С vacancy_id сотрудник заполняет открытую вакансию отдела. Превышение утверждённой
+// численности в мягком режиме возвращается в поле warning, в жёстком - кодом 409.
 func (h *EmployeeHandler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	deptIDStr := r.PathValue("id")
 	deptID, err := strconv.ParseUint(deptIDStr, 10, 32)
@@ -36,16 +41,24 @@ func (h *EmployeeHandler) CreateEmployee(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	emp, err := h.empService.Create(r.Context(), uint(deptID), req.FullName, req.Position, req.HiredAt)
+	emp, err := h.empService.Create(r.Context(), uint(deptID), req.FullName, req.Position, req.PositionID, req.VacancyID,
+		req.HiredAt, req.Attributes)
 	if err != nil {
 		switch {
 		case errors.Is(err, apperrors.ErrEmployeeDepartmentNotFound):
 			http.Error(w, err.Error(), http.StatusNotFound)
 		case errors.Is(err, apperrors.ErrInvalidFullName),
-			errors.Is(err, apperrors.ErrInvalidPosition):
+			errors.Is(err, apperrors.ErrInvalidPosition),
+			errors.Is(err, apperrors.ErrPositionNotFound),
+			errors.Is(err, apperrors.ErrVacancyNotFound),
+			errors.Is(err, apperrors.ErrInvalidVacancy),
+			errors.Is(err, apperrors.ErrInvalidAttributes):
 			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrHeadcountLimitExceeded),
+			errors.Is(err, apperrors.ErrVacancyNotOpen):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServerError(w, err)
 		}
 		return
 	}
@@ -54,3 +67,185 @@ func (h *EmployeeHandler) CreateEmployee(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(emp)
 }
+
+// ListEmployees обрабатывает GET /employees - список сотрудников с фильтрацией
+// по отделу (department_id) и атрибутам (attr.<name>=<value>) на дату as_of.
+// Уволенные сотрудники возвращаются только с include_terminated=true.
+func (h *EmployeeHandler) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	var departmentID *uint
+	if idStr := r.URL.Query().Get("department_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid department_id", http.StatusBadRequest)
+			return
+		}
+		departmentID = new(uint)
+		*departmentID = uint(id)
+	}
+
+	asOf, ok := parseAsOf(w, r)
+	if !ok {
+		return
+	}
+
+	includeTerminated := false
+	if v := r.URL.Query().Get("include_terminated"); v != "" {
+		val, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid include_terminated value", http.StatusBadRequest)
+			return
+		}
+		includeTerminated = val
+	}
+
+	emps, err := h.empService.List(r.Context(), departmentID, attributeFilter(r), asOf, includeTerminated)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidAttributes) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emps)
+}
+
+// SetEmployeeIdentifiers обрабатывает PUT /employees/{id}/identifiers - замена кода
+// и идентификаторов внешних систем сотрудника.
+func (h *EmployeeHandler) SetEmployeeIdentifiers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req identifiersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	emp, err := h.empService.SetIdentifiers(r.Context(), uint(id), req.Code, req.ExternalIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrEmployeeNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrEmployeeCodeConflict),
+			errors.Is(err, apperrors.ErrExternalIDConflict):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidCode),
+			errors.Is(err, apperrors.ErrInvalidExternalID):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emp)
+}
+
+// TerminateEmployee обрабатывает POST /employees/{id}/terminate - увольнение сотрудника.
+func (h *EmployeeHandler) TerminateEmployee(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req terminateEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	terminatedAt, ok := parseOptionalDate(w, "terminated_at", req.TerminatedAt)
+	if !ok {
+		return
+	}
+
+	emp, err := h.empService.Terminate(r.Context(), uint(id), terminatedAt, req.Reason)
+	writeEmploymentResult(w, emp, err)
+}
+
+// RehireEmployee обрабатывает POST /employees/{id}/rehire - повторный приём уволенного сотрудника.
+func (h *EmployeeHandler) RehireEmployee(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req rehireEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	hiredAt, ok := parseOptionalDate(w, "hired_at", req.HiredAt)
+	if !ok {
+		return
+	}
+
+	emp, err := h.empService.Rehire(r.Context(), uint(id), hiredAt, req.DepartmentID)
+	writeEmploymentResult(w, emp, err)
+}
+
+// SetEmployeeStatus обрабатывает PUT /employees/{id}/status - перевод сотрудника
+// в отпуск (on_leave) и возвращение из него (active).
+func (h *EmployeeHandler) SetEmployeeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req employeeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	emp, err := h.empService.SetStatus(r.Context(), uint(id), req.Status)
+	writeEmploymentResult(w, emp, err)
+}
+
+// parseOptionalDate разбирает необязательную дату в формате YYYY-MM-DD.
+// При ошибке пишет ответ 400 и возвращает ok = false.
+func parseOptionalDate(w http.ResponseWriter, field, raw string) (*time.Time, bool) {
+	if raw == "" {
+		return nil, true
+	}
+	date, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		http.Error(w, field+" must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+		return nil, false
+	}
+	return &date, true
+}
+
+// writeEmploymentResult пишет сотрудника после смены статуса занятости либо соответствующую ошибку.
+func writeEmploymentResult(w http.ResponseWriter, emp *models.Employee, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrEmployeeNotFound),
+			errors.Is(err, apperrors.ErrEmployeeDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrInvalidEmploymentDate),
+			errors.Is(err, apperrors.ErrInvalidTerminationReason),
+			errors.Is(err, apperrors.ErrInvalidEmployeeStatus):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrEmployeeTerminated),
+			errors.Is(err, apperrors.ErrEmployeeNotTerminated),
+			errors.Is(err, apperrors.ErrHeadcountLimitExceeded):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emp)
+}
@@ -21,8 +21,49 @@ type MockEmployeeService struct {
 	mock.Mock
 }
 
-func (m *MockEmployeeService) Create(ctx context.Context, departmentID uint, fullName, position string, hiredAt *time.Time) (*models.Employee, error) {
-	args := m.Called(ctx, departmentID, fullName, position, hiredAt)
+func (m *MockEmployeeService) Create(ctx context.Context, departmentID uint, fullName, position string, positionID *uint,
+	vacancyID *uint, hiredAt *time.Time, attrs models.Attributes) (*models.Employee, error) {
+	args := m.Called(ctx, departmentID, fullName, position, positionID, vacancyID, hiredAt, attrs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeService) List(ctx context.Context, departmentID *uint, attrFilter map[string]string, asOf *time.Time, includeTerminated bool) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID, attrFilter, asOf, includeTerminated)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeService) SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Employee, error) {
+	args := m.Called(ctx, id, code, externalIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeService) Terminate(ctx context.Context, id uint, terminatedAt *time.Time, reason string) (*models.Employee, error) {
+	args := m.Called(ctx, id, terminatedAt, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeService) Rehire(ctx context.Context, id uint, hiredAt *time.Time, departmentID *uint) (*models.Employee, error) {
+	args := m.Called(ctx, id, hiredAt, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeService) SetStatus(ctx context.Context, id uint, status string) (*models.Employee, error) {
+	args := m.Called(ctx, id, status)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -35,6 +76,11 @@ func setupEmployeeTest(t *testing.T) (*MockEmployeeService, *EmployeeHandler, *h
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /departments/{id}/employees", handler.CreateEmployee)
+	mux.HandleFunc("PUT /employees/{id}/identifiers", handler.SetEmployeeIdentifiers)
+	mux.HandleFunc("GET /employees", handler.ListEmployees)
+	mux.HandleFunc("POST /employees/{id}/terminate", handler.TerminateEmployee)
+	mux.HandleFunc("POST /employees/{id}/rehire", handler.RehireEmployee)
+	mux.HandleFunc("PUT /employees/{id}/status", handler.SetEmployeeStatus)
 
 	return mockSvc, handler, mux
 }
@@ -60,9 +106,9 @@ func TestCreateEmployee_Success(t *testing.T) {
 	}
 
 	// Используем mock.MatchedBy для сравнения времени
-	mockSvc.On("Create", mock.Anything, uint(1), "John Doe", "Developer", mock.MatchedBy(func(t *time.Time) bool {
+	mockSvc.On("Create", mock.Anything, uint(1), "John Doe", "Developer", (*uint)(nil), (*uint)(nil), mock.MatchedBy(func(t *time.Time) bool {
 		return t != nil && t.Equal(hiredAt)
-	})).Return(expectedEmp, nil)
+	}), models.Attributes(nil)).Return(expectedEmp, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments/1/employees", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -90,7 +136,7 @@ func TestCreateEmployee_DepartmentNotFound(t *testing.T) {
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Create", mock.Anything, uint(999), "John Doe", "Developer", (*time.Time)(nil)).Return(nil, apperrors.ErrEmployeeDepartmentNotFound)
+	mockSvc.On("Create", mock.Anything, uint(999), "John Doe", "Developer", (*uint)(nil), (*uint)(nil), (*time.Time)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrEmployeeDepartmentNotFound)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments/999/employees", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -113,7 +159,7 @@ func TestCreateEmployee_InvalidFullName(t *testing.T) {
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Create", mock.Anything, uint(1), "", "Developer", (*time.Time)(nil)).Return(nil, apperrors.ErrInvalidFullName)
+	mockSvc.On("Create", mock.Anything, uint(1), "", "Developer", (*uint)(nil), (*uint)(nil), (*time.Time)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrInvalidFullName)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments/1/employees", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -136,7 +182,7 @@ func TestCreateEmployee_InvalidPosition(t *testing.T) {
 	}
 	body, _ := json.Marshal(reqBody)
 
-	mockSvc.On("Create", mock.Anything, uint(1), "John Doe", "", (*time.Time)(nil)).Return(nil, apperrors.ErrInvalidPosition)
+	mockSvc.On("Create", mock.Anything, uint(1), "John Doe", "", (*uint)(nil), (*uint)(nil), (*time.Time)(nil), models.Attributes(nil)).Return(nil, apperrors.ErrInvalidPosition)
 
 	req := httptest.NewRequest(http.MethodPost, "/departments/1/employees", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
@@ -150,6 +196,32 @@ func TestCreateEmployee_InvalidPosition(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+func TestCreateEmployee_VacancyErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrHeadcountLimitExceeded, http.StatusConflict},
+		{apperrors.ErrVacancyNotOpen, http.StatusConflict},
+		{apperrors.ErrVacancyNotFound, http.StatusBadRequest},
+		{apperrors.ErrInvalidVacancy, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		mockSvc, _, mux := setupEmployeeTest(t)
+		vacancyID := uint(5)
+		mockSvc.On("Create", mock.Anything, uint(1), "John Doe", "", (*uint)(nil), &vacancyID, (*time.Time)(nil), models.Attributes(nil)).
+			Return(nil, tt.err)
+
+		body := `{"full_name":"John Doe","vacancy_id":5}`
+		req := httptest.NewRequest(http.MethodPost, "/departments/1/employees", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
 func TestCreateEmployee_InvalidJSON(t *testing.T) {
 	_, _, mux := setupEmployeeTest(t)
 
@@ -178,3 +250,135 @@ func TestCreateEmployee_InvalidDepartmentID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "invalid department id")
 }
+
+func TestSetEmployeeIdentifiers_Success(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	code := "EMP-7"
+	expected := &models.Employee{ID: 7, FullName: "Ivan Petrov", Code: &code}
+	mockSvc.On("SetIdentifiers", mock.Anything, uint(7), &code, map[string]string(nil)).Return(expected, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/employees/7/identifiers", bytes.NewReader([]byte(`{"code":"EMP-7"}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSetEmployeeIdentifiers_NotFound(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	mockSvc.On("SetIdentifiers", mock.Anything, uint(99), (*string)(nil), map[string]string(nil)).Return(nil, apperrors.ErrEmployeeNotFound)
+
+	req := httptest.NewRequest(http.MethodPut, "/employees/99/identifiers", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestListEmployees_IncludeTerminated(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	mockSvc.On("List", mock.Anything, (*uint)(nil), map[string]string{}, (*time.Time)(nil), true).
+		Return([]models.Employee{{ID: 1, Status: models.EmployeeTerminated}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/employees?include_terminated=true", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestTerminateEmployee_Success(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	date := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("Terminate", mock.Anything, uint(1), &date, "relocation").
+		Return(&models.Employee{ID: 1, Status: models.EmployeeTerminated, TerminatedAt: &date, TerminationReason: "relocation"}, nil)
+
+	body := bytes.NewBufferString(`{"terminated_at":"2025-03-31","reason":"relocation"}`)
+	req := httptest.NewRequest(http.MethodPost, "/employees/1/terminate", body)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.Employee
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, models.EmployeeTerminated, got.Status)
+}
+
+func TestTerminateEmployee_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"bad date", `{"terminated_at":"31.03.2025"}`, nil, http.StatusBadRequest},
+		{"before hire", `{}`, apperrors.ErrInvalidEmploymentDate, http.StatusBadRequest},
+		{"already terminated", `{}`, apperrors.ErrEmployeeTerminated, http.StatusConflict},
+		{"not found", `{}`, apperrors.ErrEmployeeNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc, _, mux := setupEmployeeTest(t)
+			if tt.err != nil {
+				mockSvc.On("Terminate", mock.Anything, uint(1), (*time.Time)(nil), "").Return(nil, tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/employees/1/terminate", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestRehireEmployee_NotTerminated(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	mockSvc.On("Rehire", mock.Anything, uint(1), (*time.Time)(nil), (*uint)(nil)).Return(nil, apperrors.ErrEmployeeNotTerminated)
+
+	req := httptest.NewRequest(http.MethodPost, "/employees/1/rehire", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestRehireEmployee_HeadcountLimit(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	mockSvc.On("Rehire", mock.Anything, uint(1), (*time.Time)(nil), (*uint)(nil)).Return(nil, apperrors.ErrHeadcountLimitExceeded)
+
+	req := httptest.NewRequest(http.MethodPost, "/employees/1/rehire", bytes.NewBufferString(`{}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestSetEmployeeStatus_Invalid(t *testing.T) {
+	mockSvc, _, mux := setupEmployeeTest(t)
+
+	mockSvc.On("SetStatus", mock.Anything, uint(1), "terminated").Return(nil, apperrors.ErrInvalidEmployeeStatus)
+
+	req := httptest.NewRequest(http.MethodPut, "/employees/1/status", bytes.NewBufferString(`{"status":"terminated"}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
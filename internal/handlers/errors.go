@@ -0,0 +1,30 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+)
+
+// writeServerError отвечает на ошибку, которую обработчик не сопоставил бизнес-ошибке.
+// Нарушения ограничений базы данных из-за параллельных запросов дают 409 Conflict,
+// конфликты транзакций и таймауты - 503 Service Unavailable, остальное - 500.
+func writeServerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrUniqueViolation),
+		errors.Is(err, apperrors.ErrForeignKeyViolation),
+		errors.Is(err, apperrors.ErrCheckViolation):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, apperrors.ErrSerializationFailure),
+		errors.Is(err, apperrors.ErrDeadlock):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, apperrors.ErrStatementTimeout),
+		errors.Is(err, apperrors.ErrLockNotAvailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteServerError(t *testing.T) {
+	tests := []struct {
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{&apperrors.DBError{Kind: apperrors.ErrUniqueViolation, Constraint: "idx_employees_code"}, http.StatusConflict, ""},
+		{&apperrors.DBError{Kind: apperrors.ErrForeignKeyViolation}, http.StatusConflict, ""},
+		{&apperrors.DBError{Kind: apperrors.ErrCheckViolation}, http.StatusConflict, ""},
+		{&apperrors.DBError{Kind: apperrors.ErrSerializationFailure}, http.StatusServiceUnavailable, "1"},
+		{&apperrors.DBError{Kind: apperrors.ErrDeadlock}, http.StatusServiceUnavailable, "1"},
+		{&apperrors.DBError{Kind: apperrors.ErrStatementTimeout}, http.StatusServiceUnavailable, ""},
+		{&apperrors.DBError{Kind: apperrors.ErrLockNotAvailable}, http.StatusServiceUnavailable, ""},
+		{errors.New("boom"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeServerError(w, tt.err)
+
+		assert.Equal(t, tt.status, w.Code, tt.err.Error())
+		assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), tt.err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// ImportHandler обрабатывает HTTP-запросы импорта данных из внешних систем.
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+// NewImportHandler создаёт новый экземпляр обработчика импорта.
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// Import обрабатывает POST /import - создание или обновление подразделений и сотрудников
+// по кодам и идентификаторам внешних систем.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req importRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.importService.Import(r.Context(), req.Departments, req.Employees)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrParentNotFound),
+			errors.Is(err, apperrors.ErrEmployeeDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrDepartmentCodeConflict),
+			errors.Is(err, apperrors.ErrEmployeeCodeConflict),
+			errors.Is(err, apperrors.ErrExternalIDConflict),
+			errors.Is(err, apperrors.ErrDepartmentNameConflict),
+			errors.Is(err, apperrors.ErrSelfParent),
+			errors.Is(err, apperrors.ErrCycleDetected),
+			errors.Is(err, apperrors.ErrHeadcountLimitExceeded),
+			errors.Is(err, apperrors.ErrEmployeeTerminated),
+			errors.Is(err, apperrors.ErrDepartmentTypeRule):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidCode),
+			errors.Is(err, apperrors.ErrInvalidExternalID),
+			errors.Is(err, apperrors.ErrImportKeyRequired),
+			errors.Is(err, apperrors.ErrImportParentAmbiguous),
+			errors.Is(err, apperrors.ErrInvalidDepartmentName),
+			errors.Is(err, apperrors.ErrDepartmentTypeNotFound),
+			errors.Is(err, apperrors.ErrInvalidFullName),
+			errors.Is(err, apperrors.ErrInvalidPosition),
+			errors.Is(err, apperrors.ErrInvalidAttributes):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockImportService — мок для ImportService
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) Import(ctx context.Context, departments []service.ImportDepartment, employees []service.ImportEmployee) (*service.ImportResult, error) {
+	args := m.Called(ctx, departments, employees)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.ImportResult), args.Error(1)
+}
+
+func setupImportTest(t *testing.T) (*MockImportService, *http.ServeMux) {
+	mockSvc := new(MockImportService)
+	handler := NewImportHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /import", handler.Import)
+
+	return mockSvc, mux
+}
+
+func TestImport_Success(t *testing.T) {
+	mockSvc, mux := setupImportTest(t)
+
+	expected := &service.ImportResult{DepartmentsCreated: 1, EmployeesCreated: 1}
+	mockSvc.On("Import", mock.Anything, mock.MatchedBy(func(d []service.ImportDepartment) bool {
+		return len(d) == 1 && *d[0].Code == "ENG" && d[0].ExternalIDs["sap"] == "1001"
+	}), mock.MatchedBy(func(e []service.ImportEmployee) bool {
+		return len(e) == 1 && e[0].DepartmentCode == "ENG"
+	})).Return(expected, nil)
+
+	body := []byte(`{
+		"departments": [{"code": "ENG", "external_ids": {"sap": "1001"}, "name": "Engineering"}],
+		"employees": [{"code": "EMP-1", "department_code": "ENG", "full_name": "Ivan Petrov", "position": "Engineer"}]
+	}`)
+	req := httptest.NewRequest(http.MethodPost, "/import", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp service.ImportResult
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, *expected, resp)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestImport_KeyRequired(t *testing.T) {
+	mockSvc, mux := setupImportTest(t)
+
+	mockSvc.On("Import", mock.Anything, mock.Anything, mock.Anything).Return(nil, apperrors.ErrImportKeyRequired)
+
+	req := httptest.NewRequest(http.MethodPost, "/import", bytes.NewReader([]byte(`{"departments":[{"name":"X"}]}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestImport_DepartmentTypeRule(t *testing.T) {
+	mockSvc, mux := setupImportTest(t)
+
+	mockSvc.On("Import", mock.Anything, mock.MatchedBy(func(d []service.ImportDepartment) bool {
+		return len(d) == 1 && d[0].Type != nil && *d[0].Type == "team"
+	}), mock.Anything).Return(nil, fmt.Errorf("departments[0]: %w", apperrors.ErrDepartmentTypeRule))
+
+	body := `{"departments":[{"code":"PAY","name":"Payments","type":"team"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/import", bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// LintHandler обрабатывает HTTP-запросы отчёта о состоянии структуры.
+type LintHandler struct {
+	lintService service.LintService
+}
+
+// NewLintHandler создаёт новый экземпляр обработчика отчёта о состоянии структуры.
+func NewLintHandler(lintService service.LintService) *LintHandler {
+	return &LintHandler{lintService: lintService}
+}
+
+// GetOrgHealth обрабатывает GET /org-health - замечания по всему дереву подразделений.
+// Параметр severity (error, warning или info) оставляет замечания этого уровня и серьёзнее.
+func (h *LintHandler) GetOrgHealth(w http.ResponseWriter, r *http.Request) {
+	minSeverity := r.URL.Query().Get("severity")
+	if minSeverity != "" && !service.ValidSeverity(minSeverity) {
+		http.Error(w, "severity must be error, warning or info", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.lintService.Report(r.Context())
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	if minSeverity != "" {
+		report.Findings = report.FindingsAtLeast(minSeverity)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLintService — мок для LintService
+type MockLintService struct {
+	mock.Mock
+}
+
+func (m *MockLintService) Report(ctx context.Context) (*service.LintReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.LintReport), args.Error(1)
+}
+
+func setupLintTest(t *testing.T) (*MockLintService, *http.ServeMux) {
+	mockSvc := new(MockLintService)
+	handler := NewLintHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /org-health", handler.GetOrgHealth)
+
+	return mockSvc, mux
+}
+
+func TestGetOrgHealth_FilterBySeverity(t *testing.T) {
+	mockSvc, mux := setupLintTest(t)
+
+	mockSvc.On("Report", mock.Anything).Return(&service.LintReport{
+		Summary: map[string]int{service.SeverityError: 1, service.SeverityWarning: 0, service.SeverityInfo: 1},
+		Findings: []service.LintFinding{
+			{Rule: service.LintDuplicateLikeName, Severity: service.SeverityError, DepartmentID: 2, Link: "/departments/2"},
+			{Rule: service.LintNoHead, Severity: service.SeverityInfo, DepartmentID: 3, Link: "/departments/3"},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/org-health?severity=warning", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got service.LintReport
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got.Findings, 1)
+	assert.Equal(t, "/departments/2", got.Findings[0].Link)
+}
+
+func TestGetOrgHealth_InvalidSeverity(t *testing.T) {
+	mockSvc, mux := setupLintTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/org-health?severity=fatal", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "Report", mock.Anything)
+}
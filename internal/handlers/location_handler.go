@@ -0,0 +1,254 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// LocationHandler обрабатывает HTTP-запросы, связанные с офисами и площадками.
+type LocationHandler struct {
+	locService service.LocationService
+}
+
+// NewLocationHandler создаёт новый экземпляр обработчика мест.
+func NewLocationHandler(locService service.LocationService) *LocationHandler {
+	return &LocationHandler{locService: locService}
+}
+
+// CreateLocation обрабатывает POST /locations - создание места.
+func (h *LocationHandler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	var req locationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := h.locService.Create(r.Context(), models.Location{
+		Name: req.Name, Address: req.Address, Country: req.Country, Timezone: req.Timezone,
+	})
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(loc)
+}
+
+// ListLocations обрабатывает GET /locations - список мест, необязательно отфильтрованный
+// по стране (country).
+func (h *LocationHandler) ListLocations(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.locService.List(r.Context(), r.URL.Query().Get("country"))
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(locations)
+}
+
+// GetLocation обрабатывает GET /locations/{id} - получение места.
+func (h *LocationHandler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := h.locService.Get(r.Context(), id)
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loc)
+}
+
+// UpdateLocation обрабатывает PUT /locations/{id} - замену названия, адреса, страны
+// и часового пояса места.
+func (h *LocationHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	var req locationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := h.locService.Update(r.Context(), id, models.Location{
+		Name: req.Name, Address: req.Address, Country: req.Country, Timezone: req.Timezone,
+	})
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loc)
+}
+
+// DeleteLocation обрабатывает DELETE /locations/{id} - удаление места, не заданного
+// подразделениям и сотрудникам.
+func (h *LocationHandler) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.locService.Delete(r.Context(), id); err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListLocationEmployees обрабатывает GET /locations/{id}/employees - работающие сотрудники
+// места, включая унаследовавших его от подразделения.
+func (h *LocationHandler) ListLocationEmployees(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	employees, err := h.locService.Employees(r.Context(), id)
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employees)
+}
+
+// GetLocationHeadcount обрабатывает GET /locations/headcount - численность работающих
+// сотрудников по местам всей организации или поддерева подразделения department_id.
+func (h *LocationHandler) GetLocationHeadcount(w http.ResponseWriter, r *http.Request) {
+	var departmentID *uint
+	if idStr := r.URL.Query().Get("department_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid department_id", http.StatusBadRequest)
+			return
+		}
+		departmentID = new(uint)
+		*departmentID = uint(id)
+	}
+
+	headcount, err := h.locService.Headcount(r.Context(), departmentID)
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(headcount)
+}
+
+// SetDepartmentLocation обрабатывает PUT /departments/{id}/location - назначение или снятие
+// места подразделения.
+func (h *LocationHandler) SetDepartmentLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req setLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.locService.SetDepartmentLocation(r.Context(), uint(id), req.LocationID)
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dept)
+}
+
+// SetEmployeeLocation обрабатывает PUT /employees/{id}/location - собственное место сотрудника
+// вместо места подразделения; null возвращает место подразделения.
+func (h *LocationHandler) SetEmployeeLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req setLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	emp, err := h.locService.SetEmployeeLocation(r.Context(), uint(id), req.LocationID)
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emp)
+}
+
+// GetEmployeeLocation обрабатывает GET /employees/{id}/location - действующее место
+// сотрудника, его часовой пояс и текущее местное время.
+func (h *LocationHandler) GetEmployeeLocation(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := h.locService.EmployeeLocation(r.Context(), uint(id))
+	if err != nil {
+		writeLocationError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loc)
+}
+
+// parseLocationID разбирает идентификатор места из пути запроса.
+func parseLocationID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid location id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeLocationError отвечает кодом, соответствующим ошибке справочника мест.
+func writeLocationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidLocation):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrLocationNotFound),
+		errors.Is(err, apperrors.ErrDepartmentNotFound),
+		errors.Is(err, apperrors.ErrEmployeeNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrLocationConflict),
+		errors.Is(err, apperrors.ErrLocationInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLocationService — мок для LocationService
+type MockLocationService struct {
+	mock.Mock
+}
+
+func (m *MockLocationService) Create(ctx context.Context, loc models.Location) (*models.Location, error) {
+	args := m.Called(ctx, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Location), args.Error(1)
+}
+
+func (m *MockLocationService) Get(ctx context.Context, id uint) (*models.Location, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Location), args.Error(1)
+}
+
+func (m *MockLocationService) List(ctx context.Context, country string) ([]models.Location, error) {
+	args := m.Called(ctx, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Location), args.Error(1)
+}
+
+func (m *MockLocationService) Update(ctx context.Context, id uint, loc models.Location) (*models.Location, error) {
+	args := m.Called(ctx, id, loc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Location), args.Error(1)
+}
+
+func (m *MockLocationService) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLocationService) Employees(ctx context.Context, id uint) ([]models.Employee, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockLocationService) Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error) {
+	args := m.Called(ctx, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LocationHeadcount), args.Error(1)
+}
+
+func (m *MockLocationService) SetDepartmentLocation(ctx context.Context, departmentID uint, locationID *uint) (*models.Department, error) {
+	args := m.Called(ctx, departmentID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockLocationService) SetEmployeeLocation(ctx context.Context, employeeID uint, locationID *uint) (*models.Employee, error) {
+	args := m.Called(ctx, employeeID, locationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockLocationService) EmployeeLocation(ctx context.Context, employeeID uint) (*models.EmployeeLocation, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmployeeLocation), args.Error(1)
+}
+
+func (m *MockLocationService) Timezone(ctx context.Context, employeeID uint) (*time.Location, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Location), args.Error(1)
+}
+
+func setupLocationTest(t *testing.T) (*MockLocationService, *http.ServeMux) {
+	mockSvc := new(MockLocationService)
+	handler := NewLocationHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /locations", handler.CreateLocation)
+	mux.HandleFunc("GET /locations", handler.ListLocations)
+	mux.HandleFunc("DELETE /locations/{id}", handler.DeleteLocation)
+	mux.HandleFunc("GET /locations/{id}/employees", handler.ListLocationEmployees)
+	mux.HandleFunc("GET /locations/headcount", handler.GetLocationHeadcount)
+	mux.HandleFunc("PUT /departments/{id}/location", handler.SetDepartmentLocation)
+	mux.HandleFunc("GET /employees/{id}/location", handler.GetEmployeeLocation)
+
+	return mockSvc, mux
+}
+
+func TestCreateLocation(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	mockSvc.On("Create", mock.Anything, models.Location{Name: "Berlin Office", Country: "DE", Timezone: "Europe/Berlin"}).
+		Return(&models.Location{ID: 1, Name: "Berlin Office", Country: "DE", Timezone: "Europe/Berlin"}, nil)
+
+	body := `{"name":"Berlin Office","country":"DE","timezone":"Europe/Berlin"}`
+	req := httptest.NewRequest(http.MethodPost, "/locations", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got models.Location
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 1, got.ID)
+}
+
+func TestCreateLocation_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrInvalidLocation, http.StatusBadRequest},
+		{apperrors.ErrLocationConflict, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupLocationTest(t)
+		mockSvc.On("Create", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/locations", bytes.NewBufferString(`{"name":"x"}`))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
+func TestListLocations(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	mockSvc.On("List", mock.Anything, "DE").Return([]models.Location{{ID: 1, Name: "Berlin Office"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/locations?country=DE", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.Location
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 1)
+}
+
+func TestDeleteLocation_InUse(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	mockSvc.On("Delete", mock.Anything, uint(2)).Return(apperrors.ErrLocationInUse)
+
+	req := httptest.NewRequest(http.MethodDelete, "/locations/2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestListLocationEmployees_NotFound(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	mockSvc.On("Employees", mock.Anything, uint(9)).Return(nil, apperrors.ErrLocationNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/locations/9/employees", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetLocationHeadcount(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	deptID := uint(5)
+	locID := 1
+	mockSvc.On("Headcount", mock.Anything, &deptID).Return([]models.LocationHeadcount{
+		{LocationID: &locID, Name: "Berlin Office", Country: "DE", Timezone: "Europe/Berlin", Headcount: 4},
+		{Headcount: 2},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/locations/headcount?department_id=5", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.LocationHeadcount
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 2)
+	assert.Nil(t, got[1].LocationID)
+}
+
+func TestGetLocationHeadcount_InvalidDepartment(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/locations/headcount?department_id=abc", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "Headcount", mock.Anything, mock.Anything)
+}
+
+func TestSetDepartmentLocation(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	locID := uint(2)
+	mockSvc.On("SetDepartmentLocation", mock.Anything, uint(5), &locID).
+		Return(&models.Department{ID: 5, Name: "Platform", LocationID: &locID}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/departments/5/location", bytes.NewBufferString(`{"location_id":2}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetEmployeeLocation(t *testing.T) {
+	mockSvc, mux := setupLocationTest(t)
+
+	deptID := 3
+	localTime := time.Date(2025, 3, 10, 5, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	mockSvc.On("EmployeeLocation", mock.Anything, uint(7)).Return(&models.EmployeeLocation{
+		EmployeeID:   7,
+		Source:       models.LocationSourceDepartment,
+		DepartmentID: &deptID,
+		Location:     &models.Location{ID: 2, Name: "New York", Country: "US", Timezone: "America/New_York"},
+		LocalTime:    &localTime,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/employees/7/location", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got models.EmployeeLocation
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, "America/New_York", got.Location.Timezone)
+	assert.Equal(t, models.LocationSourceDepartment, got.Source)
+}
@@ -0,0 +1,187 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// PositionHandler обрабатывает HTTP-запросы, связанные со справочником должностей.
+type PositionHandler struct {
+	posService service.PositionService
+}
+
+// NewPositionHandler создаёт новый экземпляр обработчика справочника должностей.
+func NewPositionHandler(posService service.PositionService) *PositionHandler {
+	return &PositionHandler{posService: posService}
+}
+
+// CreatePosition обрабатывает POST /positions - создание должности справочника.
+func (h *PositionHandler) CreatePosition(w http.ResponseWriter, r *http.Request) {
+	var req positionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pos, err := h.posService.Create(r.Context(), models.Position{Title: req.Title, JobFamily: req.JobFamily, Grade: req.Grade})
+	if err != nil {
+		writePositionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pos)
+}
+
+// ListPositions обрабатывает GET /positions - список должностей справочника,
+// необязательно отфильтрованный по профессиональному семейству (job_family).
+func (h *PositionHandler) ListPositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := h.posService.List(r.Context(), r.URL.Query().Get("job_family"))
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(positions)
+}
+
+// GetPosition обрабатывает GET /positions/{id} - получение должности справочника.
+func (h *PositionHandler) GetPosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePositionID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	pos, err := h.posService.Get(r.Context(), id)
+	if err != nil {
+		writePositionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pos)
+}
+
+// UpdatePosition обрабатывает PUT /positions/{id} - замену названия, семейства и грейда
+// должности. Новое название получают все сотрудники этой должности.
+func (h *PositionHandler) UpdatePosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePositionID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req positionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pos, err := h.posService.Update(r.Context(), id, models.Position{Title: req.Title, JobFamily: req.JobFamily, Grade: req.Grade})
+	if err != nil {
+		writePositionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pos)
+}
+
+// DeletePosition обрабатывает DELETE /positions/{id} - удаление должности, не назначенной сотрудникам.
+func (h *PositionHandler) DeletePosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePositionID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.posService.Delete(r.Context(), id); err != nil {
+		writePositionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergePosition обрабатывает POST /positions/{id}/merge-into/{targetId} - перевод сотрудников
+// должности на должность targetId и удаление должности.
+func (h *PositionHandler) MergePosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := parsePositionID(w, r, "id")
+	if !ok {
+		return
+	}
+	targetID, ok := parsePositionID(w, r, "targetId")
+	if !ok {
+		return
+	}
+
+	pos, err := h.posService.Merge(r.Context(), id, targetID)
+	if err != nil {
+		writePositionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pos)
+}
+
+// GetJobFamilies обрабатывает GET /job-families - численность работающих сотрудников
+// по профессиональным семействам и должностям всей организации или поддерева
+// подразделения department_id.
+func (h *PositionHandler) GetJobFamilies(w http.ResponseWriter, r *http.Request) {
+	var departmentID *uint
+	if idStr := r.URL.Query().Get("department_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid department_id", http.StatusBadRequest)
+			return
+		}
+		departmentID = new(uint)
+		*departmentID = uint(id)
+	}
+
+	families, err := h.posService.JobFamilies(r.Context(), departmentID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrDepartmentNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(families)
+}
+
+// parsePositionID разбирает идентификатор должности из параметра пути name.
+func parsePositionID(w http.ResponseWriter, r *http.Request, name string) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue(name), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid position id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writePositionError отвечает кодом, соответствующим ошибке справочника должностей.
+func writePositionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidPositionEntry):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrPositionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrPositionConflict),
+		errors.Is(err, apperrors.ErrPositionInUse):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPositionService — мок для PositionService
+type MockPositionService struct {
+	mock.Mock
+}
+
+func (m *MockPositionService) Create(ctx context.Context, pos models.Position) (*models.Position, error) {
+	args := m.Called(ctx, pos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionService) Get(ctx context.Context, id uint) (*models.Position, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionService) List(ctx context.Context, jobFamily string) ([]models.Position, error) {
+	args := m.Called(ctx, jobFamily)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Position), args.Error(1)
+}
+
+func (m *MockPositionService) Update(ctx context.Context, id uint, pos models.Position) (*models.Position, error) {
+	args := m.Called(ctx, id, pos)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionService) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPositionService) Merge(ctx context.Context, sourceID, targetID uint) (*models.Position, error) {
+	args := m.Called(ctx, sourceID, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionService) JobFamilies(ctx context.Context, departmentID *uint) ([]models.JobFamilyHeadcount, error) {
+	args := m.Called(ctx, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.JobFamilyHeadcount), args.Error(1)
+}
+
+func setupPositionTest(t *testing.T) (*MockPositionService, *http.ServeMux) {
+	mockSvc := new(MockPositionService)
+	handler := NewPositionHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /positions", handler.CreatePosition)
+	mux.HandleFunc("GET /positions", handler.ListPositions)
+	mux.HandleFunc("PUT /positions/{id}", handler.UpdatePosition)
+	mux.HandleFunc("DELETE /positions/{id}", handler.DeletePosition)
+	mux.HandleFunc("POST /positions/{id}/merge-into/{targetId}", handler.MergePosition)
+	mux.HandleFunc("GET /job-families", handler.GetJobFamilies)
+
+	return mockSvc, mux
+}
+
+func TestCreatePosition(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	grade := 3
+	mockSvc.On("Create", mock.Anything, models.Position{Title: "Backend Engineer", JobFamily: "Engineering", Grade: &grade}).
+		Return(&models.Position{ID: 1, Title: "Backend Engineer", JobFamily: "Engineering", Grade: &grade}, nil)
+
+	body := `{"title":"Backend Engineer","job_family":"Engineering","grade":3}`
+	req := httptest.NewRequest(http.MethodPost, "/positions", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got models.Position
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 1, got.ID)
+}
+
+func TestCreatePosition_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrInvalidPositionEntry, http.StatusBadRequest},
+		{apperrors.ErrPositionConflict, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupPositionTest(t)
+		mockSvc.On("Create", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/positions", bytes.NewBufferString(`{"title":"x"}`))
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
+func TestListPositions(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	mockSvc.On("List", mock.Anything, "Engineering").Return([]models.Position{{ID: 1, Title: "Backend Engineer"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/positions?job_family=Engineering", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.Position
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 1)
+}
+
+func TestUpdatePosition_NotFound(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	mockSvc.On("Update", mock.Anything, uint(9), models.Position{Title: "Backend Engineer"}).Return(nil, apperrors.ErrPositionNotFound)
+
+	req := httptest.NewRequest(http.MethodPut, "/positions/9", bytes.NewBufferString(`{"title":"Backend Engineer"}`))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeletePosition_InUse(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	mockSvc.On("Delete", mock.Anything, uint(2)).Return(apperrors.ErrPositionInUse)
+
+	req := httptest.NewRequest(http.MethodDelete, "/positions/2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMergePosition(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	mockSvc.On("Merge", mock.Anything, uint(2), uint(1)).Return(&models.Position{ID: 1, Title: "Backend Engineer"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/positions/2/merge-into/1", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestGetJobFamilies(t *testing.T) {
+	mockSvc, mux := setupPositionTest(t)
+
+	deptID := uint(5)
+	mockSvc.On("JobFamilies", mock.Anything, &deptID).Return([]models.JobFamilyHeadcount{
+		{JobFamily: "Engineering", Headcount: 5, Positions: []models.PositionHeadcount{{Title: "Backend Engineer", Headcount: 5}}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/job-families?department_id=5", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.JobFamilyHeadcount
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 5, got[0].Headcount)
+
+	req = httptest.NewRequest(http.MethodGet, "/job-families?department_id=abc", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
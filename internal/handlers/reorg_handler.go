@@ -0,0 +1,120 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// ReorgHandler обрабатывает HTTP-запросы пакетов запланированных изменений структуры.
+type ReorgHandler struct {
+	reorgService service.ReorgService
+}
+
+// NewReorgHandler создаёт новый экземпляр обработчика пакетов изменений.
+func NewReorgHandler(reorgService service.ReorgService) *ReorgHandler {
+	return &ReorgHandler{reorgService: reorgService}
+}
+
+// CreateReorganization обрабатывает POST /reorganizations - создание пакета изменений,
+// выполняемого в момент execute_at.
+func (h *ReorgHandler) CreateReorganization(w http.ResponseWriter, r *http.Request) {
+	var req reorgBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ExecuteAt.IsZero() {
+		http.Error(w, "execute_at is required", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.reorgService.Submit(r.Context(), req.ExecuteAt, req.Operations)
+	if err != nil {
+		switch {
+		// Пакет с переносом или удалением, требующим согласования, отклоняется как запрещённый,
+		// а не как некорректный.
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, apperrors.ErrInvalidReorgBatch),
+			errors.Is(err, apperrors.ErrInvalidExecutionTime):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(batch)
+}
+
+// ListReorganizations обрабатывает GET /reorganizations - список пакетов изменений,
+// необязательно отфильтрованный по статусу (status).
+func (h *ReorgHandler) ListReorganizations(w http.ResponseWriter, r *http.Request) {
+	batches, err := h.reorgService.List(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidReorgBatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batches)
+}
+
+// GetReorganization обрабатывает GET /reorganizations/{id} - получение пакета изменений со статусом.
+func (h *ReorgHandler) GetReorganization(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid reorganization id", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.reorgService.Get(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrReorgBatchNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batch)
+}
+
+// CancelReorganization обрабатывает POST /reorganizations/{id}/cancel - отмена ожидающего пакета.
+func (h *ReorgHandler) CancelReorganization(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid reorganization id", http.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.reorgService.Cancel(r.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrReorgBatchNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrReorgBatchNotPending):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(batch)
+}
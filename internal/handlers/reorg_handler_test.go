@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReorgService — мок для ReorgService
+type MockReorgService struct {
+	mock.Mock
+}
+
+func (m *MockReorgService) Submit(ctx context.Context, executeAt time.Time, ops []models.ReorgOperation) (*models.ReorgBatch, error) {
+	args := m.Called(ctx, executeAt, ops)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgService) Get(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgService) List(ctx context.Context, status string) ([]models.ReorgBatch, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgService) Cancel(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorgBatch), args.Error(1)
+}
+
+func setupReorgTest(t *testing.T) (*MockReorgService, *http.ServeMux) {
+	mockSvc := new(MockReorgService)
+	handler := NewReorgHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /reorganizations", handler.CreateReorganization)
+	mux.HandleFunc("GET /reorganizations", handler.ListReorganizations)
+	mux.HandleFunc("GET /reorganizations/{id}", handler.GetReorganization)
+	mux.HandleFunc("POST /reorganizations/{id}/cancel", handler.CancelReorganization)
+
+	return mockSvc, mux
+}
+
+func TestCreateReorganization_Success(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	executeAt := time.Date(2026, 4, 1, 6, 0, 0, 0, time.UTC)
+	mockSvc.On("Submit", mock.Anything, executeAt, mock.MatchedBy(func(ops []models.ReorgOperation) bool {
+		return len(ops) == 2 && ops[0].Type == "move" && *ops[0].ParentID == 2 && ops[1].EmployeeID == 7
+	})).Return(&models.ReorgBatch{ID: 1, Status: models.ReorgStatusPending, ExecuteAt: executeAt}, nil)
+
+	body := []byte(`{"execute_at": "2026-04-01T06:00:00Z", "operations": [
+		{"type": "move", "department_id": 4, "parent_id": 2},
+		{"type": "transfer", "employee_id": 7, "department_id": 2}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/reorganizations", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var resp models.ReorgBatch
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReorgStatusPending, resp.Status)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateReorganization_ValidationFailed(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	mockSvc.On("Submit", mock.Anything, mock.Anything, mock.Anything).Return(nil, apperrors.ErrInvalidReorgBatch)
+
+	body := []byte(`{"execute_at": "2026-04-01T06:00:00Z", "operations": [{"type": "rename", "department_id": 4, "name": "Sales"}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/reorganizations", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateReorganization_ApprovalRequired(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	mockSvc.On("Submit", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: %w", apperrors.ErrInvalidReorgBatch, apperrors.ErrApprovalRequired))
+
+	body := []byte(`{"execute_at": "2026-04-01T06:00:00Z", "operations": [{"type": "move", "department_id": 4, "parent_id": 2}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/reorganizations", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateReorganization_MissingExecuteAt(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	body := []byte(`{"operations": [{"type": "rename", "department_id": 4, "name": "Core"}]}`)
+	req := httptest.NewRequest(http.MethodPost, "/reorganizations", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetReorganization_NotFound(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	mockSvc.On("Get", mock.Anything, uint(99)).Return(nil, apperrors.ErrReorgBatchNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/reorganizations/99", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCancelReorganization_NotPending(t *testing.T) {
+	mockSvc, mux := setupReorgTest(t)
+
+	mockSvc.On("Cancel", mock.Anything, uint(1)).Return(nil, apperrors.ErrReorgBatchNotPending)
+
+	req := httptest.NewRequest(http.MethodPost, "/reorganizations/1/cancel", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
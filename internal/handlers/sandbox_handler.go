@@ -0,0 +1,151 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// SandboxHandler обрабатывает HTTP-запросы песочниц для пробной реорганизации.
+// Изменения внутри песочницы выполняются обычными запросами к подразделениям
+// и сотрудникам с заголовком X-Sandbox-ID.
+type SandboxHandler struct {
+	sandboxService service.SandboxService
+}
+
+// NewSandboxHandler создаёт новый экземпляр обработчика песочниц.
+func NewSandboxHandler(sandboxService service.SandboxService) *SandboxHandler {
+	return &SandboxHandler{sandboxService: sandboxService}
+}
+
+// CreateSandbox обрабатывает POST /sandboxes - создание песочницы с копией текущей структуры.
+func (h *SandboxHandler) CreateSandbox(w http.ResponseWriter, r *http.Request) {
+	var req createSandboxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sandbox, err := h.sandboxService.Create(r.Context(), req.Name)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidSandboxName) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sandbox)
+}
+
+// ListSandboxes обрабатывает GET /sandboxes - список песочниц.
+func (h *SandboxHandler) ListSandboxes(w http.ResponseWriter, r *http.Request) {
+	sandboxes, err := h.sandboxService.List(r.Context())
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sandboxes)
+}
+
+// GetSandbox обрабатывает GET /sandboxes/{id} - получение песочницы.
+func (h *SandboxHandler) GetSandbox(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSandboxID(w, r)
+	if !ok {
+		return
+	}
+
+	sandbox, err := h.sandboxService.Get(r.Context(), id)
+	if err != nil {
+		writeSandboxError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sandbox)
+}
+
+// GetSandboxChanges обрабатывает GET /sandboxes/{id}/changes - изменения песочницы
+// относительно структуры на момент её создания.
+func (h *SandboxHandler) GetSandboxChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSandboxID(w, r)
+	if !ok {
+		return
+	}
+
+	diff, err := h.sandboxService.Changes(r.Context(), id)
+	if err != nil {
+		writeSandboxError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// PromoteSandbox обрабатывает POST /sandboxes/{id}/promote - перенос изменений песочницы
+// в рабочую структуру. Возвращает перенесённые изменения.
+func (h *SandboxHandler) PromoteSandbox(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSandboxID(w, r)
+	if !ok {
+		return
+	}
+
+	diff, err := h.sandboxService.Promote(r.Context(), id)
+	if err != nil {
+		writeSandboxError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// DiscardSandbox обрабатывает DELETE /sandboxes/{id} - закрытие песочницы без переноса изменений.
+func (h *SandboxHandler) DiscardSandbox(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseSandboxID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.sandboxService.Discard(r.Context(), id); err != nil {
+		writeSandboxError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseSandboxID разбирает идентификатор песочницы из пути; при ошибке отвечает 400.
+func parseSandboxID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid sandbox id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeSandboxError отвечает статусом, соответствующим ошибке сервиса песочниц.
+func writeSandboxError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrSandboxNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrSandboxClosed), errors.Is(err, apperrors.ErrSandboxConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, apperrors.ErrApprovalRequired):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		writeServerError(w, err)
+	}
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/middleware"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSandboxService — мок для SandboxService
+type MockSandboxService struct {
+	mock.Mock
+}
+
+func (m *MockSandboxService) Create(ctx context.Context, name string) (*models.Sandbox, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxService) Get(ctx context.Context, id uint) (*models.Sandbox, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxService) List(ctx context.Context) ([]models.Sandbox, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxService) Changes(ctx context.Context, id uint) (*service.SnapshotDiff, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.SnapshotDiff), args.Error(1)
+}
+
+func (m *MockSandboxService) Promote(ctx context.Context, id uint) (*service.SnapshotDiff, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.SnapshotDiff), args.Error(1)
+}
+
+func (m *MockSandboxService) Discard(ctx context.Context, id uint) (*models.Sandbox, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxService) Within(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx, id)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(context.WithValue(ctx, sandboxCtxKey{}, id))
+}
+
+// sandboxCtxKey помечает контекст, переданный моком в обработчик песочницы
+type sandboxCtxKey struct{}
+
+func setupSandboxTest(t *testing.T) (*MockSandboxService, *http.ServeMux) {
+	mockSvc := new(MockSandboxService)
+	handler := NewSandboxHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sandboxes", handler.CreateSandbox)
+	mux.HandleFunc("GET /sandboxes", handler.ListSandboxes)
+	mux.HandleFunc("GET /sandboxes/{id}", handler.GetSandbox)
+	mux.HandleFunc("GET /sandboxes/{id}/changes", handler.GetSandboxChanges)
+	mux.HandleFunc("POST /sandboxes/{id}/promote", handler.PromoteSandbox)
+	mux.HandleFunc("DELETE /sandboxes/{id}", handler.DiscardSandbox)
+
+	return mockSvc, mux
+}
+
+func TestCreateSandbox(t *testing.T) {
+	mockSvc, mux := setupSandboxTest(t)
+
+	mockSvc.On("Create", mock.Anything, "Q3 reorg").Return(&models.Sandbox{ID: 1, Name: "Q3 reorg", Status: models.SandboxActive}, nil)
+
+	body, _ := json.Marshal(map[string]string{"name": "Q3 reorg"})
+	req := httptest.NewRequest(http.MethodPost, "/sandboxes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got models.Sandbox
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, models.SandboxActive, got.Status)
+}
+
+func TestCreateSandbox_InvalidName(t *testing.T) {
+	mockSvc, mux := setupSandboxTest(t)
+
+	mockSvc.On("Create", mock.Anything, "").Return(nil, apperrors.ErrInvalidSandboxName)
+
+	req := httptest.NewRequest(http.MethodPost, "/sandboxes", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPromoteSandbox_Conflict(t *testing.T) {
+	mockSvc, mux := setupSandboxTest(t)
+
+	mockSvc.On("Promote", mock.Anything, uint(2)).
+		Return(nil, fmt.Errorf("%w: departments [4], employees []", apperrors.ErrSandboxConflict))
+
+	req := httptest.NewRequest(http.MethodPost, "/sandboxes/2/promote", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetSandboxChanges(t *testing.T) {
+	mockSvc, mux := setupSandboxTest(t)
+
+	mockSvc.On("Changes", mock.Anything, uint(2)).Return(&service.SnapshotDiff{
+		MovedDepartments: []service.MovedDepartment{{ID: 4, Name: "Platform", NewParentID: nil}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sandboxes/2/changes", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got service.SnapshotDiff
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got.MovedDepartments, 1)
+}
+
+func TestDiscardSandbox_NotFound(t *testing.T) {
+	mockSvc, mux := setupSandboxTest(t)
+
+	mockSvc.On("Discard", mock.Anything, uint(9)).Return(nil, apperrors.ErrSandboxNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/sandboxes/9", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSandboxMiddleware(t *testing.T) {
+	mockSvc := new(MockSandboxService)
+	mockSvc.On("Within", mock.Anything, uint(2)).Return(nil)
+	mockSvc.On("Within", mock.Anything, uint(3)).Return(fmt.Errorf("%w: status is promoted", apperrors.ErrSandboxClosed))
+
+	var sandboxID any
+	log, _ := logtest.NewNullLogger()
+	handler := middleware.Sandbox(mockSvc, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sandboxID = r.Context().Value(sandboxCtxKey{})
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		header    string
+		status    int
+		sandboxID any
+	}{
+		{header: "", status: http.StatusOK, sandboxID: nil},
+		{header: "2", status: http.StatusOK, sandboxID: uint(2)},
+		{header: "3", status: http.StatusConflict, sandboxID: nil},
+		{header: "abc", status: http.StatusBadRequest, sandboxID: nil},
+	}
+	for _, tt := range tests {
+		sandboxID = nil
+		req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
+		if tt.header != "" {
+			req.Header.Set(middleware.SandboxHeader, tt.header)
+		}
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.status, w.Code, "header %q", tt.header)
+		assert.Equal(t, tt.sandboxID, sandboxID, "header %q", tt.header)
+	}
+}
+
+// sandboxScopeFunc позволяет задать SandboxScope функцией и проверить исход транзакции песочницы.
+type sandboxScopeFunc func(ctx context.Context, id uint, fn func(ctx context.Context) error) error
+
+func (f sandboxScopeFunc) Within(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+	return f(ctx, id, fn)
+}
+
+func TestSandboxMiddleware_RollsBackFailedRequest(t *testing.T) {
+	var result error
+	scope := sandboxScopeFunc(func(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+		result = fn(ctx)
+		return result
+	})
+	log, _ := logtest.NewNullLogger()
+
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusConflict, http.StatusInternalServerError} {
+		handler := middleware.Sandbox(scope, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(status), status)
+		}))
+		req := httptest.NewRequest(http.MethodPatch, "/departments/1", nil)
+		req.Header.Set(middleware.SandboxHeader, "2")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, status, w.Code)
+		assert.Equal(t, status >= http.StatusBadRequest, result != nil, "status %d must roll back the sandbox transaction", status)
+	}
+}
+
+func TestSandboxMiddleware_CommitError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{err: fmt.Errorf("%w: commit", apperrors.ErrSerializationFailure), status: http.StatusConflict},
+		{err: errors.New("connection reset"), status: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		scope := sandboxScopeFunc(func(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+			return tt.err
+		})
+		log, hook := logtest.NewNullLogger()
+		handler := middleware.Sandbox(scope, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "/departments/9")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":9}`))
+		}))
+		req := httptest.NewRequest(http.MethodPost, "/departments", nil)
+		req.Header.Set(middleware.SandboxHeader, "2")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		// Ответ обработчика не отправляется, если изменения не зафиксированы.
+		assert.Equal(t, tt.status, w.Code, tt.err.Error())
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.NotContains(t, w.Body.String(), `{"id":9}`)
+		if assert.Len(t, hook.Entries, 1) {
+			assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+			assert.ErrorIs(t, hook.LastEntry().Data[logrus.ErrorKey].(error), tt.err)
+		}
+	}
+}
+
+func TestSandboxMiddleware_SendsResponseAfterCommit(t *testing.T) {
+	committed := false
+	scope := sandboxScopeFunc(func(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		committed = true
+		return nil
+	})
+	log, _ := logtest.NewNullLogger()
+	var w *httptest.ResponseRecorder
+	handler := middleware.Sandbox(scope, log)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Location", "/departments/9")
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`{"id":9}`))
+		// До фиксации клиент ещё ничего не получил.
+		assert.Empty(t, w.Header().Get("Location"))
+		assert.Zero(t, w.Body.Len())
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/departments", nil)
+	req.Header.Set(middleware.SandboxHeader, "2")
+	w = httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.True(t, committed)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/departments/9", w.Header().Get("Location"))
+	assert.Equal(t, `{"id":9}`, w.Body.String())
+}
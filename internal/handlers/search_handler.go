@@ -0,0 +1,49 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// SearchHandler обрабатывает HTTP-запросы поиска.
+type SearchHandler struct {
+	searchService service.SearchService
+}
+
+// NewSearchHandler создаёт новый экземпляр обработчика поиска.
+func NewSearchHandler(searchService service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search обрабатывает GET /search?q=...&mode=full|autocomplete&limit=N - поиск
+// сотрудников и подразделений с результатами по убыванию релевантности.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if raw := query.Get("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	hits, err := h.searchService.Search(r.Context(), query.Get("q"), query.Get("mode"), limit)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidSearchQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hits)
+}
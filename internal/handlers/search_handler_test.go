@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchService — мок для SearchService
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) Search(ctx context.Context, query, mode string, limit int) ([]models.SearchHit, error) {
+	args := m.Called(ctx, query, mode, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SearchHit), args.Error(1)
+}
+
+func setupSearchTest(t *testing.T) (*MockSearchService, *http.ServeMux) {
+	mockSvc := new(MockSearchService)
+	handler := NewSearchHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", handler.Search)
+
+	return mockSvc, mux
+}
+
+func TestSearch_Success(t *testing.T) {
+	mockSvc, mux := setupSearchTest(t)
+
+	mockSvc.On("Search", mock.Anything, "petrov", "", 0).Return([]models.SearchHit{
+		{Type: models.SearchEmployee, ID: 7, Title: "Ivan Petrov", DepartmentID: 4, Path: models.DepartmentPath{"HQ", "Platform"}, Rank: 0.9},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=petrov", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []map[string]any
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, "employee", got[0]["type"])
+	assert.Equal(t, []any{"HQ", "Platform"}, got[0]["department_path"])
+}
+
+func TestSearch_InvalidParams(t *testing.T) {
+	mockSvc, mux := setupSearchTest(t)
+
+	mockSvc.On("Search", mock.Anything, "", "", 0).
+		Return(nil, fmt.Errorf("%w: q must be non-empty", apperrors.ErrInvalidSearchQuery))
+
+	for _, url := range []string{"/search", "/search?q=x&limit=abc"} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
@@ -0,0 +1,109 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// SnapshotHandler обрабатывает HTTP-запросы снимков структуры.
+type SnapshotHandler struct {
+	snapshotService service.SnapshotService
+}
+
+// NewSnapshotHandler создаёт новый экземпляр обработчика снимков.
+func NewSnapshotHandler(snapshotService service.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{snapshotService: snapshotService}
+}
+
+// CreateSnapshot обрабатывает POST /snapshots - сохранение снимка текущей структуры.
+// Тело запроса необязательно.
+func (h *SnapshotHandler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req createSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := h.snapshotService.Create(r.Context(), req.Label)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidSnapshotLabel) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// ListSnapshots обрабатывает GET /snapshots - список снимков без документов.
+func (h *SnapshotHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := h.snapshotService.List(r.Context())
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// GetSnapshot обрабатывает GET /snapshots/{id} - получение снимка с документом.
+func (h *SnapshotHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid snapshot id", http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := h.snapshotService.Get(r.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrSnapshotNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// DiffSnapshots обрабатывает GET /snapshots/{a}/diff/{b} - структурные различия
+// между снимками a и b.
+func (h *SnapshotHandler) DiffSnapshots(w http.ResponseWriter, r *http.Request) {
+	fromID, err := strconv.ParseUint(r.PathValue("a"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid snapshot id", http.StatusBadRequest)
+		return
+	}
+	toID, err := strconv.ParseUint(r.PathValue("b"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid snapshot id", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.snapshotService.Diff(r.Context(), uint(fromID), uint(toID))
+	if err != nil {
+		if errors.Is(err, apperrors.ErrSnapshotNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/NailUsmanov/api_organization/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSnapshotService — мок для SnapshotService
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) Create(ctx context.Context, label string) (*models.Snapshot, error) {
+	args := m.Called(ctx, label)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotService) Get(ctx context.Context, id uint) (*models.Snapshot, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotService) List(ctx context.Context) ([]models.Snapshot, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotService) Diff(ctx context.Context, fromID, toID uint) (*service.SnapshotDiff, error) {
+	args := m.Called(ctx, fromID, toID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.SnapshotDiff), args.Error(1)
+}
+
+func setupSnapshotTest(t *testing.T) (*MockSnapshotService, *http.ServeMux) {
+	mockSvc := new(MockSnapshotService)
+	handler := NewSnapshotHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /snapshots", handler.CreateSnapshot)
+	mux.HandleFunc("GET /snapshots", handler.ListSnapshots)
+	mux.HandleFunc("GET /snapshots/{id}", handler.GetSnapshot)
+	mux.HandleFunc("GET /snapshots/{a}/diff/{b}", handler.DiffSnapshots)
+
+	return mockSvc, mux
+}
+
+func TestCreateSnapshot_WithoutBody(t *testing.T) {
+	mockSvc, mux := setupSnapshotTest(t)
+
+	mockSvc.On("Create", mock.Anything, "").Return(&models.Snapshot{ID: 3, DepartmentCount: 5}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/snapshots", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateSnapshot_WithLabel(t *testing.T) {
+	mockSvc, mux := setupSnapshotTest(t)
+
+	mockSvc.On("Create", mock.Anything, "Q1 plan").Return(&models.Snapshot{ID: 4, Label: "Q1 plan"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/snapshots", bytes.NewReader([]byte(`{"label": "Q1 plan"}`)))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDiffSnapshots_Success(t *testing.T) {
+	mockSvc, mux := setupSnapshotTest(t)
+
+	mockSvc.On("Diff", mock.Anything, uint(1), uint(2)).Return(&service.SnapshotDiff{From: 1, To: 2,
+		RenamedDepartments: []service.RenamedDepartment{{ID: 2, OldName: "Engineering", NewName: "R&D"}}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/snapshots/1/diff/2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp service.SnapshotDiff
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "R&D", resp.RenamedDepartments[0].NewName)
+}
+
+func TestDiffSnapshots_NotFound(t *testing.T) {
+	mockSvc, mux := setupSnapshotTest(t)
+
+	mockSvc.On("Diff", mock.Anything, uint(1), uint(9)).Return(nil, apperrors.ErrSnapshotNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/snapshots/1/diff/9", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,134 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// VacancyHandler обрабатывает HTTP-запросы, связанные с вакансиями.
+type VacancyHandler struct {
+	vacancyService service.VacancyService
+}
+
+// NewVacancyHandler создаёт новый экземпляр обработчика вакансий.
+func NewVacancyHandler(vacancyService service.VacancyService) *VacancyHandler {
+	return &VacancyHandler{vacancyService: vacancyService}
+}
+
+// CreateVacancy обрабатывает POST /departments/{id}/vacancies - открытие вакансии в отделе.
+// Превышение утверждённой численности в мягком режиме возвращается в поле warning, в жёстком - кодом 409.
+func (h *VacancyHandler) CreateVacancy(w http.ResponseWriter, r *http.Request) {
+	deptID, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req createVacancyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	vacancy, err := h.vacancyService.Create(r.Context(), uint(deptID), req.Position, req.PositionID, req.TargetStartDate)
+	if err != nil {
+		writeVacancyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(vacancy)
+}
+
+// ListVacancies обрабатывает GET /vacancies - список вакансий с фильтрацией
+// по отделу (department_id) и статусу (status: open, filled, cancelled).
+func (h *VacancyHandler) ListVacancies(w http.ResponseWriter, r *http.Request) {
+	var departmentID *uint
+	if idStr := r.URL.Query().Get("department_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			http.Error(w, "invalid department_id", http.StatusBadRequest)
+			return
+		}
+		departmentID = new(uint)
+		*departmentID = uint(id)
+	}
+
+	vacancies, err := h.vacancyService.List(r.Context(), departmentID, r.URL.Query().Get("status"))
+	if err != nil {
+		writeVacancyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vacancies)
+}
+
+// GetVacancy обрабатывает GET /vacancies/{id} - получение вакансии.
+func (h *VacancyHandler) GetVacancy(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseVacancyID(w, r)
+	if !ok {
+		return
+	}
+
+	vacancy, err := h.vacancyService.Get(r.Context(), id)
+	if err != nil {
+		writeVacancyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vacancy)
+}
+
+// CancelVacancy обрабатывает POST /vacancies/{id}/cancel - закрытие открытой вакансии без приёма.
+func (h *VacancyHandler) CancelVacancy(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseVacancyID(w, r)
+	if !ok {
+		return
+	}
+
+	vacancy, err := h.vacancyService.Cancel(r.Context(), id)
+	if err != nil {
+		writeVacancyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vacancy)
+}
+
+// parseVacancyID разбирает идентификатор вакансии из пути запроса.
+func parseVacancyID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid vacancy id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeVacancyError отвечает кодом, соответствующим ошибке вакансии.
+func writeVacancyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidVacancy),
+		errors.Is(err, apperrors.ErrInvalidPosition),
+		errors.Is(err, apperrors.ErrPositionNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, apperrors.ErrVacancyNotFound),
+		errors.Is(err, apperrors.ErrDepartmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperrors.ErrVacancyNotOpen),
+		errors.Is(err, apperrors.ErrHeadcountLimitExceeded):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeServerError(w, err)
+	}
+}
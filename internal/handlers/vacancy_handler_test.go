@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVacancyService — мок для VacancyService
+type MockVacancyService struct {
+	mock.Mock
+}
+
+func (m *MockVacancyService) Create(ctx context.Context, departmentID uint, position string, positionID *uint, targetStartDate *time.Time) (*models.Vacancy, error) {
+	args := m.Called(ctx, departmentID, position, positionID, targetStartDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Vacancy), args.Error(1)
+}
+
+func (m *MockVacancyService) Get(ctx context.Context, id uint) (*models.Vacancy, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Vacancy), args.Error(1)
+}
+
+func (m *MockVacancyService) List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error) {
+	args := m.Called(ctx, departmentID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Vacancy), args.Error(1)
+}
+
+func (m *MockVacancyService) Cancel(ctx context.Context, id uint) (*models.Vacancy, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Vacancy), args.Error(1)
+}
+
+func setupVacancyTest(t *testing.T) (*MockVacancyService, *http.ServeMux) {
+	mockSvc := new(MockVacancyService)
+	handler := NewVacancyHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /departments/{id}/vacancies", handler.CreateVacancy)
+	mux.HandleFunc("GET /vacancies", handler.ListVacancies)
+	mux.HandleFunc("GET /vacancies/{id}", handler.GetVacancy)
+	mux.HandleFunc("POST /vacancies/{id}/cancel", handler.CancelVacancy)
+
+	return mockSvc, mux
+}
+
+func TestCreateVacancy(t *testing.T) {
+	mockSvc, mux := setupVacancyTest(t)
+
+	mockSvc.On("Create", mock.Anything, uint(1), "Developer", (*uint)(nil), mock.MatchedBy(func(d *time.Time) bool {
+		return d != nil && d.Equal(time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC))
+	})).Return(&models.Vacancy{ID: 3, DepartmentID: 1, Position: "Developer", Status: models.VacancyOpen,
+		Warning: "department headcount limit exceeded"}, nil)
+
+	body := `{"position":"Developer","target_start_date":"2024-09-01T00:00:00Z"}`
+	req := httptest.NewRequest(http.MethodPost, "/departments/1/vacancies", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var got models.Vacancy
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Equal(t, 3, got.ID)
+	assert.NotEmpty(t, got.Warning)
+}
+
+func TestCreateVacancy_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{apperrors.ErrInvalidPosition, http.StatusBadRequest},
+		{apperrors.ErrDepartmentNotFound, http.StatusNotFound},
+		{apperrors.ErrHeadcountLimitExceeded, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		mockSvc, mux := setupVacancyTest(t)
+		mockSvc.On("Create", mock.Anything, uint(1), "Developer", (*uint)(nil), (*time.Time)(nil)).Return(nil, tt.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/departments/1/vacancies", bytes.NewBufferString(`{"position":"Developer"}`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.err.Error())
+	}
+}
+
+func TestListVacancies(t *testing.T) {
+	mockSvc, mux := setupVacancyTest(t)
+	deptID := uint(2)
+	mockSvc.On("List", mock.Anything, &deptID, models.VacancyOpen).Return([]models.Vacancy{{ID: 1, DepartmentID: 2}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/vacancies?department_id=2&status=open", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var got []models.Vacancy
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+	assert.Len(t, got, 1)
+}
+
+func TestCancelVacancy(t *testing.T) {
+	mockSvc, mux := setupVacancyTest(t)
+	mockSvc.On("Cancel", mock.Anything, uint(1)).Return(&models.Vacancy{ID: 1, Status: models.VacancyCancelled}, nil)
+	mockSvc.On("Cancel", mock.Anything, uint(2)).Return(nil, apperrors.ErrVacancyNotOpen)
+
+	req := httptest.NewRequest(http.MethodPost, "/vacancies/1/cancel", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/vacancies/2/cancel", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetVacancy_NotFound(t *testing.T) {
+	mockSvc, mux := setupVacancyTest(t)
+	mockSvc.On("Get", mock.Anything, uint(9)).Return(nil, apperrors.ErrVacancyNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/vacancies/9", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,116 @@
+// Package handlers содержит HTTP-обработчики для API приложения.
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/service"
+)
+
+// VersionHandler обрабатывает HTTP-запросы изменений структуры с датой вступления в силу.
+type VersionHandler struct {
+	versionService service.VersionService
+}
+
+// NewVersionHandler создаёт новый экземпляр обработчика изменений с датой вступления в силу.
+func NewVersionHandler(versionService service.VersionService) *VersionHandler {
+	return &VersionHandler{versionService: versionService}
+}
+
+// ScheduleDepartmentChange обрабатывает POST /departments/{id}/scheduled-changes -
+// переименование и/или перенос подразделения с даты effective_from.
+func (h *VersionHandler) ScheduleDepartmentChange(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid department id", http.StatusBadRequest)
+		return
+	}
+
+	var req scheduleChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	effectiveFrom, err := time.Parse(time.DateOnly, req.EffectiveFrom)
+	if err != nil {
+		http.Error(w, "effective_from must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+		return
+	}
+
+	dept, err := h.versionService.ScheduleDepartmentChange(r.Context(), uint(id), req.Name, req.ParentID, effectiveFrom)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrDepartmentNotFound),
+			errors.Is(err, apperrors.ErrParentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrDepartmentNameConflict),
+			errors.Is(err, apperrors.ErrSelfParent),
+			errors.Is(err, apperrors.ErrCycleDetected),
+			errors.Is(err, apperrors.ErrDepartmentTypeRule):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, apperrors.ErrInvalidEffectiveDate),
+			errors.Is(err, apperrors.ErrEmptyScheduledChange),
+			errors.Is(err, apperrors.ErrInvalidDepartmentName):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrApprovalRequired):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(dept)
+}
+
+// TransferEmployee обрабатывает POST /employees/{id}/transfer - перевод сотрудника
+// в другой отдел сегодня или с даты effective_from.
+func (h *VersionHandler) TransferEmployee(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		http.Error(w, "invalid employee id", http.StatusBadRequest)
+		return
+	}
+
+	var req transferEmployeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var effectiveFrom *time.Time
+	if req.EffectiveFrom != "" {
+		date, err := time.Parse(time.DateOnly, req.EffectiveFrom)
+		if err != nil {
+			http.Error(w, "effective_from must be a date in YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+		effectiveFrom = &date
+	}
+
+	emp, err := h.versionService.TransferEmployee(r.Context(), uint(id), req.DepartmentID, effectiveFrom)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrEmployeeNotFound),
+			errors.Is(err, apperrors.ErrEmployeeDepartmentNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, apperrors.ErrInvalidEffectiveDate):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, apperrors.ErrEmployeeTerminated),
+			errors.Is(err, apperrors.ErrHeadcountLimitExceeded):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			writeServerError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(emp)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVersionService — мок для VersionService
+type MockVersionService struct {
+	mock.Mock
+}
+
+func (m *MockVersionService) ScheduleDepartmentChange(ctx context.Context, id uint, name *string, parentID *uint, effectiveFrom time.Time) (*models.Department, error) {
+	args := m.Called(ctx, id, name, parentID, effectiveFrom)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockVersionService) TransferEmployee(ctx context.Context, id, departmentID uint, effectiveFrom *time.Time) (*models.Employee, error) {
+	args := m.Called(ctx, id, departmentID, effectiveFrom)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func setupVersionTest(t *testing.T) (*MockVersionService, *http.ServeMux) {
+	mockSvc := new(MockVersionService)
+	handler := NewVersionHandler(mockSvc)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /departments/{id}/scheduled-changes", handler.ScheduleDepartmentChange)
+	mux.HandleFunc("POST /employees/{id}/transfer", handler.TransferEmployee)
+
+	return mockSvc, mux
+}
+
+func TestScheduleDepartmentChange_Accepted(t *testing.T) {
+	mockSvc, mux := setupVersionTest(t)
+
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("ScheduleDepartmentChange", mock.Anything, uint(5), mock.MatchedBy(func(name *string) bool {
+		return name != nil && *name == "Core Platform"
+	}), (*uint)(nil), from).Return(&models.Department{ID: 5, Name: "Core Platform"}, nil)
+
+	body := []byte(`{"name": "Core Platform", "effective_from": "2026-04-01"}`)
+	req := httptest.NewRequest(http.MethodPost, "/departments/5/scheduled-changes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var resp models.Department
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, "Core Platform", resp.Name)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestScheduleDepartmentChange_InvalidDate(t *testing.T) {
+	mockSvc, mux := setupVersionTest(t)
+
+	body := []byte(`{"name": "Core", "effective_from": "01.04.2026"}`)
+	req := httptest.NewRequest(http.MethodPost, "/departments/5/scheduled-changes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "ScheduleDepartmentChange", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScheduleDepartmentChange_PastDate(t *testing.T) {
+	mockSvc, mux := setupVersionTest(t)
+
+	mockSvc.On("ScheduleDepartmentChange", mock.Anything, uint(5), mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, apperrors.ErrInvalidEffectiveDate)
+
+	body := []byte(`{"name": "Core", "effective_from": "2020-01-01"}`)
+	req := httptest.NewRequest(http.MethodPost, "/departments/5/scheduled-changes", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTransferEmployee_Immediate(t *testing.T) {
+	mockSvc, mux := setupVersionTest(t)
+
+	mockSvc.On("TransferEmployee", mock.Anything, uint(7), uint(3), (*time.Time)(nil)).
+		Return(&models.Employee{ID: 7, DepartmentID: 3}, nil)
+
+	body := []byte(`{"department_id": 3}`)
+	req := httptest.NewRequest(http.MethodPost, "/employees/7/transfer", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestTransferEmployee_DepartmentNotFound(t *testing.T) {
+	mockSvc, mux := setupVersionTest(t)
+
+	mockSvc.On("TransferEmployee", mock.Anything, uint(7), uint(99), mock.Anything).
+		Return(nil, apperrors.ErrEmployeeDepartmentNotFound)
+
+	body := []byte(`{"department_id": 99, "effective_from": "2026-05-01"}`)
+	req := httptest.NewRequest(http.MethodPost, "/employees/7/transfer", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
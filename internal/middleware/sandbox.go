@@ -0,0 +1,130 @@
+// Package middleware предоставляет промежуточные обработчики (middleware) для HTTP-сервера.
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SandboxHeader - заголовок запроса с идентификатором песочницы.
+const SandboxHeader = "X-Sandbox-ID"
+
+// errSandboxRequestFailed откатывает транзакцию песочницы, если обработчик ответил ошибкой.
+var errSandboxRequestFailed = errors.New("sandbox request failed")
+
+// SandboxScope выполняет функцию над данными песочницы.
+type SandboxScope interface {
+	Within(ctx context.Context, id uint, fn func(ctx context.Context) error) error
+}
+
+// bufferedResponse накапливает заголовки, статус и тело ответа обработчика,
+// чтобы отправить их клиенту только после фиксации транзакции песочницы.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: http.Header{}}
+}
+
+func (w *bufferedResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedResponse) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// flush отправляет накопленный ответ клиенту.
+func (w *bufferedResponse) flush(dst http.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.body.Bytes())
+}
+
+// Sandbox возвращает middleware, которое выполняет запрос с заголовком X-Sandbox-ID
+// над данными указанной песочницы. Запросы без заголовка работают с рабочей структурой.
+// Ответ обработчика отправляется только после фиксации транзакции песочницы;
+// если обработчик ответил статусом 400 и выше, изменения запроса в песочнице откатываются.
+func Sandbox(scope SandboxScope, log *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(SandboxHeader)
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := strconv.ParseUint(header, 10, 32)
+			if err != nil || id == 0 {
+				http.Error(w, apperrors.ErrInvalidSandboxID.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var rec *bufferedResponse
+			err = scope.Within(r.Context(), uint(id), func(ctx context.Context) error {
+				rec = newBufferedResponse()
+				next.ServeHTTP(rec, r.WithContext(ctx))
+				if rec.status >= http.StatusBadRequest {
+					return errSandboxRequestFailed
+				}
+				return nil
+			})
+			if err == nil || errors.Is(err, errSandboxRequestFailed) {
+				rec.flush(w)
+				return
+			}
+			// Обработчик выполнился, но транзакция не зафиксирована: его ответ клиенту не отправляется.
+			if rec != nil {
+				log.WithError(err).WithFields(logrus.Fields{
+					"method":  r.Method,
+					"path":    r.URL.Path,
+					"sandbox": id,
+				}).Error("sandbox transaction commit failed")
+				if isCommitConflict(err) {
+					http.Error(w, err.Error(), http.StatusConflict)
+					return
+				}
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			switch {
+			case errors.Is(err, apperrors.ErrSandboxNotFound):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, apperrors.ErrSandboxClosed):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		})
+	}
+}
+
+// isCommitConflict сообщает, что транзакция не зафиксирована из-за параллельных изменений.
+func isCommitConflict(err error) bool {
+	return errors.Is(err, apperrors.ErrSerializationFailure) ||
+		errors.Is(err, apperrors.ErrDeadlock) ||
+		errors.Is(err, apperrors.ErrUniqueViolation) ||
+		errors.Is(err, apperrors.ErrForeignKeyViolation)
+}
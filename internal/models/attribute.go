@@ -0,0 +1,74 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Сущности, для которых определяются пользовательские атрибуты.
+const (
+	EntityDepartment = "department"
+	EntityEmployee   = "employee"
+)
+
+// Типы значений пользовательских атрибутов.
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeDate    = "date"
+	AttributeTypeEnum    = "enum"
+)
+
+// AttributeDefinition описывает пользовательский атрибут подразделений или сотрудников.
+type AttributeDefinition struct {
+	ID         int        `gorm:"primaryKey" json:"id"`
+	Entity     string     `gorm:"size:20;not null;uniqueIndex:idx_attribute_entity_name,priority:1" json:"entity"`
+	Name       string     `gorm:"size:50;not null;uniqueIndex:idx_attribute_entity_name,priority:2" json:"name"`
+	Type       string     `gorm:"size:20;not null" json:"type"`
+	Required   bool       `gorm:"not null;default:false" json:"required"`
+	EnumValues StringList `gorm:"type:jsonb;not null;default:'[]'" json:"enum_values,omitempty"`
+	Pattern    string     `gorm:"size:500;not null;default:''" json:"pattern,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Attributes хранит значения пользовательских атрибутов (имя → значение) в колонке JSONB.
+type Attributes map[string]any
+
+// Value сериализует атрибуты в JSON для записи в базу данных.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	return jsonValue(a)
+}
+
+// Scan читает атрибуты из JSON-значения базы данных.
+func (a *Attributes) Scan(src any) error {
+	if src == nil {
+		*a = Attributes{}
+		return nil
+	}
+	return scanJSON(src, a)
+}
+
+// StringList хранит список строк в колонке JSONB.
+type StringList []string
+
+// Value сериализует список в JSON для записи в базу данных.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+// Scan читает список из JSON-значения базы данных.
+func (l *StringList) Scan(src any) error {
+	if src == nil {
+		*l = StringList{}
+		return nil
+	}
+	return scanJSON(src, l)
+}
@@ -0,0 +1,68 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Статусы заявки на структурное изменение.
+const (
+	ChangeRequestPending  = "pending"
+	ChangeRequestApproved = "approved"
+	ChangeRequestRejected = "rejected"
+)
+
+// Операции, требующие согласования.
+const (
+	ChangeOpMove   = "move"
+	ChangeOpDelete = "delete"
+)
+
+// Правила выбора согласующего заявки.
+const (
+	// ApproverCommonAncestorHead - руководитель ближайшего общего предка старого и нового
+	// положения подразделения (для удаления - его родителя).
+	ApproverCommonAncestorHead = "common_ancestor_head"
+	// ApproverDepartmentHead - руководитель самого подразделения.
+	ApproverDepartmentHead = "department_head"
+)
+
+// ChangeRequest представляет заявку на перенос или удаление подразделения,
+// которая выполняется только после согласования.
+type ChangeRequest struct {
+	ID           int                  `gorm:"primaryKey" json:"id"`
+	Operation    string               `gorm:"size:20;not null" json:"operation"`
+	DepartmentID int                  `gorm:"not null" json:"department_id"`
+	Payload      ChangeRequestPayload `gorm:"type:jsonb;not null" json:"payload"`
+	RequestedBy  int                  `gorm:"not null" json:"requested_by"`
+	ApproverRule string               `gorm:"size:50;not null" json:"approver_rule"`
+	ApproverID   *int                 `json:"approver_id,omitempty"`
+	Status       string               `gorm:"size:20;not null;default:pending" json:"status"`
+	DecidedBy    *int                 `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time           `json:"decided_at,omitempty"`
+	Comment      string               `gorm:"not null;default:''" json:"comment,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
+
+// ChangeRequestPayload содержит параметры операции заявки: нового родителя для переноса
+// (0 - в корень) или режим удаления.
+type ChangeRequestPayload struct {
+	ParentID   *uint  `json:"parent_id,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+	ReassignTo *uint  `json:"reassign_to_department_id,omitempty"`
+}
+
+// Value сериализует параметры в JSON для записи в базу данных.
+func (p ChangeRequestPayload) Value() (driver.Value, error) {
+	return jsonValue(p)
+}
+
+// Scan читает параметры из JSON-значения базы данных.
+func (p *ChangeRequestPayload) Scan(src any) error {
+	if src == nil {
+		*p = ChangeRequestPayload{}
+		return nil
+	}
+	return scanJSON(src, p)
+}
@@ -5,10 +5,22 @@ import "time"
 
 // Department представляет модель подразделения (отдела) в организационной структуре.
 type Department struct {
-	ID        int          `gorm:"primaryKey" json:"id"`
-	Name      string       `gorm:"size:200;not null;uniqueIndex:idx_parent_name,priority:2" json:"name"`
-	ParentID  *uint        `gorm:"index;uniqueIndex:idx_parent_name,priority:1" json:"parent_id"`
-	CreatedAt time.Time    `json:"created_at"`
-	Children  []Department `gorm:"foreignkey:ParentID" json:"children,omitempty"`
-	Employees []Employee   `json:"employees,omitempty"`
+	ID             int          `gorm:"primaryKey" json:"id"`
+	Name           string       `gorm:"size:200;not null" json:"name"`
+	ParentID       *uint        `gorm:"index" json:"parent_id"`
+	Type           *string      `gorm:"size:50" json:"type,omitempty"`
+	Path           string       `gorm:"->" json:"path,omitempty"`
+	SearchKey      string       `gorm:"->" json:"-"`
+	NameKey        string       `gorm:"->" json:"-"`
+	SortOrder      int          `gorm:"not null;default:0" json:"sort_order"`
+	Code           *string      `gorm:"size:50;uniqueIndex" json:"code,omitempty"`
+	ExternalIDs    ExternalIDs  `gorm:"type:jsonb;not null;default:'{}'" json:"external_ids,omitempty"`
+	Attributes     Attributes   `gorm:"type:jsonb;not null;default:'{}'" json:"attributes,omitempty"`
+	HeadID         *uint        `json:"head_id,omitempty"`
+	HeadcountLimit *int         `json:"headcount_limit,omitempty"`
+	LocationID     *uint        `json:"location_id,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	Children       []Department `gorm:"foreignkey:ParentID" json:"children,omitempty"`
+	Employees      []Employee   `json:"employees,omitempty"`
+	Vacancies      []Vacancy    `json:"vacancies,omitempty"`
 }
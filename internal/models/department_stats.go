@@ -0,0 +1,25 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+// DepartmentStats содержит показатели численности и структуры поддерева подразделения.
+// Охват управления подразделения - число его сотрудников и дочерних подразделений;
+// AvgSpanOfControl усредняет его по всем подразделениям поддерева, включая корень.
+// Сотрудники с дополнительным назначением считаются отдельно от основных (Secondary*);
+// в SecondaryTotalHeadcount не входят сотрудники, основной отдел которых в том же поддереве.
+type DepartmentStats struct {
+	DepartmentID             int            `json:"department_id"`
+	DirectHeadcount          int            `json:"direct_headcount"`
+	TotalHeadcount           int            `json:"total_headcount"`
+	SecondaryDirectHeadcount int            `json:"secondary_direct_headcount"`
+	SecondaryTotalHeadcount  int            `json:"secondary_total_headcount"`
+	SubDepartments           int            `json:"sub_departments"`
+	MaxDepth                 int            `json:"max_depth"`
+	AvgSpanOfControl         float64        `json:"avg_span_of_control"`
+	HiresByMonth             []MonthlyHires `gorm:"-" json:"hires_by_month"`
+}
+
+// MonthlyHires содержит число сотрудников поддерева, принятых в течение месяца.
+type MonthlyHires struct {
+	Month string `json:"month"`
+	Hires int    `json:"hires"`
+}
@@ -0,0 +1,15 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// DepartmentType описывает тип подразделения и правила его размещения в дереве:
+// AllowRoot разрешает подразделению этого типа быть корнем, ParentTypes перечисляет
+// типы родителей, под которыми оно может находиться. Подразделения без типа
+// правилами не ограничиваются.
+type DepartmentType struct {
+	Name        string     `gorm:"primaryKey;size:50" json:"name"`
+	AllowRoot   bool       `gorm:"not null;default:false" json:"allow_root"`
+	ParentTypes StringList `gorm:"type:jsonb;not null;default:'[]'" json:"parent_types"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -3,12 +3,42 @@ package models
 
 import "time"
 
+// Статусы занятости сотрудника.
+const (
+	EmployeeActive     = "active"
+	EmployeeOnLeave    = "on_leave"
+	EmployeeTerminated = "terminated"
+)
+
 // Employee представляет модель сотрудника в организационной структуре.
 type Employee struct {
-	ID           int        `gorm:"primaryKey" json:"id"`
-	DepartmentID int        `gorm:"not null;index" json:"department_id"`
-	FullName     string     `gorm:"size:200;not null" json:"full_name"`
-	Position     string     `gorm:"size:200;not null" json:"position"`
-	HiredAt      *time.Time `json:"hired_at"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID                int         `gorm:"primaryKey" json:"id"`
+	DepartmentID      int         `gorm:"not null;index" json:"department_id"`
+	FullName          string      `gorm:"size:200;not null" json:"full_name"`
+	SearchKey         string      `gorm:"->" json:"-"`
+	Position          string      `gorm:"size:200;not null" json:"position"`
+	PositionID        *int        `gorm:"index" json:"position_id"`
+	HiredAt           *time.Time  `json:"hired_at"`
+	Status            string      `gorm:"size:20;not null;default:active" json:"status"`
+	TerminatedAt      *time.Time  `json:"terminated_at,omitempty"`
+	TerminationReason string      `gorm:"size:500;not null;default:''" json:"termination_reason,omitempty"`
+	Code              *string     `gorm:"size:50;uniqueIndex" json:"code,omitempty"`
+	ExternalIDs       ExternalIDs `gorm:"type:jsonb;not null;default:'{}'" json:"external_ids,omitempty"`
+	Attributes        Attributes  `gorm:"type:jsonb;not null;default:'{}'" json:"attributes,omitempty"`
+	LocationID        *uint       `json:"location_id,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
+	// Warning - предупреждение о превышении утверждённой численности в мягком режиме; не хранится.
+	Warning string `gorm:"-" json:"warning,omitempty"`
+	// Secondary отмечает сотрудника в составе подразделения его дополнительного назначения Assignment.
+	Secondary  bool                `gorm:"-" json:"secondary,omitempty"`
+	Assignment *EmployeeAssignment `gorm:"-" json:"assignment,omitempty"`
+}
+
+// Employed сообщает, числился ли сотрудник в штате на дату asOf,
+// при asOf = nil - числится ли он сейчас.
+func (e Employee) Employed(asOf *time.Time) bool {
+	if e.Status != EmployeeTerminated {
+		return true
+	}
+	return asOf != nil && e.TerminatedAt != nil && asOf.Before(*e.TerminatedAt)
 }
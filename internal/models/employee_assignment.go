@@ -0,0 +1,15 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// EmployeeAssignment представляет дополнительное назначение сотрудника в подразделение
+// (проект, гильдию) помимо основного: роль и долю занятости в процентах.
+type EmployeeAssignment struct {
+	ID           int       `gorm:"primaryKey" json:"id"`
+	EmployeeID   int       `gorm:"not null;uniqueIndex:idx_employee_assignments_employee_department,priority:1" json:"employee_id"`
+	DepartmentID int       `gorm:"not null;index;uniqueIndex:idx_employee_assignments_employee_department,priority:2" json:"department_id"`
+	Role         string    `gorm:"size:100;not null;default:''" json:"role"`
+	Allocation   int       `gorm:"not null" json:"allocation"`
+	CreatedAt    time.Time `json:"created_at"`
+}
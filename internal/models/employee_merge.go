@@ -0,0 +1,17 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EmployeeMerge представляет запись журнала слияния дубликата сотрудника с основной записью.
+// Source содержит состояние дубликата на момент слияния.
+type EmployeeMerge struct {
+	ID        int             `gorm:"primaryKey" json:"id"`
+	SourceID  int             `gorm:"not null;uniqueIndex" json:"source_id"`
+	TargetID  int             `gorm:"not null;index" json:"target_id"`
+	Source    json.RawMessage `gorm:"type:jsonb;not null" json:"source"`
+	CreatedAt time.Time       `json:"created_at"`
+}
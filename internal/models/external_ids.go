@@ -0,0 +1,25 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "database/sql/driver"
+
+// ExternalIDs хранит идентификаторы записи во внешних системах (система → идентификатор)
+// и сохраняется в колонку JSONB.
+type ExternalIDs map[string]string
+
+// Value сериализует идентификаторы в JSON для записи в базу данных.
+func (e ExternalIDs) Value() (driver.Value, error) {
+	if e == nil {
+		return "{}", nil
+	}
+	return jsonValue(e)
+}
+
+// Scan читает идентификаторы из JSON-значения базы данных.
+func (e *ExternalIDs) Scan(src any) error {
+	if src == nil {
+		*e = ExternalIDs{}
+		return nil
+	}
+	return scanJSON(src, e)
+}
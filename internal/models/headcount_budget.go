@@ -0,0 +1,20 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+// HeadcountBudget сравнивает утверждённую численность подразделения с фактической
+// и открытыми вакансиями. Поля Total* суммируют значения по всему поддереву;
+// TotalBudgeted учитывает только подразделения с утверждённой численностью,
+// их отсутствие отражает UnbudgetedDepartments.
+type HeadcountBudget struct {
+	DepartmentID          int               `json:"department_id"`
+	Name                  string            `json:"name"`
+	ParentID              *uint             `json:"-"`
+	Budgeted              *int              `json:"budgeted"`
+	Actual                int               `json:"actual"`
+	Open                  int               `json:"open"`
+	TotalBudgeted         int               `json:"total_budgeted"`
+	TotalActual           int               `json:"total_actual"`
+	TotalOpen             int               `json:"total_open"`
+	UnbudgetedDepartments int               `json:"unbudgeted_departments"`
+	Children              []HeadcountBudget `gorm:"-" json:"children,omitempty"`
+}
@@ -0,0 +1,22 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DepartmentHistory представляет запись журнала структурных изменений подразделения.
+// Запись не ссылается на подразделение внешним ключом, чтобы переживать его удаление.
+type DepartmentHistory struct {
+	ID           int             `gorm:"primaryKey" json:"id"`
+	DepartmentID int             `gorm:"not null;index" json:"department_id"`
+	Action       string          `gorm:"size:50;not null" json:"action"`
+	Details      json.RawMessage `gorm:"type:jsonb;not null" json:"details"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// TableName возвращает имя таблицы журнала изменений.
+func (DepartmentHistory) TableName() string {
+	return "department_history"
+}
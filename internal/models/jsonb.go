@@ -0,0 +1,28 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonValue сериализует значение в JSON-строку для записи в колонку JSONB.
+func jsonValue(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// scanJSON читает JSON-значение колонки JSONB в dst.
+func scanJSON(src, dst any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dst)
+	}
+}
@@ -0,0 +1,43 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// Источники места работы сотрудника.
+const (
+	LocationSourceEmployee   = "employee"
+	LocationSourceDepartment = "department"
+)
+
+// Location представляет офис или площадку: адрес, страну (ISO 3166-1 alpha-2)
+// и часовой пояс IANA, в котором планируются встречи и события сотрудников.
+type Location struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:200;not null" json:"name"`
+	Address   string    `gorm:"size:500;not null;default:''" json:"address"`
+	Country   string    `gorm:"size:2;not null" json:"country"`
+	Timezone  string    `gorm:"size:64;not null" json:"timezone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmployeeLocation - действующее место работы сотрудника. Source показывает, задано ли место
+// самому сотруднику или унаследовано от подразделения DepartmentID (его собственного или
+// ближайшего предка). Location равен nil, если место не задано нигде.
+type EmployeeLocation struct {
+	EmployeeID   int       `json:"employee_id"`
+	Source       string    `json:"source,omitempty"`
+	DepartmentID *int      `json:"department_id,omitempty"`
+	Location     *Location `json:"location"`
+	// LocalTime - текущее время в часовом поясе места; не заполняется без места.
+	LocalTime *time.Time `json:"local_time,omitempty"`
+}
+
+// LocationHeadcount содержит численность работающих сотрудников места.
+// LocationID равен nil для сотрудников, место которых не задано.
+type LocationHeadcount struct {
+	LocationID *int   `json:"location_id"`
+	Name       string `json:"name"`
+	Country    string `json:"country,omitempty"`
+	Timezone   string `json:"timezone,omitempty"`
+	Headcount  int    `json:"headcount"`
+}
@@ -0,0 +1,32 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// Position представляет должность справочника: название, профессиональное семейство
+// (job family) и грейд. Названия уникальны без учёта регистра и лишних пробелов.
+type Position struct {
+	ID        int       `gorm:"primaryKey" json:"id"`
+	Title     string    `gorm:"size:200;not null" json:"title"`
+	JobFamily string    `gorm:"size:100;not null;default:''" json:"job_family"`
+	Grade     *int      `json:"grade"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// JobFamilyHeadcount содержит численность профессионального семейства и его должностей.
+// Должности без семейства и названия, не найденные в справочнике, относятся к семейству "".
+type JobFamilyHeadcount struct {
+	JobFamily string              `json:"job_family"`
+	Headcount int                 `json:"headcount"`
+	Positions []PositionHeadcount `json:"positions"`
+}
+
+// PositionHeadcount содержит численность сотрудников одной должности.
+// PositionID равен nil для названий, не связанных со справочником.
+type PositionHeadcount struct {
+	PositionID *int   `json:"position_id"`
+	Title      string `json:"title"`
+	Grade      *int   `json:"grade,omitempty"`
+	JobFamily  string `json:"-"`
+	Headcount  int    `json:"headcount"`
+}
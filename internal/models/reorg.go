@@ -0,0 +1,70 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Статусы пакета запланированных изменений структуры.
+const (
+	ReorgStatusPending   = "pending"
+	ReorgStatusApplied   = "applied"
+	ReorgStatusFailed    = "failed"
+	ReorgStatusCancelled = "cancelled"
+)
+
+// Типы операций пакета запланированных изменений.
+const (
+	ReorgOpMove     = "move"
+	ReorgOpRename   = "rename"
+	ReorgOpTransfer = "transfer"
+	ReorgOpDelete   = "delete"
+)
+
+// ReorgBatch представляет пакет изменений структуры, который выполняется целиком
+// в одной транзакции в момент ExecuteAt.
+type ReorgBatch struct {
+	ID         int             `gorm:"primaryKey" json:"id"`
+	Status     string          `gorm:"size:20;not null;default:pending" json:"status"`
+	ExecuteAt  time.Time       `gorm:"not null" json:"execute_at"`
+	Operations ReorgOperations `gorm:"type:jsonb;not null" json:"operations"`
+	Error      string          `gorm:"not null;default:''" json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ExecutedAt *time.Time      `json:"executed_at,omitempty"`
+}
+
+// ReorgOperation описывает одну операцию пакета:
+//   - move - перенос подразделения DepartmentID под ParentID (0 или null - в корень);
+//   - rename - переименование подразделения DepartmentID в Name;
+//   - transfer - перевод сотрудника EmployeeID в подразделение DepartmentID;
+//   - delete - удаление подразделения DepartmentID в режиме Mode (cascade или reassign).
+type ReorgOperation struct {
+	Type         string `json:"type"`
+	DepartmentID uint   `json:"department_id,omitempty"`
+	ParentID     *uint  `json:"parent_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	EmployeeID   uint   `json:"employee_id,omitempty"`
+	Mode         string `json:"mode,omitempty"`
+	ReassignTo   *uint  `json:"reassign_to_department_id,omitempty"`
+}
+
+// ReorgOperations хранит операции пакета в колонке JSONB.
+type ReorgOperations []ReorgOperation
+
+// Value сериализует операции в JSON для записи в базу данных.
+func (o ReorgOperations) Value() (driver.Value, error) {
+	if o == nil {
+		return "[]", nil
+	}
+	return jsonValue(o)
+}
+
+// Scan читает операции из JSON-значения базы данных.
+func (o *ReorgOperations) Scan(src any) error {
+	if src == nil {
+		*o = nil
+		return nil
+	}
+	return scanJSON(src, o)
+}
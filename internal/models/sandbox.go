@@ -0,0 +1,23 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// Статусы песочницы.
+const (
+	SandboxActive    = "active"
+	SandboxPromoted  = "promoted"
+	SandboxDiscarded = "discarded"
+)
+
+// Sandbox представляет песочницу для пробной реорганизации: копию рабочей структуры
+// в отдельной схеме базы данных, изменения в которой не затрагивают рабочие данные
+// до переноса.
+type Sandbox struct {
+	ID         int        `gorm:"primaryKey" json:"id"`
+	Name       string     `gorm:"size:200;not null" json:"name"`
+	SchemaName string     `gorm:"size:63;not null" json:"-"`
+	Status     string     `gorm:"size:20;not null" json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+}
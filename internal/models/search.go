@@ -0,0 +1,32 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+// Типы результатов поиска.
+const (
+	SearchEmployee   = "employee"
+	SearchDepartment = "department"
+)
+
+// SearchHit представляет найденного сотрудника или подразделение.
+// Для подразделения DepartmentID совпадает с ID, а Path оканчивается его названием.
+type SearchHit struct {
+	Type         string         `json:"type"`
+	ID           int            `json:"id"`
+	Title        string         `json:"title"`
+	Subtitle     string         `json:"subtitle,omitempty"`
+	DepartmentID int            `json:"department_id"`
+	Path         DepartmentPath `json:"department_path,omitempty"`
+	Rank         float64        `json:"rank"`
+}
+
+// DepartmentPath содержит названия подразделений от корня к подразделению результата.
+type DepartmentPath []string
+
+// Scan читает путь из JSON-массива, собранного запросом.
+func (p *DepartmentPath) Scan(src any) error {
+	if src == nil {
+		*p = DepartmentPath{}
+		return nil
+	}
+	return scanJSON(src, p)
+}
@@ -0,0 +1,37 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// SnapshotFormatVersion - версия формата документа снимка структуры.
+const SnapshotFormatVersion = 1
+
+// Snapshot представляет неизменяемый снимок структуры подразделений с сотрудниками.
+// Document не загружается в списках снимков.
+type Snapshot struct {
+	ID              int               `gorm:"primaryKey" json:"id"`
+	Label           string            `gorm:"size:200;not null;default:''" json:"label,omitempty"`
+	Document        *SnapshotDocument `gorm:"type:jsonb;not null" json:"document,omitempty"`
+	DepartmentCount int               `gorm:"not null" json:"department_count"`
+	EmployeeCount   int               `gorm:"not null" json:"employee_count"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// SnapshotDocument содержит дерево подразделений с сотрудниками на момент снимка.
+type SnapshotDocument struct {
+	FormatVersion int          `json:"format_version"`
+	Departments   []Department `json:"departments"`
+}
+
+// Value сериализует документ в JSON для записи в базу данных.
+func (d SnapshotDocument) Value() (driver.Value, error) {
+	return jsonValue(d)
+}
+
+// Scan читает документ из JSON-значения базы данных.
+func (d *SnapshotDocument) Scan(src any) error {
+	return scanJSON(src, d)
+}
@@ -0,0 +1,27 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// Статусы вакансии.
+const (
+	VacancyOpen      = "open"
+	VacancyFilled    = "filled"
+	VacancyCancelled = "cancelled"
+)
+
+// Vacancy представляет незанятую позицию в подразделении. Закрытая вакансия хранит
+// время закрытия, заполненная - ещё и принятого на неё сотрудника.
+type Vacancy struct {
+	ID              int        `gorm:"primaryKey" json:"id"`
+	DepartmentID    int        `gorm:"not null;index" json:"department_id"`
+	Position        string     `gorm:"size:200;not null" json:"position"`
+	PositionID      *int       `json:"position_id"`
+	TargetStartDate *time.Time `gorm:"type:date" json:"target_start_date"`
+	Status          string     `gorm:"size:20;not null;default:open" json:"status"`
+	EmployeeID      *int       `json:"employee_id,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	// Warning - предупреждение о превышении утверждённой численности в мягком режиме; не хранится.
+	Warning string `gorm:"-" json:"warning,omitempty"`
+}
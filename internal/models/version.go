@@ -0,0 +1,26 @@
+// Package models содержит GORM-модели данных, соответствующие таблицам в базе данных.
+package models
+
+import "time"
+
+// DepartmentVersion описывает название и родителя подразделения в период [ValidFrom, ValidTo).
+// ValidTo, равный nil, означает, что версия действует бессрочно.
+type DepartmentVersion struct {
+	ID           int        `gorm:"primaryKey" json:"id"`
+	DepartmentID int        `gorm:"not null" json:"department_id"`
+	Name         string     `gorm:"size:200;not null" json:"name"`
+	ParentID     *uint      `json:"parent_id"`
+	ValidFrom    time.Time  `gorm:"type:date;not null" json:"valid_from"`
+	ValidTo      *time.Time `gorm:"type:date" json:"valid_to"`
+}
+
+// EmployeeVersion описывает назначение сотрудника в отдел в период [ValidFrom, ValidTo).
+type EmployeeVersion struct {
+	ID           int        `gorm:"primaryKey" json:"id"`
+	EmployeeID   int        `gorm:"not null" json:"employee_id"`
+	DepartmentID int        `gorm:"not null" json:"department_id"`
+	FullName     string     `gorm:"size:200;not null" json:"full_name"`
+	Position     string     `gorm:"size:200;not null" json:"position"`
+	ValidFrom    time.Time  `gorm:"type:date;not null" json:"valid_from"`
+	ValidTo      *time.Time `gorm:"type:date" json:"valid_to"`
+}
@@ -0,0 +1,83 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// AssignmentRepo реализует репозиторий дополнительных назначений сотрудников.
+// Сумма долей назначений сотрудника проверяется триггером employee_assignments_allocation.
+type AssignmentRepo struct {
+	db *gorm.DB
+}
+
+// NewAssignmentRepo создаёт новый экземпляр репозитория дополнительных назначений.
+func NewAssignmentRepo(db *gorm.DB) *AssignmentRepo {
+	return &AssignmentRepo{db: db}
+}
+
+// Get возвращает назначение сотрудника employeeID в подразделение departmentID.
+func (r *AssignmentRepo) Get(ctx context.Context, employeeID, departmentID uint) (*models.EmployeeAssignment, error) {
+	var assignment models.EmployeeAssignment
+	err := conn(ctx, r.db).Where("employee_id = ? AND department_id = ?", employeeID, departmentID).
+		First(&assignment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &assignment, err
+}
+
+// Save создаёт новое назначение или сохраняет роль и долю существующего.
+func (r *AssignmentRepo) Save(ctx context.Context, assignment *models.EmployeeAssignment) error {
+	return conn(ctx, r.db).Save(assignment).Error
+}
+
+// Delete удаляет назначение по его идентификатору.
+func (r *AssignmentRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.EmployeeAssignment{}, id).Error
+}
+
+// ListByEmployee возвращает назначения сотрудника в порядке создания.
+func (r *AssignmentRepo) ListByEmployee(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error) {
+	var assignments []models.EmployeeAssignment
+	err := conn(ctx, r.db).Where("employee_id = ?", employeeID).Order("created_at, id").Find(&assignments).Error
+	return assignments, err
+}
+
+// ListMembers возвращает работающих сотрудников с дополнительным назначением в подразделение
+// departmentID; у каждого заполнены Secondary и Assignment.
+func (r *AssignmentRepo) ListMembers(ctx context.Context, departmentID uint) ([]models.Employee, error) {
+	var assignments []models.EmployeeAssignment
+	err := conn(ctx, r.db).Where("department_id = ?", departmentID).Order("created_at, id").Find(&assignments).Error
+	if err != nil || len(assignments) == 0 {
+		return []models.Employee{}, err
+	}
+	ids := make([]int, len(assignments))
+	for i, a := range assignments {
+		ids[i] = a.EmployeeID
+	}
+	var employees []models.Employee
+	err = conn(ctx, r.db).Where("id IN ? AND status <> ?", ids, models.EmployeeTerminated).Find(&employees).Error
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.Employee, len(employees))
+	for _, emp := range employees {
+		byID[emp.ID] = emp
+	}
+	members := make([]models.Employee, 0, len(employees))
+	for i := range assignments {
+		emp, ok := byID[assignments[i].EmployeeID]
+		if !ok {
+			continue
+		}
+		emp.Secondary = true
+		emp.Assignment = &assignments[i]
+		members = append(members, emp)
+	}
+	return members, nil
+}
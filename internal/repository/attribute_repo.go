@@ -0,0 +1,70 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// AttributeRepo реализует репозиторий определений пользовательских атрибутов.
+type AttributeRepo struct {
+	db *gorm.DB
+}
+
+// NewAttributeRepo создаёт новый экземпляр репозитория определений атрибутов.
+func NewAttributeRepo(db *gorm.DB) *AttributeRepo {
+	return &AttributeRepo{db: db}
+}
+
+// Create сохраняет новое определение атрибута.
+func (a *AttributeRepo) Create(ctx context.Context, def *models.AttributeDefinition) error {
+	return conn(ctx, a.db).Create(def).Error
+}
+
+// GetByID возвращает определение атрибута по его идентификатору.
+func (a *AttributeRepo) GetByID(ctx context.Context, id uint) (*models.AttributeDefinition, error) {
+	var def models.AttributeDefinition
+	err := conn(ctx, a.db).First(&def, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &def, err
+}
+
+// GetByName возвращает определение атрибута сущности по имени.
+func (a *AttributeRepo) GetByName(ctx context.Context, entity, name string) (*models.AttributeDefinition, error) {
+	var def models.AttributeDefinition
+	err := conn(ctx, a.db).Where("entity = ? AND name = ?", entity, name).First(&def).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &def, err
+}
+
+// ListByEntity возвращает определения атрибутов сущности; пустая entity возвращает все определения.
+func (a *AttributeRepo) ListByEntity(ctx context.Context, entity string) ([]models.AttributeDefinition, error) {
+	var defs []models.AttributeDefinition
+	query := conn(ctx, a.db).Order("entity, name")
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	err := query.Find(&defs).Error
+	return defs, err
+}
+
+// Delete удаляет определение атрибута и его значения у всех записей сущности.
+func (a *AttributeRepo) Delete(ctx context.Context, def *models.AttributeDefinition) error {
+	table := "departments"
+	if def.Entity == models.EntityEmployee {
+		table = "employees"
+	}
+	db := conn(ctx, a.db)
+	err := db.Exec("UPDATE "+table+" SET attributes = attributes - ? WHERE attributes -> ? IS NOT NULL", def.Name, def.Name).Error
+	if err != nil {
+		return err
+	}
+	return db.Delete(&models.AttributeDefinition{}, def.ID).Error
+}
@@ -0,0 +1,65 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChangeRequestRepo реализует репозиторий заявок на структурные изменения.
+type ChangeRequestRepo struct {
+	db *gorm.DB
+}
+
+// NewChangeRequestRepo создаёт новый экземпляр репозитория заявок.
+func NewChangeRequestRepo(db *gorm.DB) *ChangeRequestRepo {
+	return &ChangeRequestRepo{db: db}
+}
+
+// Create сохраняет новую заявку.
+func (r *ChangeRequestRepo) Create(ctx context.Context, req *models.ChangeRequest) error {
+	return conn(ctx, r.db).Create(req).Error
+}
+
+// GetByID возвращает заявку по её идентификатору.
+func (r *ChangeRequestRepo) GetByID(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	var req models.ChangeRequest
+	err := conn(ctx, r.db).First(&req, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &req, err
+}
+
+// Lock возвращает заявку, блокируя её строку до конца транзакции,
+// чтобы по заявке не было принято два решения одновременно.
+func (r *ChangeRequestRepo) Lock(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	var req models.ChangeRequest
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&req, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &req, err
+}
+
+// List возвращает заявки от новых к старым; пустой status возвращает все заявки.
+func (r *ChangeRequestRepo) List(ctx context.Context, status string) ([]models.ChangeRequest, error) {
+	var requests []models.ChangeRequest
+	query := conn(ctx, r.db).Order("id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+// Update сохраняет решение по заявке.
+func (r *ChangeRequestRepo) Update(ctx context.Context, req *models.ChangeRequest) error {
+	return conn(ctx, r.db).Model(req).
+		Select("approver_id", "status", "decided_by", "decided_at", "comment").
+		Updates(req).Error
+}
@@ -3,10 +3,16 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DepartmentRepo реализует репозиторий для работы с подразделениями в базе данных.
@@ -19,42 +25,154 @@ func NewDepartmentRepository(db *gorm.DB) *DepartmentRepo {
 	return &DepartmentRepo{db: db}
 }
 
-// Create сохраняет новое подразделение в базе данных.
+// Create сохраняет новое подразделение в базе данных. Путь заполняется триггером и возвращается в dept.
+// Если порядок не задан, подразделение ставится последним среди соседей. Соседнее подразделение
+// с тем же ключом названия, созданное параллельно, даёт ErrDepartmentNameConflict.
 func (d *DepartmentRepo) Create(ctx context.Context, dept *models.Department) error {
-	return d.db.WithContext(ctx).Create(dept).Error
+	db := conn(ctx, d.db)
+	if dept.SortOrder == 0 {
+		err := whereParent(db.Model(&models.Department{}), dept.ParentID).
+			Select("COALESCE(MAX(sort_order), 0) + 1").Scan(&dept.SortOrder).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nameConflict(db.Clauses(clause.Returning{}).Create(dept).Error)
 }
 
 // GetByID возвращает подразделение по его идентификатору.
 func (d *DepartmentRepo) GetByID(ctx context.Context, id uint) (*models.Department, error) {
 	var dept models.Department
-	err := d.db.WithContext(ctx).First(&dept, id).Error
+	err := conn(ctx, d.db).First(&dept, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &dept, err
+}
+
+// Lock возвращает подразделение, блокируя его строку до конца транзакции,
+// чтобы параллельные приёмы в подразделение проверяли его численность по очереди.
+func (d *DepartmentRepo) Lock(ctx context.Context, id uint) (*models.Department, error) {
+	var dept models.Department
+	err := conn(ctx, d.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&dept, id).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
 	return &dept, err
 }
 
-// Update обновляет существующее подразделение в базе данных.
+// Update обновляет существующее подразделение в базе данных. Совпадение ключа названия
+// с соседним подразделением даёт ErrDepartmentNameConflict.
 func (d *DepartmentRepo) Update(ctx context.Context, dept *models.Department) error {
-	return d.db.WithContext(ctx).Save(dept).Error
+	return nameConflict(conn(ctx, d.db).Clauses(clause.Returning{}).Save(dept).Error)
 }
 
 // Delete удаляет подразделение по его идентификатору.
 func (d *DepartmentRepo) Delete(ctx context.Context, id uint) error {
-	return d.db.WithContext(ctx).Delete(models.Department{}, id).Error
+	return conn(ctx, d.db).Delete(models.Department{}, id).Error
 }
 
-// GetChildren возвращает список прямых дочерних подразделений для указанного родителя.
+// GetChildren возвращает список прямых дочерних подразделений для указанного родителя в порядке сортировки.
 func (d *DepartmentRepo) GetChildren(ctx context.Context, parentID *uint) ([]models.Department, error) {
 	var depts []models.Department
-	err := d.db.WithContext(ctx).Where("parent_id = ?", parentID).Find(&depts).Error
+	err := whereParent(conn(ctx, d.db), parentID).Order("sort_order, id").Find(&depts).Error
 	return depts, err
 }
 
-// GetByNameAndParent возвращает подразделение по его имени и родителю.
+// GetByNameAndParent возвращает подразделение по его имени и родителю. Названия сравниваются
+// по ключу department_name_key: без учёта регистра, лишних пробелов и формы Unicode.
 func (d *DepartmentRepo) GetByNameAndParent(ctx context.Context, name string, parentID *uint) (*models.Department, error) {
 	var dept models.Department
-	err := d.db.WithContext(ctx).Where("name = ? AND parent_id = ?", name, parentID).First(&dept).Error
+	err := whereParent(conn(ctx, d.db), parentID).Where("name_key = department_name_key(?)", name).First(&dept).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &dept, err
+}
+
+// nameConflict заменяет нарушение уникального индекса ключа названия на ErrDepartmentNameConflict.
+// Копии индексов в схемах песочниц получают сгенерированные имена, но содержат имя колонки name_key.
+func nameConflict(err error) error {
+	var dbErr *apperrors.DBError
+	err = translateError(err)
+	if errors.As(err, &dbErr) && errors.Is(dbErr.Kind, apperrors.ErrUniqueViolation) &&
+		strings.Contains(dbErr.Constraint, "name_key") {
+		return apperrors.ErrDepartmentNameConflict
+	}
+	return err
+}
+
+// SetSortOrder присваивает подразделениям порядковые номера по их позиции в ids, начиная с 1.
+func (d *DepartmentRepo) SetSortOrder(ctx context.Context, ids []uint) error {
+	db := conn(ctx, d.db)
+	for i, id := range ids {
+		err := db.Model(&models.Department{}).Where("id = ?", id).Update("sort_order", i+1).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// whereParent добавляет условие на родителя; nil соответствует корневым подразделениям.
+func whereParent(db *gorm.DB, parentID *uint) *gorm.DB {
+	if parentID == nil {
+		return db.Where("parent_id IS NULL")
+	}
+	return db.Where("parent_id = ?", *parentID)
+}
+
+// List возвращает подразделения, значения атрибутов которых содержат attrFilter.
+// При заданном asOf названия и родители берутся из версий, действовавших на эту дату.
+func (d *DepartmentRepo) List(ctx context.Context, attrFilter models.Attributes, asOf *time.Time) ([]models.Department, error) {
+	var depts []models.Department
+	if asOf != nil {
+		query := "SELECT * FROM (" + departmentsAsOf + ") t"
+		if len(attrFilter) > 0 {
+			query += " WHERE attributes @> CAST(@attrs AS jsonb)"
+		}
+		err := conn(ctx, d.db).Raw(query+" ORDER BY id", map[string]any{
+			"as_of": dateParam(*asOf),
+			"attrs": attrFilter,
+		}).Scan(&depts).Error
+		return depts, err
+	}
+
+	query := conn(ctx, d.db).Order("path")
+	if len(attrFilter) > 0 {
+		query = query.Where("attributes @> ?::jsonb", attrFilter)
+	}
+	err := query.Find(&depts).Error
+	return depts, err
+}
+
+// GetAsOf возвращает подразделение в состоянии на дату asOf или nil, если тогда его не существовало.
+func (d *DepartmentRepo) GetAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Department, error) {
+	var depts []models.Department
+	err := conn(ctx, d.db).Raw("SELECT * FROM ("+departmentsAsOf+") t WHERE id = @id", map[string]any{
+		"as_of": dateParam(asOf),
+		"id":    id,
+	}).Scan(&depts).Error
+	if err != nil || len(depts) == 0 {
+		return nil, err
+	}
+	return &depts[0], nil
+}
+
+// GetByCode возвращает подразделение по его коду.
+func (d *DepartmentRepo) GetByCode(ctx context.Context, code string) (*models.Department, error) {
+	var dept models.Department
+	err := conn(ctx, d.db).Where("code = ?", code).First(&dept).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &dept, err
+}
+
+// GetByExternalID возвращает подразделение по идентификатору во внешней системе.
+func (d *DepartmentRepo) GetByExternalID(ctx context.Context, system, externalID string) (*models.Department, error) {
+	var dept models.Department
+	err := conn(ctx, d.db).Where("external_ids @> ?::jsonb", externalIDFilter(system, externalID)).First(&dept).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -62,37 +180,242 @@ func (d *DepartmentRepo) GetByNameAndParent(ctx context.Context, name string, pa
 }
 
 // GetSubTree возвращает всех потомков указанного подразделения до заданной глубины.
-func (d *DepartmentRepo) GetSubTree(ctx context.Context, rootID uint, depth int) ([]models.Department, error) {
+// Потомки выбираются по диапазону материализованного пути без рекурсивного обхода.
+// При заданном asOf дерево строится по версиям, действовавшим на эту дату.
+func (d *DepartmentRepo) GetSubTree(ctx context.Context, rootID uint, depth int, asOf *time.Time) ([]models.Department, error) {
 	if depth < 2 {
 		return nil, nil
 	}
 	maxLevel := depth - 1
+	if asOf != nil {
+		return d.getSubTreeAsOf(ctx, rootID, maxLevel, *asOf)
+	}
 	query := `
-		WITH RECURSIVE dept_tree AS (
-			SELECT id, name, parent_id, created_at, 1 AS level
-			FROM departments
-			WHERE parent_id = $1
+		SELECT d.*
+		FROM departments r
+		INNER JOIN departments d ON d.path > r.path AND d.path < r.path || '~'
+		WHERE r.id = $1
+			AND ` + pathLevel("d.path") + ` - ` + pathLevel("r.path") + ` <= $2
+		ORDER BY d.sort_order, d.id;
+	`
+	var depts []models.Department
+	err := conn(ctx, d.db).Raw(query, rootID, maxLevel).Scan(&depts).Error
+	return depts, err
+}
+
+// GetDescendants возвращает всех потомков подразделения без ограничения глубины.
+// Потомки выбираются по префиксу материализованного пути (диапазон от path корня до path || '~').
+func (d *DepartmentRepo) GetDescendants(ctx context.Context, rootID uint) ([]models.Department, error) {
+	query := `
+		SELECT d.*
+		FROM departments r
+		INNER JOIN departments d ON d.path > r.path AND d.path < r.path || '~'
+		WHERE r.id = $1
+		ORDER BY d.sort_order, d.id;
+	`
+	var depts []models.Department
+	err := conn(ctx, d.db).Raw(query, rootID).Scan(&depts).Error
+	return depts, err
+}
+
+// getSubTreeAsOf возвращает потомков подразделения до уровня maxLevel по версиям на дату asOf.
+// Пути в прошлом не хранятся, поэтому дерево обходится рекурсивно по parent_id версий.
+func (d *DepartmentRepo) getSubTreeAsOf(ctx context.Context, rootID uint, maxLevel int, asOf time.Time) ([]models.Department, error) {
+	query := `
+		WITH RECURSIVE snapshot AS (` + departmentsAsOf + `),
+		tree AS (
+			SELECT s.*, 1 AS level FROM snapshot s WHERE s.parent_id = @root
 			UNION ALL
-			SELECT d.id, d.name, d.parent_id, d.created_at, dt.level + 1
-			FROM departments d
-			INNER JOIN dept_tree dt ON d.parent_id = dt.id
-			WHERE dt.level < $2
+			SELECT s.*, t.level + 1 FROM snapshot s INNER JOIN tree t ON s.parent_id = t.id
+			WHERE t.level < @max_level
 		)
-		SELECT id, name, parent_id, created_at FROM dept_tree ORDER BY id;
+		SELECT id, name, parent_id, type, sort_order, code, external_ids, attributes, head_id, headcount_limit, location_id, created_at
+		FROM tree
+		ORDER BY sort_order, id;
 	`
-	rows, err := d.db.WithContext(ctx).Raw(query, rootID, maxLevel).Rows()
+	var depts []models.Department
+	err := conn(ctx, d.db).Raw(query, map[string]any{
+		"as_of":     dateParam(asOf),
+		"root":      rootID,
+		"max_level": maxLevel,
+	}).Scan(&depts).Error
+	return depts, err
+}
+
+// GetAncestors возвращает предков подразделения от корня к непосредственному родителю.
+func (d *DepartmentRepo) GetAncestors(ctx context.Context, id uint) ([]models.Department, error) {
+	dept, err := d.GetByID(ctx, id)
+	if err != nil || dept == nil {
+		return nil, err
+	}
+	ids := pathIDs(dept.Path)
+	if len(ids) < 2 {
+		return []models.Department{}, nil
+	}
+	var ancestors []models.Department
+	err = conn(ctx, d.db).Where("id IN ?", ids[:len(ids)-1]).Order("path").Find(&ancestors).Error
+	return ancestors, err
+}
+
+// IsDescendant сообщает, является ли подразделение id потомком подразделения ancestorID.
+func (d *DepartmentRepo) IsDescendant(ctx context.Context, ancestorID, id uint) (bool, error) {
+	var exists bool
+	err := conn(ctx, d.db).Raw(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM departments a
+			INNER JOIN departments d ON d.path > a.path AND d.path < a.path || '~'
+			WHERE a.id = $1 AND d.id = $2
+		)`, ancestorID, id).Scan(&exists).Error
+	return exists, err
+}
+
+// Stats вычисляет показатели поддерева подразделения агрегатами SQL: поддерево выбирается
+// по диапазону материализованного пути вместе с самим подразделением. Найм считается
+// помесячно за months месяцев начиная с первого числа месяца since. Уволенные сотрудники
+// не входят в численность и охват управления, но их найм учитывается.
+func (d *DepartmentRepo) Stats(ctx context.Context, id uint, since time.Time, months int) (*models.DepartmentStats, error) {
+	subtree := `
+		WITH subtree AS (
+			SELECT d.id, ` + pathLevel("d.path") + ` - ` + pathLevel("r.path") + ` AS level
+			FROM departments r
+			INNER JOIN departments d ON d.path >= r.path AND d.path < r.path || '~'
+			WHERE r.id = @id
+		)`
+	stats := models.DepartmentStats{DepartmentID: int(id)}
+	err := conn(ctx, d.db).Raw(subtree+`,
+		spans AS (
+			SELECT s.id,
+				(SELECT count(*) FROM employees e WHERE e.department_id = s.id AND e.status <> 'terminated')
+				+ (SELECT count(*) FROM departments c WHERE c.parent_id = s.id) AS span
+			FROM subtree s
+		)
+		SELECT
+			(SELECT count(*) FROM employees e WHERE e.department_id = @id AND e.status <> 'terminated') AS direct_headcount,
+			(SELECT count(*) FROM employees e INNER JOIN subtree s ON s.id = e.department_id
+				WHERE e.status <> 'terminated') AS total_headcount,
+			(SELECT GREATEST(count(*) - 1, 0) FROM subtree) AS sub_departments,
+			(SELECT COALESCE(max(level), 0) FROM subtree) AS max_depth,
+			(SELECT COALESCE(round(avg(span), 2), 0)::float8 FROM spans) AS avg_span_of_control,
+			(SELECT count(*) FROM employee_assignments a INNER JOIN employees e ON e.id = a.employee_id
+				WHERE a.department_id = @id AND e.status <> 'terminated') AS secondary_direct_headcount,
+			(SELECT count(DISTINCT a.employee_id) FROM employee_assignments a
+				INNER JOIN subtree s ON s.id = a.department_id
+				INNER JOIN employees e ON e.id = a.employee_id
+				WHERE e.status <> 'terminated' AND e.department_id NOT IN (SELECT id FROM subtree)) AS secondary_total_headcount`,
+		map[string]any{"id": id}).
+		Scan(&stats).Error
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var depts []models.Department
-	for rows.Next() {
-		var d models.Department
-		if err := rows.Scan(&d.ID, &d.Name, &d.ParentID, &d.CreatedAt); err != nil {
-			return nil, err
+	err = conn(ctx, d.db).Raw(subtree+`
+		SELECT to_char(m.month, 'YYYY-MM') AS month, count(e.id) AS hires
+		FROM generate_series(0, @months - 1) AS n
+		CROSS JOIN LATERAL (SELECT (CAST(@since AS date) + make_interval(months => n))::date AS month) m
+		LEFT JOIN (employees e INNER JOIN subtree s ON s.id = e.department_id)
+			ON e.hired_at >= m.month AND e.hired_at < m.month + interval '1 month'
+		GROUP BY m.month
+		ORDER BY m.month`,
+		map[string]any{"id": id, "since": dateParam(since), "months": months}).
+		Scan(&stats.HiresByMonth).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// HeadcountBudget возвращает утверждённую и фактическую численность и число открытых вакансий
+// подразделения id и каждого подразделения его поддерева (без учёта потомков) в порядке пути.
+// Уволенные сотрудники не учитываются.
+func (d *DepartmentRepo) HeadcountBudget(ctx context.Context, id uint) ([]models.HeadcountBudget, error) {
+	var rows []models.HeadcountBudget
+	err := conn(ctx, d.db).Raw(`
+		SELECT d.id AS department_id, d.name, d.parent_id, d.headcount_limit AS budgeted,
+			(SELECT count(*) FROM employees e WHERE e.department_id = d.id AND e.status <> 'terminated') AS actual,
+			(SELECT count(*) FROM vacancies v WHERE v.department_id = d.id AND v.status = 'open') AS open
+		FROM departments r
+		INNER JOIN departments d ON d.path >= r.path AND d.path < r.path || '~'
+		WHERE r.id = ?
+		ORDER BY d.path`, id).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// PathMismatch описывает подразделение, сохранённый путь которого расходится с фактической иерархией.
+type PathMismatch struct {
+	ID       int    `json:"id"`
+	Stored   string `json:"stored"`
+	Expected string `json:"expected"`
+}
+
+// expectedPathsCTE вычисляет пути подразделений рекурсивным обходом от корней.
+const expectedPathsCTE = `
+	WITH RECURSIVE tree AS (
+		SELECT id, '/' || id || '/' AS path
+		FROM departments
+		WHERE parent_id IS NULL
+		UNION ALL
+		SELECT d.id, t.path || d.id || '/'
+		FROM departments d
+		INNER JOIN tree t ON d.parent_id = t.id
+	)`
+
+// CheckPaths сверяет сохранённые пути с иерархией parent_id и возвращает расхождения.
+// Подразделения, недостижимые от корней, возвращаются с пустым ожидаемым путём.
+func (d *DepartmentRepo) CheckPaths(ctx context.Context) ([]PathMismatch, error) {
+	var mismatches []PathMismatch
+	err := conn(ctx, d.db).Raw(expectedPathsCTE + `
+		SELECT d.id, d.path AS stored, COALESCE(tree.path, '') AS expected
+		FROM departments d
+		LEFT JOIN tree ON tree.id = d.id
+		WHERE tree.path IS DISTINCT FROM d.path
+		ORDER BY d.id`).Scan(&mismatches).Error
+	return mismatches, err
+}
+
+// RebuildPaths пересчитывает пути всех подразделений и возвращает число исправленных строк.
+func (d *DepartmentRepo) RebuildPaths(ctx context.Context) (int64, error) {
+	res := conn(ctx, d.db).Exec(expectedPathsCTE + `
+		UPDATE departments d SET path = tree.path
+		FROM tree
+		WHERE d.id = tree.id AND d.path IS DISTINCT FROM tree.path`)
+	return res.RowsAffected, res.Error
+}
+
+// externalIDFilter строит JSON-фильтр для поиска по идентификатору внешней системы оператором @>.
+func externalIDFilter(system, externalID string) string {
+	b, _ := json.Marshal(map[string]string{system: externalID})
+	return string(b)
+}
+
+// departmentsAsOf выбирает подразделения в состоянии на дату @as_of: название и родитель
+// берутся из версий, остальные поля - из текущей записи, которой у удалённых подразделений нет.
+const departmentsAsOf = `
+	SELECT v.department_id AS id, v.name, v.parent_id, d.type, COALESCE(d.sort_order, 0) AS sort_order, d.code,
+		COALESCE(d.external_ids, '{}') AS external_ids, COALESCE(d.attributes, '{}') AS attributes, d.head_id, d.headcount_limit,
+		d.location_id, COALESCE(d.created_at, v.valid_from) AS created_at
+	FROM department_versions v
+	LEFT JOIN departments d ON d.id = v.department_id
+	WHERE v.valid_from <= CAST(@as_of AS date) AND (v.valid_to IS NULL OR v.valid_to > CAST(@as_of AS date))`
+
+// dateParam форматирует дату для сравнения с колонками типа DATE без учёта часового пояса.
+func dateParam(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// pathLevel возвращает SQL-выражение уровня вложенности по материализованному пути.
+func pathLevel(column string) string {
+	return "(length(" + column + ") - length(replace(" + column + ", '/', '')))"
+}
+
+// pathIDs разбирает материализованный путь на идентификаторы подразделений.
+func pathIDs(path string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if id, err := strconv.ParseUint(part, 10, 32); err == nil {
+			ids = append(ids, uint(id))
 		}
-		depts = append(depts, d)
 	}
-	return depts, nil
+	return ids
 }
@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -383,3 +386,24 @@ func TestDepartmentRepo_GetSubTree_Error(t *testing.T) {
 	assert.Nil(t, tree)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestPathIDs(t *testing.T) {
+	assert.Equal(t, []uint{1, 5, 12}, pathIDs("/1/5/12/"))
+	assert.Equal(t, []uint{7}, pathIDs("/7/"))
+	assert.Nil(t, pathIDs(""))
+}
+
+func TestNameConflict(t *testing.T) {
+	dup := &pgconn.PgError{Code: "23505", ConstraintName: "idx_departments_parent_name_key"}
+	assert.ErrorIs(t, nameConflict(dup), apperrors.ErrDepartmentNameConflict)
+
+	// Копия индекса в схеме песочницы получает сгенерированное имя.
+	sandboxDup := &pgconn.PgError{Code: "23505", ConstraintName: "departments_parent_id_name_key_idx"}
+	assert.ErrorIs(t, nameConflict(fmt.Errorf("insert: %w", sandboxDup)), apperrors.ErrDepartmentNameConflict)
+
+	codeDup := &pgconn.PgError{Code: "23505", ConstraintName: "idx_departments_code"}
+	err := nameConflict(codeDup)
+	assert.ErrorIs(t, err, apperrors.ErrUniqueViolation)
+	assert.NotErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	assert.NoError(t, nameConflict(nil))
+}
@@ -0,0 +1,85 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// DepartmentTypeRepo реализует репозиторий типов подразделений. Правила вложенности
+// для всех способов изменения дерева дополнительно проверяет триггер departments_type_rule.
+type DepartmentTypeRepo struct {
+	db *gorm.DB
+}
+
+// NewDepartmentTypeRepo создаёт новый экземпляр репозитория типов подразделений.
+func NewDepartmentTypeRepo(db *gorm.DB) *DepartmentTypeRepo {
+	return &DepartmentTypeRepo{db: db}
+}
+
+// Create сохраняет новый тип подразделения.
+func (r *DepartmentTypeRepo) Create(ctx context.Context, t *models.DepartmentType) error {
+	return conn(ctx, r.db).Create(t).Error
+}
+
+// GetByName возвращает тип подразделения по имени.
+func (r *DepartmentTypeRepo) GetByName(ctx context.Context, name string) (*models.DepartmentType, error) {
+	var t models.DepartmentType
+	err := conn(ctx, r.db).Where("name = ?", name).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &t, err
+}
+
+// List возвращает все типы подразделений по имени.
+func (r *DepartmentTypeRepo) List(ctx context.Context) ([]models.DepartmentType, error) {
+	var types []models.DepartmentType
+	err := conn(ctx, r.db).Order("name").Find(&types).Error
+	return types, err
+}
+
+// Update сохраняет правила вложенности типа.
+func (r *DepartmentTypeRepo) Update(ctx context.Context, t *models.DepartmentType) error {
+	return conn(ctx, r.db).Model(t).Select("allow_root", "parent_types").Updates(t).Error
+}
+
+// Delete удаляет тип подразделения и исключает его из допустимых родителей других типов.
+func (r *DepartmentTypeRepo) Delete(ctx context.Context, name string) error {
+	db := conn(ctx, r.db)
+	err := db.Exec("UPDATE department_types SET parent_types = parent_types - ? WHERE jsonb_exists(parent_types, ?)", name, name).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("name = ?", name).Delete(&models.DepartmentType{}).Error
+}
+
+// CountUsage возвращает количество подразделений типа name.
+func (r *DepartmentTypeRepo) CountUsage(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&models.Department{}).Where("type = ?", name).Count(&count).Error
+	return count, err
+}
+
+// CountViolations возвращает количество подразделений типа t, размещение которых
+// нарушает правила t: корневых при запрете корня или находящихся под родителем
+// с типом не из списка допустимых.
+func (r *DepartmentTypeRepo) CountViolations(ctx context.Context, t *models.DepartmentType) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Raw(`
+		SELECT count(*)
+		FROM departments d
+		LEFT JOIN departments p ON p.id = d.parent_id
+		WHERE d.type = @name
+			AND ((d.parent_id IS NULL AND NOT @allow_root)
+				OR (p.type IS NOT NULL AND NOT jsonb_exists(CAST(@parent_types AS jsonb), p.type)))`,
+		map[string]any{
+			"name":         t.Name,
+			"allow_root":   t.AllowRoot,
+			"parent_types": t.ParentTypes,
+		}).Scan(&count).Error
+	return count, err
+}
@@ -0,0 +1,96 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// EmployeeMergeRepo реализует слияние записей сотрудников и журнал слияний.
+type EmployeeMergeRepo struct {
+	db *gorm.DB
+}
+
+// NewEmployeeMergeRepo создаёт новый экземпляр репозитория слияния сотрудников.
+func NewEmployeeMergeRepo(db *gorm.DB) *EmployeeMergeRepo {
+	return &EmployeeMergeRepo{db: db}
+}
+
+// Merge записывает слияние source с targetID в журнал, передаёт targetID руководство
+// подразделениями source, заполненную source вакансию и дополнительные назначения source,
+// затем удаляет source. Версии source остаются в employee_versions. Возвращает
+// ErrEmployeeMergeConflict, если вакансии заполнили обе записи или сумма долей назначений
+// после слияния превысит 100%.
+func (r *EmployeeMergeRepo) Merge(ctx context.Context, targetID uint, source *models.Employee) (*models.EmployeeMerge, error) {
+	db := conn(ctx, r.db)
+	payload, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	merge := &models.EmployeeMerge{SourceID: source.ID, TargetID: int(targetID), Source: payload}
+	if err := db.Create(merge).Error; err != nil {
+		return nil, err
+	}
+	err = db.Model(&models.Department{}).Where("head_id = ?", source.ID).Update("head_id", targetID).Error
+	if err != nil {
+		return nil, err
+	}
+	var filled int64
+	err = db.Model(&models.Vacancy{}).Where("employee_id IN ?", []int{source.ID, int(targetID)}).Count(&filled).Error
+	if err != nil {
+		return nil, err
+	}
+	if filled > 1 {
+		return nil, fmt.Errorf("%w: employees %d and %d both filled a vacancy", apperrors.ErrEmployeeMergeConflict, targetID, source.ID)
+	}
+	err = db.Model(&models.Vacancy{}).Where("employee_id = ?", source.ID).Update("employee_id", targetID).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.mergeAssignments(db, targetID, source.ID); err != nil {
+		return nil, err
+	}
+	if err := db.Delete(&models.Employee{}, source.ID).Error; err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// mergeAssignments передаёт targetID дополнительные назначения sourceID. Назначения в один
+// отдел объединяются с суммой долей, назначение в основной отдел targetID не переносится.
+func (r *EmployeeMergeRepo) mergeAssignments(db *gorm.DB, targetID uint, sourceID int) error {
+	params := map[string]any{"source": sourceID, "target": targetID}
+	var total int
+	err := db.Raw(`SELECT COALESCE(sum(a.allocation), 0) FROM employee_assignments a
+		WHERE a.employee_id IN (@source, @target)
+			AND a.department_id <> (SELECT e.department_id FROM employees e WHERE e.id = @target)`, params).
+		Scan(&total).Error
+	if err != nil {
+		return err
+	}
+	if total > 100 {
+		return fmt.Errorf("%w: total allocation of employee %d would be %d percent, max 100",
+			apperrors.ErrEmployeeMergeConflict, targetID, total)
+	}
+	err = db.Exec(`WITH merged AS (
+			DELETE FROM employee_assignments a
+			WHERE a.employee_id = @source
+				AND EXISTS (SELECT 1 FROM employee_assignments t WHERE t.employee_id = @target AND t.department_id = a.department_id)
+			RETURNING a.department_id, a.allocation
+		)
+		UPDATE employee_assignments t SET allocation = t.allocation + m.allocation
+		FROM merged m
+		WHERE t.employee_id = @target AND t.department_id = m.department_id`, params).Error
+	if err != nil {
+		return err
+	}
+	// Назначение в основной отдел targetID удаляется вместе с source.
+	return db.Exec(`UPDATE employee_assignments a SET employee_id = @target
+		WHERE a.employee_id = @source
+			AND a.department_id <> (SELECT e.department_id FROM employees e WHERE e.id = @target)`, params).Error
+}
@@ -4,6 +4,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/NailUsmanov/api_organization/internal/models"
 	"gorm.io/gorm"
@@ -21,13 +22,33 @@ func NewEmployeeRepo(db *gorm.DB) *EmployeeRepo {
 
 // Create сохраняет нового сотрудника в базе данных.
 func (e *EmployeeRepo) Create(ctx context.Context, emp *models.Employee) error {
-	return e.db.WithContext(ctx).Create(emp).Error
+	return conn(ctx, e.db).Create(emp).Error
 }
 
 // GetByID возвращает сотрудника по его идентификатору.
 func (e *EmployeeRepo) GetByID(ctx context.Context, id uint) (*models.Employee, error) {
 	var emp models.Employee
-	err := e.db.WithContext(ctx).First(&emp, id).Error
+	err := conn(ctx, e.db).First(&emp, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &emp, err
+}
+
+// GetByCode возвращает сотрудника по его коду.
+func (e *EmployeeRepo) GetByCode(ctx context.Context, code string) (*models.Employee, error) {
+	var emp models.Employee
+	err := conn(ctx, e.db).Where("code = ?", code).First(&emp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &emp, err
+}
+
+// GetByExternalID возвращает сотрудника по идентификатору во внешней системе.
+func (e *EmployeeRepo) GetByExternalID(ctx context.Context, system, externalID string) (*models.Employee, error) {
+	var emp models.Employee
+	err := conn(ctx, e.db).Where("external_ids @> ?::jsonb", externalIDFilter(system, externalID)).First(&emp).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -36,31 +57,132 @@ func (e *EmployeeRepo) GetByID(ctx context.Context, id uint) (*models.Employee,
 
 // Update обновляет существующего сотрудника в базе данных.
 func (e *EmployeeRepo) Update(ctx context.Context, emp *models.Employee) error {
-	return e.db.WithContext(ctx).Save(emp).Error
+	return conn(ctx, e.db).Save(emp).Error
+}
+
+// Terminate сохраняет увольнение сотрудника и снимает его с руководства подразделениями.
+func (e *EmployeeRepo) Terminate(ctx context.Context, emp *models.Employee) error {
+	return conn(ctx, e.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(emp).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Department{}).Where("head_id = ?", emp.ID).Update("head_id", nil).Error
+	})
 }
 
 // Delete удаляет сотрудника по его идентификатору.
 func (e *EmployeeRepo) Delete(ctx context.Context, id uint) error {
-	return e.db.WithContext(ctx).Delete(&models.Employee{}, id).Error
+	return conn(ctx, e.db).Delete(&models.Employee{}, id).Error
 }
 
 // ListByDepartment возвращает список сотрудников указанного отдела.
-func (e *EmployeeRepo) ListByDepartment(ctx context.Context, departmenID uint, orderBy string) ([]models.Employee, error) {
-	var emps []models.Employee
-	query := e.db.WithContext(ctx).Where("department_id = ?", departmenID)
+// При заданном asOf возвращает сотрудников, числившихся в отделе на эту дату.
+func (e *EmployeeRepo) ListByDepartment(ctx context.Context, departmenID uint, orderBy string, asOf *time.Time) ([]models.Employee, error) {
+	order := "created_at"
 	if orderBy == "full_name" {
-		query = query.Order("full_name")
-	} else {
-		query = query.Order("created_at")
+		order = "full_name"
 	}
 
-	err := query.Find(&emps).Error
+	var emps []models.Employee
+	if asOf != nil {
+		err := conn(ctx, e.db).Raw("SELECT * FROM ("+employeesAsOf+") t WHERE department_id = @department ORDER BY "+order,
+			map[string]any{"as_of": dateParam(*asOf), "department": departmenID}).Scan(&emps).Error
+		return emps, err
+	}
+
+	err := conn(ctx, e.db).Where("department_id = ?", departmenID).Order(order).Find(&emps).Error
+
+	return emps, err
+}
 
+// List возвращает сотрудников, значения атрибутов которых содержат attrFilter,
+// при заданном departmentID - только сотрудников этого отдела. При заданном asOf
+// отдел, ФИО и должность берутся из версий, действовавших на эту дату.
+func (e *EmployeeRepo) List(ctx context.Context, departmentID *uint, attrFilter models.Attributes, asOf *time.Time) ([]models.Employee, error) {
+	var emps []models.Employee
+	if asOf != nil {
+		query := "SELECT * FROM (" + employeesAsOf + ") t WHERE TRUE"
+		if departmentID != nil {
+			query += " AND department_id = @department"
+		}
+		if len(attrFilter) > 0 {
+			query += " AND attributes @> CAST(@attrs AS jsonb)"
+		}
+		err := conn(ctx, e.db).Raw(query+" ORDER BY full_name, id", map[string]any{
+			"as_of":      dateParam(*asOf),
+			"department": departmentID,
+			"attrs":      attrFilter,
+		}).Scan(&emps).Error
+		return emps, err
+	}
+
+	query := conn(ctx, e.db).Order("full_name, id")
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	if len(attrFilter) > 0 {
+		query = query.Where("attributes @> ?::jsonb", attrFilter)
+	}
+	err := query.Find(&emps).Error
 	return emps, err
 }
 
-// MoveToDepartment перемещает всех сотрудников из одного отдела в другой.
+// MoveToDepartment перемещает всех сотрудников из одного отдела в другой вместе
+// с дополнительными назначениями в него. Если у сотрудника уже есть назначение в целевой
+// отдел, его доля увеличивается на долю переносимого назначения; назначение в основной
+// отдел сотрудника не переносится.
 func (e *EmployeeRepo) MoveToDepartment(ctx context.Context, departmentID uint, targerDerpartmentID uint) error {
-	return e.db.WithContext(ctx).Model(&models.Employee{}).Where("depatment_id").
+	db := conn(ctx, e.db)
+	err := db.Model(&models.Employee{}).Where("department_id = ?", departmentID).
 		Update("department_id", targerDerpartmentID).Error
+	if err != nil {
+		return err
+	}
+	// Сумма долей сотрудника не меняется, поэтому объединённая доля не превышает 100%.
+	err = db.Exec(`WITH merged AS (
+			DELETE FROM employee_assignments a
+			WHERE a.department_id = @source
+				AND EXISTS (SELECT 1 FROM employee_assignments t WHERE t.employee_id = a.employee_id AND t.department_id = @target)
+			RETURNING a.employee_id, a.allocation
+		)
+		UPDATE employee_assignments t SET allocation = t.allocation + m.allocation
+		FROM merged m
+		WHERE t.employee_id = m.employee_id AND t.department_id = @target`,
+		map[string]any{"source": departmentID, "target": targerDerpartmentID}).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec(`UPDATE employee_assignments a SET department_id = @target
+		WHERE a.department_id = @source
+			AND NOT EXISTS (SELECT 1 FROM employee_assignments t WHERE t.employee_id = a.employee_id AND t.department_id = @target)
+			AND NOT EXISTS (SELECT 1 FROM employees e WHERE e.id = a.employee_id AND e.department_id = @target)`,
+		map[string]any{"source": departmentID, "target": targerDerpartmentID}).Error
 }
+
+// CountByDepartment возвращает количество сотрудников указанного отдела.
+func (e *EmployeeRepo) CountByDepartment(ctx context.Context, departmentID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, e.db).Model(&models.Employee{}).Where("department_id = ?", departmentID).Count(&count).Error
+	return count, err
+}
+
+// CountEmployed возвращает количество неуволенных сотрудников указанного отдела.
+func (e *EmployeeRepo) CountEmployed(ctx context.Context, departmentID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, e.db).Model(&models.Employee{}).
+		Where("department_id = ? AND status <> ?", departmentID, models.EmployeeTerminated).Count(&count).Error
+	return count, err
+}
+
+// employeesAsOf выбирает сотрудников в состоянии на дату @as_of: отдел, ФИО и должность
+// берутся из версий, должность справочника - по названию из версии, остальные поля -
+// из текущей записи, которой у удалённых сотрудников нет.
+const employeesAsOf = `
+	SELECT v.employee_id AS id, v.department_id, v.full_name, v.position,
+		(SELECT p.id FROM positions p WHERE position_key(p.title) = position_key(v.position)) AS position_id, e.hired_at,
+		COALESCE(e.status, 'active') AS status, e.terminated_at, COALESCE(e.termination_reason, '') AS termination_reason,
+		e.code, COALESCE(e.external_ids, '{}') AS external_ids, COALESCE(e.attributes, '{}') AS attributes,
+		COALESCE(e.created_at, v.valid_from) AS created_at
+	FROM employee_versions v
+	LEFT JOIN employees e ON e.id = v.employee_id
+	WHERE v.valid_from <= CAST(@as_of AS date) AND (v.valid_to IS NULL OR v.valid_to > CAST(@as_of AS date))`
@@ -0,0 +1,31 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// HistoryRepo реализует репозиторий журнала структурных изменений подразделений.
+type HistoryRepo struct {
+	db *gorm.DB
+}
+
+// NewHistoryRepo создаёт новый экземпляр репозитория журнала изменений.
+func NewHistoryRepo(db *gorm.DB) *HistoryRepo {
+	return &HistoryRepo{db: db}
+}
+
+// Create сохраняет новую запись журнала.
+func (h *HistoryRepo) Create(ctx context.Context, entry *models.DepartmentHistory) error {
+	return conn(ctx, h.db).Create(entry).Error
+}
+
+// ListByDepartment возвращает записи журнала указанного подразделения в хронологическом порядке.
+func (h *HistoryRepo) ListByDepartment(ctx context.Context, departmentID uint) ([]models.DepartmentHistory, error) {
+	var entries []models.DepartmentHistory
+	err := conn(ctx, h.db).Where("department_id = ?", departmentID).Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
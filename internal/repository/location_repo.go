@@ -0,0 +1,129 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// departmentLocations - действующее место каждого подразделения: собственное или
+// ближайшего предка, у которого место задано. Подразделения без места не попадают в выборку.
+const departmentLocations = `
+	SELECT DISTINCT ON (d.id) d.id AS department_id, a.location_id
+	FROM departments d
+	INNER JOIN departments a ON d.path >= a.path AND d.path < a.path || '~'
+	WHERE a.location_id IS NOT NULL
+	ORDER BY d.id, length(a.path) DESC`
+
+// LocationRepo реализует репозиторий офисов и площадок.
+type LocationRepo struct {
+	db *gorm.DB
+}
+
+// NewLocationRepo создаёт новый экземпляр репозитория мест.
+func NewLocationRepo(db *gorm.DB) *LocationRepo {
+	return &LocationRepo{db: db}
+}
+
+// Create сохраняет новое место.
+func (r *LocationRepo) Create(ctx context.Context, loc *models.Location) error {
+	return conn(ctx, r.db).Create(loc).Error
+}
+
+// GetByID возвращает место по его идентификатору.
+func (r *LocationRepo) GetByID(ctx context.Context, id uint) (*models.Location, error) {
+	var loc models.Location
+	err := conn(ctx, r.db).First(&loc, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &loc, err
+}
+
+// GetByName возвращает место по названию без учёта регистра.
+func (r *LocationRepo) GetByName(ctx context.Context, name string) (*models.Location, error) {
+	var loc models.Location
+	err := conn(ctx, r.db).Where("lower(name) = lower(?)", name).First(&loc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &loc, err
+}
+
+// List возвращает места по стране и названию; пустой country возвращает все места.
+func (r *LocationRepo) List(ctx context.Context, country string) ([]models.Location, error) {
+	var locations []models.Location
+	query := conn(ctx, r.db).Order("country, name")
+	if country != "" {
+		query = query.Where("country = ?", country)
+	}
+	err := query.Find(&locations).Error
+	return locations, err
+}
+
+// Update сохраняет изменённое место.
+func (r *LocationRepo) Update(ctx context.Context, loc *models.Location) error {
+	return conn(ctx, r.db).Save(loc).Error
+}
+
+// Delete удаляет место по его идентификатору.
+func (r *LocationRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.Location{}, id).Error
+}
+
+// CountUsage возвращает число подразделений и сотрудников, включая уволенных, которым задано место.
+func (r *LocationRepo) CountUsage(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Raw(`
+		SELECT (SELECT count(*) FROM departments WHERE location_id = @id)
+			+ (SELECT count(*) FROM employees WHERE location_id = @id)`, map[string]any{"id": id}).
+		Scan(&count).Error
+	return count, err
+}
+
+// ListEmployees возвращает работающих сотрудников, действующее место которых - id:
+// заданное им самим или унаследованное от подразделения.
+func (r *LocationRepo) ListEmployees(ctx context.Context, id uint) ([]models.Employee, error) {
+	var employees []models.Employee
+	err := conn(ctx, r.db).Raw(`
+		WITH dept_location AS (`+departmentLocations+`)
+		SELECT e.*
+		FROM employees e
+		LEFT JOIN dept_location dl ON dl.department_id = e.department_id
+		WHERE e.status <> 'terminated' AND COALESCE(e.location_id, dl.location_id) = @id
+		ORDER BY e.full_name, e.id`, map[string]any{"id": id}).
+		Scan(&employees).Error
+	return employees, err
+}
+
+// Headcount возвращает численность работающих сотрудников по действующим местам:
+// всей организации при departmentID = nil, иначе поддерева подразделения.
+// Сотрудники без места собираются в строку с LocationID = nil.
+func (r *LocationRepo) Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error) {
+	query := `
+		WITH dept_location AS (` + departmentLocations + `)
+		SELECT l.id AS location_id, COALESCE(l.name, '') AS name, COALESCE(l.country, '') AS country,
+			COALESCE(l.timezone, '') AS timezone, count(*) AS headcount
+		FROM employees e
+		LEFT JOIN dept_location dl ON dl.department_id = e.department_id
+		LEFT JOIN locations l ON l.id = COALESCE(e.location_id, dl.location_id)
+		WHERE e.status <> 'terminated'`
+	args := map[string]any{}
+	if departmentID != nil {
+		query += ` AND e.department_id IN (
+			SELECT d.id FROM departments r
+			INNER JOIN departments d ON d.path >= r.path AND d.path < r.path || '~'
+			WHERE r.id = @id)`
+		args["id"] = *departmentID
+	}
+	query += `
+		GROUP BY l.id
+		ORDER BY l.id IS NULL, headcount DESC, name`
+
+	var rows []models.LocationHeadcount
+	err := conn(ctx, r.db).Raw(query, args).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,94 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgErrorKinds сопоставляет коды SQLSTATE PostgreSQL ошибкам базы данных из apperrors.
+var pgErrorKinds = map[string]error{
+	"23505": apperrors.ErrUniqueViolation,
+	"23503": apperrors.ErrForeignKeyViolation,
+	"23514": apperrors.ErrCheckViolation,
+	"40001": apperrors.ErrSerializationFailure,
+	"40P01": apperrors.ErrDeadlock,
+	"57014": apperrors.ErrStatementTimeout,
+	"55P03": apperrors.ErrLockNotAvailable,
+}
+
+// translateError заменяет ошибку PostgreSQL с известным кодом SQLSTATE на *apperrors.DBError.
+// Исходная ошибка остаётся доступной через errors.As; прочие ошибки возвращаются как есть.
+func translateError(err error) error {
+	var dbErr *apperrors.DBError
+	if err == nil || errors.As(err, &dbErr) {
+		return err
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	kind, ok := pgErrorKinds[pgErr.Code]
+	if !ok {
+		return err
+	}
+	return &apperrors.DBError{Kind: kind, Constraint: pgErr.ConstraintName, Table: pgErr.TableName, Err: err}
+}
+
+// Retryable сообщает, откатилась ли транзакция из-за конфликта с параллельной транзакцией
+// (ошибка сериализации или взаимная блокировка), после которого её можно выполнить заново.
+func Retryable(err error) bool {
+	return errors.Is(err, apperrors.ErrSerializationFailure) || errors.Is(err, apperrors.ErrDeadlock)
+}
+
+// ErrorTranslator - плагин GORM, который переводит ошибки PostgreSQL всех запросов
+// в *apperrors.DBError, чтобы сервисы различали нарушения ограничений и конфликты транзакций.
+type ErrorTranslator struct{}
+
+// Name возвращает имя плагина.
+func (ErrorTranslator) Name() string {
+	return "repository:error_translator"
+}
+
+// Initialize регистрирует перевод ошибок последним шагом каждой операции GORM.
+func (ErrorTranslator) Initialize(db *gorm.DB) error {
+	translate := func(db *gorm.DB) {
+		db.Error = translateError(db.Error)
+	}
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("*").Register("repository:translate_error", translate),
+		callbacks.Query().After("*").Register("repository:translate_error", translate),
+		callbacks.Update().After("*").Register("repository:translate_error", translate),
+		callbacks.Delete().After("*").Register("repository:translate_error", translate),
+		callbacks.Row().After("*").Register("repository:translate_error", translate),
+		callbacks.Raw().After("*").Register("repository:translate_error", translate),
+	)
+}
+
+// retryAttempts - сколько раз выполняется идемпотентная операция при конфликтах транзакций.
+const retryAttempts = 3
+
+// retryBackoff - пауза перед повтором, умножаемая на номер попытки.
+var retryBackoff = 20 * time.Millisecond
+
+// retryTransient выполняет fn и повторяет её, пока она возвращает Retryable-ошибку,
+// не более retryAttempts раз. Отмена контекста прерывает ожидание повтора.
+func retryTransient(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if attempt == retryAttempts || !Retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * retryBackoff):
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		code string
+		kind error
+	}{
+		{"23505", apperrors.ErrUniqueViolation},
+		{"23503", apperrors.ErrForeignKeyViolation},
+		{"23514", apperrors.ErrCheckViolation},
+		{"40001", apperrors.ErrSerializationFailure},
+		{"40P01", apperrors.ErrDeadlock},
+		{"57014", apperrors.ErrStatementTimeout},
+		{"55P03", apperrors.ErrLockNotAvailable},
+	}
+	for _, tt := range tests {
+		pgErr := &pgconn.PgError{Code: tt.code, ConstraintName: "fk_test", TableName: "departments"}
+		err := translateError(fmt.Errorf("query: %w", pgErr))
+
+		assert.ErrorIs(t, err, tt.kind, tt.code)
+		var dbErr *apperrors.DBError
+		assert.True(t, errors.As(err, &dbErr))
+		assert.Equal(t, "fk_test", dbErr.Constraint)
+		assert.Equal(t, "departments", dbErr.Table)
+		var original *pgconn.PgError
+		assert.True(t, errors.As(err, &original), "original error stays reachable")
+		assert.Same(t, err, translateError(err), "translation is idempotent")
+	}
+
+	unknown := &pgconn.PgError{Code: "42P01"}
+	assert.Equal(t, error(unknown), translateError(unknown))
+	plain := errors.New("boom")
+	assert.Equal(t, plain, translateError(plain))
+	assert.NoError(t, translateError(nil))
+}
+
+func TestRetryable(t *testing.T) {
+	assert.True(t, Retryable(translateError(&pgconn.PgError{Code: "40001"})))
+	assert.True(t, Retryable(translateError(&pgconn.PgError{Code: "40P01"})))
+	assert.False(t, Retryable(translateError(&pgconn.PgError{Code: "23505"})))
+	assert.False(t, Retryable(nil))
+}
+
+func TestRetryTransient(t *testing.T) {
+	retryBackoff = 0
+	serialization := translateError(&pgconn.PgError{Code: "40001"})
+
+	calls := 0
+	err := retryTransient(context.Background(), func() error {
+		calls++
+		if calls < 2 {
+			return serialization
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = retryTransient(context.Background(), func() error {
+		calls++
+		return serialization
+	})
+	assert.ErrorIs(t, err, apperrors.ErrSerializationFailure)
+	assert.Equal(t, retryAttempts, calls, "gives up after the last attempt")
+
+	calls = 0
+	unique := translateError(&pgconn.PgError{Code: "23505"})
+	err = retryTransient(context.Background(), func() error {
+		calls++
+		return unique
+	})
+	assert.ErrorIs(t, err, apperrors.ErrUniqueViolation)
+	assert.Equal(t, 1, calls, "constraint violations are not retried")
+}
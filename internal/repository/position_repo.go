@@ -0,0 +1,144 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// PositionRepo реализует репозиторий справочника должностей. Название должности
+// сотрудника согласуется со справочником триггером employees_position.
+type PositionRepo struct {
+	db *gorm.DB
+}
+
+// NewPositionRepo создаёт новый экземпляр репозитория справочника должностей.
+func NewPositionRepo(db *gorm.DB) *PositionRepo {
+	return &PositionRepo{db: db}
+}
+
+// Create сохраняет новую должность и связывает с ней сотрудников, название должности
+// которых совпадает с ней, но не найдено в справочнике.
+func (r *PositionRepo) Create(ctx context.Context, pos *models.Position) error {
+	if err := conn(ctx, r.db).Create(pos).Error; err != nil {
+		return err
+	}
+	return r.link(ctx, pos)
+}
+
+// GetByID возвращает должность по её идентификатору.
+func (r *PositionRepo) GetByID(ctx context.Context, id uint) (*models.Position, error) {
+	var pos models.Position
+	err := conn(ctx, r.db).First(&pos, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &pos, err
+}
+
+// GetByTitle возвращает должность по названию без учёта регистра и лишних пробелов.
+func (r *PositionRepo) GetByTitle(ctx context.Context, title string) (*models.Position, error) {
+	var pos models.Position
+	err := conn(ctx, r.db).Where("position_key(title) = position_key(?)", title).First(&pos).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &pos, err
+}
+
+// List возвращает должности по семейству и названию; пустой jobFamily возвращает все должности.
+func (r *PositionRepo) List(ctx context.Context, jobFamily string) ([]models.Position, error) {
+	var positions []models.Position
+	query := conn(ctx, r.db).Order("job_family, grade NULLS LAST, title")
+	if jobFamily != "" {
+		query = query.Where("job_family = ?", jobFamily)
+	}
+	err := query.Find(&positions).Error
+	return positions, err
+}
+
+// Update сохраняет должность, переносит новое название сотрудникам и вакансиям этой должности
+// и связывает с ней сотрудников, название должности которых теперь с ней совпадает.
+func (r *PositionRepo) Update(ctx context.Context, pos *models.Position) error {
+	db := conn(ctx, r.db)
+	if err := db.Save(pos).Error; err != nil {
+		return err
+	}
+	err := db.Model(&models.Employee{}).Where("position_id = ?", pos.ID).Update("position", pos.Title).Error
+	if err != nil {
+		return err
+	}
+	err = db.Model(&models.Vacancy{}).Where("position_id = ?", pos.ID).Update("position", pos.Title).Error
+	if err != nil {
+		return err
+	}
+	return r.link(ctx, pos)
+}
+
+// Delete удаляет должность по её идентификатору.
+func (r *PositionRepo) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.Position{}, id).Error
+}
+
+// CountUsage возвращает количество сотрудников, включая уволенных, и вакансий с должностью id.
+func (r *PositionRepo) CountUsage(ctx context.Context, id uint) (int64, error) {
+	var employees, vacancies int64
+	db := conn(ctx, r.db)
+	if err := db.Model(&models.Employee{}).Where("position_id = ?", id).Count(&employees).Error; err != nil {
+		return 0, err
+	}
+	err := db.Model(&models.Vacancy{}).Where("position_id = ?", id).Count(&vacancies).Error
+	return employees + vacancies, err
+}
+
+// Merge переводит сотрудников и вакансии должности sourceID на должность targetID и удаляет sourceID.
+func (r *PositionRepo) Merge(ctx context.Context, sourceID, targetID uint) error {
+	db := conn(ctx, r.db)
+	err := db.Model(&models.Employee{}).Where("position_id = ?", sourceID).Update("position_id", targetID).Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec(`UPDATE vacancies SET position_id = p.id, position = p.title
+		FROM positions p WHERE p.id = ? AND vacancies.position_id = ?`, targetID, sourceID).Error
+	if err != nil {
+		return err
+	}
+	return db.Delete(&models.Position{}, sourceID).Error
+}
+
+// Headcount возвращает численность работающих сотрудников по должностям: всей организации
+// при departmentID = nil, иначе поддерева подразделения. Названия, не связанные
+// со справочником, группируются по ключу названия.
+func (r *PositionRepo) Headcount(ctx context.Context, departmentID *uint) ([]models.PositionHeadcount, error) {
+	query := `
+		SELECT p.id AS position_id, COALESCE(p.title, min(e.position)) AS title, p.grade,
+			COALESCE(p.job_family, '') AS job_family, count(*) AS headcount
+		FROM employees e
+		LEFT JOIN positions p ON p.id = e.position_id
+		WHERE e.status <> 'terminated'`
+	args := map[string]any{}
+	if departmentID != nil {
+		query += ` AND e.department_id IN (
+			SELECT d.id FROM departments r
+			INNER JOIN departments d ON d.path >= r.path AND d.path < r.path || '~'
+			WHERE r.id = @id)`
+		args["id"] = *departmentID
+	}
+	query += `
+		GROUP BY p.id, CASE WHEN p.id IS NULL THEN position_key(e.position) END
+		ORDER BY job_family, p.grade NULLS LAST, title`
+
+	var rows []models.PositionHeadcount
+	err := conn(ctx, r.db).Raw(query, args).Scan(&rows).Error
+	return rows, err
+}
+
+// link связывает с должностью pos сотрудников без должности справочника с тем же названием.
+func (r *PositionRepo) link(ctx context.Context, pos *models.Position) error {
+	return conn(ctx, r.db).Model(&models.Employee{}).
+		Where("position_id IS NULL AND position_key(position) = position_key(?)", pos.Title).
+		Update("position_id", pos.ID).Error
+}
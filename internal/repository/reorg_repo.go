@@ -0,0 +1,86 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReorgRepo реализует репозиторий пакетов запланированных изменений структуры.
+type ReorgRepo struct {
+	db *gorm.DB
+}
+
+// NewReorgRepo создаёт новый экземпляр репозитория пакетов изменений.
+func NewReorgRepo(db *gorm.DB) *ReorgRepo {
+	return &ReorgRepo{db: db}
+}
+
+// Create сохраняет новый пакет изменений.
+func (r *ReorgRepo) Create(ctx context.Context, batch *models.ReorgBatch) error {
+	return conn(ctx, r.db).Create(batch).Error
+}
+
+// GetByID возвращает пакет изменений по его идентификатору.
+func (r *ReorgRepo) GetByID(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	var batch models.ReorgBatch
+	err := conn(ctx, r.db).First(&batch, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &batch, err
+}
+
+// Lock возвращает пакет изменений, блокируя его строку до конца транзакции,
+// чтобы пакет не был одновременно выполнен и отменён.
+func (r *ReorgRepo) Lock(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	var batch models.ReorgBatch
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&batch, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &batch, err
+}
+
+// List возвращает пакеты изменений по времени выполнения; пустой status возвращает все пакеты.
+func (r *ReorgRepo) List(ctx context.Context, status string) ([]models.ReorgBatch, error) {
+	var batches []models.ReorgBatch
+	query := conn(ctx, r.db).Order("execute_at, id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&batches).Error
+	return batches, err
+}
+
+// Due возвращает ожидающие пакеты, время выполнения которых наступило к моменту now.
+func (r *ReorgRepo) Due(ctx context.Context, now time.Time) ([]models.ReorgBatch, error) {
+	var batches []models.ReorgBatch
+	err := conn(ctx, r.db).
+		Where("status = ? AND execute_at <= ?", models.ReorgStatusPending, now).
+		Order("execute_at, id").
+		Find(&batches).Error
+	return batches, err
+}
+
+// MarkFailed помечает пакет как failed с текстом ошибки, только если он всё ещё ожидает
+// выполнения, чтобы не затереть отмену или выполнение в параллельной транзакции.
+// Сообщает, был ли пакет помечен.
+func (r *ReorgRepo) MarkFailed(ctx context.Context, id uint, message string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.ReorgBatch{}).
+		Where("id = ? AND status = ?", id, models.ReorgStatusPending).
+		Updates(map[string]any{"status": models.ReorgStatusFailed, "error": message, "executed_at": at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Update сохраняет статус, ошибку и время выполнения пакета.
+func (r *ReorgRepo) Update(ctx context.Context, batch *models.ReorgBatch) error {
+	return conn(ctx, r.db).Model(batch).
+		Select("status", "error", "executed_at").
+		Updates(batch).Error
+}
@@ -0,0 +1,356 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sandboxTables - таблицы, копии которых создаются в схеме песочницы. Остальные таблицы
+// (определения атрибутов, заявки, пакеты изменений, снимки) песочница видит через
+// search_path и разделяет с рабочими данными.
+var sandboxTables = []string{
+	"departments", "employees", "vacancies", "department_history", "department_versions", "employee_versions",
+	"employee_merges", "employee_assignments",
+}
+
+// sandboxBaseTables - таблицы, состояние которых на момент создания песочницы сохраняется
+// в таблицах base_* для вычисления и переноса изменений.
+var sandboxBaseTables = []string{"departments", "employees", "employee_assignments"}
+
+// SandboxRepo реализует репозиторий песочниц. Каждая песочница - схема базы данных
+// с копиями рабочих таблиц, их внешних ключей и триггеров.
+type SandboxRepo struct {
+	db *gorm.DB
+}
+
+// NewSandboxRepo создаёт новый экземпляр репозитория песочниц.
+func NewSandboxRepo(db *gorm.DB) *SandboxRepo {
+	return &SandboxRepo{db: db}
+}
+
+// sandboxSchema возвращает имя схемы песочницы.
+func sandboxSchema(id int) string {
+	return fmt.Sprintf("sandbox_%d", id)
+}
+
+// Create сохраняет песочницу и создаёт её схему с копией текущих данных.
+// Вызывается в транзакции: при ошибке не остаётся ни записи, ни схемы.
+func (r *SandboxRepo) Create(ctx context.Context, sandbox *models.Sandbox) error {
+	db := conn(ctx, r.db)
+	if err := db.Create(sandbox).Error; err != nil {
+		return err
+	}
+	sandbox.SchemaName = sandboxSchema(sandbox.ID)
+	if err := db.Model(sandbox).Update("schema_name", sandbox.SchemaName).Error; err != nil {
+		return err
+	}
+
+	// Определения внешних ключей и триггеров читаются до смены search_path:
+	// в них остаются неполные имена таблиц, которые затем разрешаются в схему песочницы.
+	var constraints []struct {
+		TableName  string
+		Name       string
+		Definition string
+	}
+	err := db.Raw(`
+		SELECT c.conrelid::regclass::text AS table_name, c.conname AS name, pg_get_constraintdef(c.oid) AS definition
+		FROM pg_constraint c
+		WHERE c.contype = 'f' AND c.conrelid::regclass::text IN ?`, sandboxTables).
+		Scan(&constraints).Error
+	if err != nil {
+		return err
+	}
+	var triggers []string
+	err = db.Raw(`
+		SELECT pg_get_triggerdef(t.oid)
+		FROM pg_trigger t
+		WHERE NOT t.tgisinternal AND t.tgrelid::regclass::text IN ?`, sandboxTables).
+		Scan(&triggers).Error
+	if err != nil {
+		return err
+	}
+
+	schema := sandbox.SchemaName
+	statements := []string{
+		"CREATE SCHEMA " + schema,
+		"SET LOCAL search_path TO " + schema + ", public",
+	}
+	for _, table := range sandboxTables {
+		statements = append(statements,
+			fmt.Sprintf("CREATE TABLE %s.%s (LIKE public.%s INCLUDING ALL)", schema, table, table),
+			fmt.Sprintf("INSERT INTO %s.%s SELECT * FROM public.%s", schema, table, table))
+	}
+	for _, table := range sandboxBaseTables {
+		statements = append(statements,
+			fmt.Sprintf("CREATE TABLE %s.base_%s AS SELECT * FROM public.%s", schema, table, table))
+	}
+	for _, c := range constraints {
+		statements = append(statements,
+			fmt.Sprintf("ALTER TABLE %s.%s ADD CONSTRAINT %s %s", schema, c.TableName, c.Name, c.Definition))
+	}
+	for _, trigger := range triggers {
+		statements = append(statements, strings.Replace(trigger, " ON public.", " ON ", 1))
+	}
+	statements = append(statements, "SET LOCAL search_path TO DEFAULT")
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("create sandbox schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetByID возвращает песочницу по её идентификатору.
+func (r *SandboxRepo) GetByID(ctx context.Context, id uint) (*models.Sandbox, error) {
+	var sandbox models.Sandbox
+	err := conn(ctx, r.db).First(&sandbox, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &sandbox, err
+}
+
+// Lock возвращает песочницу, блокируя её строку до конца транзакции,
+// чтобы песочницу не перенесли и не удалили одновременно.
+func (r *SandboxRepo) Lock(ctx context.Context, id uint) (*models.Sandbox, error) {
+	var sandbox models.Sandbox
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&sandbox, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &sandbox, err
+}
+
+// Share возвращает песочницу, блокируя её строку от изменения до конца транзакции.
+// Запросы к данным песочницы выполняются одновременно, а перенос и закрытие, которые
+// блокируют строку через Lock, ждут их завершения.
+func (r *SandboxRepo) Share(ctx context.Context, id uint) (*models.Sandbox, error) {
+	var sandbox models.Sandbox
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "SHARE"}).First(&sandbox, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &sandbox, err
+}
+
+// List возвращает песочницы от новых к старым.
+func (r *SandboxRepo) List(ctx context.Context) ([]models.Sandbox, error) {
+	var sandboxes []models.Sandbox
+	err := conn(ctx, r.db).Order("id DESC").Find(&sandboxes).Error
+	return sandboxes, err
+}
+
+// Update сохраняет статус и время закрытия песочницы.
+func (r *SandboxRepo) Update(ctx context.Context, sandbox *models.Sandbox) error {
+	return conn(ctx, r.db).Model(sandbox).
+		Select("status", "closed_at").
+		Updates(sandbox).Error
+}
+
+// Within выполняет fn так, что до конца текущей транзакции неполные имена таблиц
+// разрешаются в схему песочницы. Репозитории, вызванные с переданным в fn контекстом,
+// читают и изменяют копии таблиц вместо рабочих. Вызывается в транзакции; если схемы
+// уже нет, возвращает ErrSandboxClosed, не переключая search_path.
+func (r *SandboxRepo) Within(ctx context.Context, schema string, fn func(ctx context.Context) error) error {
+	db := conn(ctx, r.db)
+	var exists bool
+	if err := db.Raw("SELECT to_regnamespace(?) IS NOT NULL", schema).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: schema %s does not exist", apperrors.ErrSandboxClosed, schema)
+	}
+	if err := db.Exec("SET LOCAL search_path TO " + schema + ", public").Error; err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// Departments возвращает подразделения песочницы; при base - состояние на момент её создания.
+func (r *SandboxRepo) Departments(ctx context.Context, schema string, base bool) ([]models.Department, error) {
+	var departments []models.Department
+	err := conn(ctx, r.db).Table(sandboxTable(schema, "departments", base)).Order("id").Find(&departments).Error
+	return departments, err
+}
+
+// Employees возвращает сотрудников песочницы; при base - состояние на момент её создания.
+func (r *SandboxRepo) Employees(ctx context.Context, schema string, base bool) ([]models.Employee, error) {
+	var employees []models.Employee
+	err := conn(ctx, r.db).Table(sandboxTable(schema, "employees", base)).Order("id").Find(&employees).Error
+	return employees, err
+}
+
+// Conflicts возвращает идентификаторы подразделений и сотрудников, которые изменены
+// в песочнице и одновременно изменены в рабочих таблицах после её создания.
+func (r *SandboxRepo) Conflicts(ctx context.Context, schema string) (departments, employees []int, err error) {
+	query := func(table string) ([]int, error) {
+		var ids []int
+		err := conn(ctx, r.db).Raw(fmt.Sprintf(`
+			SELECT b.id
+			FROM %[1]s.base_%[2]s b
+			LEFT JOIN %[1]s.%[2]s s ON s.id = b.id
+			LEFT JOIN public.%[2]s p ON p.id = b.id
+			WHERE (s.id IS NULL OR %[3]s)
+				AND (p.id IS NULL OR %[4]s)
+				AND NOT (s.id IS NULL AND p.id IS NULL)
+			ORDER BY b.id`, schema, table, rowChanged("s", "b"), rowChanged("p", "b"))).
+			Scan(&ids).Error
+		return ids, err
+	}
+	if departments, err = query("departments"); err != nil {
+		return nil, nil, err
+	}
+	if employees, err = query("employees"); err != nil {
+		return nil, nil, err
+	}
+	return departments, employees, nil
+}
+
+// Promote переносит изменения песочницы в рабочие таблицы в транзакции из контекста:
+// новые, изменённые и удалённые подразделения, сотрудников и их дополнительные назначения,
+// новые вакансии, закрытие открытых вакансий и записи журнала слияния сотрудников.
+// Подразделения применяются по одному в порядке пути, чтобы триггеры пересчитали пути
+// поддеревьев; руководители назначаются последними, когда все сотрудники уже перенесены.
+func (r *SandboxRepo) Promote(ctx context.Context, schema string) error {
+	db := conn(ctx, r.db)
+	deptColumns, err := r.columns(ctx, "departments")
+	if err != nil {
+		return err
+	}
+	empColumns, err := r.columns(ctx, "employees")
+	if err != nil {
+		return err
+	}
+
+	var added, changed []int
+	err = db.Raw(fmt.Sprintf(`
+		SELECT s.id FROM %[1]s.departments s
+		WHERE NOT EXISTS (SELECT 1 FROM %[1]s.base_departments b WHERE b.id = s.id)
+		ORDER BY s.path`, schema)).Scan(&added).Error
+	if err != nil {
+		return err
+	}
+	err = db.Raw(fmt.Sprintf(`
+		SELECT s.id FROM %[1]s.departments s
+		JOIN %[1]s.base_departments b ON b.id = s.id
+		WHERE %[2]s
+		ORDER BY s.path`, schema, rowChanged("s", "b"))).Scan(&changed).Error
+	if err != nil {
+		return err
+	}
+
+	// Руководитель может быть новым сотрудником, поэтому head_id назначается в конце.
+	insertValues := make([]string, len(deptColumns))
+	updateSet := make([]string, 0, len(deptColumns))
+	for i, column := range deptColumns {
+		insertValues[i] = "s." + column
+		if column == "head_id" {
+			insertValues[i] = "NULL"
+			continue
+		}
+		updateSet = append(updateSet, fmt.Sprintf("%[1]s = s.%[1]s", column))
+	}
+	for _, id := range added {
+		err := db.Exec(fmt.Sprintf("INSERT INTO public.departments (id, %s) SELECT s.id, %s FROM %s.departments s WHERE s.id = ?",
+			strings.Join(deptColumns, ", "), strings.Join(insertValues, ", "), schema), id).Error
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range changed {
+		err := db.Exec(fmt.Sprintf("UPDATE public.departments d SET %s FROM %s.departments s WHERE d.id = s.id AND s.id = ?",
+			strings.Join(updateSet, ", "), schema), id).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	empSet := make([]string, len(empColumns))
+	for i, column := range empColumns {
+		empSet[i] = fmt.Sprintf("%[1]s = s.%[1]s", column)
+	}
+	statements := []string{
+		fmt.Sprintf(`INSERT INTO public.employees (id, %[2]s)
+			SELECT s.id, s.%[3]s FROM %[1]s.employees s
+			WHERE NOT EXISTS (SELECT 1 FROM %[1]s.base_employees b WHERE b.id = s.id)`,
+			schema, strings.Join(empColumns, ", "), strings.Join(empColumns, ", s.")),
+		fmt.Sprintf(`UPDATE public.employees e SET %[2]s
+			FROM %[1]s.employees s JOIN %[1]s.base_employees b ON b.id = s.id
+			WHERE e.id = s.id AND %[3]s`, schema, strings.Join(empSet, ", "), rowChanged("s", "b")),
+		fmt.Sprintf(`DELETE FROM public.employees
+			WHERE id IN (SELECT id FROM %[1]s.base_employees EXCEPT SELECT id FROM %[1]s.employees)`, schema),
+		fmt.Sprintf(`DELETE FROM public.departments
+			WHERE id IN (SELECT id FROM %[1]s.base_departments EXCEPT SELECT id FROM %[1]s.departments)`, schema),
+		fmt.Sprintf(`UPDATE public.departments d SET head_id = s.head_id
+			FROM %[1]s.departments s LEFT JOIN %[1]s.base_departments b ON b.id = s.id
+			WHERE d.id = s.id AND (b.id IS NULL OR s.head_id IS DISTINCT FROM b.head_id)`, schema),
+		fmt.Sprintf(`INSERT INTO public.vacancies
+			SELECT v.* FROM %[1]s.vacancies v
+			WHERE NOT EXISTS (SELECT 1 FROM public.vacancies p WHERE p.id = v.id)
+				AND EXISTS (SELECT 1 FROM public.departments d WHERE d.id = v.department_id)`, schema),
+		fmt.Sprintf(`UPDATE public.vacancies p SET status = v.status, employee_id = v.employee_id, closed_at = v.closed_at
+			FROM %[1]s.vacancies v
+			WHERE p.id = v.id AND p.status = 'open' AND v.status <> 'open'
+				AND (v.employee_id IS NULL OR EXISTS (SELECT 1 FROM public.employees e WHERE e.id = v.employee_id))`, schema),
+		fmt.Sprintf(`DELETE FROM public.employee_assignments
+			WHERE id IN (SELECT id FROM %[1]s.base_employee_assignments EXCEPT SELECT id FROM %[1]s.employee_assignments)`, schema),
+		fmt.Sprintf(`UPDATE public.employee_assignments p SET role = s.role, allocation = s.allocation
+			FROM %[1]s.employee_assignments s JOIN %[1]s.base_employee_assignments b ON b.id = s.id
+			WHERE p.id = s.id AND %[2]s`, schema, rowChanged("s", "b")),
+		fmt.Sprintf(`INSERT INTO public.employee_assignments
+			SELECT s.* FROM %[1]s.employee_assignments s
+			WHERE NOT EXISTS (SELECT 1 FROM %[1]s.base_employee_assignments b WHERE b.id = s.id)
+				AND EXISTS (SELECT 1 FROM public.employees e WHERE e.id = s.employee_id AND e.department_id <> s.department_id)
+				AND EXISTS (SELECT 1 FROM public.departments d WHERE d.id = s.department_id)
+			ON CONFLICT (employee_id, department_id) DO NOTHING`, schema),
+		fmt.Sprintf(`INSERT INTO public.employee_merges
+			SELECT m.* FROM %[1]s.employee_merges m
+			WHERE NOT EXISTS (SELECT 1 FROM public.employee_merges p WHERE p.id = m.id)`, schema),
+	}
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Drop удаляет схему песочницы со всеми копиями таблиц.
+func (r *SandboxRepo) Drop(ctx context.Context, schema string) error {
+	return conn(ctx, r.db).Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error
+}
+
+// columns возвращает изменяемые столбцы рабочей таблицы: без идентификатора
+// и пути, который поддерживается триггерами.
+func (r *SandboxRepo) columns(ctx context.Context, table string) ([]string, error) {
+	var columns []string
+	err := conn(ctx, r.db).Raw(`
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = ? AND column_name NOT IN ('id', 'path')
+			AND is_generated = 'NEVER'
+		ORDER BY ordinal_position`, table).
+		Scan(&columns).Error
+	return columns, err
+}
+
+// sandboxTable возвращает полное имя копии таблицы в схеме песочницы.
+func sandboxTable(schema, table string, base bool) string {
+	if base {
+		return schema + ".base_" + table
+	}
+	return schema + "." + table
+}
+
+// rowChanged возвращает SQL-условие различия двух строк одной таблицы без учёта пути.
+func rowChanged(a, b string) string {
+	return fmt.Sprintf("(to_jsonb(%s) - 'path') IS DISTINCT FROM (to_jsonb(%s) - 'path')", a, b)
+}
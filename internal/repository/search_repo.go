@@ -0,0 +1,100 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// searchQuery находит сотрудников по имени и должности и подразделения по названию:
+// полнотекстовым совпадением слов или, для опечаток, сходством триграмм со словами
+// (оператор <% расширения pg_trgm). Имена и названия сравниваются и по ключам
+// транслитерации, поэтому "Ivan Petrov" находит "Иван Петров" и наоборот.
+// Выражения совпадают с индексами миграций 0014 и 0015.
+const searchQuery = `
+	SELECT * FROM (
+		SELECT 'employee' AS type, e.id, e.full_name AS title, e.position AS subtitle, e.department_id,
+			ts_rank(to_tsvector('simple', e.full_name || ' ' || e.position), q.query)
+				+ ts_rank(to_tsvector('simple', e.search_key), q.key)
+				+ GREATEST(word_similarity(@q, e.full_name), word_similarity(@q, e.position),
+					word_similarity(k.key, e.search_key)) AS rank
+		FROM employees e, plainto_tsquery('simple', @q) AS q(query), translit_key(@q) AS k(key),
+			plainto_tsquery('simple', k.key) AS kq(key)
+		WHERE to_tsvector('simple', e.full_name || ' ' || e.position) @@ q.query
+			OR to_tsvector('simple', e.search_key) @@ kq.key
+			OR @q <% e.full_name OR @q <% e.position OR k.key <% e.search_key
+		UNION ALL
+		SELECT 'department', d.id, d.name, '', d.id,
+			ts_rank(to_tsvector('simple', d.name), q.query) + ts_rank(to_tsvector('simple', d.search_key), kq.key)
+				+ GREATEST(word_similarity(@q, d.name), word_similarity(k.key, d.search_key))
+		FROM departments d, plainto_tsquery('simple', @q) AS q(query), translit_key(@q) AS k(key),
+			plainto_tsquery('simple', k.key) AS kq(key)
+		WHERE to_tsvector('simple', d.name) @@ q.query OR to_tsvector('simple', d.search_key) @@ kq.key
+			OR @q <% d.name OR k.key <% d.search_key
+	) hits
+	ORDER BY rank DESC, type, id
+	LIMIT @limit`
+
+// autocompleteQuery находит сотрудников и подразделения по префиксам слов исходного
+// запроса и их ключей транслитерации. Слова передаются одной строкой через пробел.
+const autocompleteQuery = `
+	WITH w AS (
+		SELECT string_agg(word || ':*', ' & ') AS query,
+			coalesce(string_agg(replace(translit_key(word), ' ', ':* & ') || ':*', ' & ')
+				FILTER (WHERE translit_key(word) <> ''), '') AS key
+		FROM regexp_split_to_table(@words, ' ') AS word
+	), q AS (
+		SELECT to_tsquery('simple', w.query) AS query, to_tsquery('simple', w.key) AS key FROM w
+	)
+	SELECT * FROM (
+		SELECT 'employee' AS type, e.id, e.full_name AS title, e.position AS subtitle, e.department_id,
+			ts_rank(to_tsvector('simple', e.full_name || ' ' || e.position), q.query)
+				+ ts_rank(to_tsvector('simple', e.search_key), q.key) AS rank
+		FROM employees e, q
+		WHERE to_tsvector('simple', e.full_name || ' ' || e.position) @@ q.query
+			OR to_tsvector('simple', e.search_key) @@ q.key
+		UNION ALL
+		SELECT 'department', d.id, d.name, '', d.id,
+			ts_rank(to_tsvector('simple', d.name), q.query) + ts_rank(to_tsvector('simple', d.search_key), q.key)
+		FROM departments d, q
+		WHERE to_tsvector('simple', d.name) @@ q.query OR to_tsvector('simple', d.search_key) @@ q.key
+	) hits
+	ORDER BY rank DESC, type, id
+	LIMIT @limit`
+
+// SearchRepo реализует поиск сотрудников и подразделений.
+type SearchRepo struct {
+	db *gorm.DB
+}
+
+// NewSearchRepo создаёт новый экземпляр репозитория поиска.
+func NewSearchRepo(db *gorm.DB) *SearchRepo {
+	return &SearchRepo{db: db}
+}
+
+// Search возвращает не больше limit результатов по убыванию релевантности
+// вместе с путём подразделения каждого результата от корня.
+func (r *SearchRepo) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	hits := []models.SearchHit{}
+	err := conn(ctx, r.db).Raw(`
+		SELECT h.type, h.id, h.title, h.subtitle, h.department_id, h.rank,
+			(SELECT json_agg(a.name ORDER BY a.path) FROM departments a WHERE starts_with(d.path, a.path)) AS path
+		FROM (`+searchQuery+`) h
+		INNER JOIN departments d ON d.id = h.department_id
+		ORDER BY h.rank DESC, h.type, h.id`,
+		map[string]any{"q": query, "limit": limit}).
+		Scan(&hits).Error
+	return hits, err
+}
+
+// Autocomplete возвращает не больше limit результатов для подсказок при вводе без путей
+// подразделений. Каждое из words - префикс слова; слова должны состоять только из букв и цифр.
+func (r *SearchRepo) Autocomplete(ctx context.Context, words []string, limit int) ([]models.SearchHit, error) {
+	hits := []models.SearchHit{}
+	err := conn(ctx, r.db).Raw(autocompleteQuery, map[string]any{"words": strings.Join(words, " "), "limit": limit}).
+		Scan(&hits).Error
+	return hits, err
+}
@@ -0,0 +1,45 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// SnapshotRepo реализует репозиторий снимков структуры.
+type SnapshotRepo struct {
+	db *gorm.DB
+}
+
+// NewSnapshotRepo создаёт новый экземпляр репозитория снимков.
+func NewSnapshotRepo(db *gorm.DB) *SnapshotRepo {
+	return &SnapshotRepo{db: db}
+}
+
+// Create сохраняет новый снимок.
+func (r *SnapshotRepo) Create(ctx context.Context, snapshot *models.Snapshot) error {
+	return conn(ctx, r.db).Create(snapshot).Error
+}
+
+// GetByID возвращает снимок вместе с документом.
+func (r *SnapshotRepo) GetByID(ctx context.Context, id uint) (*models.Snapshot, error) {
+	var snapshot models.Snapshot
+	err := conn(ctx, r.db).First(&snapshot, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &snapshot, err
+}
+
+// List возвращает снимки от новых к старым без документов.
+func (r *SnapshotRepo) List(ctx context.Context) ([]models.Snapshot, error) {
+	var snapshots []models.Snapshot
+	err := conn(ctx, r.db).
+		Select("id", "label", "department_count", "employee_count", "created_at").
+		Order("id DESC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
@@ -0,0 +1,51 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey - ключ контекста, под которым хранится открытая транзакция.
+type txKey struct{}
+
+// TxManager управляет транзакциями, общими для нескольких репозиториев.
+type TxManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager создаёт новый менеджер транзакций.
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTransaction выполняет fn в транзакции. Репозитории, вызванные с переданным
+// в fn контекстом, работают внутри этой транзакции. Вложенные вызовы используют точки сохранения.
+// Ошибки PostgreSQL, в том числе при фиксации транзакции, переводятся в *apperrors.DBError.
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return translateError(conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	}))
+}
+
+// WithinRetryableTransaction выполняет fn как WithinTransaction и повторяет транзакцию целиком,
+// если она откатилась из-за ошибки сериализации или взаимной блокировки. fn должна быть
+// идемпотентной и не иметь побочных эффектов вне базы данных. Внутри уже открытой транзакции
+// повтор невозможен: откатывается вся внешняя транзакция, и ошибка возвращается вызывающему.
+func (m *TxManager) WithinRetryableTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return m.WithinTransaction(ctx, fn)
+	}
+	return retryTransient(ctx, func() error {
+		return m.WithinTransaction(ctx, fn)
+	})
+}
+
+// conn возвращает транзакцию из контекста, если она открыта, иначе - соединение репозитория.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,76 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// VacancyRepo реализует репозиторий для работы с вакансиями в базе данных.
+type VacancyRepo struct {
+	db *gorm.DB
+}
+
+// NewVacancyRepo создаёт новый экземпляр репозитория вакансий.
+func NewVacancyRepo(db *gorm.DB) *VacancyRepo {
+	return &VacancyRepo{db: db}
+}
+
+// Create сохраняет новую вакансию в базе данных.
+func (v *VacancyRepo) Create(ctx context.Context, vacancy *models.Vacancy) error {
+	return conn(ctx, v.db).Create(vacancy).Error
+}
+
+// GetByID возвращает вакансию по её идентификатору.
+func (v *VacancyRepo) GetByID(ctx context.Context, id uint) (*models.Vacancy, error) {
+	var vacancy models.Vacancy
+	err := conn(ctx, v.db).First(&vacancy, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &vacancy, err
+}
+
+// Update сохраняет статус вакансии и принятого на неё сотрудника.
+func (v *VacancyRepo) Update(ctx context.Context, vacancy *models.Vacancy) error {
+	return conn(ctx, v.db).Save(vacancy).Error
+}
+
+// List возвращает вакансии, при заданном departmentID - только этого отдела,
+// при непустом status - только с этим статусом.
+func (v *VacancyRepo) List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error) {
+	var vacancies []models.Vacancy
+	query := conn(ctx, v.db).Order("id")
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&vacancies).Error
+	return vacancies, err
+}
+
+// ListByDepartment возвращает список вакансий указанного отдела.
+func (v *VacancyRepo) ListByDepartment(ctx context.Context, departmentID uint) ([]models.Vacancy, error) {
+	var vacancies []models.Vacancy
+	err := conn(ctx, v.db).Where("department_id = ?", departmentID).Order("id").Find(&vacancies).Error
+	return vacancies, err
+}
+
+// MoveToDepartment переносит все вакансии отдела departmentID в отдел targetDepartmentID.
+func (v *VacancyRepo) MoveToDepartment(ctx context.Context, departmentID, targetDepartmentID uint) error {
+	return conn(ctx, v.db).Model(&models.Vacancy{}).Where("department_id = ?", departmentID).
+		Update("department_id", targetDepartmentID).Error
+}
+
+// CountOpen возвращает количество открытых вакансий указанного отдела.
+func (v *VacancyRepo) CountOpen(ctx context.Context, departmentID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, v.db).Model(&models.Vacancy{}).
+		Where("department_id = ? AND status = ?", departmentID, models.VacancyOpen).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,100 @@
+// Package repository предоставляет реализацию доступа к данным для работы с базой данных.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"gorm.io/gorm"
+)
+
+// VersionRepo реализует репозиторий версий подразделений и сотрудников с периодами действия.
+// Текущие изменения записываются в версии триггерами, репозиторий добавляет изменения с будущей датой.
+type VersionRepo struct {
+	db *gorm.DB
+}
+
+// NewVersionRepo создаёт новый экземпляр репозитория версий.
+func NewVersionRepo(db *gorm.DB) *VersionRepo {
+	return &VersionRepo{db: db}
+}
+
+// ScheduleDepartment задаёт название и родителя подразделения начиная с даты from.
+func (v *VersionRepo) ScheduleDepartment(ctx context.Context, id uint, from time.Time, name string, parentID *uint) error {
+	return conn(ctx, v.db).Exec(
+		"SELECT department_versions_set(CAST(? AS INT), CAST(? AS DATE), CAST(? AS VARCHAR), CAST(? AS INT))",
+		id, dateParam(from), name, parentID,
+	).Error
+}
+
+// ScheduleEmployee переводит сотрудника в отдел departmentID начиная с даты from.
+// ФИО и должность берутся из версии, действующей на эту дату.
+func (v *VersionRepo) ScheduleEmployee(ctx context.Context, id uint, from time.Time, departmentID uint) error {
+	date := dateParam(from)
+	return conn(ctx, v.db).Exec(`
+		SELECT employee_versions_set(v.employee_id, CAST(? AS DATE), CAST(? AS INT), v.full_name, v.position)
+		FROM employee_versions v
+		WHERE v.employee_id = ? AND v.valid_from <= CAST(? AS DATE) AND (v.valid_to IS NULL OR v.valid_to > CAST(? AS DATE))
+	`, date, departmentID, id, date, date).Error
+}
+
+// Today возвращает текущую дату по часам БД - ту же CURRENT_DATE, с которой триггеры
+// открывают версии текущих изменений.
+func (v *VersionRepo) Today(ctx context.Context) (time.Time, error) {
+	var today time.Time
+	err := conn(ctx, v.db).Raw("SELECT CURRENT_DATE").Scan(&today).Error
+	return today, err
+}
+
+// DueDepartmentVersions возвращает действующие на CURRENT_DATE версии, которые ещё не применены
+// к текущим записям подразделений, в порядке наступления.
+func (v *VersionRepo) DueDepartmentVersions(ctx context.Context) ([]models.DepartmentVersion, error) {
+	var versions []models.DepartmentVersion
+	err := conn(ctx, v.db).Raw(`
+		SELECT v.*
+		FROM department_versions v
+		INNER JOIN departments d ON d.id = v.department_id
+		WHERE v.valid_from <= CURRENT_DATE AND (v.valid_to IS NULL OR v.valid_to > CURRENT_DATE)
+			AND (v.name <> d.name OR v.parent_id IS DISTINCT FROM d.parent_id)
+		ORDER BY v.valid_from, v.id
+	`).Scan(&versions).Error
+	return versions, err
+}
+
+// DueEmployeeVersions возвращает действующие на CURRENT_DATE версии, которые ещё не применены
+// к текущим записям сотрудников, в порядке наступления.
+func (v *VersionRepo) DueEmployeeVersions(ctx context.Context) ([]models.EmployeeVersion, error) {
+	var versions []models.EmployeeVersion
+	err := conn(ctx, v.db).Raw(`
+		SELECT v.*
+		FROM employee_versions v
+		INNER JOIN employees e ON e.id = v.employee_id
+		WHERE v.valid_from <= CURRENT_DATE AND (v.valid_to IS NULL OR v.valid_to > CURRENT_DATE)
+			AND (v.department_id <> e.department_id OR v.full_name <> e.full_name OR v.position <> e.position)
+		ORDER BY v.valid_from, v.id
+	`).Scan(&versions).Error
+	return versions, err
+}
+
+// DiscardDepartmentVersion удаляет версию подразделения, продлевая предыдущую на её период.
+func (v *VersionRepo) DiscardDepartmentVersion(ctx context.Context, version *models.DepartmentVersion) error {
+	db := conn(ctx, v.db)
+	if err := db.Delete(&models.DepartmentVersion{}, version.ID).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.DepartmentVersion{}).
+		Where("department_id = ? AND valid_to = ?", version.DepartmentID, dateParam(version.ValidFrom)).
+		Update("valid_to", version.ValidTo).Error
+}
+
+// DiscardEmployeeVersion удаляет версию сотрудника, продлевая предыдущую на её период.
+func (v *VersionRepo) DiscardEmployeeVersion(ctx context.Context, version *models.EmployeeVersion) error {
+	db := conn(ctx, v.db)
+	if err := db.Delete(&models.EmployeeVersion{}, version.ID).Error; err != nil {
+		return err
+	}
+	return db.Model(&models.EmployeeVersion{}).
+		Where("employee_id = ? AND valid_to = ?", version.EmployeeID, dateParam(version.ValidFrom)).
+		Update("valid_to", version.ValidTo).Error
+}
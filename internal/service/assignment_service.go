@@ -0,0 +1,139 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// AssignmentRepository определяет интерфейс репозитория дополнительных назначений сотрудников.
+type AssignmentRepository interface {
+	Get(ctx context.Context, employeeID, departmentID uint) (*models.EmployeeAssignment, error)
+	Save(ctx context.Context, assignment *models.EmployeeAssignment) error
+	Delete(ctx context.Context, id uint) error
+	ListByEmployee(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error)
+	ListMembers(ctx context.Context, departmentID uint) ([]models.Employee, error)
+}
+
+// AssignmentService определяет интерфейс дополнительных назначений сотрудников,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type AssignmentService interface {
+	List(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error)
+	Set(ctx context.Context, employeeID, departmentID uint, role string, allocation int) (*models.EmployeeAssignment, error)
+	Delete(ctx context.Context, employeeID, departmentID uint) error
+}
+
+// AssignmentSvc реализует дополнительные назначения сотрудников в подразделения
+// (матричная структура): сотрудник остаётся в основном подразделении и работает
+// в других с ролью и долей занятости. Сумма долей назначений не больше 100%.
+type AssignmentSvc struct {
+	assignmentRepo AssignmentRepository
+	empRepo        EmployeeRepository
+	deptRepo       DepartmentRepository
+	tx             Transactor
+}
+
+// NewAssignmentService создаёт новый экземпляр сервиса дополнительных назначений.
+func NewAssignmentService(assignmentRepo AssignmentRepository, empRepo EmployeeRepository, deptRepo DepartmentRepository,
+	tx Transactor) *AssignmentSvc {
+	return &AssignmentSvc{assignmentRepo: assignmentRepo, empRepo: empRepo, deptRepo: deptRepo, tx: tx}
+}
+
+// List возвращает дополнительные назначения сотрудника.
+func (s *AssignmentSvc) List(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error) {
+	if _, err := s.employee(ctx, employeeID); err != nil {
+		return nil, err
+	}
+	return s.assignmentRepo.ListByEmployee(ctx, employeeID)
+}
+
+// Set назначает сотрудника в подразделение с ролью и долей занятости allocation (1-100%)
+// или заменяет роль и долю существующего назначения. Основное подразделение сотрудника
+// назначением быть не может; уволенного сотрудника назначить нельзя.
+func (s *AssignmentSvc) Set(ctx context.Context, employeeID, departmentID uint, role string, allocation int) (*models.EmployeeAssignment, error) {
+	role = strings.TrimSpace(role)
+	if len(role) > 100 {
+		return nil, fmt.Errorf("%w: role must be max 100 characters", apperrors.ErrInvalidAssignment)
+	}
+	if allocation < 1 || allocation > 100 {
+		return nil, fmt.Errorf("%w: allocation must be between 1 and 100", apperrors.ErrInvalidAssignment)
+	}
+
+	var assignment *models.EmployeeAssignment
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		emp, err := s.employee(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		if emp.Status == models.EmployeeTerminated {
+			return fmt.Errorf("%w: %d", apperrors.ErrEmployeeTerminated, employeeID)
+		}
+		if uint(emp.DepartmentID) == departmentID {
+			return fmt.Errorf("%w: department %d is the employee's home department", apperrors.ErrInvalidAssignment, departmentID)
+		}
+		dept, err := s.deptRepo.GetByID(ctx, departmentID)
+		if err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+
+		existing, err := s.assignmentRepo.ListByEmployee(ctx, employeeID)
+		if err != nil {
+			return err
+		}
+		total := allocation
+		for i := range existing {
+			if uint(existing[i].DepartmentID) == departmentID {
+				assignment = &existing[i]
+				continue
+			}
+			total += existing[i].Allocation
+		}
+		if total > 100 {
+			return fmt.Errorf("%w: total allocation would be %d%%", apperrors.ErrAllocationExceeded, total)
+		}
+
+		if assignment == nil {
+			assignment = &models.EmployeeAssignment{EmployeeID: int(employeeID), DepartmentID: int(departmentID)}
+		}
+		assignment.Role = role
+		assignment.Allocation = allocation
+		return s.assignmentRepo.Save(ctx, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// Delete снимает назначение сотрудника в подразделение.
+func (s *AssignmentSvc) Delete(ctx context.Context, employeeID, departmentID uint) error {
+	return s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		assignment, err := s.assignmentRepo.Get(ctx, employeeID, departmentID)
+		if err != nil {
+			return err
+		}
+		if assignment == nil {
+			return fmt.Errorf("%w: employee %d in department %d", apperrors.ErrAssignmentNotFound, employeeID, departmentID)
+		}
+		return s.assignmentRepo.Delete(ctx, uint(assignment.ID))
+	})
+}
+
+// employee возвращает сотрудника по идентификатору.
+func (s *AssignmentSvc) employee(ctx context.Context, id uint) (*models.Employee, error) {
+	emp, err := s.empRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrEmployeeNotFound, id)
+	}
+	return emp, nil
+}
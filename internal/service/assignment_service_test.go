@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAssignmentRepo - мок для репозитория дополнительных назначений
+type MockAssignmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentRepo) Get(ctx context.Context, employeeID, departmentID uint) (*models.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeID, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmployeeAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) Save(ctx context.Context, assignment *models.EmployeeAssignment) error {
+	args := m.Called(ctx, assignment)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) ListByEmployee(ctx context.Context, employeeID uint) ([]models.EmployeeAssignment, error) {
+	args := m.Called(ctx, employeeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EmployeeAssignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) ListMembers(ctx context.Context, departmentID uint) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+// noAssignments возвращает репозиторий назначений, в котором у подразделений нет дополнительных сотрудников.
+func noAssignments() *MockAssignmentRepo {
+	repo := new(MockAssignmentRepo)
+	repo.On("ListMembers", mock.Anything, mock.Anything).Return([]models.Employee{}, nil).Maybe()
+	return repo
+}
+
+func setupAssignmentService(t *testing.T) (*AssignmentSvc, *MockAssignmentRepo, *MockEmployeeRepo, *MockDepartmentRepo) {
+	mockAssignmentRepo := new(MockAssignmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewAssignmentService(mockAssignmentRepo, mockEmpRepo, mockDeptRepo, fakeTx{})
+	return service, mockAssignmentRepo, mockEmpRepo, mockDeptRepo
+}
+
+func TestAssignmentSet_CreatesAndReplaces(t *testing.T) {
+	ctx := context.Background()
+	service, mockAssignmentRepo, mockEmpRepo, mockDeptRepo := setupAssignmentService(t)
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, DepartmentID: 10, Status: models.EmployeeActive}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(20)).Return(&models.Department{ID: 20}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(30)).Return(&models.Department{ID: 30}, nil)
+	mockAssignmentRepo.On("ListByEmployee", ctx, uint(1)).Return([]models.EmployeeAssignment{
+		{ID: 5, EmployeeID: 1, DepartmentID: 20, Role: "member", Allocation: 40},
+	}, nil).Once()
+	mockAssignmentRepo.On("ListByEmployee", ctx, uint(1)).Return([]models.EmployeeAssignment{
+		{ID: 5, EmployeeID: 1, DepartmentID: 20, Role: "lead", Allocation: 30},
+	}, nil).Once()
+	mockAssignmentRepo.On("Save", ctx, mock.Anything).Return(nil)
+
+	// Замена доли существующего назначения не складывается с прежней долей.
+	assignment, err := service.Set(ctx, 1, 20, " lead ", 80)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, assignment.ID)
+	assert.Equal(t, "lead", assignment.Role)
+	assert.Equal(t, 80, assignment.Allocation)
+
+	assignment, err = service.Set(ctx, 1, 30, "guild", 70)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, assignment.ID)
+	assert.Equal(t, 30, assignment.DepartmentID)
+}
+
+func TestAssignmentSet_AllocationExceeded(t *testing.T) {
+	ctx := context.Background()
+	service, mockAssignmentRepo, mockEmpRepo, mockDeptRepo := setupAssignmentService(t)
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, DepartmentID: 10, Status: models.EmployeeActive}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(30)).Return(&models.Department{ID: 30}, nil)
+	mockAssignmentRepo.On("ListByEmployee", ctx, uint(1)).Return([]models.EmployeeAssignment{
+		{ID: 5, EmployeeID: 1, DepartmentID: 20, Allocation: 50},
+		{ID: 6, EmployeeID: 1, DepartmentID: 21, Allocation: 30},
+	}, nil)
+
+	_, err := service.Set(ctx, 1, 30, "guild", 25)
+
+	assert.ErrorIs(t, err, apperrors.ErrAllocationExceeded)
+	mockAssignmentRepo.AssertNotCalled(t, "Save", ctx, mock.Anything)
+}
+
+func TestAssignmentSet_Validation(t *testing.T) {
+	ctx := context.Background()
+	service, _, mockEmpRepo, _ := setupAssignmentService(t)
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, DepartmentID: 10, Status: models.EmployeeActive}, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(2)).Return(&models.Employee{ID: 2, DepartmentID: 10, Status: models.EmployeeTerminated}, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(3)).Return(nil, nil)
+
+	_, err := service.Set(ctx, 1, 20, "member", 0)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+	_, err = service.Set(ctx, 1, 20, "member", 101)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+	_, err = service.Set(ctx, 1, 10, "member", 20)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAssignment)
+	_, err = service.Set(ctx, 2, 20, "member", 20)
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeTerminated)
+	_, err = service.Set(ctx, 3, 20, "member", 20)
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeNotFound)
+}
+
+func TestAssignmentDelete(t *testing.T) {
+	ctx := context.Background()
+	service, mockAssignmentRepo, _, _ := setupAssignmentService(t)
+	mockAssignmentRepo.On("Get", ctx, uint(1), uint(20)).Return(&models.EmployeeAssignment{ID: 5}, nil)
+	mockAssignmentRepo.On("Get", ctx, uint(1), uint(30)).Return(nil, nil)
+	mockAssignmentRepo.On("Delete", ctx, uint(5)).Return(nil)
+
+	assert.NoError(t, service.Delete(ctx, 1, 20))
+	assert.ErrorIs(t, service.Delete(ctx, 1, 30), apperrors.ErrAssignmentNotFound)
+}
+
+func TestGetByID_IncludesSecondaryMembers(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockAssignmentRepo := new(MockAssignmentRepo)
+	service := NewDepartmentService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), mockAssignmentRepo, new(MockHistoryRepo), fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Platform guild"}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", (*time.Time)(nil)).
+		Return([]models.Employee{{ID: 1, DepartmentID: 1}}, nil)
+	mockAssignmentRepo.On("ListMembers", ctx, uint(1)).Return([]models.Employee{
+		{ID: 2, DepartmentID: 7, Secondary: true, Assignment: &models.EmployeeAssignment{EmployeeID: 2, DepartmentID: 1, Allocation: 20}},
+	}, nil)
+
+	dept, err := service.GetByID(ctx, 1, 1, true, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, dept.Employees, 2)
+	assert.False(t, dept.Employees[0].Secondary)
+	assert.True(t, dept.Employees[1].Secondary)
+	assert.Equal(t, 20, dept.Employees[1].Assignment.Allocation)
+
+	// Назначения не версионируются: на прошедшую дату возвращаются только основные сотрудники.
+	asOf := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockDeptRepo.On("GetAsOf", ctx, uint(1), asOf).Return(&models.Department{ID: 1}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", &asOf).Return([]models.Employee{{ID: 1, DepartmentID: 1}}, nil)
+
+	dept, err = service.GetByID(ctx, 1, 1, true, &asOf)
+
+	assert.NoError(t, err)
+	assert.Len(t, dept.Employees, 1)
+	mockAssignmentRepo.AssertNumberOfCalls(t, "ListMembers", 1)
+}
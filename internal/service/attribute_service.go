@@ -0,0 +1,285 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// attributeNamePattern - имена атрибутов: строчные латинские буквы, цифры и подчёркивания.
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// AttributeRepository определяет интерфейс репозитория определений атрибутов, необходимый для работы сервиса.
+type AttributeRepository interface {
+	Create(ctx context.Context, def *models.AttributeDefinition) error
+	GetByID(ctx context.Context, id uint) (*models.AttributeDefinition, error)
+	GetByName(ctx context.Context, entity, name string) (*models.AttributeDefinition, error)
+	ListByEntity(ctx context.Context, entity string) ([]models.AttributeDefinition, error)
+	Delete(ctx context.Context, def *models.AttributeDefinition) error
+}
+
+// AttributeSchema проверяет значения пользовательских атрибутов по их определениям.
+type AttributeSchema interface {
+	// Validate проверяет атрибуты сущности и возвращает их с приведёнными значениями.
+	Validate(ctx context.Context, entity string, attrs models.Attributes) (models.Attributes, error)
+	// ParseFilter преобразует строковые значения фильтра из запроса в типизированные атрибуты.
+	ParseFilter(ctx context.Context, entity string, filter map[string]string) (models.Attributes, error)
+}
+
+// AttributeService определяет интерфейс для управления определениями атрибутов,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type AttributeService interface {
+	CreateDefinition(ctx context.Context, def models.AttributeDefinition) (*models.AttributeDefinition, error)
+	ListDefinitions(ctx context.Context, entity string) ([]models.AttributeDefinition, error)
+	DeleteDefinition(ctx context.Context, id uint) error
+}
+
+// AttrService реализует управление определениями атрибутов и проверку их значений.
+type AttrService struct {
+	repo AttributeRepository
+	tx   Transactor
+}
+
+// NewAttributeService создаёт новый экземпляр сервиса атрибутов.
+func NewAttributeService(repo AttributeRepository, tx Transactor) *AttrService {
+	return &AttrService{repo: repo, tx: tx}
+}
+
+// CreateDefinition проверяет и сохраняет новое определение атрибута.
+func (s *AttrService) CreateDefinition(ctx context.Context, def models.AttributeDefinition) (*models.AttributeDefinition, error) {
+	if err := validateDefinition(&def); err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetByName(ctx, def.Entity, def.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperrors.ErrAttributeDefinitionConflict
+	}
+	if err := s.repo.Create(ctx, &def); err != nil {
+		return nil, fmt.Errorf("failed to create attribute definition: %w", err)
+	}
+	return &def, nil
+}
+
+// ListDefinitions возвращает определения атрибутов сущности; пустая entity возвращает все.
+func (s *AttrService) ListDefinitions(ctx context.Context, entity string) ([]models.AttributeDefinition, error) {
+	if entity != "" && !isAttributeEntity(entity) {
+		return nil, fmt.Errorf("%w: unknown entity %q", apperrors.ErrInvalidAttributeDefinition, entity)
+	}
+	return s.repo.ListByEntity(ctx, entity)
+}
+
+// DeleteDefinition удаляет определение атрибута вместе с его значениями у всех записей.
+func (s *AttrService) DeleteDefinition(ctx context.Context, id uint) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		def, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if def == nil {
+			return apperrors.ErrAttributeDefinitionNotFound
+		}
+		return s.repo.Delete(ctx, def)
+	})
+}
+
+// Validate проверяет, что все атрибуты определены, имеют допустимые значения и что
+// заданы все обязательные атрибуты. Значения nil отбрасываются.
+func (s *AttrService) Validate(ctx context.Context, entity string, attrs models.Attributes) (models.Attributes, error) {
+	defs, err := s.definitionsByName(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	clean := make(models.Attributes, len(attrs))
+	for name, value := range attrs {
+		def, ok := defs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q", apperrors.ErrInvalidAttributes, name)
+		}
+		if value == nil {
+			continue
+		}
+		v, err := coerceAttribute(def, value)
+		if err != nil {
+			return nil, err
+		}
+		clean[name] = v
+	}
+	for name, def := range defs {
+		if _, ok := clean[name]; def.Required && !ok {
+			return nil, fmt.Errorf("%w: attribute %q is required", apperrors.ErrInvalidAttributes, name)
+		}
+	}
+	return clean, nil
+}
+
+// ParseFilter преобразует строковые значения фильтра в значения типов, заданных определениями.
+func (s *AttrService) ParseFilter(ctx context.Context, entity string, filter map[string]string) (models.Attributes, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	defs, err := s.definitionsByName(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(models.Attributes, len(filter))
+	for name, raw := range filter {
+		def, ok := defs[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q", apperrors.ErrInvalidAttributes, name)
+		}
+		var value any = raw
+		switch def.Type {
+		case models.AttributeTypeNumber:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: attribute %q must be a number", apperrors.ErrInvalidAttributes, name)
+			}
+			value = n
+		case models.AttributeTypeBoolean:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%w: attribute %q must be a boolean", apperrors.ErrInvalidAttributes, name)
+			}
+			value = b
+		}
+		v, err := coerceAttribute(def, value)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = v
+	}
+	return parsed, nil
+}
+
+// definitionsByName возвращает определения атрибутов сущности, проиндексированные по имени.
+func (s *AttrService) definitionsByName(ctx context.Context, entity string) (map[string]models.AttributeDefinition, error) {
+	defs, err := s.repo.ListByEntity(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]models.AttributeDefinition, len(defs))
+	for _, def := range defs {
+		byName[def.Name] = def
+	}
+	return byName, nil
+}
+
+// coerceAttribute проверяет значение атрибута на соответствие определению.
+func coerceAttribute(def models.AttributeDefinition, value any) (any, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: attribute %q %s", apperrors.ErrInvalidAttributes, def.Name, reason)
+	}
+
+	switch def.Type {
+	case models.AttributeTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+		return nil, invalid("must be a number")
+	case models.AttributeTypeBoolean:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+		return nil, invalid("must be a boolean")
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, invalid("must be a string")
+	}
+	switch def.Type {
+	case models.AttributeTypeDate:
+		if _, err := time.Parse(time.DateOnly, s); err != nil {
+			return nil, invalid("must be a date in YYYY-MM-DD format")
+		}
+	case models.AttributeTypeEnum:
+		if !slices.Contains(def.EnumValues, s) {
+			return nil, invalid("must be one of the enum values")
+		}
+	case models.AttributeTypeString:
+		if def.Pattern != "" {
+			if matched, _ := regexp.MatchString(def.Pattern, s); !matched {
+				return nil, invalid("does not match the pattern")
+			}
+		}
+	}
+	return s, nil
+}
+
+// mergeAttributes накладывает изменения на текущие атрибуты; значение nil удаляет атрибут.
+func mergeAttributes(current, patch models.Attributes) models.Attributes {
+	merged := make(models.Attributes, len(current)+len(patch))
+	for name, value := range current {
+		merged[name] = value
+	}
+	for name, value := range patch {
+		if value == nil {
+			delete(merged, name)
+			continue
+		}
+		merged[name] = value
+	}
+	return merged
+}
+
+// validateDefinition проверяет определение атрибута.
+func validateDefinition(def *models.AttributeDefinition) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s", apperrors.ErrInvalidAttributeDefinition, reason)
+	}
+
+	if !isAttributeEntity(def.Entity) {
+		return invalid("entity must be 'department' or 'employee'")
+	}
+	if !attributeNamePattern.MatchString(def.Name) {
+		return invalid("name must start with a lowercase latin letter and contain only lowercase latin letters, digits and underscores (max 50)")
+	}
+	switch def.Type {
+	case models.AttributeTypeString, models.AttributeTypeNumber, models.AttributeTypeBoolean, models.AttributeTypeDate:
+		if len(def.EnumValues) > 0 {
+			return invalid("enum_values are allowed only for enum attributes")
+		}
+	case models.AttributeTypeEnum:
+		if len(def.EnumValues) == 0 {
+			return invalid("enum attributes require enum_values")
+		}
+		seen := make(map[string]bool, len(def.EnumValues))
+		for _, v := range def.EnumValues {
+			if v == "" || seen[v] {
+				return invalid("enum_values must be non-empty and unique")
+			}
+			seen[v] = true
+		}
+	default:
+		return invalid("type must be one of string, number, boolean, date, enum")
+	}
+	if def.Pattern != "" {
+		if def.Type != models.AttributeTypeString {
+			return invalid("pattern is allowed only for string attributes")
+		}
+		if _, err := regexp.Compile(def.Pattern); err != nil {
+			return invalid("pattern is not a valid regular expression")
+		}
+	}
+	return nil
+}
+
+// isAttributeEntity сообщает, поддерживает ли сущность пользовательские атрибуты.
+func isAttributeEntity(entity string) bool {
+	return entity == models.EntityDepartment || entity == models.EntityEmployee
+}
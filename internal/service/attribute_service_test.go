@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAttributeRepo - мок для репозитория определений атрибутов
+type MockAttributeRepo struct {
+	mock.Mock
+}
+
+func (m *MockAttributeRepo) Create(ctx context.Context, def *models.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepo) GetByID(ctx context.Context, id uint) (*models.AttributeDefinition, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepo) GetByName(ctx context.Context, entity, name string) (*models.AttributeDefinition, error) {
+	args := m.Called(ctx, entity, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepo) ListByEntity(ctx context.Context, entity string) ([]models.AttributeDefinition, error) {
+	args := m.Called(ctx, entity)
+	return args.Get(0).([]models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepo) Delete(ctx context.Context, def *models.AttributeDefinition) error {
+	args := m.Called(ctx, def)
+	return args.Error(0)
+}
+
+// departmentDefinitions - набор определений атрибутов подразделений для тестов
+var departmentDefinitions = []models.AttributeDefinition{
+	{ID: 1, Entity: models.EntityDepartment, Name: "cost_center", Type: models.AttributeTypeString, Required: true, Pattern: `^CC-\d+$`},
+	{ID: 2, Entity: models.EntityDepartment, Name: "headcount", Type: models.AttributeTypeNumber},
+	{ID: 3, Entity: models.EntityDepartment, Name: "remote", Type: models.AttributeTypeBoolean},
+	{ID: 4, Entity: models.EntityDepartment, Name: "opened_on", Type: models.AttributeTypeDate},
+	{ID: 5, Entity: models.EntityDepartment, Name: "tier", Type: models.AttributeTypeEnum, EnumValues: models.StringList{"gold", "silver"}},
+}
+
+func setupAttributeService(t *testing.T) (*AttrService, *MockAttributeRepo) {
+	mockRepo := new(MockAttributeRepo)
+	return NewAttributeService(mockRepo, fakeTx{}), mockRepo
+}
+
+func TestCreateDefinition_Success(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByName", ctx, models.EntityEmployee, "grade").Return(nil, nil)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*models.AttributeDefinition")).Return(nil)
+
+	def, err := service.CreateDefinition(ctx, models.AttributeDefinition{
+		Entity:     models.EntityEmployee,
+		Name:       "grade",
+		Type:       models.AttributeTypeEnum,
+		EnumValues: models.StringList{"junior", "senior"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "grade", def.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateDefinition_Invalid(t *testing.T) {
+	service, _ := setupAttributeService(t)
+	ctx := context.Background()
+
+	cases := []models.AttributeDefinition{
+		{Entity: "vacancy", Name: "grade", Type: models.AttributeTypeString},
+		{Entity: models.EntityEmployee, Name: "Grade", Type: models.AttributeTypeString},
+		{Entity: models.EntityEmployee, Name: "grade", Type: "money"},
+		{Entity: models.EntityEmployee, Name: "grade", Type: models.AttributeTypeEnum},
+		{Entity: models.EntityEmployee, Name: "grade", Type: models.AttributeTypeEnum, EnumValues: models.StringList{"a", "a"}},
+		{Entity: models.EntityEmployee, Name: "grade", Type: models.AttributeTypeNumber, Pattern: `^\d+$`},
+		{Entity: models.EntityEmployee, Name: "grade", Type: models.AttributeTypeString, Pattern: `(`},
+	}
+	for _, def := range cases {
+		_, err := service.CreateDefinition(ctx, def)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidAttributeDefinition, "definition %+v", def)
+	}
+}
+
+func TestCreateDefinition_Conflict(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByName", ctx, models.EntityDepartment, "tier").Return(&departmentDefinitions[4], nil)
+
+	def, err := service.CreateDefinition(ctx, models.AttributeDefinition{
+		Entity: models.EntityDepartment, Name: "tier", Type: models.AttributeTypeString,
+	})
+
+	assert.Nil(t, def)
+	assert.ErrorIs(t, err, apperrors.ErrAttributeDefinitionConflict)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteDefinition_NotFound(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("GetByID", ctx, uint(99)).Return(nil, nil)
+
+	err := service.DeleteDefinition(ctx, 99)
+
+	assert.ErrorIs(t, err, apperrors.ErrAttributeDefinitionNotFound)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestValidateAttributes_Success(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListByEntity", ctx, models.EntityDepartment).Return(departmentDefinitions, nil)
+
+	attrs, err := service.Validate(ctx, models.EntityDepartment, models.Attributes{
+		"cost_center": "CC-42",
+		"headcount":   float64(12),
+		"remote":      true,
+		"opened_on":   "2024-03-01",
+		"tier":        "gold",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, attrs, 5)
+	assert.Equal(t, float64(12), attrs["headcount"])
+}
+
+func TestValidateAttributes_Invalid(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListByEntity", ctx, models.EntityDepartment).Return(departmentDefinitions, nil)
+
+	cases := []models.Attributes{
+		{},
+		{"cost_center": "CC-1", "unknown": "x"},
+		{"cost_center": "42"},
+		{"cost_center": "CC-1", "headcount": "12"},
+		{"cost_center": "CC-1", "remote": "yes"},
+		{"cost_center": "CC-1", "opened_on": "01.03.2024"},
+		{"cost_center": "CC-1", "tier": "bronze"},
+		{"cost_center": nil},
+	}
+	for _, attrs := range cases {
+		_, err := service.Validate(ctx, models.EntityDepartment, attrs)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidAttributes, "attributes %v", attrs)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	service, mockRepo := setupAttributeService(t)
+	ctx := context.Background()
+
+	mockRepo.On("ListByEntity", ctx, models.EntityDepartment).Return(departmentDefinitions, nil)
+
+	filter, err := service.ParseFilter(ctx, models.EntityDepartment, map[string]string{
+		"headcount": "12",
+		"remote":    "true",
+		"tier":      "gold",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.Attributes{"headcount": float64(12), "remote": true, "tier": "gold"}, filter)
+
+	_, err = service.ParseFilter(ctx, models.EntityDepartment, map[string]string{"headcount": "many"})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidAttributes)
+}
+
+func TestMergeAttributes(t *testing.T) {
+	current := models.Attributes{"tier": "gold", "remote": true}
+
+	merged := mergeAttributes(current, models.Attributes{"tier": "silver", "remote": nil, "headcount": float64(3)})
+
+	assert.Equal(t, models.Attributes{"tier": "silver", "headcount": float64(3)}, merged)
+	assert.Equal(t, "gold", current["tier"])
+}
@@ -0,0 +1,44 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+)
+
+// Режимы согласования переносов и удалений подразделений.
+const (
+	// ChangeApprovalRequired - переносы и удаления выполняются только по согласованной заявке.
+	ChangeApprovalRequired = "required"
+	// ChangeApprovalOptional - заявки не обязательны, изменения выполняются напрямую.
+	ChangeApprovalOptional = "optional"
+)
+
+// approvedKey и sandboxKey - ключи контекста, которыми помечаются выполнение согласованной
+// заявки и запрос к данным песочницы.
+type (
+	approvedKey struct{}
+	sandboxKey  struct{}
+)
+
+// withApproval помечает контекст как выполнение согласованной заявки.
+func withApproval(ctx context.Context) context.Context {
+	return context.WithValue(ctx, approvedKey{}, true)
+}
+
+// withinSandbox помечает контекст как запрос к данным песочницы: её изменения не затрагивают
+// рабочую структуру и проверяются при переносе в неё.
+func withinSandbox(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sandboxKey{}, true)
+}
+
+// requireApproval возвращает ErrApprovalRequired, если в режиме mode операция operation
+// над подразделением id выполняется в рабочей структуре не по согласованной заявке.
+func requireApproval(ctx context.Context, mode, operation string, id uint) error {
+	if mode != ChangeApprovalRequired || ctx.Value(approvedKey{}) != nil || ctx.Value(sandboxKey{}) != nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s of department %d", apperrors.ErrApprovalRequired, operation, id)
+}
@@ -0,0 +1,324 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// ChangeRequestRepository определяет интерфейс репозитория заявок на структурные изменения.
+type ChangeRequestRepository interface {
+	Create(ctx context.Context, req *models.ChangeRequest) error
+	GetByID(ctx context.Context, id uint) (*models.ChangeRequest, error)
+	Lock(ctx context.Context, id uint) (*models.ChangeRequest, error)
+	List(ctx context.Context, status string) ([]models.ChangeRequest, error)
+	Update(ctx context.Context, req *models.ChangeRequest) error
+}
+
+// ChangeRequestService определяет интерфейс согласования переносов и удалений подразделений,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type ChangeRequestService interface {
+	Submit(ctx context.Context, req models.ChangeRequest) (*models.ChangeRequest, error)
+	Get(ctx context.Context, id uint) (*models.ChangeRequest, error)
+	List(ctx context.Context, status string) ([]models.ChangeRequest, error)
+	Approve(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error)
+	Reject(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error)
+}
+
+// ChangeRequestSvc реализует заявки на перенос и удаление подразделений: операция
+// выполняется методами DepService только после согласования руководителем,
+// определённым правилом заявки.
+type ChangeRequestSvc struct {
+	requestRepo ChangeRequestRepository
+	deptRepo    DepartmentRepository
+	empRepo     EmployeeRepository
+	historyRepo HistoryRepository
+	departments DepartmentService
+	types       DepartmentTypeRules
+	tx          Transactor
+	now         func() time.Time
+}
+
+// NewChangeRequestService создаёт новый экземпляр сервиса заявок.
+func NewChangeRequestService(requestRepo ChangeRequestRepository, deptRepo DepartmentRepository, empRepo EmployeeRepository,
+	historyRepo HistoryRepository, departments DepartmentService, types DepartmentTypeRules, tx Transactor) *ChangeRequestSvc {
+	return &ChangeRequestSvc{requestRepo: requestRepo, deptRepo: deptRepo, empRepo: empRepo, historyRepo: historyRepo,
+		departments: departments, types: types, tx: tx, now: time.Now}
+}
+
+// Submit проверяет заявку на текущей структуре, определяет согласующего и сохраняет
+// заявку в статусе pending. Сама операция не выполняется.
+func (s *ChangeRequestSvc) Submit(ctx context.Context, req models.ChangeRequest) (*models.ChangeRequest, error) {
+	if req.ApproverRule == "" {
+		req.ApproverRule = models.ApproverCommonAncestorHead
+	}
+	if err := validateChangeRequest(req); err != nil {
+		return nil, err
+	}
+
+	requester, err := s.empRepo.GetByID(ctx, uint(req.RequestedBy))
+	if err != nil {
+		return nil, err
+	}
+	if requester == nil {
+		return nil, fmt.Errorf("%w: requester", apperrors.ErrEmployeeNotFound)
+	}
+	if err := s.check(ctx, &req); err != nil {
+		return nil, err
+	}
+	approver, err := s.resolveApprover(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ID = 0
+	req.ApproverID = approver
+	req.Status = models.ChangeRequestPending
+	req.DecidedBy = nil
+	req.DecidedAt = nil
+	req.Comment = ""
+	if err := s.requestRepo.Create(ctx, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Get возвращает заявку по идентификатору.
+func (s *ChangeRequestSvc) Get(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	req, err := s.requestRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, apperrors.ErrChangeRequestNotFound
+	}
+	return req, nil
+}
+
+// List возвращает заявки, необязательно отфильтрованные по статусу.
+func (s *ChangeRequestSvc) List(ctx context.Context, status string) ([]models.ChangeRequest, error) {
+	switch status {
+	case "", models.ChangeRequestPending, models.ChangeRequestApproved, models.ChangeRequestRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", apperrors.ErrInvalidChangeRequest, status)
+	}
+	return s.requestRepo.List(ctx, status)
+}
+
+// Approve согласует заявку и выполняет её операцию в той же транзакции.
+// Перед выполнением заявка заново проверяется на текущей структуре: при конфликте
+// возвращается ошибка, а заявка остаётся ожидающей.
+func (s *ChangeRequestSvc) Approve(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error) {
+	return s.decide(ctx, id, approverID, comment, func(ctx context.Context, req *models.ChangeRequest) error {
+		if err := s.check(ctx, req); err != nil {
+			return err
+		}
+		ctx = withApproval(ctx)
+		switch req.Operation {
+		case models.ChangeOpMove:
+			parentID := req.Payload.ParentID
+			if parentID == nil {
+				parentID = new(uint)
+			}
+			if _, err := s.departments.Update(ctx, uint(req.DepartmentID), nil, parentID, nil, nil); err != nil {
+				return err
+			}
+		case models.ChangeOpDelete:
+			if err := s.departments.Delete(ctx, uint(req.DepartmentID), req.Payload.Mode, req.Payload.ReassignTo); err != nil {
+				return err
+			}
+		}
+		req.Status = models.ChangeRequestApproved
+		return nil
+	})
+}
+
+// Reject отклоняет заявку без выполнения операции.
+func (s *ChangeRequestSvc) Reject(ctx context.Context, id, approverID uint, comment string) (*models.ChangeRequest, error) {
+	return s.decide(ctx, id, approverID, comment, func(ctx context.Context, req *models.ChangeRequest) error {
+		req.Status = models.ChangeRequestRejected
+		return nil
+	})
+}
+
+// decide проверяет, что заявка ожидает решения, а approverID - её текущий согласующий,
+// применяет решение apply и сохраняет заявку с записью в журнал подразделения.
+func (s *ChangeRequestSvc) decide(ctx context.Context, id, approverID uint, comment string,
+	apply func(ctx context.Context, req *models.ChangeRequest) error) (*models.ChangeRequest, error) {
+	var result *models.ChangeRequest
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		req, err := s.requestRepo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return apperrors.ErrChangeRequestNotFound
+		}
+		if req.Status != models.ChangeRequestPending {
+			return fmt.Errorf("%w: status is %s", apperrors.ErrChangeRequestNotPending, req.Status)
+		}
+
+		// Руководители могли смениться с момента подачи заявки.
+		approver, err := s.resolveApprover(ctx, req)
+		if err != nil {
+			return err
+		}
+		if *approver != int(approverID) {
+			return apperrors.ErrNotApprover
+		}
+		if err := apply(ctx, req); err != nil {
+			return err
+		}
+
+		now := s.now()
+		decidedBy := int(approverID)
+		req.ApproverID = approver
+		req.DecidedBy = &decidedBy
+		req.DecidedAt = &now
+		req.Comment = comment
+		if err := s.requestRepo.Update(ctx, req); err != nil {
+			return err
+		}
+		result = req
+		return writeHistory(ctx, s.historyRepo, uint(req.DepartmentID), "change_request_"+req.Status, map[string]any{
+			"change_request_id": req.ID,
+			"operation":         req.Operation,
+			"payload":           req.Payload,
+			"requested_by":      req.RequestedBy,
+			"decided_by":        decidedBy,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// check проверяет, что операцию заявки можно выполнить на текущей структуре.
+func (s *ChangeRequestSvc) check(ctx context.Context, req *models.ChangeRequest) error {
+	dept, err := s.deptRepo.GetByID(ctx, uint(req.DepartmentID))
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return apperrors.ErrDepartmentNotFound
+	}
+
+	switch req.Operation {
+	case models.ChangeOpMove:
+		return checkPlacement(ctx, s.deptRepo, s.types, dept, dept.Name, s.newParent(req), time.Time{})
+	case models.ChangeOpDelete:
+		if req.Payload.Mode != "reassign" {
+			return nil
+		}
+		target, err := s.deptRepo.GetByID(ctx, *req.Payload.ReassignTo)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return apperrors.ErrTargetDepartmentNotFound
+		}
+		id := uint(dept.ID)
+		children, err := s.deptRepo.GetChildren(ctx, &id)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			return apperrors.ErrReassignWithChildren
+		}
+	}
+	return nil
+}
+
+// resolveApprover возвращает сотрудника, который по правилу заявки должен её согласовать:
+// руководителя выбранного подразделения или, если руководитель не назначен, ближайшего
+// вышестоящего руководителя.
+func (s *ChangeRequestSvc) resolveApprover(ctx context.Context, req *models.ChangeRequest) (*int, error) {
+	chain, err := s.chain(ctx, uint(req.DepartmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ApproverRule == models.ApproverCommonAncestorHead {
+		// Общий предок старого и нового положения: для удаления - родитель подразделения,
+		// для переноса - общая часть цепочек текущего родителя и нового родителя.
+		chain = chain[:len(chain)-1]
+		if req.Operation == models.ChangeOpMove {
+			var target []models.Department
+			if parentID := s.newParent(req); parentID != nil {
+				if target, err = s.chain(ctx, *parentID); err != nil {
+					return nil, err
+				}
+			}
+			common := 0
+			for common < len(chain) && common < len(target) && chain[common].ID == target[common].ID {
+				common++
+			}
+			chain = chain[:common]
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].HeadID != nil {
+			head := int(*chain[i].HeadID)
+			return &head, nil
+		}
+	}
+	return nil, apperrors.ErrNoApprover
+}
+
+// chain возвращает подразделение вместе с предками от корня к самому подразделению.
+func (s *ChangeRequestSvc) chain(ctx context.Context, id uint) ([]models.Department, error) {
+	dept, err := s.deptRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+	ancestors, err := s.deptRepo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append(ancestors, *dept), nil
+}
+
+// newParent возвращает нового родителя из заявки на перенос; nil - перенос в корень.
+func (s *ChangeRequestSvc) newParent(req *models.ChangeRequest) *uint {
+	if req.Payload.ParentID == nil || *req.Payload.ParentID == 0 {
+		return nil
+	}
+	return req.Payload.ParentID
+}
+
+// validateChangeRequest проверяет операцию, её параметры и правило выбора согласующего.
+func validateChangeRequest(req models.ChangeRequest) error {
+	if req.DepartmentID <= 0 || req.RequestedBy <= 0 {
+		return fmt.Errorf("%w: department_id and requested_by are required", apperrors.ErrInvalidChangeRequest)
+	}
+	switch req.Operation {
+	case models.ChangeOpMove:
+	case models.ChangeOpDelete:
+		switch req.Payload.Mode {
+		case "cascade":
+		case "reassign":
+			if req.Payload.ReassignTo == nil || *req.Payload.ReassignTo == uint(req.DepartmentID) {
+				return fmt.Errorf("%w: reassign_to_department_id must be another department", apperrors.ErrInvalidChangeRequest)
+			}
+		default:
+			return fmt.Errorf("%w: mode must be cascade or reassign", apperrors.ErrInvalidChangeRequest)
+		}
+	default:
+		return fmt.Errorf("%w: operation must be move or delete", apperrors.ErrInvalidChangeRequest)
+	}
+	switch req.ApproverRule {
+	case models.ApproverCommonAncestorHead, models.ApproverDepartmentHead:
+	default:
+		return fmt.Errorf("%w: unknown approver_rule %q", apperrors.ErrInvalidChangeRequest, req.ApproverRule)
+	}
+	return nil
+}
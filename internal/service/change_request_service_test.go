@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockChangeRequestRepo - мок для репозитория заявок
+type MockChangeRequestRepo struct {
+	mock.Mock
+}
+
+func (m *MockChangeRequestRepo) Create(ctx context.Context, req *models.ChangeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockChangeRequestRepo) GetByID(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestRepo) Lock(ctx context.Context, id uint) (*models.ChangeRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestRepo) List(ctx context.Context, status string) ([]models.ChangeRequest, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]models.ChangeRequest), args.Error(1)
+}
+
+func (m *MockChangeRequestRepo) Update(ctx context.Context, req *models.ChangeRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func setupChangeRequestService(t *testing.T) (*ChangeRequestSvc, *MockChangeRequestRepo, *MockDepartmentRepo, *MockEmployeeRepo, *MockHistoryRepo) {
+	mockRequestRepo := new(MockChangeRequestRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	departments := NewDepartmentService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), noAssignments(), mockHistoryRepo, fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalRequired)
+	service := NewChangeRequestService(mockRequestRepo, mockDeptRepo, mockEmpRepo, mockHistoryRepo, departments, fakeTypes{}, fakeTx{})
+	return service, mockRequestRepo, mockDeptRepo, mockEmpRepo, mockHistoryRepo
+}
+
+// mockOrgTree настраивает дерево 1 (руководитель 100) → 2 → 4 и 1 → 3 (руководитель 300).
+// ctx - контекст вызовов репозитория или mock.Anything, если сервис передаёт производный контекст.
+func mockOrgTree(ctx any, mockDeptRepo *MockDepartmentRepo) {
+	root := models.Department{ID: 1, Name: "Company", HeadID: uintPtr(100)}
+	engineering := models.Department{ID: 2, Name: "Engineering", ParentID: uintPtr(1)}
+	sales := models.Department{ID: 3, Name: "Sales", ParentID: uintPtr(1), HeadID: uintPtr(300)}
+	platform := models.Department{ID: 4, Name: "Platform", ParentID: uintPtr(2)}
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&root, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&engineering, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(3)).Return(&sales, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&platform, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(1)).Return([]models.Department{}, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(2)).Return([]models.Department{root}, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(3)).Return([]models.Department{root}, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(4)).Return([]models.Department{root, engineering}, nil)
+}
+
+func TestChangeRequestSubmit_CommonAncestorHead(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, mockEmpRepo, _ := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockEmpRepo.On("GetByID", ctx, uint(42)).Return(&models.Employee{ID: 42}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(4), uint(3)).Return(false, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform", uintPtr(3)).Return(nil, nil)
+	mockRequestRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	req, err := service.Submit(ctx, models.ChangeRequest{
+		Operation:    models.ChangeOpMove,
+		DepartmentID: 4,
+		Payload:      models.ChangeRequestPayload{ParentID: uintPtr(3)},
+		RequestedBy:  42,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestPending, req.Status)
+	assert.Equal(t, models.ApproverCommonAncestorHead, req.ApproverRule)
+	assert.Equal(t, 100, *req.ApproverID)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestSubmit_DepartmentHeadFallsBackToAncestor(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, mockEmpRepo, _ := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockEmpRepo.On("GetByID", ctx, uint(42)).Return(&models.Employee{ID: 42}, nil)
+	mockRequestRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	req, err := service.Submit(ctx, models.ChangeRequest{
+		Operation:    models.ChangeOpDelete,
+		DepartmentID: 4,
+		Payload:      models.ChangeRequestPayload{Mode: "cascade"},
+		RequestedBy:  42,
+		ApproverRule: models.ApproverDepartmentHead,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, *req.ApproverID)
+}
+
+func TestChangeRequestSubmit_NoApprover(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, mockEmpRepo, _ := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockEmpRepo.On("GetByID", ctx, uint(42)).Return(&models.Employee{ID: 42}, nil)
+
+	_, err := service.Submit(ctx, models.ChangeRequest{
+		Operation:    models.ChangeOpDelete,
+		DepartmentID: 1,
+		Payload:      models.ChangeRequestPayload{Mode: "cascade"},
+		RequestedBy:  42,
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrNoApprover)
+	mockRequestRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestSubmit_InvalidMode(t *testing.T) {
+	service, _, mockDeptRepo, _, _ := setupChangeRequestService(t)
+
+	_, err := service.Submit(context.Background(), models.ChangeRequest{
+		Operation:    models.ChangeOpDelete,
+		DepartmentID: 4,
+		Payload:      models.ChangeRequestPayload{Mode: "archive"},
+		RequestedBy:  42,
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidChangeRequest)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestApprove_ExecutesMove(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, _, mockHistoryRepo := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	// Операция выполняется с контекстом, помеченным как согласованный, поэтому вызовы
+	// репозитория подразделений сверяются без контекста.
+	mockOrgTree(mock.Anything, mockDeptRepo)
+	mockRequestRepo.On("Lock", ctx, uint(7)).Return(&models.ChangeRequest{ID: 7, Operation: models.ChangeOpMove,
+		DepartmentID: 4, Payload: models.ChangeRequestPayload{ParentID: uintPtr(3)}, RequestedBy: 42,
+		ApproverRule: models.ApproverCommonAncestorHead, Status: models.ChangeRequestPending}, nil)
+	mockDeptRepo.On("IsDescendant", mock.Anything, uint(4), uint(3)).Return(false, nil)
+	mockDeptRepo.On("GetByNameAndParent", mock.Anything, "Platform", uintPtr(3)).Return(nil, nil)
+	mockDeptRepo.On("Update", mock.Anything, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 4 && d.ParentID != nil && *d.ParentID == 3
+	})).Return(nil)
+	mockRequestRepo.On("Update", ctx, mock.MatchedBy(func(r *models.ChangeRequest) bool {
+		return r.Status == models.ChangeRequestApproved && *r.DecidedBy == 100 && r.DecidedAt != nil
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(e *models.DepartmentHistory) bool {
+		return e.DepartmentID == 4 && e.Action == "change_request_approved"
+	})).Return(nil)
+
+	req, err := service.Approve(ctx, 7, 100, "ok")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestApproved, req.Status)
+	assert.Equal(t, "ok", req.Comment)
+	mockDeptRepo.AssertCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRequestRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestChangeRequestApprove_ConflictAtApplyTime(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, _, _ := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockRequestRepo.On("Lock", ctx, uint(7)).Return(&models.ChangeRequest{ID: 7, Operation: models.ChangeOpMove,
+		DepartmentID: 4, Payload: models.ChangeRequestPayload{ParentID: uintPtr(3)}, RequestedBy: 42,
+		ApproverRule: models.ApproverCommonAncestorHead, Status: models.ChangeRequestPending}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(4), uint(3)).Return(false, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform", uintPtr(3)).Return(&models.Department{ID: 8, Name: "Platform"}, nil)
+
+	req, err := service.Approve(ctx, 7, 100, "")
+
+	assert.Nil(t, req)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRequestRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestApprove_WrongApprover(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, _, _ := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockRequestRepo.On("Lock", ctx, uint(7)).Return(&models.ChangeRequest{ID: 7, Operation: models.ChangeOpDelete,
+		DepartmentID: 4, Payload: models.ChangeRequestPayload{Mode: "cascade"}, RequestedBy: 42,
+		ApproverRule: models.ApproverCommonAncestorHead, Status: models.ChangeRequestPending}, nil)
+
+	_, err := service.Approve(ctx, 7, 300, "")
+
+	assert.ErrorIs(t, err, apperrors.ErrNotApprover)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestReject(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, _, mockHistoryRepo := setupChangeRequestService(t)
+	ctx := context.Background()
+
+	mockOrgTree(ctx, mockDeptRepo)
+	mockRequestRepo.On("Lock", ctx, uint(7)).Return(&models.ChangeRequest{ID: 7, Operation: models.ChangeOpDelete,
+		DepartmentID: 4, Payload: models.ChangeRequestPayload{Mode: "cascade"}, RequestedBy: 42,
+		ApproverRule: models.ApproverCommonAncestorHead, Status: models.ChangeRequestPending}, nil)
+	mockRequestRepo.On("Lock", ctx, uint(8)).Return(&models.ChangeRequest{ID: 8, Status: models.ChangeRequestApproved}, nil)
+	mockRequestRepo.On("Update", ctx, mock.Anything).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	req, err := service.Reject(ctx, 7, 100, "not now")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChangeRequestRejected, req.Status)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	_, err = service.Reject(ctx, 8, 100, "")
+	assert.ErrorIs(t, err, apperrors.ErrChangeRequestNotPending)
+}
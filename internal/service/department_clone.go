@@ -0,0 +1,116 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// Clone копирует структуру поддерева подразделения id под родителя parentID с новым
+// названием корня. Сотрудники не копируются; при withVacancies их должности
+// переносятся в копию как вакансии. Возвращает новое дерево целиком.
+func (s *DepService) Clone(ctx context.Context, id uint, name string, parentID *uint, withVacancies bool) (*models.Department, error) {
+	cleanName, err := s.names.Normalize(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidDepartmentName, err)
+	}
+
+	var root *models.Department
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		source, err := s.deptRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+
+		var parent *models.Department
+		if parentID != nil {
+			if parent, err = s.deptRepo.GetByID(ctx, *parentID); err != nil {
+				return err
+			}
+			if parent == nil {
+				return apperrors.ErrParentNotFound
+			}
+		}
+		// Копия сохраняет типы подразделений, поэтому её корень должен подходить новому родителю.
+		if err := s.types.CheckPlacement(ctx, source.Type, parent, nil); err != nil {
+			return err
+		}
+		existing, err := findSibling(ctx, s.deptRepo, 0, cleanName, parentID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return apperrors.ErrDepartmentNameConflict
+		}
+
+		// Поддерево читается целиком и до вставки, поэтому копию можно разместить и внутри исходного дерева.
+		flat, err := s.deptRepo.GetDescendants(ctx, id)
+		if err != nil {
+			return err
+		}
+		source.Children = buildChildrenTree(flat, id)
+		source.Name = cleanName
+
+		root, err = s.cloneNode(ctx, source, parentID, withVacancies)
+		if err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, uint(root.ID), "clone", map[string]any{
+			"source_id":      id,
+			"with_vacancies": withVacancies,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// cloneNode создаёт копию подразделения src под parentID и рекурсивно копирует его потомков.
+func (s *DepService) cloneNode(ctx context.Context, src *models.Department, parentID *uint, withVacancies bool) (*models.Department, error) {
+	dept := &models.Department{
+		Name:       src.Name,
+		ParentID:   parentID,
+		Type:       src.Type,
+		LocationID: src.LocationID,
+		Attributes: src.Attributes,
+	}
+	if err := s.deptRepo.Create(ctx, dept); err != nil {
+		return nil, fmt.Errorf("failed to create department: %w", err)
+	}
+
+	if withVacancies {
+		employees, err := s.empRepo.ListByDepartment(ctx, uint(src.ID), "created_at", nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, emp := range employedOnly(employees, nil) {
+			vacancy := &models.Vacancy{
+				DepartmentID: dept.ID,
+				Position:     emp.Position,
+				PositionID:   emp.PositionID,
+				Status:       models.VacancyOpen,
+			}
+			if err := s.vacancyRepo.Create(ctx, vacancy); err != nil {
+				return nil, fmt.Errorf("failed to create vacancy: %w", err)
+			}
+			dept.Vacancies = append(dept.Vacancies, *vacancy)
+		}
+	}
+
+	newID := uint(dept.ID)
+	for i := range src.Children {
+		child, err := s.cloneNode(ctx, &src.Children[i], &newID, withVacancies)
+		if err != nil {
+			return nil, err
+		}
+		dept.Children = append(dept.Children, *child)
+	}
+	return dept, nil
+}
@@ -0,0 +1,93 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// GetByCode возвращает подразделение с поддеревом по его коду.
+func (s *DepService) GetByCode(ctx context.Context, code string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	clean, err := ValidateCode(code)
+	if err != nil {
+		return nil, err
+	}
+	dept, err := s.deptRepo.GetByCode(ctx, clean)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+	return s.GetByID(ctx, uint(dept.ID), depth, includeEmployees, asOf)
+}
+
+// GetByExternalID возвращает подразделение с поддеревом по идентификатору во внешней системе.
+func (s *DepService) GetByExternalID(ctx context.Context, system, externalID string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	dept, err := s.deptRepo.GetByExternalID(ctx, system, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+	return s.GetByID(ctx, uint(dept.ID), depth, includeEmployees, asOf)
+}
+
+// SetIdentifiers заменяет код и идентификаторы внешних систем подразделения.
+// code, равный nil, удаляет код.
+func (s *DepService) SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Department, error) {
+	cleanCode, err := validateCodePtr(code)
+	if err != nil {
+		return nil, err
+	}
+	cleanIDs, err := ValidateExternalIDs(externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dept, err := s.deptRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+	if err := checkDepartmentIdentifiers(ctx, s.deptRepo, id, cleanCode, cleanIDs); err != nil {
+		return nil, err
+	}
+
+	dept.Code = cleanCode
+	dept.ExternalIDs = cleanIDs
+	if err := s.deptRepo.Update(ctx, dept); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// checkDepartmentIdentifiers проверяет, что код и внешние идентификаторы не заняты
+// другим подразделением. Для нового подразделения id равен 0.
+func checkDepartmentIdentifiers(ctx context.Context, repo DepartmentRepository, id uint, code *string, externalIDs models.ExternalIDs) error {
+	if code != nil {
+		existing, err := repo.GetByCode(ctx, *code)
+		if err != nil {
+			return err
+		}
+		if existing != nil && uint(existing.ID) != id {
+			return apperrors.ErrDepartmentCodeConflict
+		}
+	}
+	for system, extID := range externalIDs {
+		existing, err := repo.GetByExternalID(ctx, system, extID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && uint(existing.ID) != id {
+			return apperrors.ErrExternalIDConflict
+		}
+	}
+	return nil
+}
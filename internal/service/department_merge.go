@@ -0,0 +1,178 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// MergeResult описывает план слияния подразделения с целевым или результат его выполнения.
+type MergeResult struct {
+	SourceID           uint        `json:"source_id"`
+	TargetID           uint        `json:"target_id"`
+	DryRun             bool        `json:"dry_run"`
+	MovedEmployees     int64       `json:"moved_employees"`
+	MovedDepartments   []MergeStep `json:"moved_departments"`
+	MergedDepartments  []MergeStep `json:"merged_departments"`
+	DeletedDepartments []uint      `json:"deleted_departments"`
+	Warnings           []string    `json:"warnings,omitempty"`
+}
+
+// MergeStep описывает одно действие слияния: перенос подразделения под новый родитель
+// либо слияние подразделения с одноимённым подразделением цели.
+type MergeStep struct {
+	DepartmentID uint `json:"department_id"`
+	TargetID     uint `json:"target_id"`
+}
+
+// Merge переносит сотрудников, вакансии и дочерние подразделения источника в целевое подразделение,
+// рекурсивно сливает одноимённые дочерние подразделения и удаляет источник.
+// В режиме dryRun возвращает план слияния, не изменяя данные.
+func (s *DepService) Merge(ctx context.Context, sourceID, targetID uint, dryRun bool) (*MergeResult, error) {
+	if sourceID == targetID {
+		return nil, apperrors.ErrMergeIntoSelf
+	}
+
+	var result *MergeResult
+	run := func(ctx context.Context) error {
+		source, err := s.deptRepo.GetByID(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		target, err := s.deptRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return apperrors.ErrTargetDepartmentNotFound
+		}
+
+		isDescendant, err := s.deptRepo.IsDescendant(ctx, sourceID, targetID)
+		if err != nil {
+			return err
+		}
+		if isDescendant {
+			return apperrors.ErrMergeIntoDescendant
+		}
+		// Слияние удаляет исходное подразделение; предпросмотр согласования не требует.
+		if !dryRun {
+			if err := requireApproval(ctx, s.approvalMode, "merge", sourceID); err != nil {
+				return err
+			}
+		}
+
+		result = &MergeResult{
+			SourceID:           sourceID,
+			TargetID:           targetID,
+			DryRun:             dryRun,
+			MovedDepartments:   []MergeStep{},
+			MergedDepartments:  []MergeStep{},
+			DeletedDepartments: []uint{},
+		}
+		if err := s.planMerge(ctx, sourceID, target, result); err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+		return s.applyMerge(ctx, result)
+	}
+
+	var err error
+	if dryRun {
+		err = run(ctx)
+	} else {
+		err = s.tx.WithinTransaction(ctx, run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// planMerge рекурсивно строит план слияния подразделения sourceID с target.
+// Каждое переносимое дочернее подразделение проверяется по правилам типов для нового родителя,
+// а сотрудники и открытые вакансии сливаемого подразделения - по утверждённой численности target.
+func (s *DepService) planMerge(ctx context.Context, sourceID uint, target *models.Department, result *MergeResult) error {
+	targetID := uint(target.ID)
+	count, err := s.empRepo.CountByDepartment(ctx, sourceID)
+	if err != nil {
+		return err
+	}
+	result.MovedEmployees += count
+	warning, err := s.guard.checkMove(ctx, sourceID, target, true)
+	if err != nil {
+		return err
+	}
+	if warning != "" {
+		result.Warnings = append(result.Warnings, warning)
+	}
+	result.MergedDepartments = append(result.MergedDepartments, MergeStep{DepartmentID: sourceID, TargetID: targetID})
+	result.DeletedDepartments = append(result.DeletedDepartments, sourceID)
+
+	sourceChildren, err := s.deptRepo.GetChildren(ctx, &sourceID)
+	if err != nil {
+		return err
+	}
+	targetChildren, err := s.deptRepo.GetChildren(ctx, &targetID)
+	if err != nil {
+		return err
+	}
+	// Дочерние подразделения сопоставляются по ключу department_name_key, как при проверке
+	// уникальности названий: иначе "Sales" и "sales" оказались бы соседями с одним ключом.
+	byName := make(map[string]*models.Department, len(targetChildren))
+	for i := range targetChildren {
+		byName[targetChildren[i].NameKey] = &targetChildren[i]
+	}
+
+	for _, c := range sourceChildren {
+		if match, ok := byName[c.NameKey]; ok {
+			if err := s.planMerge(ctx, uint(c.ID), match, result); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.types.CheckPlacement(ctx, c.Type, target, nil); err != nil {
+			return err
+		}
+		result.MovedDepartments = append(result.MovedDepartments, MergeStep{DepartmentID: uint(c.ID), TargetID: targetID})
+	}
+	return nil
+}
+
+// applyMerge выполняет построенный план слияния и записывает его в журнал изменений.
+func (s *DepService) applyMerge(ctx context.Context, result *MergeResult) error {
+	for _, step := range result.MergedDepartments {
+		if err := s.empRepo.MoveToDepartment(ctx, step.DepartmentID, step.TargetID); err != nil {
+			return err
+		}
+		if err := s.vacancyRepo.MoveToDepartment(ctx, step.DepartmentID, step.TargetID); err != nil {
+			return err
+		}
+	}
+	for _, step := range result.MovedDepartments {
+		dept, err := s.deptRepo.GetByID(ctx, step.DepartmentID)
+		if err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		parentID := step.TargetID
+		dept.ParentID = &parentID
+		if err := s.deptRepo.Update(ctx, dept); err != nil {
+			return err
+		}
+	}
+	// Слитые дочерние подразделения к этому моменту пусты (без сотрудников и вакансий)
+	// и удаляются каскадно вместе с источником.
+	if err := s.deptRepo.Delete(ctx, result.SourceID); err != nil {
+		return err
+	}
+	return s.recordHistory(ctx, result.TargetID, "merge", result)
+}
@@ -0,0 +1,101 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// Reorder ставит подразделение на позицию position (с нуля) среди соседей.
+// Позиция за концом списка означает последнее место. Возвращает соседей в новом порядке.
+func (s *DepService) Reorder(ctx context.Context, id uint, position int) ([]models.Department, error) {
+	if position < 0 {
+		return nil, apperrors.ErrInvalidSortPosition
+	}
+
+	var ordered []models.Department
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		dept, err := s.deptRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		siblings, err := s.deptRepo.GetChildren(ctx, dept.ParentID)
+		if err != nil {
+			return err
+		}
+
+		ordered = make([]models.Department, 0, len(siblings))
+		for _, sib := range siblings {
+			if uint(sib.ID) != id {
+				ordered = append(ordered, sib)
+			}
+		}
+		if position > len(ordered) {
+			position = len(ordered)
+		}
+		ordered = append(ordered[:position], append([]models.Department{*dept}, ordered[position:]...)...)
+
+		return s.applySortOrder(ctx, ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// ReorderChildren задаёт порядок всех дочерних подразделений parentID.
+// childIDs должен содержать каждое дочернее подразделение ровно один раз.
+func (s *DepService) ReorderChildren(ctx context.Context, parentID uint, childIDs []uint) ([]models.Department, error) {
+	var ordered []models.Department
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		parent, err := s.deptRepo.GetByID(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		children, err := s.deptRepo.GetChildren(ctx, &parentID)
+		if err != nil {
+			return err
+		}
+		if len(children) != len(childIDs) {
+			return apperrors.ErrInvalidChildrenOrder
+		}
+
+		byID := make(map[uint]models.Department, len(children))
+		for _, c := range children {
+			byID[uint(c.ID)] = c
+		}
+		ordered = make([]models.Department, 0, len(childIDs))
+		for _, cid := range childIDs {
+			child, ok := byID[cid]
+			if !ok {
+				return apperrors.ErrInvalidChildrenOrder
+			}
+			delete(byID, cid)
+			ordered = append(ordered, child)
+		}
+
+		return s.applySortOrder(ctx, ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// applySortOrder сохраняет порядок подразделений и проставляет его в переданных моделях.
+func (s *DepService) applySortOrder(ctx context.Context, ordered []models.Department) error {
+	ids := make([]uint, len(ordered))
+	for i := range ordered {
+		ids[i] = uint(ordered[i].ID)
+		ordered[i].SortOrder = i + 1
+	}
+	return s.deptRepo.SetSortOrder(ctx, ids)
+}
@@ -5,75 +5,133 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
 )
 
 // DepartmentService определяет интерфейс для работы с подразделениями,
 // который будет реализован сервисом и использован обработчиками HTTP
 type DepartmentService interface {
-	Create(ctx context.Context, name string, parentID *uint) (*models.Department, error)
-	GetByID(ctx context.Context, id uint, depth int, includeEmployees bool) (*models.Department, error)
-	Update(ctx context.Context, id uint, name *string, parentID *uint) (*models.Department, error)
+	Create(ctx context.Context, name string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error)
+	GetByID(ctx context.Context, id uint, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error)
+	List(ctx context.Context, attrFilter map[string]string, asOf *time.Time) ([]models.Department, error)
+	Update(ctx context.Context, id uint, name *string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error)
 	Delete(ctx context.Context, id uint, mode string, reassignTo *uint) error
+	Merge(ctx context.Context, sourceID, targetID uint, dryRun bool) (*MergeResult, error)
+	Clone(ctx context.Context, id uint, name string, parentID *uint, withVacancies bool) (*models.Department, error)
+	Reorder(ctx context.Context, id uint, position int) ([]models.Department, error)
+	ReorderChildren(ctx context.Context, parentID uint, childIDs []uint) ([]models.Department, error)
+	GetByCode(ctx context.Context, code string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error)
+	GetByExternalID(ctx context.Context, system, externalID string, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error)
+	SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Department, error)
+	SetHead(ctx context.Context, id uint, employeeID *uint) (*models.Department, error)
+	Stats(ctx context.Context, id uint, months int) (*models.DepartmentStats, error)
+	SetHeadcountLimit(ctx context.Context, id uint, limit *int) (*models.Department, error)
+	HeadcountBudget(ctx context.Context, id uint) (*models.HeadcountBudget, error)
 }
 
 // DepartmentRepository определяет интерфейс репозитория подразделений, необходимый для работы сервиса.
 type DepartmentRepository interface {
 	Create(ctx context.Context, dept *models.Department) error
 	GetByID(ctx context.Context, id uint) (*models.Department, error)
+	Lock(ctx context.Context, id uint) (*models.Department, error)
 	Update(ctx context.Context, dept *models.Department) error
 	Delete(ctx context.Context, id uint) error
 	GetChildren(ctx context.Context, parentID *uint) ([]models.Department, error)
-	GetSubTree(ctx context.Context, rootID uint, depth int) ([]models.Department, error)
+	GetSubTree(ctx context.Context, rootID uint, depth int, asOf *time.Time) ([]models.Department, error)
+	GetDescendants(ctx context.Context, rootID uint) ([]models.Department, error)
+	GetAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Department, error)
+	GetAncestors(ctx context.Context, id uint) ([]models.Department, error)
+	IsDescendant(ctx context.Context, ancestorID, id uint) (bool, error)
+	SetSortOrder(ctx context.Context, ids []uint) error
 	GetByNameAndParent(ctx context.Context, name string, parentID *uint) (*models.Department, error)
+	GetByCode(ctx context.Context, code string) (*models.Department, error)
+	GetByExternalID(ctx context.Context, system, externalID string) (*models.Department, error)
+	List(ctx context.Context, attrFilter models.Attributes, asOf *time.Time) ([]models.Department, error)
+	Stats(ctx context.Context, id uint, since time.Time, months int) (*models.DepartmentStats, error)
+	HeadcountBudget(ctx context.Context, id uint) ([]models.HeadcountBudget, error)
 }
 
-// DepService реализует бизнес-логику для работы с подразделениями.
-type DepService struct {
-	deptRepo DepartmentRepository
-	empRepo  EmployeeRepository
+// VacancyRepository определяет интерфейс репозитория вакансий, необходимый для работы сервиса.
+type VacancyRepository interface {
+	Create(ctx context.Context, vacancy *models.Vacancy) error
+	GetByID(ctx context.Context, id uint) (*models.Vacancy, error)
+	Update(ctx context.Context, vacancy *models.Vacancy) error
+	List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error)
+	CountOpen(ctx context.Context, departmentID uint) (int64, error)
+	MoveToDepartment(ctx context.Context, departmentID, targetDepartmentID uint) error
 }
 
-// NewDepartmentService создаёт новый экземпляр сервиса подразделений.
-func NewDepartmentService(deptRepo DepartmentRepository, empRepo EmployeeRepository) *DepService {
-	return &DepService{deptRepo: deptRepo, empRepo: empRepo}
+// HistoryRepository определяет интерфейс репозитория журнала структурных изменений.
+type HistoryRepository interface {
+	Create(ctx context.Context, entry *models.DepartmentHistory) error
 }
 
-// ValidateName проверяет и очищает название подразделения.
-func ValidateName(name string) (string, error) {
-	trimmed := strings.TrimSpace(name)
-	if trimmed == "" {
-		return "", errors.New("name cannot be empty")
-	}
+// Transactor выполняет функцию в транзакции, общей для всех репозиториев,
+// вызванных с переданным в неё контекстом.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinRetryableTransaction повторяет транзакцию, откатившуюся из-за конфликта
+	// с параллельной транзакцией. Подходит только для идемпотентных операций.
+	WithinRetryableTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
 
-	if len(trimmed) > 200 {
-		return "", errors.New("name too long (max 200)")
-	}
+// DepService реализует бизнес-логику для работы с подразделениями.
+type DepService struct {
+	deptRepo       DepartmentRepository
+	empRepo        EmployeeRepository
+	vacancyRepo    VacancyRepository
+	assignmentRepo AssignmentRepository
+	historyRepo    HistoryRepository
+	attrs          AttributeSchema
+	types          DepartmentTypeRules
+	names          NamingPolicy
+	guard          headcountGuard
+	tx             Transactor
+	approvalMode   string
+}
 
-	return trimmed, nil
+// NewDepartmentService создаёт новый экземпляр сервиса подразделений; limitMode - режим проверки
+// утверждённой численности при переносе сотрудников в другое подразделение, approvalMode - режим
+// согласования переносов и удалений (ChangeApprovalRequired или ChangeApprovalOptional).
+func NewDepartmentService(deptRepo DepartmentRepository, empRepo EmployeeRepository, vacancyRepo VacancyRepository,
+	assignmentRepo AssignmentRepository, historyRepo HistoryRepository, attrs AttributeSchema, types DepartmentTypeRules,
+	names NamingPolicy, tx Transactor, limitMode string, approvalMode string) *DepService {
+	return &DepService{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo, assignmentRepo: assignmentRepo,
+		historyRepo: historyRepo, attrs: attrs, types: types, names: names, tx: tx, approvalMode: approvalMode,
+		guard: headcountGuard{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo, mode: limitMode}}
 }
 
 // Create реализует бизнес-логику создания нового подразделения.
-func (s *DepService) Create(ctx context.Context, name string, parentID *uint) (*models.Department, error) {
-	clearName, err := ValidateName(name)
+// Тип deptType (nil или пустой - без типа) должен допускать размещение под parentID.
+func (s *DepService) Create(ctx context.Context, name string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error) {
+	clearName, err := s.names.Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanAttrs, err := s.attrs.Validate(ctx, models.EntityDepartment, attrs)
 	if err != nil {
 		return nil, err
 	}
 
-	existing, err := s.deptRepo.GetByNameAndParent(ctx, clearName, parentID)
+	existing, err := findSibling(ctx, s.deptRepo, 0, clearName, parentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check uniqueness: %w", err)
 	}
 	if existing != nil {
-		return nil, errors.New("department with this name already exists the same parent")
+		return nil, nameConflictError("department with this name already exists the same parent")
 	}
 
+	var parent *models.Department
 	if parentID != nil {
-		parent, err := s.deptRepo.GetByID(ctx, *parentID)
+		parent, err = s.deptRepo.GetByID(ctx, *parentID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check parent existence: %w", err)
 		}
@@ -81,9 +139,15 @@ func (s *DepService) Create(ctx context.Context, name string, parentID *uint) (*
 			return nil, errors.New("parent department not found")
 		}
 	}
+	deptType = departmentType(deptType)
+	if err := s.types.CheckPlacement(ctx, deptType, parent, nil); err != nil {
+		return nil, err
+	}
 	dept := &models.Department{
-		Name:     clearName,
-		ParentID: parentID,
+		Name:       clearName,
+		ParentID:   parentID,
+		Type:       deptType,
+		Attributes: cleanAttrs,
 	}
 
 	if err := s.deptRepo.Create(ctx, dept); err != nil {
@@ -94,8 +158,17 @@ func (s *DepService) Create(ctx context.Context, name string, parentID *uint) (*
 }
 
 // GetByID реализует бизнес-логику получения подразделения с поддеревом.
-func (s *DepService) GetByID(ctx context.Context, id uint, depth int, includeEmployees bool) (*models.Department, error) {
-	root, err := s.deptRepo.GetByID(ctx, id)
+// При заданном asOf подразделение, поддерево и сотрудники возвращаются в состоянии на эту дату.
+// Вслед за основными сотрудниками идут сотрудники с дополнительным назначением в подразделение
+// (Secondary); назначения не версионируются, поэтому при asOf они не возвращаются.
+func (s *DepService) GetByID(ctx context.Context, id uint, depth int, includeEmployees bool, asOf *time.Time) (*models.Department, error) {
+	var root *models.Department
+	var err error
+	if asOf != nil {
+		root, err = s.deptRepo.GetAsOf(ctx, id, *asOf)
+	} else {
+		root, err = s.deptRepo.GetByID(ctx, id)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -104,18 +177,25 @@ func (s *DepService) GetByID(ctx context.Context, id uint, depth int, includeEmp
 	}
 
 	if includeEmployees {
-		employees, err := s.empRepo.ListByDepartment(ctx, id, "created_at")
+		employees, err := s.empRepo.ListByDepartment(ctx, id, "created_at", asOf)
 		if err != nil {
 			return nil, err
 		}
-		root.Employees = employees
+		root.Employees = employedOnly(employees, asOf)
+		if asOf == nil {
+			members, err := s.assignmentRepo.ListMembers(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			root.Employees = append(root.Employees, members...)
+		}
 	}
 	if depth > 1 {
-		children, err := s.deptRepo.GetSubTree(ctx, id, depth)
+		children, err := s.deptRepo.GetSubTree(ctx, id, depth, asOf)
 		if err != nil {
 			return nil, err
 		}
-		root.Children = s.buildChildrenTree(children, id)
+		root.Children = buildChildrenTree(children, id)
 	} else {
 		root.Children = []models.Department{}
 	}
@@ -123,8 +203,18 @@ func (s *DepService) GetByID(ctx context.Context, id uint, depth int, includeEmp
 	return root, nil
 }
 
+// List возвращает подразделения, у которых атрибуты совпадают с фильтром,
+// при заданном asOf - в состоянии на эту дату.
+func (s *DepService) List(ctx context.Context, attrFilter map[string]string, asOf *time.Time) ([]models.Department, error) {
+	filter, err := s.attrs.ParseFilter(ctx, models.EntityDepartment, attrFilter)
+	if err != nil {
+		return nil, err
+	}
+	return s.deptRepo.List(ctx, filter, asOf)
+}
+
 // buildChildrenTree преобразует плоский список подразделений в иерархическую структуру.
-func (s *DepService) buildChildrenTree(flat []models.Department, rootID uint) []models.Department {
+func buildChildrenTree(flat []models.Department, rootID uint) []models.Department {
 	if len(flat) == 0 {
 		return nil
 	}
@@ -158,7 +248,10 @@ func (s *DepService) buildChildrenTree(flat []models.Department, rootID uint) []
 }
 
 // Update реализует бизнес-логику обновления подразделения.
-func (s *DepService) Update(ctx context.Context, id uint, name *string, parentID *uint) (*models.Department, error) {
+// Непустой deptType задаёт тип, пустая строка снимает его; при смене типа или родителя
+// проверяются правила вложенности для нового родителя и дочерних подразделений.
+// Перенос в режиме ChangeApprovalRequired выполняется только по согласованной заявке.
+func (s *DepService) Update(ctx context.Context, id uint, name *string, parentID *uint, deptType *string, attrs models.Attributes) (*models.Department, error) {
 	dept, err := s.deptRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -166,9 +259,20 @@ func (s *DepService) Update(ctx context.Context, id uint, name *string, parentID
 	if dept == nil {
 		return nil, errors.New("department not found")
 	}
+	if parentID != nil {
+		newParent := parentID
+		if *parentID == 0 {
+			newParent = nil
+		}
+		if !sameParent(dept.ParentID, newParent) {
+			if err := requireApproval(ctx, s.approvalMode, "move", id); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	if name != nil {
-		cleanName, err := ValidateName(*name)
+		cleanName, err := s.names.Normalize(*name)
 		if err != nil {
 			return nil, err
 		}
@@ -177,22 +281,23 @@ func (s *DepService) Update(ctx context.Context, id uint, name *string, parentID
 		if parentID != nil {
 			checkParent = parentID
 		}
-		existing, err := s.deptRepo.GetByNameAndParent(ctx, cleanName, checkParent)
+		existing, err := findSibling(ctx, s.deptRepo, id, cleanName, checkParent)
 		if err != nil {
 			return nil, err
 		}
-		if existing != nil && uint(existing.ID) != id {
-			return nil, errors.New("department with this name already exists under the same parent")
+		if existing != nil {
+			return nil, nameConflictError("department with this name already exists under the same parent")
 		}
 		dept.Name = cleanName
 	}
 
+	var parent *models.Department
 	if parentID != nil {
 		if *parentID == id {
 			return nil, errors.New("cannot set parent to itself")
 		}
 		if *parentID != 0 {
-			parent, err := s.deptRepo.GetByID(ctx, *parentID)
+			parent, err = s.deptRepo.GetByID(ctx, *parentID)
 			if err != nil {
 				return nil, err
 			}
@@ -201,25 +306,77 @@ func (s *DepService) Update(ctx context.Context, id uint, name *string, parentID
 			}
 		}
 		// Проверка на цикл
-		descendants, err := s.deptRepo.GetSubTree(ctx, id, 100) // большая глубина для получения всех потомков
+		isDescendant, err := s.deptRepo.IsDescendant(ctx, id, *parentID)
 		if err != nil {
 			return nil, err
 		}
-		for _, d := range descendants {
-			if uint(d.ID) == *parentID {
-				return nil, errors.New("cannot move department to its own descendant")
-			}
+		if isDescendant {
+			return nil, errors.New("cannot move department to its own descendant")
 		}
 		dept.ParentID = parentID
+		// parent_id = 0 делает подразделение корневым
+		if *parentID == 0 {
+			dept.ParentID = nil
+		}
+	}
+
+	if parentID != nil || deptType != nil {
+		if err := s.checkTypePlacement(ctx, dept, parent, parentID != nil, deptType); err != nil {
+			return nil, err
+		}
 	}
 
+	merged, err := s.attrs.Validate(ctx, models.EntityDepartment, mergeAttributes(dept.Attributes, attrs))
+	if err != nil {
+		return nil, err
+	}
+	dept.Attributes = merged
+
 	if err := s.deptRepo.Update(ctx, dept); err != nil {
 		return nil, err
 	}
 	return dept, nil
 }
 
+// checkTypePlacement применяет новый тип deptType к dept и проверяет правила вложенности.
+// moved означает, что parent - уже проверенный новый родитель; иначе родитель читается заново.
+// Дочерние подразделения проверяются только при смене типа.
+func (s *DepService) checkTypePlacement(ctx context.Context, dept *models.Department, parent *models.Department,
+	moved bool, deptType *string) error {
+	var children []models.Department
+	if deptType != nil {
+		dept.Type = departmentType(deptType)
+		id := uint(dept.ID)
+		var err error
+		if children, err = s.deptRepo.GetChildren(ctx, &id); err != nil {
+			return err
+		}
+	}
+	if !moved && dept.ParentID != nil {
+		var err error
+		if parent, err = s.deptRepo.GetByID(ctx, *dept.ParentID); err != nil {
+			return err
+		}
+	}
+	return s.types.CheckPlacement(ctx, dept.Type, parent, children)
+}
+
+// departmentType нормализует тип подразделения из запроса: пустая строка означает отсутствие типа.
+func departmentType(deptType *string) *string {
+	if deptType == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*deptType)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
+
 // Delete реализует бизнес-логику удаления подразделения с учётом режима.
+// В режиме ChangeApprovalRequired удаление выполняется только по согласованной заявке.
+// При переназначении сотрудников превышение утверждённой численности целевого подразделения
+// в жёстком режиме отклоняется, в мягком - допускается.
 func (s *DepService) Delete(ctx context.Context, id uint, mode string, reassignTo *uint) error {
 	dept, err := s.deptRepo.GetByID(ctx, id)
 	if err != nil {
@@ -228,6 +385,9 @@ func (s *DepService) Delete(ctx context.Context, id uint, mode string, reassignT
 	if dept == nil {
 		return errors.New("department not found")
 	}
+	if err := requireApproval(ctx, s.approvalMode, "delete", id); err != nil {
+		return err
+	}
 
 	switch mode {
 	case "cascade":
@@ -251,12 +411,69 @@ func (s *DepService) Delete(ctx context.Context, id uint, mode string, reassignT
 		if len(children) > 0 {
 			return errors.New("cannot reassign department with children; delete children first or use cascade mode")
 		}
-		// Переназначаем сотрудников
-		if err := s.empRepo.MoveToDepartment(ctx, id, *reassignTo); err != nil {
-			return err
-		}
-		return s.deptRepo.Delete(ctx, id)
+		// Переназначаем сотрудников: целевое подразделение должно вместить их по утверждённой численности.
+		return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if _, err := s.guard.checkMove(ctx, id, target, false); err != nil {
+				return err
+			}
+			if err := s.empRepo.MoveToDepartment(ctx, id, *reassignTo); err != nil {
+				return err
+			}
+			return s.deptRepo.Delete(ctx, id)
+		})
 	default:
 		return errors.New("invalid mode, must be 'cascade' or 'reassign'")
 	}
 }
+
+// SetHead назначает руководителя подразделения; employeeID, равный nil, снимает руководителя.
+// Руководитель согласует заявки на перенос и удаление подразделений.
+func (s *DepService) SetHead(ctx context.Context, id uint, employeeID *uint) (*models.Department, error) {
+	dept, err := s.deptRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+	if employeeID != nil {
+		emp, err := s.empRepo.GetByID(ctx, *employeeID)
+		if err != nil {
+			return nil, err
+		}
+		if emp == nil {
+			return nil, apperrors.ErrEmployeeNotFound
+		}
+		if emp.Status == models.EmployeeTerminated {
+			return nil, apperrors.ErrEmployeeTerminated
+		}
+	}
+
+	dept.HeadID = employeeID
+	if err := s.deptRepo.Update(ctx, dept); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// recordHistory сохраняет запись о структурном изменении подразделения в журнал.
+func (s *DepService) recordHistory(ctx context.Context, departmentID uint, action string, details any) error {
+	return writeHistory(ctx, s.historyRepo, departmentID, action, details)
+}
+
+// writeHistory сохраняет запись журнала структурных изменений через переданный репозиторий.
+func writeHistory(ctx context.Context, repo HistoryRepository, departmentID uint, action string, details any) error {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode history details: %w", err)
+	}
+	entry := &models.DepartmentHistory{
+		DepartmentID: int(departmentID),
+		Action:       action,
+		Details:      payload,
+	}
+	if err := repo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	return nil
+}
@@ -3,8 +3,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,6 +31,14 @@ func (m *MockDepartmentRepo) GetByID(ctx context.Context, id uint) (*models.Depa
 	return args.Get(0).(*models.Department), args.Error(1)
 }
 
+func (m *MockDepartmentRepo) Lock(ctx context.Context, id uint) (*models.Department, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
 func (m *MockDepartmentRepo) Update(ctx context.Context, dept *models.Department) error {
 	args := m.Called(ctx, dept)
 	return args.Error(0)
@@ -43,11 +54,76 @@ func (m *MockDepartmentRepo) GetChildren(ctx context.Context, parentID *uint) ([
 	return args.Get(0).([]models.Department), args.Error(1)
 }
 
-func (m *MockDepartmentRepo) GetSubTree(ctx context.Context, rootID uint, depth int) ([]models.Department, error) {
-	args := m.Called(ctx, rootID, depth)
+func (m *MockDepartmentRepo) GetSubTree(ctx context.Context, rootID uint, depth int, asOf *time.Time) ([]models.Department, error) {
+	args := m.Called(ctx, rootID, depth, asOf)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) GetDescendants(ctx context.Context, rootID uint) ([]models.Department, error) {
+	args := m.Called(ctx, rootID)
 	return args.Get(0).([]models.Department), args.Error(1)
 }
 
+func (m *MockDepartmentRepo) GetAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Department, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) GetAncestors(ctx context.Context, id uint) ([]models.Department, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) IsDescendant(ctx context.Context, ancestorID, id uint) (bool, error) {
+	args := m.Called(ctx, ancestorID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) SetSortOrder(ctx context.Context, ids []uint) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepo) GetByCode(ctx context.Context, code string) (*models.Department, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) List(ctx context.Context, attrFilter models.Attributes, asOf *time.Time) ([]models.Department, error) {
+	args := m.Called(ctx, attrFilter, asOf)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) Stats(ctx context.Context, id uint, since time.Time, months int) (*models.DepartmentStats, error) {
+	args := m.Called(ctx, id, since, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentStats), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) HeadcountBudget(ctx context.Context, id uint) ([]models.HeadcountBudget, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HeadcountBudget), args.Error(1)
+}
+
+func (m *MockDepartmentRepo) GetByExternalID(ctx context.Context, system, externalID string) (*models.Department, error) {
+	args := m.Called(ctx, system, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
 func (m *MockDepartmentRepo) GetByNameAndParent(ctx context.Context, name string, parentID *uint) (*models.Department, error) {
 	args := m.Called(ctx, name, parentID)
 	if args.Get(0) == nil {
@@ -74,18 +150,44 @@ func (m *MockEmployeeRepo) GetByID(ctx context.Context, id uint) (*models.Employ
 	return args.Get(0).(*models.Employee), args.Error(1)
 }
 
+func (m *MockEmployeeRepo) GetByCode(ctx context.Context, code string) (*models.Employee, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) List(ctx context.Context, departmentID *uint, attrFilter models.Attributes, asOf *time.Time) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID, attrFilter, asOf)
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) GetByExternalID(ctx context.Context, system, externalID string) (*models.Employee, error) {
+	args := m.Called(ctx, system, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
 func (m *MockEmployeeRepo) Update(ctx context.Context, emp *models.Employee) error {
 	args := m.Called(ctx, emp)
 	return args.Error(0)
 }
 
+func (m *MockEmployeeRepo) Terminate(ctx context.Context, emp *models.Employee) error {
+	args := m.Called(ctx, emp)
+	return args.Error(0)
+}
+
 func (m *MockEmployeeRepo) Delete(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockEmployeeRepo) ListByDepartment(ctx context.Context, departmentID uint, orderBy string) ([]models.Employee, error) {
-	args := m.Called(ctx, departmentID, orderBy)
+func (m *MockEmployeeRepo) ListByDepartment(ctx context.Context, departmentID uint, orderBy string, asOf *time.Time) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID, orderBy, asOf)
 	return args.Get(0).([]models.Employee), args.Error(1)
 }
 
@@ -94,11 +196,105 @@ func (m *MockEmployeeRepo) MoveToDepartment(ctx context.Context, departmentID ui
 	return args.Error(0)
 }
 
+func (m *MockEmployeeRepo) CountByDepartment(ctx context.Context, departmentID uint) (int64, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEmployeeRepo) CountEmployed(ctx context.Context, departmentID uint) (int64, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockVacancyRepo - мок для репозитория вакансий
+type MockVacancyRepo struct {
+	mock.Mock
+}
+
+func (m *MockVacancyRepo) Create(ctx context.Context, vacancy *models.Vacancy) error {
+	args := m.Called(ctx, vacancy)
+	return args.Error(0)
+}
+
+func (m *MockVacancyRepo) GetByID(ctx context.Context, id uint) (*models.Vacancy, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Vacancy), args.Error(1)
+}
+
+func (m *MockVacancyRepo) Update(ctx context.Context, vacancy *models.Vacancy) error {
+	args := m.Called(ctx, vacancy)
+	return args.Error(0)
+}
+
+func (m *MockVacancyRepo) List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error) {
+	args := m.Called(ctx, departmentID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Vacancy), args.Error(1)
+}
+
+func (m *MockVacancyRepo) CountOpen(ctx context.Context, departmentID uint) (int64, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockVacancyRepo) MoveToDepartment(ctx context.Context, departmentID, targetDepartmentID uint) error {
+	args := m.Called(ctx, departmentID, targetDepartmentID)
+	return args.Error(0)
+}
+
+// MockHistoryRepo - мок для журнала структурных изменений
+type MockHistoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockHistoryRepo) Create(ctx context.Context, entry *models.DepartmentHistory) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+// fakeSchema принимает любые атрибуты без проверки
+type fakeSchema struct{}
+
+func (fakeSchema) Validate(ctx context.Context, entity string, attrs models.Attributes) (models.Attributes, error) {
+	return attrs, nil
+}
+
+// fakeTypes разрешает любое размещение подразделений.
+type fakeTypes struct{}
+
+func (fakeTypes) CheckPlacement(ctx context.Context, deptType *string, parent *models.Department, children []models.Department) error {
+	return nil
+}
+
+func (fakeSchema) ParseFilter(ctx context.Context, entity string, filter map[string]string) (models.Attributes, error) {
+	attrs := make(models.Attributes, len(filter))
+	for name, value := range filter {
+		attrs[name] = value
+	}
+	return attrs, nil
+}
+
+// fakeTx выполняет функцию без транзакции, передавая исходный контекст
+type fakeTx struct{}
+
+func (fakeTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) WithinRetryableTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 // Вспомогательная функция для создания сервиса с моками
 func setupDepartmentService(t *testing.T) (*DepService, *MockDepartmentRepo, *MockEmployeeRepo) {
 	mockDeptRepo := new(MockDepartmentRepo)
 	mockEmpRepo := new(MockEmployeeRepo)
-	service := NewDepartmentService(mockDeptRepo, mockEmpRepo)
+	service := NewDepartmentService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo), fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
 	return service, mockDeptRepo, mockEmpRepo
 }
 
@@ -113,7 +309,7 @@ func TestCreate_Success(t *testing.T) {
 		return dept.Name == "IT" && dept.ParentID == nil
 	})).Return(nil)
 
-	dept, err := service.Create(ctx, "IT", nil)
+	dept, err := service.Create(ctx, "IT", nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -135,7 +331,7 @@ func TestCreate_WithParent_Success(t *testing.T) {
 		return dept.Name == "Child" && *dept.ParentID == parentID
 	})).Return(nil)
 
-	dept, err := service.Create(ctx, "Child", &parentID)
+	dept, err := service.Create(ctx, "Child", &parentID, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -148,7 +344,7 @@ func TestCreate_EmptyName(t *testing.T) {
 	service, mockDeptRepo, _ := setupDepartmentService(t)
 	ctx := context.Background()
 
-	dept, err := service.Create(ctx, "", nil)
+	dept, err := service.Create(ctx, "", nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "name cannot be empty", err.Error())
@@ -162,7 +358,7 @@ func TestCreate_NameTooLong(t *testing.T) {
 	ctx := context.Background()
 
 	longName := string(make([]byte, 201))
-	dept, err := service.Create(ctx, longName, nil)
+	dept, err := service.Create(ctx, longName, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "name too long (max 200)", err.Error())
@@ -179,7 +375,7 @@ func TestCreate_NameConflict(t *testing.T) {
 
 	mockDeptRepo.On("GetByNameAndParent", ctx, "IT", (*uint)(nil)).Return(existingDept, nil)
 
-	dept, err := service.Create(ctx, "IT", nil)
+	dept, err := service.Create(ctx, "IT", nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "department with this name already exists the same parent", err.Error())
@@ -197,7 +393,7 @@ func TestCreate_ParentNotFound(t *testing.T) {
 	mockDeptRepo.On("GetByNameAndParent", ctx, "Child", &parentID).Return(nil, nil)
 	mockDeptRepo.On("GetByID", ctx, parentID).Return(nil, nil)
 
-	dept, err := service.Create(ctx, "Child", &parentID)
+	dept, err := service.Create(ctx, "Child", &parentID, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "parent department not found", err.Error())
@@ -219,9 +415,9 @@ func TestGetByID_Success(t *testing.T) {
 	employees := []models.Employee{
 		{ID: 1, FullName: "John", DepartmentID: 1},
 	}
-	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at").Return(employees, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", (*time.Time)(nil)).Return(employees, nil)
 
-	dept, err := service.GetByID(ctx, 1, 1, true)
+	dept, err := service.GetByID(ctx, 1, 1, true, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -232,6 +428,24 @@ func TestGetByID_Success(t *testing.T) {
 	mockEmpRepo.AssertExpectations(t)
 }
 
+func TestGetByID_ExcludesTerminated(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupDepartmentService(t)
+	ctx := context.Background()
+	terminatedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "IT"}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 1, FullName: "John", DepartmentID: 1, Status: models.EmployeeOnLeave},
+		{ID: 2, FullName: "Jane", DepartmentID: 1, Status: models.EmployeeTerminated, TerminatedAt: &terminatedAt},
+	}, nil)
+
+	dept, err := service.GetByID(ctx, 1, 1, true, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, dept.Employees, 1)
+	assert.Equal(t, 1, dept.Employees[0].ID)
+}
+
 func TestGetByID_WithDepth(t *testing.T) {
 	service, mockDeptRepo, _ := setupDepartmentService(t)
 	ctx := context.Background()
@@ -244,9 +458,9 @@ func TestGetByID_WithDepth(t *testing.T) {
 		{ID: 2, Name: "Child1", ParentID: &[]uint{1}[0]},
 		{ID: 3, Name: "Child2", ParentID: &[]uint{1}[0]},
 	}
-	mockDeptRepo.On("GetSubTree", ctx, uint(1), 2).Return(children, nil)
+	mockDeptRepo.On("GetSubTree", ctx, uint(1), 2, (*time.Time)(nil)).Return(children, nil)
 
-	dept, err := service.GetByID(ctx, 1, 2, false)
+	dept, err := service.GetByID(ctx, 1, 2, false, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -260,7 +474,7 @@ func TestGetByID_NotFound(t *testing.T) {
 
 	mockDeptRepo.On("GetByID", ctx, uint(999)).Return(nil, nil)
 
-	dept, err := service.GetByID(ctx, 999, 1, true)
+	dept, err := service.GetByID(ctx, 999, 1, true, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "deparment not found", err.Error())
@@ -283,7 +497,7 @@ func TestUpdate_NameOnly(t *testing.T) {
 		return dept.ID == 1 && dept.Name == newName
 	})).Return(nil)
 
-	dept, err := service.Update(ctx, 1, &newName, nil)
+	dept, err := service.Update(ctx, 1, &newName, nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -301,12 +515,12 @@ func TestUpdate_ParentOnly(t *testing.T) {
 
 	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(existingDept, nil)
 	mockDeptRepo.On("GetByID", ctx, newParentID).Return(parentDept, nil)
-	mockDeptRepo.On("GetSubTree", ctx, uint(1), 100).Return([]models.Department{}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(1), newParentID).Return(false, nil)
 	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(dept *models.Department) bool {
 		return dept.ID == 1 && *dept.ParentID == newParentID
 	})).Return(nil)
 
-	dept, err := service.Update(ctx, 1, nil, &newParentID)
+	dept, err := service.Update(ctx, 1, nil, &newParentID, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, dept)
@@ -314,6 +528,71 @@ func TestUpdate_ParentOnly(t *testing.T) {
 	mockDeptRepo.AssertExpectations(t)
 }
 
+func TestUpdate_MoveToRoot(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	oldParentID := uint(2)
+	existingDept := &models.Department{ID: 1, Name: "Dept", ParentID: &oldParentID}
+	rootParentID := uint(0)
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(existingDept, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(1), rootParentID).Return(false, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(dept *models.Department) bool {
+		return dept.ID == 1 && dept.ParentID == nil
+	})).Return(nil)
+
+	dept, err := service.Update(ctx, 1, nil, &rootParentID, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, dept.ParentID)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestSetHead(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupDepartmentService(t)
+	ctx := context.Background()
+
+	headID := uint(7)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Dept"}, nil)
+	mockEmpRepo.On("GetByID", ctx, headID).Return(&models.Employee{ID: 7}, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(99)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(dept *models.Department) bool {
+		return dept.ID == 1 && dept.HeadID != nil && *dept.HeadID == headID
+	})).Return(nil)
+
+	dept, err := service.SetHead(ctx, 1, &headID)
+	assert.NoError(t, err)
+	assert.Equal(t, headID, *dept.HeadID)
+
+	missing := uint(99)
+	_, err = service.SetHead(ctx, 1, &missing)
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeNotFound)
+}
+
+func TestStats(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Dept"}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(nil, nil)
+	mockDeptRepo.On("Stats", ctx, uint(1), mock.MatchedBy(func(since time.Time) bool {
+		now := time.Now().UTC()
+		return since.Day() == 1 && since.AddDate(0, 2, 0).Month() == now.Month()
+	}), 3).Return(&models.DepartmentStats{DepartmentID: 1, TotalHeadcount: 5}, nil)
+
+	stats, err := service.Stats(ctx, 1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stats.TotalHeadcount)
+
+	_, err = service.Stats(ctx, 2, 3)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+
+	_, err = service.Stats(ctx, 1, 0)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidStatsWindow)
+	mockDeptRepo.AssertNumberOfCalls(t, "Stats", 1)
+}
+
 func TestUpdate_NotFound(t *testing.T) {
 	service, mockDeptRepo, _ := setupDepartmentService(t)
 	ctx := context.Background()
@@ -321,7 +600,7 @@ func TestUpdate_NotFound(t *testing.T) {
 	mockDeptRepo.On("GetByID", ctx, uint(999)).Return(nil, nil)
 
 	newName := "NewName"
-	dept, err := service.Update(ctx, 999, &newName, nil)
+	dept, err := service.Update(ctx, 999, &newName, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "department not found", err.Error())
@@ -340,7 +619,7 @@ func TestUpdate_NameConflict(t *testing.T) {
 	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(existingDept, nil)
 	mockDeptRepo.On("GetByNameAndParent", ctx, newName, (*uint)(nil)).Return(conflictingDept, nil)
 
-	dept, err := service.Update(ctx, 1, &newName, nil)
+	dept, err := service.Update(ctx, 1, &newName, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "department with this name already exists under the same parent", err.Error())
@@ -358,7 +637,7 @@ func TestUpdate_SelfParent(t *testing.T) {
 
 	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(existingDept, nil)
 
-	dept, err := service.Update(ctx, 1, nil, &selfID)
+	dept, err := service.Update(ctx, 1, nil, &selfID, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "cannot set parent to itself", err.Error())
@@ -376,14 +655,9 @@ func TestUpdate_CycleDetected(t *testing.T) {
 
 	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(existingDept, nil)
 	mockDeptRepo.On("GetByID", ctx, newParentID).Return(&models.Department{ID: 3}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(1), newParentID).Return(true, nil)
 
-	descendants := []models.Department{
-		{ID: 2, Name: "Child", ParentID: &[]uint{1}[0]},
-		{ID: 3, Name: "Grandchild", ParentID: &[]uint{2}[0]},
-	}
-	mockDeptRepo.On("GetSubTree", ctx, uint(1), 100).Return(descendants, nil)
-
-	dept, err := service.Update(ctx, 1, nil, &newParentID)
+	dept, err := service.Update(ctx, 1, nil, &newParentID, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "cannot move department to its own descendant", err.Error())
@@ -392,6 +666,38 @@ func TestUpdate_CycleDetected(t *testing.T) {
 	mockDeptRepo.AssertNotCalled(t, "Update")
 }
 
+func TestUpdate_MoveRequiresApproval(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	service.approvalMode = ChangeApprovalRequired
+	ctx := context.Background()
+
+	parentID := uint(2)
+	newParentID := uint(3)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Dept", ParentID: &parentID}, nil)
+
+	_, err := service.Update(ctx, 1, nil, &newParentID, nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrApprovalRequired)
+
+	// Тот же родитель - не перенос, согласование не требуется.
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 2, Name: "Parent"}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(1), parentID).Return(false, nil)
+	mockDeptRepo.On("Update", ctx, mock.Anything).Return(nil).Once()
+
+	_, err = service.Update(ctx, 1, nil, &parentID, nil, nil)
+	assert.NoError(t, err)
+
+	// Согласованная заявка выполняет перенос.
+	approved := withApproval(ctx)
+	mockDeptRepo.On("GetByID", approved, uint(1)).Return(&models.Department{ID: 1, Name: "Dept", ParentID: &parentID}, nil)
+	mockDeptRepo.On("GetByID", approved, newParentID).Return(&models.Department{ID: 3, Name: "Other"}, nil)
+	mockDeptRepo.On("IsDescendant", approved, uint(1), newParentID).Return(false, nil)
+	mockDeptRepo.On("Update", approved, mock.Anything).Return(nil)
+
+	dept, err := service.Update(approved, 1, nil, &newParentID, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, newParentID, *dept.ParentID)
+}
+
 // --- Тесты для Delete ---
 
 func TestDelete_Cascade(t *testing.T) {
@@ -430,6 +736,29 @@ func TestDelete_Reassign(t *testing.T) {
 	mockEmpRepo.AssertExpectations(t)
 }
 
+func TestDelete_ReassignHeadcountLimit(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupDepartmentService(t)
+	service.guard.mode = HeadcountLimitHard
+	ctx := context.Background()
+	mockVacancyRepo := service.vacancyRepo.(*MockVacancyRepo)
+
+	reassignTo := uint(2)
+	target := &models.Department{ID: 2, Name: "Target", HeadcountLimit: intPtr(2)}
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "ToDelete"}, nil)
+	mockDeptRepo.On("GetByID", ctx, reassignTo).Return(target, nil)
+	mockDeptRepo.On("GetChildren", ctx, &[]uint{1}[0]).Return([]models.Department{}, nil)
+	mockDeptRepo.On("Lock", ctx, reassignTo).Return(target, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(1)).Return(int64(2), nil)
+	mockEmpRepo.On("CountEmployed", ctx, reassignTo).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, reassignTo).Return(int64(0), nil)
+
+	err := service.Delete(ctx, 1, "reassign", &reassignTo)
+
+	assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+	mockEmpRepo.AssertNotCalled(t, "MoveToDepartment", mock.Anything, mock.Anything, mock.Anything)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
 func TestDelete_NotFound(t *testing.T) {
 	service, mockDeptRepo, _ := setupDepartmentService(t)
 	ctx := context.Background()
@@ -471,4 +800,437 @@ func TestDelete_ReassignWithoutTarget(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "reassign_to_department_id is required for reassign mode", err.Error())
 	mockDeptRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestDelete_RequiresApproval(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	service.approvalMode = ChangeApprovalRequired
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Dept"}, nil)
+
+	err := service.Delete(ctx, 1, "cascade", nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrApprovalRequired)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// --- Тесты для Merge ---
+
+// Вспомогательная функция для создания сервиса с моком журнала изменений
+func setupMergeService(t *testing.T) (*DepService, *MockDepartmentRepo, *MockEmployeeRepo, *MockHistoryRepo) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewDepartmentService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), noAssignments(), mockHistoryRepo, fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	return service, mockDeptRepo, mockEmpRepo, mockHistoryRepo
+}
+
+func TestMerge_DryRun(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockHistoryRepo := setupMergeService(t)
+	ctx := context.Background()
+
+	sourceID, targetID := uint(1), uint(2)
+	backendSrcID, backendDstID := uint(3), uint(4)
+
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Source"}, nil)
+	mockDeptRepo.On("GetByID", ctx, targetID).Return(&models.Department{ID: 2, Name: "Target"}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, sourceID, targetID).Return(false, nil)
+	// Названия совпадают по ключу department_name_key, но не побайтово.
+	mockDeptRepo.On("GetChildren", ctx, &sourceID).Return([]models.Department{
+		{ID: 3, Name: "backend", NameKey: "backend", ParentID: &sourceID},
+		{ID: 6, Name: "Backend tools", NameKey: "backend tools", ParentID: &sourceID},
+	}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &targetID).Return([]models.Department{{ID: 4, Name: "Backend", NameKey: "backend", ParentID: &targetID}}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &backendSrcID).Return([]models.Department{{ID: 5, Name: "API", NameKey: "api", ParentID: &backendSrcID}}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &backendDstID).Return([]models.Department{}, nil)
+	mockEmpRepo.On("CountByDepartment", ctx, sourceID).Return(int64(2), nil)
+	mockEmpRepo.On("CountByDepartment", ctx, backendSrcID).Return(int64(3), nil)
+
+	result, err := service.Merge(ctx, sourceID, targetID, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, int64(5), result.MovedEmployees)
+	assert.Equal(t, []MergeStep{{DepartmentID: 1, TargetID: 2}, {DepartmentID: 3, TargetID: 4}}, result.MergedDepartments)
+	assert.Equal(t, []MergeStep{{DepartmentID: 5, TargetID: 4}, {DepartmentID: 6, TargetID: 2}}, result.MovedDepartments)
+	assert.Equal(t, []uint{1, 3}, result.DeletedDepartments)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	mockEmpRepo.AssertNotCalled(t, "MoveToDepartment", mock.Anything, mock.Anything, mock.Anything)
+	mockHistoryRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestMerge_HeadcountLimit(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, _ := setupMergeService(t)
+	ctx := context.Background()
+	mockVacancyRepo := service.vacancyRepo.(*MockVacancyRepo)
+
+	sourceID, targetID := uint(1), uint(2)
+	target := &models.Department{ID: 2, Name: "Target", HeadcountLimit: intPtr(3)}
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Source"}, nil)
+	mockDeptRepo.On("GetByID", ctx, targetID).Return(target, nil)
+	mockDeptRepo.On("Lock", ctx, targetID).Return(target, nil)
+	mockDeptRepo.On("IsDescendant", ctx, sourceID, targetID).Return(false, nil)
+	mockDeptRepo.On("GetChildren", ctx, &sourceID).Return([]models.Department{}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &targetID).Return([]models.Department{}, nil)
+	mockEmpRepo.On("CountByDepartment", ctx, sourceID).Return(int64(2), nil)
+	mockEmpRepo.On("CountEmployed", ctx, sourceID).Return(int64(2), nil)
+	mockEmpRepo.On("CountEmployed", ctx, targetID).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, sourceID).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, targetID).Return(int64(0), nil)
+
+	// Двое сотрудников и вакансия источника не помещаются в два свободных места цели:
+	// в мягком режиме план получает предупреждение, в жёстком слияние отклоняется.
+	result, err := service.Merge(ctx, sourceID, targetID, true)
+	assert.NoError(t, err)
+	if assert.Len(t, result.Warnings, 1) {
+		assert.Contains(t, result.Warnings[0], "1 of 3 budgeted seats")
+	}
+
+	service.guard.mode = HeadcountLimitHard
+	_, err = service.Merge(ctx, sourceID, targetID, false)
+	assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+	mockEmpRepo.AssertNotCalled(t, "MoveToDepartment", mock.Anything, mock.Anything, mock.Anything)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestMerge_Apply(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockHistoryRepo := setupMergeService(t)
+	ctx := context.Background()
+
+	sourceID, targetID := uint(1), uint(2)
+	qaID := uint(3)
+
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Source"}, nil)
+	mockDeptRepo.On("GetByID", ctx, targetID).Return(&models.Department{ID: 2, Name: "Target"}, nil)
+	mockDeptRepo.On("GetByID", ctx, qaID).Return(&models.Department{ID: 3, Name: "QA", ParentID: &sourceID}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, sourceID, targetID).Return(false, nil)
+	mockDeptRepo.On("GetChildren", ctx, &sourceID).Return([]models.Department{{ID: 3, Name: "QA", NameKey: "qa", ParentID: &sourceID}}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &targetID).Return([]models.Department{}, nil)
+	mockEmpRepo.On("CountByDepartment", ctx, sourceID).Return(int64(2), nil)
+	mockEmpRepo.On("MoveToDepartment", ctx, sourceID, targetID).Return(nil)
+	mockVacancyRepo := service.vacancyRepo.(*MockVacancyRepo)
+	mockVacancyRepo.On("MoveToDepartment", ctx, sourceID, targetID).Return(nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(dept *models.Department) bool {
+		return dept.ID == 3 && *dept.ParentID == targetID
+	})).Return(nil)
+	mockDeptRepo.On("Delete", ctx, sourceID).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.DepartmentHistory) bool {
+		return entry.DepartmentID == 2 && entry.Action == "merge"
+	})).Return(nil)
+
+	result, err := service.Merge(ctx, sourceID, targetID, false)
+
+	assert.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, int64(2), result.MovedEmployees)
+	mockDeptRepo.AssertExpectations(t)
+	mockEmpRepo.AssertExpectations(t)
+	mockVacancyRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestMerge_IntoSelf(t *testing.T) {
+	service, mockDeptRepo, _, _ := setupMergeService(t)
+	ctx := context.Background()
+
+	result, err := service.Merge(ctx, 1, 1, false)
+
+	assert.ErrorIs(t, err, apperrors.ErrMergeIntoSelf)
+	assert.Nil(t, result)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestMerge_IntoDescendant(t *testing.T) {
+	service, mockDeptRepo, _, _ := setupMergeService(t)
+	ctx := context.Background()
+
+	sourceID, targetID := uint(1), uint(3)
+
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Source"}, nil)
+	mockDeptRepo.On("GetByID", ctx, targetID).Return(&models.Department{ID: 3, Name: "Child", ParentID: &sourceID}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, sourceID, targetID).Return(true, nil)
+
+	result, err := service.Merge(ctx, sourceID, targetID, false)
+
+	assert.ErrorIs(t, err, apperrors.ErrMergeIntoDescendant)
+	assert.Nil(t, result)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+// --- Тесты для Clone ---
+
+func TestClone_WithVacancies(t *testing.T) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockVacancyRepo := new(MockVacancyRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewDepartmentService(mockDeptRepo, mockEmpRepo, mockVacancyRepo, noAssignments(), mockHistoryRepo, fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	ctx := context.Background()
+
+	sourceID, parentID := uint(1), uint(10)
+	nextID := 100
+
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Russia"}, nil)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 10, Name: "Regions"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Kazakhstan", &parentID).Return(nil, nil)
+	mockDeptRepo.On("GetDescendants", ctx, sourceID).Return([]models.Department{
+		{ID: 2, Name: "Sales", ParentID: &sourceID},
+		{ID: 3, Name: "Support", ParentID: &sourceID},
+	}, nil)
+	mockDeptRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Department).ID = nextID
+		nextID++
+	}).Return(nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", (*time.Time)(nil)).Return([]models.Employee{{ID: 7, Position: "Country Manager"}}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(2), "created_at", (*time.Time)(nil)).Return([]models.Employee{}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(3), "created_at", (*time.Time)(nil)).Return([]models.Employee{}, nil)
+	mockVacancyRepo.On("Create", ctx, mock.MatchedBy(func(v *models.Vacancy) bool {
+		return v.DepartmentID == 100 && v.Position == "Country Manager"
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.DepartmentHistory) bool {
+		return entry.DepartmentID == 100 && entry.Action == "clone"
+	})).Return(nil)
+
+	root, err := service.Clone(ctx, sourceID, " Kazakhstan ", &parentID, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, root.ID)
+	assert.Equal(t, "Kazakhstan", root.Name)
+	assert.Equal(t, parentID, *root.ParentID)
+	assert.Len(t, root.Vacancies, 1)
+	assert.Len(t, root.Children, 2)
+	assert.Equal(t, "Sales", root.Children[0].Name)
+	assert.Equal(t, uint(100), *root.Children[0].ParentID)
+	assert.Empty(t, root.Employees)
+	mockDeptRepo.AssertNumberOfCalls(t, "Create", 3)
+	mockVacancyRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestClone_CopiesDeepSubtree(t *testing.T) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewDepartmentService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), noAssignments(), mockHistoryRepo, fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	ctx := context.Background()
+
+	// Цепочка глубже прежнего ограничения в 100 уровней копируется целиком.
+	const depth = 150
+	descendants := make([]models.Department, 0, depth)
+	for i := 2; i <= depth+1; i++ {
+		parentID := uint(i - 1)
+		descendants = append(descendants, models.Department{ID: i, Name: fmt.Sprintf("Level %d", i), ParentID: &parentID})
+	}
+	nextID := 1000
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Russia"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Kazakhstan", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("GetDescendants", ctx, uint(1)).Return(descendants, nil)
+	mockDeptRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Department).ID = nextID
+		nextID++
+	}).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	root, err := service.Clone(ctx, 1, "Kazakhstan", nil, false)
+
+	assert.NoError(t, err)
+	levels := 0
+	for node := root; len(node.Children) > 0; node = &node.Children[0] {
+		levels++
+	}
+	assert.Equal(t, depth, levels)
+	mockDeptRepo.AssertNumberOfCalls(t, "Create", depth+1)
+}
+
+func TestClone_NameConflict(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Russia"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Russia", (*uint)(nil)).Return(&models.Department{ID: 1, Name: "Russia"}, nil)
+
+	root, err := service.Clone(ctx, 1, "Russia", nil, false)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	assert.Nil(t, root)
+	mockDeptRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestClone_InvalidName(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+
+	root, err := service.Clone(context.Background(), 1, "  ", nil, false)
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidDepartmentName)
+	assert.Nil(t, root)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+// --- Тесты для Reorder ---
+
+func TestReorder_MovesBeforeSibling(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	finance := &models.Department{ID: 4, Name: "Finance", ParentID: &parentID, SortOrder: 3}
+
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(finance, nil)
+	mockDeptRepo.On("GetChildren", ctx, &parentID).Return([]models.Department{
+		{ID: 2, Name: "IT", ParentID: &parentID, SortOrder: 1},
+		{ID: 3, Name: "HR", ParentID: &parentID, SortOrder: 2},
+		*finance,
+	}, nil)
+	mockDeptRepo.On("SetSortOrder", ctx, []uint{4, 2, 3}).Return(nil)
+
+	depts, err := service.Reorder(ctx, 4, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, depts, 3)
+	assert.Equal(t, "Finance", depts[0].Name)
+	assert.Equal(t, 1, depts[0].SortOrder)
+	assert.Equal(t, 3, depts[2].SortOrder)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestReorder_PositionPastEnd(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	it := &models.Department{ID: 2, Name: "IT", ParentID: &parentID, SortOrder: 1}
+
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(it, nil)
+	mockDeptRepo.On("GetChildren", ctx, &parentID).Return([]models.Department{
+		*it,
+		{ID: 3, Name: "HR", ParentID: &parentID, SortOrder: 2},
+	}, nil)
+	mockDeptRepo.On("SetSortOrder", ctx, []uint{3, 2}).Return(nil)
+
+	depts, err := service.Reorder(ctx, 2, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "IT", depts[1].Name)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestReorder_NegativePosition(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+
+	depts, err := service.Reorder(context.Background(), 2, -1)
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidSortPosition)
+	assert.Nil(t, depts)
+	mockDeptRepo.AssertNotCalled(t, "SetSortOrder", mock.Anything, mock.Anything)
+}
+
+func TestReorderChildren_Success(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 1, Name: "Company"}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &parentID).Return([]models.Department{
+		{ID: 2, Name: "IT", ParentID: &parentID},
+		{ID: 3, Name: "Finance", ParentID: &parentID},
+	}, nil)
+	mockDeptRepo.On("SetSortOrder", ctx, []uint{3, 2}).Return(nil)
+
+	depts, err := service.ReorderChildren(ctx, parentID, []uint{3, 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Finance", depts[0].Name)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestReorderChildren_IncompleteList(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 1, Name: "Company"}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &parentID).Return([]models.Department{
+		{ID: 2, Name: "IT", ParentID: &parentID},
+		{ID: 3, Name: "Finance", ParentID: &parentID},
+	}, nil)
+
+	depts, err := service.ReorderChildren(ctx, parentID, []uint{3, 3})
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidChildrenOrder)
+	assert.Nil(t, depts)
+	mockDeptRepo.AssertNotCalled(t, "SetSortOrder", mock.Anything, mock.Anything)
+}
+
+// --- Тесты для кодов и внешних идентификаторов ---
+
+func TestValidateCode(t *testing.T) {
+	code, err := ValidateCode(" eng-plt-01 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "ENG-PLT-01", code)
+
+	for _, bad := range []string{"", "ENG--PLT", "-ENG", "ENG PLT", "ОТД-1"} {
+		_, err := ValidateCode(bad)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidCode, bad)
+	}
+}
+
+func TestValidateExternalIDs(t *testing.T) {
+	ids, err := ValidateExternalIDs(map[string]string{"sap": " 1001 "})
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExternalIDs{"sap": "1001"}, ids)
+
+	_, err = ValidateExternalIDs(map[string]string{"SAP HR": "1001"})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidExternalID)
+
+	_, err = ValidateExternalIDs(map[string]string{"sap": " "})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidExternalID)
+}
+
+func TestSetIdentifiers_Success(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	dept := &models.Department{ID: 1, Name: "Platform"}
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(dept, nil)
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT-01").Return(nil, nil)
+	mockDeptRepo.On("GetByExternalID", ctx, "sap", "1001").Return(dept, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return *d.Code == "ENG-PLT-01" && d.ExternalIDs["sap"] == "1001"
+	})).Return(nil)
+
+	code := "eng-plt-01"
+	result, err := service.SetIdentifiers(ctx, 1, &code, map[string]string{"sap": "1001"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ENG-PLT-01", *result.Code)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestSetIdentifiers_CodeConflict(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT-01").Return(&models.Department{ID: 2, Name: "Other"}, nil)
+
+	code := "ENG-PLT-01"
+	result, err := service.SetIdentifiers(ctx, 1, &code, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentCodeConflict)
+	assert.Nil(t, result)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestGetByCode_NotFound(t *testing.T) {
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT-01").Return(nil, nil)
+
+	dept, err := service.GetByCode(ctx, "ENG-PLT-01", 1, false, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+	assert.Nil(t, dept)
+}
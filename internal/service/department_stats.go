@@ -0,0 +1,32 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// maxStatsMonths - наибольшее окно помесячной статистики найма.
+const maxStatsMonths = 60
+
+// Stats возвращает показатели численности и структуры поддерева подразделения
+// и помесячный найм за последние months месяцев, включая текущий.
+func (s *DepService) Stats(ctx context.Context, id uint, months int) (*models.DepartmentStats, error) {
+	if months < 1 || months > maxStatsMonths {
+		return nil, apperrors.ErrInvalidStatsWindow
+	}
+	dept, err := s.deptRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+
+	now := time.Now().UTC()
+	since := time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+	return s.deptRepo.Stats(ctx, id, since, months)
+}
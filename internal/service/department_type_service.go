@@ -0,0 +1,215 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// departmentTypeNamePattern - имена типов: строчные латинские буквы, цифры и подчёркивания.
+var departmentTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// DepartmentTypeRepository определяет интерфейс репозитория типов подразделений.
+type DepartmentTypeRepository interface {
+	Create(ctx context.Context, t *models.DepartmentType) error
+	GetByName(ctx context.Context, name string) (*models.DepartmentType, error)
+	List(ctx context.Context) ([]models.DepartmentType, error)
+	Update(ctx context.Context, t *models.DepartmentType) error
+	Delete(ctx context.Context, name string) error
+	CountUsage(ctx context.Context, name string) (int64, error)
+	CountViolations(ctx context.Context, t *models.DepartmentType) (int64, error)
+}
+
+// DepartmentTypeRules проверяет размещение подразделений по правилам вложенности типов.
+type DepartmentTypeRules interface {
+	// CheckPlacement проверяет, что подразделение типа deptType может находиться под parent
+	// (nil - корень) и быть родителем children. Подразделения без типа не ограничиваются.
+	CheckPlacement(ctx context.Context, deptType *string, parent *models.Department, children []models.Department) error
+}
+
+// DepartmentTypeService определяет интерфейс справочника типов подразделений,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type DepartmentTypeService interface {
+	Create(ctx context.Context, t models.DepartmentType) (*models.DepartmentType, error)
+	List(ctx context.Context) ([]models.DepartmentType, error)
+	Update(ctx context.Context, name string, t models.DepartmentType) (*models.DepartmentType, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// DeptTypeSvc реализует справочник типов подразделений и проверку правил вложенности.
+type DeptTypeSvc struct {
+	repo DepartmentTypeRepository
+	tx   Transactor
+}
+
+// NewDepartmentTypeService создаёт новый экземпляр сервиса типов подразделений.
+func NewDepartmentTypeService(repo DepartmentTypeRepository, tx Transactor) *DeptTypeSvc {
+	return &DeptTypeSvc{repo: repo, tx: tx}
+}
+
+// Create проверяет и сохраняет новый тип подразделения.
+func (s *DeptTypeSvc) Create(ctx context.Context, t models.DepartmentType) (*models.DepartmentType, error) {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetByName(ctx, strings.TrimSpace(t.Name))
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: %q", apperrors.ErrDepartmentTypeConflict, existing.Name)
+		}
+		if err := s.validate(ctx, &t); err != nil {
+			return err
+		}
+		err = s.repo.Create(ctx, &t)
+		if errors.Is(err, apperrors.ErrUniqueViolation) {
+			return fmt.Errorf("%w: %q", apperrors.ErrDepartmentTypeConflict, t.Name)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// List возвращает все типы подразделений.
+func (s *DeptTypeSvc) List(ctx context.Context) ([]models.DepartmentType, error) {
+	return s.repo.List(ctx)
+}
+
+// Update заменяет правила вложенности типа. Правила, которым не соответствуют
+// уже существующие подразделения этого типа, отклоняются.
+func (s *DeptTypeSvc) Update(ctx context.Context, name string, t models.DepartmentType) (*models.DepartmentType, error) {
+	var result *models.DepartmentType
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.get(ctx, name)
+		if err != nil {
+			return err
+		}
+		t.Name = existing.Name
+		if err := s.validate(ctx, &t); err != nil {
+			return err
+		}
+		violations, err := s.repo.CountViolations(ctx, &t)
+		if err != nil {
+			return err
+		}
+		if violations > 0 {
+			return fmt.Errorf("%w: %d department(s) of type %q would break the new rules",
+				apperrors.ErrDepartmentTypeRule, violations, name)
+		}
+		existing.AllowRoot = t.AllowRoot
+		existing.ParentTypes = t.ParentTypes
+		result = existing
+		return s.repo.Update(ctx, existing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete удаляет тип, не назначенный ни одному подразделению, и исключает его
+// из допустимых родителей других типов.
+func (s *DeptTypeSvc) Delete(ctx context.Context, name string) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.get(ctx, name); err != nil {
+			return err
+		}
+		count, err := s.repo.CountUsage(ctx, name)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %d department(s) have type %q", apperrors.ErrDepartmentTypeInUse, count, name)
+		}
+		return s.repo.Delete(ctx, name)
+	})
+}
+
+// CheckPlacement проверяет, что подразделение типа deptType может находиться под parent
+// и быть родителем children. Родитель и дети без типа правилам не мешают.
+func (s *DeptTypeSvc) CheckPlacement(ctx context.Context, deptType *string, parent *models.Department, children []models.Department) error {
+	if deptType == nil {
+		return nil
+	}
+	rule, err := s.get(ctx, *deptType)
+	if err != nil {
+		return err
+	}
+	switch {
+	case parent == nil && !rule.AllowRoot:
+		return fmt.Errorf("%w: type %q cannot be a root department", apperrors.ErrDepartmentTypeRule, rule.Name)
+	case parent != nil && parent.Type != nil && !slices.Contains(rule.ParentTypes, *parent.Type):
+		return fmt.Errorf("%w: type %q cannot be placed under %q", apperrors.ErrDepartmentTypeRule, rule.Name, *parent.Type)
+	}
+
+	checked := map[string]bool{}
+	for _, child := range children {
+		if child.Type == nil || checked[*child.Type] {
+			continue
+		}
+		childRule, err := s.get(ctx, *child.Type)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(childRule.ParentTypes, rule.Name) {
+			return fmt.Errorf("%w: child %q of type %q cannot be placed under %q",
+				apperrors.ErrDepartmentTypeRule, child.Name, childRule.Name, rule.Name)
+		}
+		checked[*child.Type] = true
+	}
+	return nil
+}
+
+// get возвращает тип подразделения по имени или ErrDepartmentTypeNotFound.
+func (s *DeptTypeSvc) get(ctx context.Context, name string) (*models.DepartmentType, error) {
+	t, err := s.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("%w: %q", apperrors.ErrDepartmentTypeNotFound, name)
+	}
+	return t, nil
+}
+
+// validate нормализует и проверяет имя и правила типа: допустимые родители должны
+// существовать (или совпадать с самим типом), а тип - иметь хотя бы одно допустимое место.
+func (s *DeptTypeSvc) validate(ctx context.Context, t *models.DepartmentType) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if !departmentTypeNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("%w: name must start with a letter and contain only lowercase letters, digits and underscores (max 50)",
+			apperrors.ErrInvalidDepartmentType)
+	}
+
+	parents := models.StringList{}
+	for _, p := range t.ParentTypes {
+		p = strings.TrimSpace(p)
+		if slices.Contains(parents, p) {
+			continue
+		}
+		if p != t.Name {
+			existing, err := s.repo.GetByName(ctx, p)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return fmt.Errorf("%w: unknown parent type %q", apperrors.ErrInvalidDepartmentType, p)
+			}
+		}
+		parents = append(parents, p)
+	}
+	if !t.AllowRoot && len(parents) == 0 {
+		return fmt.Errorf("%w: type must allow root or list at least one parent type", apperrors.ErrInvalidDepartmentType)
+	}
+	t.ParentTypes = parents
+	return nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDepartmentTypeRepo - мок для репозитория типов подразделений
+type MockDepartmentTypeRepo struct {
+	mock.Mock
+}
+
+func (m *MockDepartmentTypeRepo) Create(ctx context.Context, t *models.DepartmentType) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentTypeRepo) GetByName(ctx context.Context, name string) (*models.DepartmentType, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentType), args.Error(1)
+}
+
+func (m *MockDepartmentTypeRepo) List(ctx context.Context) ([]models.DepartmentType, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DepartmentType), args.Error(1)
+}
+
+func (m *MockDepartmentTypeRepo) Update(ctx context.Context, t *models.DepartmentType) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentTypeRepo) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentTypeRepo) CountUsage(ctx context.Context, name string) (int64, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDepartmentTypeRepo) CountViolations(ctx context.Context, t *models.DepartmentType) (int64, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// defaultTypes возвращает мок репозитория с типами company > division > department > team.
+func defaultTypes() *MockDepartmentTypeRepo {
+	repo := new(MockDepartmentTypeRepo)
+	for _, t := range []models.DepartmentType{
+		{Name: "company", AllowRoot: true, ParentTypes: models.StringList{}},
+		{Name: "division", ParentTypes: models.StringList{"company"}},
+		{Name: "department", ParentTypes: models.StringList{"company", "division"}},
+		{Name: "team", ParentTypes: models.StringList{"division", "department"}},
+	} {
+		repo.On("GetByName", mock.Anything, t.Name).Return(&t, nil).Maybe()
+	}
+	repo.On("GetByName", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return repo
+}
+
+func TestDepartmentTypeCheckPlacement(t *testing.T) {
+	ctx := context.Background()
+	service := NewDepartmentTypeService(defaultTypes(), fakeTx{})
+	division := &models.Department{ID: 1, Name: "Retail", Type: strPtr("division")}
+	team := &models.Department{ID: 2, Name: "Payments", Type: strPtr("team")}
+
+	assert.NoError(t, service.CheckPlacement(ctx, strPtr("company"), nil, nil))
+	assert.NoError(t, service.CheckPlacement(ctx, strPtr("team"), division, nil))
+	assert.NoError(t, service.CheckPlacement(ctx, strPtr("division"), &models.Department{ID: 3}, nil))
+	assert.NoError(t, service.CheckPlacement(ctx, nil, team, nil))
+
+	err := service.CheckPlacement(ctx, strPtr("division"), team, nil)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	err = service.CheckPlacement(ctx, strPtr("team"), nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	err = service.CheckPlacement(ctx, strPtr("team"), nil, []models.Department{*division})
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	err = service.CheckPlacement(ctx, strPtr("squad"), division, nil)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeNotFound)
+}
+
+func TestDepartmentTypeCreate(t *testing.T) {
+	ctx := context.Background()
+	repo := defaultTypes()
+	service := NewDepartmentTypeService(repo, fakeTx{})
+	repo.On("Create", ctx, mock.Anything).Return(nil)
+
+	created, err := service.Create(ctx, models.DepartmentType{Name: "squad", ParentTypes: models.StringList{" team ", "squad", "team"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StringList{"team", "squad"}, created.ParentTypes)
+
+	tests := []struct {
+		typ models.DepartmentType
+		err error
+	}{
+		{models.DepartmentType{Name: "Squad", AllowRoot: true}, apperrors.ErrInvalidDepartmentType},
+		{models.DepartmentType{Name: "squad"}, apperrors.ErrInvalidDepartmentType},
+		{models.DepartmentType{Name: "squad", ParentTypes: models.StringList{"guild"}}, apperrors.ErrInvalidDepartmentType},
+		{models.DepartmentType{Name: "team", ParentTypes: models.StringList{"department"}}, apperrors.ErrDepartmentTypeConflict},
+	}
+	for _, tt := range tests {
+		_, err := service.Create(ctx, tt.typ)
+		assert.ErrorIs(t, err, tt.err, tt.typ.Name)
+	}
+	repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestDepartmentTypeUpdate_RejectsViolations(t *testing.T) {
+	ctx := context.Background()
+	repo := defaultTypes()
+	service := NewDepartmentTypeService(repo, fakeTx{})
+	repo.On("CountViolations", ctx, mock.MatchedBy(func(t *models.DepartmentType) bool {
+		return t.Name == "team" && len(t.ParentTypes) == 1
+	})).Return(int64(2), nil)
+
+	_, err := service.Update(ctx, "team", models.DepartmentType{ParentTypes: models.StringList{"department"}})
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	repo.AssertNotCalled(t, "Update", ctx, mock.Anything)
+}
+
+func TestDepartmentTypeDelete_InUse(t *testing.T) {
+	ctx := context.Background()
+	repo := defaultTypes()
+	service := NewDepartmentTypeService(repo, fakeTx{})
+	repo.On("CountUsage", ctx, "team").Return(int64(3), nil)
+	repo.On("CountUsage", ctx, "division").Return(int64(0), nil)
+	repo.On("Delete", ctx, "division").Return(nil)
+
+	assert.ErrorIs(t, service.Delete(ctx, "team"), apperrors.ErrDepartmentTypeInUse)
+	assert.NoError(t, service.Delete(ctx, "division"))
+	assert.ErrorIs(t, service.Delete(ctx, "guild"), apperrors.ErrDepartmentTypeNotFound)
+}
+
+func TestDepartmentCreate_TypeRule(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewDepartmentService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo),
+		fakeSchema{}, NewDepartmentTypeService(defaultTypes(), fakeTx{}), DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	parentID := uint(2)
+	mockDeptRepo.On("GetByNameAndParent", ctx, mock.Anything, &parentID).Return(nil, nil)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 2, Name: "Payments", Type: strPtr("team")}, nil)
+
+	// Команда не может быть родителем дивизиона.
+	_, err := service.Create(ctx, "Retail", &parentID, strPtr("division"), nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockDeptRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+}
+
+func TestDepartmentUpdate_TypeChecksChildren(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewDepartmentService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo),
+		fakeSchema{}, NewDepartmentTypeService(defaultTypes(), fakeTx{}), DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	parentID := uint(1)
+	id := uint(2)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2, Name: "Retail", ParentID: &parentID}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &id).Return([]models.Department{{ID: 3, Name: "Web", Type: strPtr("department")}}, nil)
+	mockDeptRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+	_, err := service.Update(ctx, 2, nil, nil, strPtr("department"), nil)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+
+	dept, err := service.Update(ctx, 2, nil, nil, strPtr("division"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "division", *dept.Type)
+	mockDeptRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// typeRules возвращает сервис правил с типами из defaultTypes.
+func typeRules() *DeptTypeSvc {
+	return NewDepartmentTypeService(defaultTypes(), fakeTx{})
+}
+
+func TestScheduleDepartmentChange_TypeRule(t *testing.T) {
+	service, mockDeptRepo, _, mockVersionRepo, _ := setupVersionService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	team := &models.Department{ID: 5, Name: "Payments", Type: strPtr("team"), ParentID: uintPtr(2)}
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(team, nil)
+	mockDeptRepo.On("GetAsOf", ctx, uint(5), from).Return(team, nil)
+
+	// Команда не может стать корнем.
+	result, err := service.ScheduleDepartmentChange(ctx, 5, nil, uintPtr(0), from)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockVersionRepo.AssertNotCalled(t, "ScheduleDepartment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyDue_TypeRule(t *testing.T) {
+	service, mockDeptRepo, _, mockVersionRepo, mockHistoryRepo := setupVersionService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	companyID := uint(1)
+	version := models.DepartmentVersion{ID: 11, DepartmentID: 5, Name: "Payments", ParentID: &companyID, ValidFrom: versionToday}
+	mockVersionRepo.On("DueDepartmentVersions", ctx).Return([]models.DepartmentVersion{version}, nil)
+	mockVersionRepo.On("DueEmployeeVersions", ctx).Return([]models.EmployeeVersion{}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(&models.Department{ID: 5, Name: "Payments", Type: strPtr("team")}, nil)
+	mockDeptRepo.On("GetByID", ctx, companyID).Return(&models.Department{ID: 1, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(5), companyID).Return(false, nil)
+	mockVersionRepo.On("DiscardDepartmentVersion", ctx, mock.Anything).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(e *models.DepartmentHistory) bool {
+		return e.DepartmentID == 5 && e.Action == "scheduled_change_failed"
+	})).Return(nil)
+
+	applied, err := service.ApplyDue(ctx)
+
+	assert.Equal(t, 0, applied)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockVersionRepo.AssertExpectations(t)
+}
+
+func TestReorgSubmit_TypeRule(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Payments", Type: strPtr("team")}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(4), uint(2)).Return(false, nil)
+
+	batch, err := service.Submit(ctx, clock.now.Add(time.Hour), []models.ReorgOperation{
+		{Type: models.ReorgOpMove, DepartmentID: 4, ParentID: uintPtr(2)},
+	})
+
+	assert.Nil(t, batch)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockReorgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestChangeRequestSubmit_TypeRule(t *testing.T) {
+	service, mockRequestRepo, mockDeptRepo, mockEmpRepo, _ := setupChangeRequestService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Payments", Type: strPtr("team")}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(4), uint(2)).Return(false, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(42)).Return(&models.Employee{ID: 42}, nil).Maybe()
+
+	_, err := service.Submit(ctx, models.ChangeRequest{
+		Operation:    models.ChangeOpMove,
+		DepartmentID: 4,
+		Payload:      models.ChangeRequestPayload{ParentID: uintPtr(2)},
+		RequestedBy:  42,
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockRequestRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_CreateTypeRule(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByCode", ctx, "ACME").Return(&models.Department{ID: 1, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("GetByCode", ctx, "PAY").Return(nil, nil)
+
+	_, err := service.Import(ctx, []ImportDepartment{{Code: strPtr("PAY"), Name: "Payments", Type: strPtr("team"),
+		ParentCode: strPtr("ACME")}}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockDeptRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_MoveTypeRule(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	payments := &models.Department{ID: 5, Name: "Payments", Type: strPtr("team"), ParentID: uintPtr(3), Code: strPtr("PAY")}
+	mockDeptRepo.On("GetByCode", ctx, "PAY").Return(payments, nil)
+
+	// Команду нельзя перенести в корень.
+	_, err := service.Import(ctx, []ImportDepartment{{Code: strPtr("PAY"), Name: "Payments", MoveToRoot: true}}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestMerge_TypeRuleForMovedChildren(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, _ := setupMergeService(t)
+	service.types = typeRules()
+	ctx := context.Background()
+
+	sourceID, targetID := uint(1), uint(2)
+	mockDeptRepo.On("GetByID", ctx, sourceID).Return(&models.Department{ID: 1, Name: "Retail", Type: strPtr("division")}, nil)
+	mockDeptRepo.On("GetByID", ctx, targetID).Return(&models.Department{ID: 2, Name: "Acme", Type: strPtr("company")}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, sourceID, targetID).Return(false, nil)
+	mockDeptRepo.On("GetChildren", ctx, &sourceID).Return([]models.Department{
+		{ID: 3, Name: "Web", NameKey: "web", Type: strPtr("department"), ParentID: &sourceID},
+		{ID: 4, Name: "Payments", NameKey: "payments", Type: strPtr("team"), ParentID: &sourceID},
+	}, nil)
+	mockDeptRepo.On("GetChildren", ctx, &targetID).Return([]models.Department{}, nil)
+	mockEmpRepo.On("CountByDepartment", ctx, sourceID).Return(int64(0), nil)
+
+	// Команда из дивизиона не может оказаться прямо под компанией, даже в предпросмотре.
+	for _, dryRun := range []bool{true, false} {
+		result, err := service.Merge(ctx, sourceID, targetID, dryRun)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, apperrors.ErrDepartmentTypeRule)
+	}
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockDeptRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
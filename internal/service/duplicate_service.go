@@ -0,0 +1,335 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// DefaultDuplicateConfidence - минимальная уверенность пары по умолчанию: одного совпадения
+// имени недостаточно, нужен ещё хотя бы один признак.
+const DefaultDuplicateConfidence = 0.6
+
+// Признаки совпадения пары сотрудников.
+const (
+	DuplicateSameName           = "same_name"
+	DuplicateSameHireDate       = "same_hire_date"
+	DuplicateCloseHireDate      = "close_hire_date"
+	DuplicateSameDepartment     = "same_department"
+	DuplicateAdjacentDepartment = "adjacent_department"
+	DuplicateNearbyDepartment   = "nearby_department"
+)
+
+// duplicateWeights - вклад признаков в уверенность пары; сумма всех признаков не больше 1.
+var duplicateWeights = map[string]float64{
+	DuplicateSameName:           0.5,
+	DuplicateSameHireDate:       0.3,
+	DuplicateCloseHireDate:      0.15,
+	DuplicateSameDepartment:     0.2,
+	DuplicateAdjacentDepartment: 0.15,
+	DuplicateNearbyDepartment:   0.1,
+}
+
+// closeHireDays - наибольшая разница дат найма, при которой они считаются близкими.
+const closeHireDays = 31
+
+// EmployeeMergeRepository определяет интерфейс репозитория слияния сотрудников.
+type EmployeeMergeRepository interface {
+	Merge(ctx context.Context, targetID uint, source *models.Employee) (*models.EmployeeMerge, error)
+}
+
+// DuplicateService определяет интерфейс поиска и слияния дубликатов сотрудников,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type DuplicateService interface {
+	Find(ctx context.Context, minConfidence float64) ([]DuplicateCluster, error)
+	Merge(ctx context.Context, targetID uint, sourceIDs []uint) (*EmployeeMergeResult, error)
+}
+
+// DuplicateCluster описывает группу записей, вероятно относящихся к одному человеку.
+// Confidence - наибольшая уверенность среди пар группы.
+type DuplicateCluster struct {
+	Confidence float64           `json:"confidence"`
+	Employees  []models.Employee `json:"employees"`
+	Pairs      []DuplicatePair   `json:"pairs"`
+}
+
+// DuplicatePair описывает пару записей группы и признаки, по которым они совпали.
+type DuplicatePair struct {
+	EmployeeIDs [2]int   `json:"employee_ids"`
+	Confidence  float64  `json:"confidence"`
+	Reasons     []string `json:"reasons"`
+}
+
+// EmployeeMergeResult описывает результат слияния: основную запись после слияния
+// и записи журнала по каждому поглощённому дубликату.
+type EmployeeMergeResult struct {
+	Employee *models.Employee       `json:"employee"`
+	Merges   []models.EmployeeMerge `json:"merges"`
+}
+
+// DuplicateSvc реализует поиск дубликатов сотрудников по нормализованному имени, дате найма
+// и близости подразделений в дереве и слияние дубликатов с основной записью.
+type DuplicateSvc struct {
+	empRepo     EmployeeRepository
+	deptRepo    DepartmentRepository
+	mergeRepo   EmployeeMergeRepository
+	historyRepo HistoryRepository
+	tx          Transactor
+}
+
+// NewDuplicateService создаёт новый экземпляр сервиса дубликатов сотрудников.
+func NewDuplicateService(empRepo EmployeeRepository, deptRepo DepartmentRepository, mergeRepo EmployeeMergeRepository,
+	historyRepo HistoryRepository, tx Transactor) *DuplicateSvc {
+	return &DuplicateSvc{empRepo: empRepo, deptRepo: deptRepo, mergeRepo: mergeRepo, historyRepo: historyRepo, tx: tx}
+}
+
+// Find возвращает группы вероятных дубликатов, связанные парами с уверенностью не ниже
+// minConfidence, по убыванию уверенности. Кандидаты - сотрудники с одинаковым ключом
+// транслитерации имени, поэтому "Иван Петров" и "Ivan Petrov" попадают в одну группу.
+func (s *DuplicateSvc) Find(ctx context.Context, minConfidence float64) ([]DuplicateCluster, error) {
+	if minConfidence <= 0 || minConfidence > 1 {
+		return nil, fmt.Errorf("%w: min_confidence must be in (0, 1]", apperrors.ErrInvalidDuplicateQuery)
+	}
+	depts, err := s.deptRepo.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := s.empRepo.List(ctx, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[int]int, len(depts))
+	for _, dept := range depts {
+		if dept.ParentID != nil {
+			parents[dept.ID] = int(*dept.ParentID)
+		}
+	}
+
+	groups := make(map[string][]models.Employee)
+	for _, emp := range employees {
+		key := emp.SearchKey
+		if key == "" {
+			key = strings.ToLower(strings.Join(strings.Fields(emp.FullName), " "))
+		}
+		groups[key] = append(groups[key], emp)
+	}
+
+	clusters := []DuplicateCluster{}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+
+		// Пары с достаточной уверенностью объединяют записи в группы (объединение множеств).
+		root := make([]int, len(group))
+		for i := range root {
+			root[i] = i
+		}
+		var find func(i int) int
+		find = func(i int) int {
+			if root[i] != i {
+				root[i] = find(root[i])
+			}
+			return root[i]
+		}
+		pairs := make(map[int][]DuplicatePair)
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				pair := scoreDuplicates(group[i], group[j], parents)
+				if pair.Confidence < minConfidence {
+					continue
+				}
+				root[find(j)] = find(i)
+				pairs[i] = append(pairs[i], pair)
+			}
+		}
+
+		byRoot := make(map[int]*DuplicateCluster)
+		var roots []int
+		for i, emp := range group {
+			r := find(i)
+			cluster, ok := byRoot[r]
+			if !ok {
+				cluster = &DuplicateCluster{}
+				byRoot[r] = cluster
+				roots = append(roots, r)
+			}
+			cluster.Employees = append(cluster.Employees, emp)
+			for _, pair := range pairs[i] {
+				cluster.Pairs = append(cluster.Pairs, pair)
+				cluster.Confidence = math.Max(cluster.Confidence, pair.Confidence)
+			}
+		}
+		for _, r := range roots {
+			if len(byRoot[r].Employees) > 1 {
+				clusters = append(clusters, *byRoot[r])
+			}
+		}
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Confidence != clusters[j].Confidence {
+			return clusters[i].Confidence > clusters[j].Confidence
+		}
+		return clusters[i].Employees[0].ID < clusters[j].Employees[0].ID
+	})
+	return clusters, nil
+}
+
+// scoreDuplicates вычисляет уверенность того, что записи a и b с одинаковым
+// нормализованным именем относятся к одному человеку.
+func scoreDuplicates(a, b models.Employee, parents map[int]int) DuplicatePair {
+	reasons := []string{DuplicateSameName}
+	if a.HiredAt != nil && b.HiredAt != nil {
+		days := math.Abs(a.HiredAt.Sub(*b.HiredAt).Hours() / 24)
+		switch {
+		case days < 1:
+			reasons = append(reasons, DuplicateSameHireDate)
+		case days <= closeHireDays:
+			reasons = append(reasons, DuplicateCloseHireDate)
+		}
+	}
+	switch departmentDistance(a.DepartmentID, b.DepartmentID, parents) {
+	case 0:
+		reasons = append(reasons, DuplicateSameDepartment)
+	case 1:
+		reasons = append(reasons, DuplicateAdjacentDepartment)
+	case 2:
+		reasons = append(reasons, DuplicateNearbyDepartment)
+	}
+
+	var confidence float64
+	for _, reason := range reasons {
+		confidence += duplicateWeights[reason]
+	}
+	return DuplicatePair{
+		EmployeeIDs: [2]int{a.ID, b.ID},
+		Confidence:  math.Round(confidence*100) / 100,
+		Reasons:     reasons,
+	}
+}
+
+// departmentDistance возвращает число рёбер дерева между подразделениями a и b
+// или -1, если у них нет общего предка.
+func departmentDistance(a, b int, parents map[int]int) int {
+	depthA := map[int]int{}
+	for id, depth := a, 0; ; depth++ {
+		depthA[id] = depth
+		parent, ok := parents[id]
+		if !ok {
+			break
+		}
+		id = parent
+	}
+	for id, depth := b, 0; ; depth++ {
+		if d, ok := depthA[id]; ok {
+			return d + depth
+		}
+		parent, ok := parents[id]
+		if !ok {
+			return -1
+		}
+		id = parent
+	}
+}
+
+// Merge сливает дубликаты sourceIDs с основной записью targetID одной транзакцией.
+// Основная запись получает самую раннюю дату найма, недостающие код, идентификаторы
+// внешних систем и атрибуты дубликатов, а также руководство их подразделениями.
+// Дубликаты удаляются; их состояние сохраняется в журнале слияний, версии - под прежними
+// идентификаторами. Разные идентификаторы одной внешней системы отклоняют слияние.
+func (s *DuplicateSvc) Merge(ctx context.Context, targetID uint, sourceIDs []uint) (*EmployeeMergeResult, error) {
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one duplicate id is required", apperrors.ErrInvalidEmployeeMerge)
+	}
+	seen := map[uint]bool{targetID: true}
+	for _, id := range sourceIDs {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: duplicate ids must be distinct and differ from the target", apperrors.ErrInvalidEmployeeMerge)
+		}
+		seen[id] = true
+	}
+
+	var result *EmployeeMergeResult
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.empRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return fmt.Errorf("%w: %d", apperrors.ErrEmployeeNotFound, targetID)
+		}
+		sources := make([]*models.Employee, 0, len(sourceIDs))
+		for _, id := range sourceIDs {
+			source, err := s.empRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if source == nil {
+				return fmt.Errorf("%w: %d", apperrors.ErrEmployeeNotFound, id)
+			}
+			if err := absorbEmployee(target, source); err != nil {
+				return err
+			}
+			sources = append(sources, source)
+		}
+
+		result = &EmployeeMergeResult{Employee: target, Merges: make([]models.EmployeeMerge, 0, len(sources))}
+		for _, source := range sources {
+			merge, err := s.mergeRepo.Merge(ctx, targetID, source)
+			if err != nil {
+				return err
+			}
+			result.Merges = append(result.Merges, *merge)
+		}
+		// Код дубликата переходит к основной записи только после удаления дубликата.
+		if err := s.empRepo.Update(ctx, target); err != nil {
+			return err
+		}
+		return writeHistory(ctx, s.historyRepo, uint(target.DepartmentID), "employee_merge", map[string]any{
+			"employee_id":         target.ID,
+			"merged_employee_ids": sourceIDs,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// absorbEmployee переносит в target данные source, которых у target нет.
+func absorbEmployee(target, source *models.Employee) error {
+	if source.HiredAt != nil && (target.HiredAt == nil || source.HiredAt.Before(*target.HiredAt)) {
+		target.HiredAt = source.HiredAt
+	}
+	if target.Code == nil {
+		target.Code = source.Code
+	}
+	for system, id := range source.ExternalIDs {
+		if existing, ok := target.ExternalIDs[system]; ok && existing != id {
+			return fmt.Errorf("%w: %s ids %q and %q", apperrors.ErrEmployeeMergeConflict, system, existing, id)
+		}
+		if target.ExternalIDs == nil {
+			target.ExternalIDs = models.ExternalIDs{}
+		}
+		target.ExternalIDs[system] = id
+	}
+	for name, value := range source.Attributes {
+		if _, ok := target.Attributes[name]; ok {
+			continue
+		}
+		if target.Attributes == nil {
+			target.Attributes = models.Attributes{}
+		}
+		target.Attributes[name] = value
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEmployeeMergeRepo - мок для репозитория слияния сотрудников
+type MockEmployeeMergeRepo struct {
+	mock.Mock
+}
+
+func (m *MockEmployeeMergeRepo) Merge(ctx context.Context, targetID uint, source *models.Employee) (*models.EmployeeMerge, error) {
+	args := m.Called(ctx, targetID, source)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmployeeMerge), args.Error(1)
+}
+
+func setupDuplicateService(t *testing.T) (*DuplicateSvc, *MockEmployeeRepo, *MockDepartmentRepo, *MockEmployeeMergeRepo, *MockHistoryRepo) {
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockMergeRepo := new(MockEmployeeMergeRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewDuplicateService(mockEmpRepo, mockDeptRepo, mockMergeRepo, mockHistoryRepo, fakeTx{})
+	return service, mockEmpRepo, mockDeptRepo, mockMergeRepo, mockHistoryRepo
+}
+
+func TestFindDuplicates(t *testing.T) {
+	service, mockEmpRepo, mockDeptRepo, _, _ := setupDuplicateService(t)
+	ctx := context.Background()
+	hired := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	later := hired.AddDate(0, 0, 10)
+
+	// HQ -> Sales -> Inside Sales, HQ -> Ops.
+	mockDeptRepo.On("List", ctx, models.Attributes(nil), (*time.Time)(nil)).Return([]models.Department{
+		{ID: 1, Name: "HQ"},
+		{ID: 2, Name: "Sales", ParentID: uintPtr(1)},
+		{ID: 3, Name: "Inside Sales", ParentID: uintPtr(2)},
+		{ID: 4, Name: "Ops", ParentID: uintPtr(1)},
+	}, nil)
+	mockEmpRepo.On("List", ctx, (*uint)(nil), models.Attributes(nil), (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 10, FullName: "Иван Петров", SearchKey: "ivan petrov", DepartmentID: 2, HiredAt: &hired},
+		{ID: 11, FullName: "Ivan Petrov", SearchKey: "ivan petrov", DepartmentID: 2, HiredAt: &hired},
+		{ID: 12, FullName: "Ivan  Petrov", SearchKey: "ivan petrov", DepartmentID: 3, HiredAt: &later},
+		{ID: 13, FullName: "Ivan Petrov", SearchKey: "ivan petrov", DepartmentID: 4},
+		{ID: 20, FullName: "Anna Smirnova", SearchKey: "ana smirnova", DepartmentID: 2},
+	}, nil)
+
+	clusters, err := service.Find(ctx, DefaultDuplicateConfidence)
+
+	assert.NoError(t, err)
+	assert.Len(t, clusters, 1)
+	assert.Equal(t, 1.0, clusters[0].Confidence)
+	var ids []int
+	for _, emp := range clusters[0].Employees {
+		ids = append(ids, emp.ID)
+	}
+	// 13 в Ops без даты найма: только имя и соседство через HQ, уверенность 0.6.
+	assert.Equal(t, []int{10, 11, 12, 13}, ids)
+	assert.Contains(t, clusters[0].Pairs, DuplicatePair{
+		EmployeeIDs: [2]int{10, 12}, Confidence: 0.8,
+		Reasons: []string{DuplicateSameName, DuplicateCloseHireDate, DuplicateAdjacentDepartment},
+	})
+
+	strict, err := service.Find(ctx, 0.9)
+	assert.NoError(t, err)
+	assert.Len(t, strict, 1)
+	assert.Len(t, strict[0].Employees, 2)
+
+	_, err = service.Find(ctx, 1.5)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidDuplicateQuery)
+}
+
+func TestMergeEmployees(t *testing.T) {
+	service, mockEmpRepo, _, mockMergeRepo, mockHistoryRepo := setupDuplicateService(t)
+	ctx := context.Background()
+	hired := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	earlier := hired.AddDate(0, -1, 0)
+	code := "E-11"
+
+	target := &models.Employee{ID: 10, DepartmentID: 2, FullName: "Иван Петров", HiredAt: &hired,
+		ExternalIDs: models.ExternalIDs{"hr": "100"}, Attributes: models.Attributes{"grade": "senior"}}
+	source := &models.Employee{ID: 11, DepartmentID: 2, FullName: "Ivan Petrov", HiredAt: &earlier, Code: &code,
+		ExternalIDs: models.ExternalIDs{"hr": "100", "crm": "7"}, Attributes: models.Attributes{"grade": "junior", "office": "Kazan"}}
+	mockEmpRepo.On("GetByID", ctx, uint(10)).Return(target, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(11)).Return(source, nil)
+	mockMergeRepo.On("Merge", ctx, uint(10), source).Return(&models.EmployeeMerge{ID: 1, SourceID: 11, TargetID: 10}, nil)
+	mockEmpRepo.On("Update", ctx, mock.AnythingOfType("*models.Employee")).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(h *models.DepartmentHistory) bool {
+		return h.DepartmentID == 2 && h.Action == "employee_merge"
+	})).Return(nil)
+
+	result, err := service.Merge(ctx, 10, []uint{11})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &earlier, result.Employee.HiredAt)
+	assert.Equal(t, &code, result.Employee.Code)
+	assert.Equal(t, models.ExternalIDs{"hr": "100", "crm": "7"}, result.Employee.ExternalIDs)
+	assert.Equal(t, models.Attributes{"grade": "senior", "office": "Kazan"}, result.Employee.Attributes)
+	assert.Len(t, result.Merges, 1)
+	mockMergeRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestMergeEmployees_ExternalIDConflict(t *testing.T) {
+	service, mockEmpRepo, _, mockMergeRepo, _ := setupDuplicateService(t)
+	ctx := context.Background()
+
+	mockEmpRepo.On("GetByID", ctx, uint(10)).Return(&models.Employee{ID: 10, ExternalIDs: models.ExternalIDs{"hr": "100"}}, nil)
+	mockEmpRepo.On("GetByID", ctx, uint(11)).Return(&models.Employee{ID: 11, ExternalIDs: models.ExternalIDs{"hr": "200"}}, nil)
+
+	_, err := service.Merge(ctx, 10, []uint{11})
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeMergeConflict)
+	mockMergeRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeEmployees_InvalidIDs(t *testing.T) {
+	service, _, _, _, _ := setupDuplicateService(t)
+	ctx := context.Background()
+
+	for _, ids := range [][]uint{nil, {10}, {11, 11}} {
+		_, err := service.Merge(ctx, 10, ids)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidEmployeeMerge)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,6 +31,27 @@ func (m *MockEmployeeRepoForService) GetByID(ctx context.Context, id uint) (*mod
 	return args.Get(0).(*models.Employee), args.Error(1)
 }
 
+func (m *MockEmployeeRepoForService) GetByCode(ctx context.Context, code string) (*models.Employee, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepoForService) List(ctx context.Context, departmentID *uint, attrFilter models.Attributes, asOf *time.Time) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID, attrFilter, asOf)
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockEmployeeRepoForService) GetByExternalID(ctx context.Context, system, externalID string) (*models.Employee, error) {
+	args := m.Called(ctx, system, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Employee), args.Error(1)
+}
+
 func (m *MockEmployeeRepoForService) Update(ctx context.Context, emp *models.Employee) error {
 	args := m.Called(ctx, emp)
 	return args.Error(0)
@@ -40,8 +62,8 @@ func (m *MockEmployeeRepoForService) Delete(ctx context.Context, id uint) error
 	return args.Error(0)
 }
 
-func (m *MockEmployeeRepoForService) ListByDepartment(ctx context.Context, departmentID uint, orderBy string) ([]models.Employee, error) {
-	args := m.Called(ctx, departmentID, orderBy)
+func (m *MockEmployeeRepoForService) ListByDepartment(ctx context.Context, departmentID uint, orderBy string, asOf *time.Time) ([]models.Employee, error) {
+	args := m.Called(ctx, departmentID, orderBy, asOf)
 	return args.Get(0).([]models.Employee), args.Error(1)
 }
 
@@ -50,6 +72,21 @@ func (m *MockEmployeeRepoForService) MoveToDepartment(ctx context.Context, depar
 	return args.Error(0)
 }
 
+func (m *MockEmployeeRepoForService) CountByDepartment(ctx context.Context, departmentID uint) (int64, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEmployeeRepoForService) CountEmployed(ctx context.Context, departmentID uint) (int64, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockEmployeeRepoForService) Terminate(ctx context.Context, emp *models.Employee) error {
+	args := m.Called(ctx, emp)
+	return args.Error(0)
+}
+
 // MockDepartmentRepoForEmployee - мок для repository.DepartmentRepo (нужен только GetByID)
 type MockDepartmentRepoForEmployee struct {
 	mock.Mock
@@ -68,6 +105,14 @@ func (m *MockDepartmentRepoForEmployee) GetByID(ctx context.Context, id uint) (*
 	return args.Get(0).(*models.Department), args.Error(1)
 }
 
+func (m *MockDepartmentRepoForEmployee) Lock(ctx context.Context, id uint) (*models.Department, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
 func (m *MockDepartmentRepoForEmployee) Update(ctx context.Context, dept *models.Department) error {
 	args := m.Called(ctx, dept)
 	return args.Error(0)
@@ -83,11 +128,76 @@ func (m *MockDepartmentRepoForEmployee) GetChildren(ctx context.Context, parentI
 	return args.Get(0).([]models.Department), args.Error(1)
 }
 
-func (m *MockDepartmentRepoForEmployee) GetSubTree(ctx context.Context, rootID uint, depth int) ([]models.Department, error) {
-	args := m.Called(ctx, rootID, depth)
+func (m *MockDepartmentRepoForEmployee) GetSubTree(ctx context.Context, rootID uint, depth int, asOf *time.Time) ([]models.Department, error) {
+	args := m.Called(ctx, rootID, depth, asOf)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) GetDescendants(ctx context.Context, rootID uint) ([]models.Department, error) {
+	args := m.Called(ctx, rootID)
 	return args.Get(0).([]models.Department), args.Error(1)
 }
 
+func (m *MockDepartmentRepoForEmployee) GetAsOf(ctx context.Context, id uint, asOf time.Time) (*models.Department, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) GetAncestors(ctx context.Context, id uint) ([]models.Department, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) IsDescendant(ctx context.Context, ancestorID, id uint) (bool, error) {
+	args := m.Called(ctx, ancestorID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) SetSortOrder(ctx context.Context, ids []uint) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func (m *MockDepartmentRepoForEmployee) GetByCode(ctx context.Context, code string) (*models.Department, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) Stats(ctx context.Context, id uint, since time.Time, months int) (*models.DepartmentStats, error) {
+	args := m.Called(ctx, id, since, months)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DepartmentStats), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) HeadcountBudget(ctx context.Context, id uint) ([]models.HeadcountBudget, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HeadcountBudget), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) List(ctx context.Context, attrFilter models.Attributes, asOf *time.Time) ([]models.Department, error) {
+	args := m.Called(ctx, attrFilter, asOf)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockDepartmentRepoForEmployee) GetByExternalID(ctx context.Context, system, externalID string) (*models.Department, error) {
+	args := m.Called(ctx, system, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Department), args.Error(1)
+}
+
 func (m *MockDepartmentRepoForEmployee) GetByNameAndParent(ctx context.Context, name string, parentID *uint) (*models.Department, error) {
 	args := m.Called(ctx, name, parentID)
 	if args.Get(0) == nil {
@@ -100,7 +210,10 @@ func (m *MockDepartmentRepoForEmployee) GetByNameAndParent(ctx context.Context,
 func setupEmployeeService(t *testing.T) (*EmpService, *MockEmployeeRepoForService, *MockDepartmentRepoForEmployee) {
 	mockEmpRepo := new(MockEmployeeRepoForService)
 	mockDeptRepo := new(MockDepartmentRepoForEmployee)
-	service := NewEmpService(mockEmpRepo, mockDeptRepo)
+	// Названия должностей в тестах не найдены в справочнике.
+	mockPosRepo := new(MockPositionRepo)
+	mockPosRepo.On("GetByTitle", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	service := NewEmpService(mockEmpRepo, mockDeptRepo, mockPosRepo, new(MockVacancyRepo), fakeSchema{}, fakeTx{}, HeadcountLimitSoft)
 	return service, mockEmpRepo, mockDeptRepo
 }
 
@@ -124,7 +237,7 @@ func TestEmployeeCreate_Success(t *testing.T) {
 			emp.HiredAt == &hiredAt
 	})).Return(nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", &hiredAt)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, &hiredAt, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, emp)
@@ -154,7 +267,7 @@ func TestEmployeeCreate_Success_WithoutHiredAt(t *testing.T) {
 			emp.HiredAt == nil
 	})).Return(nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, emp)
@@ -176,7 +289,7 @@ func TestEmployeeCreate_DepartmentNotFound(t *testing.T) {
 	// Отдел не найден
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(nil, nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "department not found", err.Error())
@@ -196,7 +309,7 @@ func TestEmployeeCreate_DepartmentError(t *testing.T) {
 	// Ошибка при проверке отдела
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(nil, dbErr)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, dbErr, err)
@@ -215,7 +328,7 @@ func TestEmployeeCreate_EmptyFullName(t *testing.T) {
 	// Отдел существует
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(&models.Department{ID: 1, Name: "IT"}, nil)
 
-	emp, err := service.Create(ctx, departmentID, "", "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, "", "Developer", nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "full_name must be not empty", err.Error())
@@ -235,7 +348,7 @@ func TestEmployeeCreate_FullNameTooLong(t *testing.T) {
 	// Отдел существует
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(&models.Department{ID: 1, Name: "IT"}, nil)
 
-	emp, err := service.Create(ctx, departmentID, longName, "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, longName, "Developer", nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "full_name can't be longer than 200", err.Error())
@@ -254,7 +367,7 @@ func TestEmployeeCreate_EmptyPosition(t *testing.T) {
 	// Отдел существует
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(&models.Department{ID: 1, Name: "IT"}, nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "", nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "", nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "position must be non-empty and max 200 characters", err.Error())
@@ -274,7 +387,7 @@ func TestEmployeeCreate_PositionTooLong(t *testing.T) {
 	// Отдел существует
 	mockDeptRepo.On("GetByID", ctx, departmentID).Return(&models.Department{ID: 1, Name: "IT"}, nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", longPosition, nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", longPosition, nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "position must be non-empty and max 200 characters", err.Error())
@@ -299,7 +412,7 @@ func TestEmployeeCreate_TrimSpaces(t *testing.T) {
 			emp.Position == "Developer" // пробелы обрезаны
 	})).Return(nil)
 
-	emp, err := service.Create(ctx, departmentID, "  John Doe  ", "  Developer  ", nil)
+	emp, err := service.Create(ctx, departmentID, "  John Doe  ", "  Developer  ", nil, nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, emp)
@@ -328,7 +441,7 @@ func TestEmployeeCreate_WithHiredAt(t *testing.T) {
 			emp.HiredAt.Equal(hiredAt)
 	})).Return(nil)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", &hiredAt)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, &hiredAt, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, emp)
@@ -353,7 +466,7 @@ func TestEmployeeCreate_CreateError(t *testing.T) {
 	// Ошибка при создании
 	mockEmpRepo.On("Create", ctx, mock.Anything).Return(dbErr)
 
-	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil)
+	emp, err := service.Create(ctx, departmentID, "John Doe", "Developer", nil, nil, nil, nil)
 
 	// Проверяем, что вернулась ошибка
 	assert.Error(t, err)
@@ -363,3 +476,181 @@ func TestEmployeeCreate_CreateError(t *testing.T) {
 	mockDeptRepo.AssertExpectations(t)
 	mockEmpRepo.AssertExpectations(t)
 }
+
+func TestEmployeeCreate_CatalogPosition(t *testing.T) {
+	mockEmpRepo := new(MockEmployeeRepoForService)
+	mockDeptRepo := new(MockDepartmentRepoForEmployee)
+	mockPosRepo := new(MockPositionRepo)
+	service := NewEmpService(mockEmpRepo, mockDeptRepo, mockPosRepo, new(MockVacancyRepo), fakeSchema{}, fakeTx{}, HeadcountLimitSoft)
+	ctx := context.Background()
+
+	backend := &models.Position{ID: 7, Title: "Backend Engineer", JobFamily: "Engineering"}
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "IT"}, nil)
+	mockPosRepo.On("GetByID", ctx, uint(7)).Return(backend, nil)
+	mockPosRepo.On("GetByID", ctx, uint(8)).Return(nil, nil)
+	mockPosRepo.On("GetByTitle", ctx, "backend  engineer").Return(backend, nil)
+	mockEmpRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	// По идентификатору справочника название из запроса не нужно.
+	emp, err := service.Create(ctx, 1, "John Doe", "", uintPtr(7), nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", emp.Position)
+	assert.Equal(t, 7, *emp.PositionID)
+
+	// Название, найденное в справочнике, заменяется названием справочника.
+	emp, err = service.Create(ctx, 1, "Jane Doe", "backend  engineer", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", emp.Position)
+	assert.Equal(t, 7, *emp.PositionID)
+
+	_, err = service.Create(ctx, 1, "John Doe", "", uintPtr(8), nil, nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrPositionNotFound)
+}
+
+// --- Тесты для Terminate, Rehire и SetStatus ---
+
+func setupLifecycleService(t *testing.T) (*EmpService, *MockEmployeeRepoForService, *MockDepartmentRepoForEmployee) {
+	service, mockEmpRepo, mockDeptRepo := setupEmployeeService(t)
+	service.now = func() time.Time { return time.Date(2025, 6, 10, 15, 0, 0, 0, time.UTC) }
+	return service, mockEmpRepo, mockDeptRepo
+}
+
+func TestEmployeeTerminate_Success(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+	hiredAt := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, HiredAt: &hiredAt, Status: models.EmployeeActive}, nil)
+	mockEmpRepo.On("Terminate", ctx, mock.AnythingOfType("*models.Employee")).Return(nil)
+
+	emp, err := service.Terminate(ctx, 1, nil, "  relocation ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.EmployeeTerminated, emp.Status)
+	assert.Equal(t, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), *emp.TerminatedAt)
+	assert.Equal(t, "relocation", emp.TerminationReason)
+	assert.False(t, emp.Employed(nil))
+	before := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	assert.True(t, emp.Employed(&before))
+}
+
+func TestEmployeeTerminate_InvalidDate(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+	hiredAt := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, HiredAt: &hiredAt, Status: models.EmployeeActive}, nil)
+
+	beforeHire := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	_, err := service.Terminate(ctx, 1, &beforeHire, "")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidEmploymentDate)
+
+	future := time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC)
+	_, err = service.Terminate(ctx, 1, &future, "")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidEmploymentDate)
+
+	mockEmpRepo.AssertNotCalled(t, "Terminate", mock.Anything, mock.Anything)
+}
+
+func TestEmployeeTerminate_AlreadyTerminated(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+	terminatedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, Status: models.EmployeeTerminated, TerminatedAt: &terminatedAt}, nil)
+
+	_, err := service.Terminate(ctx, 1, nil, "")
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeTerminated)
+}
+
+func TestEmployeeRehire(t *testing.T) {
+	service, mockEmpRepo, mockDeptRepo := setupLifecycleService(t)
+	ctx := context.Background()
+	terminatedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, DepartmentID: 1, Status: models.EmployeeTerminated,
+		TerminatedAt: &terminatedAt, TerminationReason: "relocation"}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2}, nil)
+	mockEmpRepo.On("Update", ctx, mock.AnythingOfType("*models.Employee")).Return(nil)
+
+	tooEarly := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	_, err := service.Rehire(ctx, 1, &tooEarly, nil)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidEmploymentDate)
+
+	departmentID := uint(2)
+	emp, err := service.Rehire(ctx, 1, nil, &departmentID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.EmployeeActive, emp.Status)
+	assert.Equal(t, 2, emp.DepartmentID)
+	assert.Nil(t, emp.TerminatedAt)
+	assert.Empty(t, emp.TerminationReason)
+	assert.Equal(t, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC), *emp.HiredAt)
+}
+
+func TestEmployeeRehire_HeadcountLimit(t *testing.T) {
+	service, mockEmpRepo, mockDeptRepo := setupLifecycleService(t)
+	ctx := context.Background()
+	terminatedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockVacancyRepo := service.guard.vacancyRepo.(*MockVacancyRepo)
+
+	// Уволенный сотрудник не занимает место, поэтому повторный приём в тот же отдел проверяется.
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, DepartmentID: 1, Status: models.EmployeeTerminated,
+		TerminatedAt: &terminatedAt}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, HeadcountLimit: intPtr(1)}, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(1)).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, uint(1)).Return(int64(0), nil)
+	mockEmpRepo.On("Update", ctx, mock.AnythingOfType("*models.Employee")).Return(nil)
+
+	emp, err := service.Rehire(ctx, 1, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, emp.Warning, "1 of 1 budgeted seats")
+}
+
+func TestEmployeeRehire_NotTerminated(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, Status: models.EmployeeOnLeave}, nil)
+
+	_, err := service.Rehire(ctx, 1, nil, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeNotTerminated)
+}
+
+func TestEmployeeSetStatus(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+
+	mockEmpRepo.On("GetByID", ctx, uint(1)).Return(&models.Employee{ID: 1, Status: models.EmployeeActive}, nil)
+	mockEmpRepo.On("Update", ctx, mock.AnythingOfType("*models.Employee")).Return(nil)
+
+	emp, err := service.SetStatus(ctx, 1, models.EmployeeOnLeave)
+	assert.NoError(t, err)
+	assert.Equal(t, models.EmployeeOnLeave, emp.Status)
+
+	_, err = service.SetStatus(ctx, 1, models.EmployeeTerminated)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidEmployeeStatus)
+}
+
+func TestEmployeeList_ExcludesTerminated(t *testing.T) {
+	service, mockEmpRepo, _ := setupLifecycleService(t)
+	ctx := context.Background()
+	terminatedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	mockEmpRepo.On("List", ctx, (*uint)(nil), models.Attributes{}, (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 1, Status: models.EmployeeActive},
+		{ID: 2, Status: models.EmployeeOnLeave},
+		{ID: 3, Status: models.EmployeeTerminated, TerminatedAt: &terminatedAt},
+	}, nil)
+
+	employees, err := service.List(ctx, nil, nil, nil, false)
+	assert.NoError(t, err)
+	assert.Len(t, employees, 2)
+
+	employees, err = service.List(ctx, nil, nil, nil, true)
+	assert.NoError(t, err)
+	assert.Len(t, employees, 3)
+}
@@ -0,0 +1,155 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// maxTerminationReasonLength - наибольшая длина причины увольнения.
+const maxTerminationReasonLength = 500
+
+// Terminate увольняет сотрудника с даты terminatedAt (по умолчанию сегодня) и снимает его
+// с руководства подразделениями. Дата не может быть раньше даты найма и позже сегодняшней.
+func (e *EmpService) Terminate(ctx context.Context, id uint, terminatedAt *time.Time, reason string) (*models.Employee, error) {
+	reason = strings.TrimSpace(reason)
+	if len(reason) > maxTerminationReasonLength {
+		return nil, fmt.Errorf("%w: max %d characters", apperrors.ErrInvalidTerminationReason, maxTerminationReasonLength)
+	}
+
+	emp, err := e.empRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, apperrors.ErrEmployeeNotFound
+	}
+	if emp.Status == models.EmployeeTerminated {
+		return nil, apperrors.ErrEmployeeTerminated
+	}
+
+	date, err := e.employmentDate(terminatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if emp.HiredAt != nil && date.Before(dateOf(*emp.HiredAt)) {
+		return nil, fmt.Errorf("%w: terminated_at cannot predate hired_at %s",
+			apperrors.ErrInvalidEmploymentDate, emp.HiredAt.Format(time.DateOnly))
+	}
+
+	emp.Status = models.EmployeeTerminated
+	emp.TerminatedAt = &date
+	emp.TerminationReason = reason
+	if err := e.empRepo.Terminate(ctx, emp); err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// Rehire снова принимает уволенного сотрудника с даты hiredAt (по умолчанию сегодня),
+// при заданном departmentID - в другое подразделение. Дата найма не может быть раньше
+// даты увольнения и позже сегодняшней. Сотрудник снова занимает место в подразделении,
+// поэтому приём проверяется по его утверждённой численности.
+func (e *EmpService) Rehire(ctx context.Context, id uint, hiredAt *time.Time, departmentID *uint) (*models.Employee, error) {
+	emp, err := e.empRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, apperrors.ErrEmployeeNotFound
+	}
+	if emp.Status != models.EmployeeTerminated {
+		return nil, apperrors.ErrEmployeeNotTerminated
+	}
+
+	date, err := e.employmentDate(hiredAt)
+	if err != nil {
+		return nil, err
+	}
+	if date.Before(dateOf(*emp.TerminatedAt)) {
+		return nil, fmt.Errorf("%w: hired_at cannot predate terminated_at %s",
+			apperrors.ErrInvalidEmploymentDate, emp.TerminatedAt.Format(time.DateOnly))
+	}
+	if departmentID != nil {
+		emp.DepartmentID = int(*departmentID)
+	}
+	dept, err := e.deptRepo.GetByID(ctx, uint(emp.DepartmentID))
+	if err != nil {
+		return nil, err
+	}
+	if dept == nil {
+		return nil, apperrors.ErrEmployeeDepartmentNotFound
+	}
+
+	emp.Status = models.EmployeeActive
+	emp.HiredAt = &date
+	emp.TerminatedAt = nil
+	emp.TerminationReason = ""
+	err = e.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if emp.Warning, err = e.guard.check(ctx, dept, false); err != nil {
+			return err
+		}
+		return e.empRepo.Update(ctx, emp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// SetStatus переводит работающего сотрудника в отпуск (on_leave) или возвращает из него (active).
+// Увольнение и повторный приём выполняются через Terminate и Rehire.
+func (e *EmpService) SetStatus(ctx context.Context, id uint, status string) (*models.Employee, error) {
+	if status != models.EmployeeActive && status != models.EmployeeOnLeave {
+		return nil, fmt.Errorf("%w: status must be %s or %s", apperrors.ErrInvalidEmployeeStatus,
+			models.EmployeeActive, models.EmployeeOnLeave)
+	}
+
+	emp, err := e.empRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, apperrors.ErrEmployeeNotFound
+	}
+	if emp.Status == models.EmployeeTerminated {
+		return nil, apperrors.ErrEmployeeTerminated
+	}
+
+	emp.Status = status
+	if err := e.empRepo.Update(ctx, emp); err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// employmentDate возвращает дату увольнения или приёма: переданную или сегодняшнюю.
+// Даты в будущем не допускаются.
+func (e *EmpService) employmentDate(date *time.Time) (time.Time, error) {
+	today := dateOf(e.now())
+	if date == nil {
+		return today, nil
+	}
+	d := dateOf(*date)
+	if d.After(today) {
+		return time.Time{}, fmt.Errorf("%w: date cannot be in the future", apperrors.ErrInvalidEmploymentDate)
+	}
+	return d, nil
+}
+
+// employedOnly оставляет сотрудников, числившихся в штате на дату asOf (при nil - сейчас).
+func employedOnly(employees []models.Employee, asOf *time.Time) []models.Employee {
+	result := make([]models.Employee, 0, len(employees))
+	for _, emp := range employees {
+		if emp.Employed(asOf) {
+			result = append(result, emp)
+		}
+	}
+	return result
+}
@@ -6,9 +6,11 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
 	"github.com/NailUsmanov/api_organization/internal/models"
 )
 
@@ -18,29 +20,55 @@ type EmployeeRepository interface {
 	GetByID(ctx context.Context, id uint) (*models.Employee, error)
 	Update(ctx context.Context, emp *models.Employee) error
 	Delete(ctx context.Context, id uint) error
-	ListByDepartment(ctx context.Context, departmenID uint, orderBy string) ([]models.Employee, error)
+	ListByDepartment(ctx context.Context, departmenID uint, orderBy string, asOf *time.Time) ([]models.Employee, error)
 	MoveToDepartment(ctx context.Context, departmentID uint, targerDerpartmentID uint) error
+	CountByDepartment(ctx context.Context, departmentID uint) (int64, error)
+	CountEmployed(ctx context.Context, departmentID uint) (int64, error)
+	GetByCode(ctx context.Context, code string) (*models.Employee, error)
+	GetByExternalID(ctx context.Context, system, externalID string) (*models.Employee, error)
+	List(ctx context.Context, departmentID *uint, attrFilter models.Attributes, asOf *time.Time) ([]models.Employee, error)
+	Terminate(ctx context.Context, emp *models.Employee) error
 }
 
 // EmployeeService определяет интерфейс для работы с сотрудниками,
 // который будет реализован сервисом и использован обработчиками HTTP.
 type EmployeeService interface {
-	Create(ctx context.Context, departmentID uint, fullName, position string, hiredAt *time.Time) (*models.Employee, error)
+	Create(ctx context.Context, departmentID uint, fullName, position string, positionID *uint, vacancyID *uint,
+		hiredAt *time.Time, attrs models.Attributes) (*models.Employee, error)
+	List(ctx context.Context, departmentID *uint, attrFilter map[string]string, asOf *time.Time, includeTerminated bool) ([]models.Employee, error)
+	SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Employee, error)
+	Terminate(ctx context.Context, id uint, terminatedAt *time.Time, reason string) (*models.Employee, error)
+	Rehire(ctx context.Context, id uint, hiredAt *time.Time, departmentID *uint) (*models.Employee, error)
+	SetStatus(ctx context.Context, id uint, status string) (*models.Employee, error)
 }
 
 // EmpService реализует бизнес-логику для работы с сотрудниками.
 type EmpService struct {
-	empRepo  EmployeeRepository
-	deptRepo DepartmentRepository
+	empRepo     EmployeeRepository
+	deptRepo    DepartmentRepository
+	posRepo     PositionRepository
+	vacancyRepo VacancyRepository
+	attrs       AttributeSchema
+	guard       headcountGuard
+	tx          Transactor
+	now         func() time.Time
 }
 
-// NewEmpService создаёт новый экземпляр сервиса сотрудников.
-func NewEmpService(epmRepo EmployeeRepository, deptRepo DepartmentRepository) *EmpService {
-	return &EmpService{empRepo: epmRepo, deptRepo: deptRepo}
+// NewEmpService создаёт новый экземпляр сервиса сотрудников; limitMode - режим проверки
+// утверждённой численности подразделения (HeadcountLimitSoft или HeadcountLimitHard).
+func NewEmpService(epmRepo EmployeeRepository, deptRepo DepartmentRepository, posRepo PositionRepository,
+	vacancyRepo VacancyRepository, attrs AttributeSchema, tx Transactor, limitMode string) *EmpService {
+	return &EmpService{empRepo: epmRepo, deptRepo: deptRepo, posRepo: posRepo, vacancyRepo: vacancyRepo, attrs: attrs,
+		guard: headcountGuard{deptRepo: deptRepo, empRepo: epmRepo, vacancyRepo: vacancyRepo, mode: limitMode}, tx: tx, now: time.Now}
 }
 
-// Create реализует бизнес-логику создания нового сотрудника.
-func (e *EmpService) Create(ctx context.Context, departmentID uint, fullName, position string, hiredAt *time.Time) (*models.Employee, error) {
+// Create реализует бизнес-логику создания нового сотрудника. Должность задаётся
+// идентификатором справочника positionID либо названием position; название, найденное
+// в справочнике, связывает сотрудника с должностью справочника.
+// При заданном vacancyID сотрудник заполняет открытую вакансию своего подразделения
+// и по умолчанию получает её должность; иначе приём проверяется по утверждённой численности.
+func (e *EmpService) Create(ctx context.Context, departmentID uint, fullName, position string, positionID *uint,
+	vacancyID *uint, hiredAt *time.Time, attrs models.Attributes) (*models.Employee, error) {
 	dept, err := e.deptRepo.GetByID(ctx, departmentID)
 	if err != nil {
 		return nil, err
@@ -59,9 +87,41 @@ func (e *EmpService) Create(ctx context.Context, departmentID uint, fullName, po
 		return nil, errors.New("full_name can't be longer than 200")
 	}
 
+	var vacancy *models.Vacancy
 	cleanPosition := strings.TrimSpace(position)
-	if cleanPosition == "" || len(cleanPosition) > 200 {
-		return nil, errors.New("position must be non-empty and max 200 characters")
+	if vacancyID != nil {
+		if vacancy, err = e.openVacancy(ctx, *vacancyID, departmentID); err != nil {
+			return nil, err
+		}
+		if positionID == nil && cleanPosition == "" {
+			cleanPosition = vacancy.Position
+			if vacancy.PositionID != nil {
+				id := uint(*vacancy.PositionID)
+				positionID = &id
+			}
+		}
+	}
+
+	var pos *models.Position
+	if positionID != nil {
+		if pos, err = e.posRepo.GetByID(ctx, *positionID); err != nil {
+			return nil, err
+		}
+		if pos == nil {
+			return nil, fmt.Errorf("%w: %d", apperrors.ErrPositionNotFound, *positionID)
+		}
+	} else {
+		if cleanPosition == "" || len(cleanPosition) > 200 {
+			return nil, errors.New("position must be non-empty and max 200 characters")
+		}
+		if pos, err = e.posRepo.GetByTitle(ctx, cleanPosition); err != nil {
+			return nil, err
+		}
+	}
+
+	cleanAttrs, err := e.attrs.Validate(ctx, models.EntityEmployee, attrs)
+	if err != nil {
+		return nil, err
 	}
 
 	emp := &models.Employee{
@@ -69,11 +129,121 @@ func (e *EmpService) Create(ctx context.Context, departmentID uint, fullName, po
 		FullName:     cleanFullName,
 		Position:     cleanPosition,
 		HiredAt:      hiredAt,
+		Attributes:   cleanAttrs,
+	}
+	if pos != nil {
+		emp.Position = pos.Title
+		emp.PositionID = &pos.ID
 	}
 
-	if err := e.empRepo.Create(ctx, emp); err != nil {
+	err = e.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if emp.Warning, err = e.guard.check(ctx, dept, vacancy != nil); err != nil {
+			return err
+		}
+		if err := e.empRepo.Create(ctx, emp); err != nil {
+			return err
+		}
+		if vacancy == nil {
+			return nil
+		}
+		now := e.now()
+		vacancy.Status = models.VacancyFilled
+		vacancy.EmployeeID = &emp.ID
+		vacancy.ClosedAt = &now
+		return e.vacancyRepo.Update(ctx, vacancy)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return emp, nil
 }
+
+// openVacancy возвращает открытую вакансию id подразделения departmentID.
+func (e *EmpService) openVacancy(ctx context.Context, id, departmentID uint) (*models.Vacancy, error) {
+	vacancy, err := e.vacancyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if vacancy == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrVacancyNotFound, id)
+	}
+	if uint(vacancy.DepartmentID) != departmentID {
+		return nil, fmt.Errorf("%w: vacancy %d belongs to department %d", apperrors.ErrInvalidVacancy, id, vacancy.DepartmentID)
+	}
+	if vacancy.Status != models.VacancyOpen {
+		return nil, fmt.Errorf("%w: status is %s", apperrors.ErrVacancyNotOpen, vacancy.Status)
+	}
+	return vacancy, nil
+}
+
+// List возвращает сотрудников, у которых атрибуты совпадают с фильтром,
+// при заданном departmentID - только сотрудников этого отдела, при заданном asOf - в состоянии на эту дату.
+// Уволенные сотрудники возвращаются только при includeTerminated.
+func (e *EmpService) List(ctx context.Context, departmentID *uint, attrFilter map[string]string, asOf *time.Time, includeTerminated bool) ([]models.Employee, error) {
+	filter, err := e.attrs.ParseFilter(ctx, models.EntityEmployee, attrFilter)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := e.empRepo.List(ctx, departmentID, filter, asOf)
+	if err != nil || includeTerminated {
+		return employees, err
+	}
+	return employedOnly(employees, asOf), nil
+}
+
+// SetIdentifiers заменяет код и идентификаторы внешних систем сотрудника.
+// code, равный nil, удаляет код.
+func (e *EmpService) SetIdentifiers(ctx context.Context, id uint, code *string, externalIDs map[string]string) (*models.Employee, error) {
+	cleanCode, err := validateCodePtr(code)
+	if err != nil {
+		return nil, err
+	}
+	cleanIDs, err := ValidateExternalIDs(externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	emp, err := e.empRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, apperrors.ErrEmployeeNotFound
+	}
+	if err := checkEmployeeIdentifiers(ctx, e.empRepo, id, cleanCode, cleanIDs); err != nil {
+		return nil, err
+	}
+
+	emp.Code = cleanCode
+	emp.ExternalIDs = cleanIDs
+	if err := e.empRepo.Update(ctx, emp); err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// checkEmployeeIdentifiers проверяет, что код и внешние идентификаторы не заняты
+// другим сотрудником. Для нового сотрудника id равен 0.
+func checkEmployeeIdentifiers(ctx context.Context, repo EmployeeRepository, id uint, code *string, externalIDs models.ExternalIDs) error {
+	if code != nil {
+		existing, err := repo.GetByCode(ctx, *code)
+		if err != nil {
+			return err
+		}
+		if existing != nil && uint(existing.ID) != id {
+			return apperrors.ErrEmployeeCodeConflict
+		}
+	}
+	for system, extID := range externalIDs {
+		existing, err := repo.GetByExternalID(ctx, system, extID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && uint(existing.ID) != id {
+			return apperrors.ErrExternalIDConflict
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// Режимы проверки утверждённой численности подразделения.
+const (
+	// HeadcountLimitSoft - превышение допускается, запись получает предупреждение.
+	HeadcountLimitSoft = "soft"
+	// HeadcountLimitHard - превышение отклоняется.
+	HeadcountLimitHard = "hard"
+)
+
+// headcountGuard проверяет утверждённую численность подразделения на всех путях, которые
+// добавляют в него людей: приём, повторный приём, перевод, импорт, слияние, перенос сотрудников
+// удаляемого подразделения и открытие вакансии. Место в подразделении занимают неуволенные
+// сотрудники и открытые вакансии.
+type headcountGuard struct {
+	deptRepo    DepartmentRepository
+	empRepo     EmployeeRepository
+	vacancyRepo VacancyRepository
+	mode        string
+}
+
+// check проверяет, что в подразделении dept есть место ещё для одной записи. При filling
+// запись занимает место заполняемой вакансии и численность не меняет.
+func (g headcountGuard) check(ctx context.Context, dept *models.Department, filling bool) (string, error) {
+	if filling {
+		return "", nil
+	}
+	return g.checkSeats(ctx, dept, 1)
+}
+
+// checkMove проверяет, что в подразделении dept хватит мест для неуволенных сотрудников
+// подразделения sourceID, которые в него переносятся, а при withVacancies - и для его открытых вакансий.
+func (g headcountGuard) checkMove(ctx context.Context, sourceID uint, dept *models.Department, withVacancies bool) (string, error) {
+	if dept.HeadcountLimit == nil {
+		return "", nil
+	}
+	seats, err := g.empRepo.CountEmployed(ctx, sourceID)
+	if err != nil {
+		return "", err
+	}
+	if withVacancies {
+		open, err := g.vacancyRepo.CountOpen(ctx, sourceID)
+		if err != nil {
+			return "", err
+		}
+		seats += open
+	}
+	return g.checkSeats(ctx, dept, seats)
+}
+
+// checkSeats проверяет, что в подразделении dept есть место ещё для seats записей. Возвращает
+// предупреждение о превышении в мягком режиме и ErrHeadcountLimitExceeded - в жёстком.
+// В жёстком режиме строка подразделения блокируется до конца транзакции: параллельные
+// приёмы в то же подразделение проверяются по очереди и не превышают численность вместе.
+func (g headcountGuard) checkSeats(ctx context.Context, dept *models.Department, seats int64) (string, error) {
+	if dept.HeadcountLimit == nil || seats == 0 {
+		return "", nil
+	}
+	if g.mode == HeadcountLimitHard {
+		locked, err := g.deptRepo.Lock(ctx, uint(dept.ID))
+		if err != nil {
+			return "", err
+		}
+		if locked == nil {
+			return "", apperrors.ErrDepartmentNotFound
+		}
+		if locked.HeadcountLimit == nil {
+			return "", nil
+		}
+		dept = locked
+	}
+	employed, err := g.empRepo.CountEmployed(ctx, uint(dept.ID))
+	if err != nil {
+		return "", err
+	}
+	open, err := g.vacancyRepo.CountOpen(ctx, uint(dept.ID))
+	if err != nil {
+		return "", err
+	}
+	occupied := employed + open
+	if occupied+seats <= int64(*dept.HeadcountLimit) {
+		return "", nil
+	}
+	message := fmt.Sprintf("department %d has %d of %d budgeted seats occupied (%d open vacancies)",
+		dept.ID, occupied, *dept.HeadcountLimit, open)
+	if seats > 1 {
+		message += fmt.Sprintf(", %d more requested", seats)
+	}
+	if g.mode == HeadcountLimitHard {
+		return "", fmt.Errorf("%w: %s", apperrors.ErrHeadcountLimitExceeded, message)
+	}
+	return apperrors.ErrHeadcountLimitExceeded.Error() + ": " + message, nil
+}
+
+// SetHeadcountLimit задаёт утверждённую численность подразделения; limit = nil снимает ограничение.
+// Уже превышенная численность не мешает уменьшить ограничение: оно действует на новые записи.
+func (s *DepService) SetHeadcountLimit(ctx context.Context, id uint, limit *int) (*models.Department, error) {
+	if limit != nil && *limit < 0 {
+		return nil, apperrors.ErrInvalidHeadcountLimit
+	}
+	var dept *models.Department
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if dept, err = s.deptRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		previous := dept.HeadcountLimit
+		dept.HeadcountLimit = limit
+		if err := s.deptRepo.Update(ctx, dept); err != nil {
+			return err
+		}
+		return s.recordHistory(ctx, id, "headcount_limit", map[string]any{"from": previous, "to": limit})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// HeadcountBudget возвращает утверждённую, фактическую и открытую численность подразделения
+// и его поддерева деревом; у каждого узла итоги Total* сложены по его поддереву.
+func (s *DepService) HeadcountBudget(ctx context.Context, id uint) (*models.HeadcountBudget, error) {
+	rows, err := s.deptRepo.HeadcountBudget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, apperrors.ErrDepartmentNotFound
+	}
+
+	children := make(map[int][]models.HeadcountBudget)
+	for _, row := range rows[1:] {
+		if row.ParentID != nil {
+			children[int(*row.ParentID)] = append(children[int(*row.ParentID)], row)
+		}
+	}
+	var build func(node models.HeadcountBudget) models.HeadcountBudget
+	build = func(node models.HeadcountBudget) models.HeadcountBudget {
+		node.TotalActual, node.TotalOpen = node.Actual, node.Open
+		if node.Budgeted != nil {
+			node.TotalBudgeted = *node.Budgeted
+		} else {
+			node.UnbudgetedDepartments = 1
+		}
+		for _, child := range children[node.DepartmentID] {
+			child = build(child)
+			node.TotalBudgeted += child.TotalBudgeted
+			node.TotalActual += child.TotalActual
+			node.TotalOpen += child.TotalOpen
+			node.UnbudgetedDepartments += child.UnbudgetedDepartments
+			node.Children = append(node.Children, child)
+		}
+		return node
+	}
+	root := build(rows[0])
+	return &root, nil
+}
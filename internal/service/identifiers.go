@@ -0,0 +1,51 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+var (
+	// codePattern - коды вида ENG-PLT-01: группы латинских букв и цифр через одиночный дефис.
+	codePattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+	// systemPattern - имена внешних систем: строчные латинские буквы, цифры и подчёркивания.
+	systemPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+)
+
+// ValidateCode проверяет код и приводит его к верхнему регистру.
+func ValidateCode(code string) (string, error) {
+	clean := strings.ToUpper(strings.TrimSpace(code))
+	if len(clean) > 50 || !codePattern.MatchString(clean) {
+		return "", apperrors.ErrInvalidCode
+	}
+	return clean, nil
+}
+
+// ValidateExternalIDs проверяет идентификаторы внешних систем и очищает значения от пробелов.
+func ValidateExternalIDs(ids map[string]string) (models.ExternalIDs, error) {
+	clean := make(models.ExternalIDs, len(ids))
+	for system, id := range ids {
+		id = strings.TrimSpace(id)
+		if !systemPattern.MatchString(system) || id == "" || len(id) > 200 {
+			return nil, apperrors.ErrInvalidExternalID
+		}
+		clean[system] = id
+	}
+	return clean, nil
+}
+
+// validateCodePtr проверяет необязательный код; nil остаётся nil.
+func validateCodePtr(code *string) (*string, error) {
+	if code == nil {
+		return nil, nil
+	}
+	clean, err := ValidateCode(*code)
+	if err != nil {
+		return nil, err
+	}
+	return &clean, nil
+}
@@ -0,0 +1,406 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// ImportDepartment описывает запись импорта подразделения. Подразделение сопоставляется
+// с существующим по коду, а при его отсутствии - по идентификаторам внешних систем.
+// Существующее подразделение без parent_code остаётся у прежнего родителя;
+// в корень его переносит только MoveToRoot. Тип задаётся только при создании подразделения.
+type ImportDepartment struct {
+	Code        *string           `json:"code"`
+	ExternalIDs map[string]string `json:"external_ids"`
+	Name        string            `json:"name"`
+	Type        *string           `json:"type"`
+	ParentCode  *string           `json:"parent_code"`
+	MoveToRoot  bool              `json:"move_to_root"`
+	Attributes  models.Attributes `json:"attributes"`
+}
+
+// ImportEmployee описывает запись импорта сотрудника. Сотрудник сопоставляется
+// с существующим по коду, а при его отсутствии - по идентификаторам внешних систем.
+type ImportEmployee struct {
+	Code           *string           `json:"code"`
+	ExternalIDs    map[string]string `json:"external_ids"`
+	DepartmentCode string            `json:"department_code"`
+	FullName       string            `json:"full_name"`
+	Position       string            `json:"position"`
+	HiredAt        *time.Time        `json:"hired_at"`
+	Attributes     models.Attributes `json:"attributes"`
+}
+
+// ImportResult содержит количество созданных и обновлённых записей и предупреждения
+// о превышении утверждённой численности подразделений в мягком режиме.
+type ImportResult struct {
+	DepartmentsCreated int      `json:"departments_created"`
+	DepartmentsUpdated int      `json:"departments_updated"`
+	EmployeesCreated   int      `json:"employees_created"`
+	EmployeesUpdated   int      `json:"employees_updated"`
+	Warnings           []string `json:"warnings,omitempty"`
+}
+
+// ImportService определяет интерфейс импорта данных из внешних систем.
+type ImportService interface {
+	Import(ctx context.Context, departments []ImportDepartment, employees []ImportEmployee) (*ImportResult, error)
+}
+
+// ImpService реализует импорт подразделений и сотрудников с обновлением по коду и внешним идентификаторам.
+type ImpService struct {
+	deptRepo     DepartmentRepository
+	empRepo      EmployeeRepository
+	attrs        AttributeSchema
+	types        DepartmentTypeRules
+	names        NamingPolicy
+	guard        headcountGuard
+	tx           Transactor
+	approvalMode string
+}
+
+// NewImportService создаёт новый экземпляр сервиса импорта; limitMode - режим проверки
+// утверждённой численности подразделения, approvalMode - режим согласования переносов подразделений.
+func NewImportService(deptRepo DepartmentRepository, empRepo EmployeeRepository, vacancyRepo VacancyRepository,
+	attrs AttributeSchema, types DepartmentTypeRules, names NamingPolicy, tx Transactor, limitMode string,
+	approvalMode string) *ImpService {
+	return &ImpService{deptRepo: deptRepo, empRepo: empRepo, attrs: attrs, types: types, names: names, tx: tx,
+		approvalMode: approvalMode, guard: headcountGuard{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo,
+			mode: limitMode}}
+}
+
+// Import создаёт или обновляет подразделения, затем сотрудников, в одной транзакции.
+// Родительские подразделения должны существовать или идти в списке раньше дочерних.
+func (s *ImpService) Import(ctx context.Context, departments []ImportDepartment, employees []ImportEmployee) (*ImportResult, error) {
+	result := &ImportResult{}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i, rec := range departments {
+			created, err := s.upsertDepartment(ctx, rec)
+			if err != nil {
+				return fmt.Errorf("departments[%d]: %w", i, err)
+			}
+			if created {
+				result.DepartmentsCreated++
+			} else {
+				result.DepartmentsUpdated++
+			}
+		}
+		for i, rec := range employees {
+			created, warning, err := s.upsertEmployee(ctx, rec)
+			if err != nil {
+				return fmt.Errorf("employees[%d]: %w", i, err)
+			}
+			if warning != "" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("employees[%d]: %s", i, warning))
+			}
+			if created {
+				result.EmployeesCreated++
+			} else {
+				result.EmployeesUpdated++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// upsertDepartment создаёт или обновляет подразделение и сообщает, было ли оно создано.
+func (s *ImpService) upsertDepartment(ctx context.Context, rec ImportDepartment) (bool, error) {
+	code, err := validateCodePtr(rec.Code)
+	if err != nil {
+		return false, err
+	}
+	externalIDs, err := ValidateExternalIDs(rec.ExternalIDs)
+	if err != nil {
+		return false, err
+	}
+	if code == nil && len(externalIDs) == 0 {
+		return false, apperrors.ErrImportKeyRequired
+	}
+	if rec.ParentCode != nil && rec.MoveToRoot {
+		return false, apperrors.ErrImportParentAmbiguous
+	}
+	name, err := s.names.Normalize(rec.Name)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", apperrors.ErrInvalidDepartmentName, err)
+	}
+
+	var parentID *uint
+	if rec.ParentCode != nil {
+		parentCode, err := ValidateCode(*rec.ParentCode)
+		if err != nil {
+			return false, err
+		}
+		parent, err := s.deptRepo.GetByCode(ctx, parentCode)
+		if err != nil {
+			return false, err
+		}
+		if parent == nil {
+			return false, apperrors.ErrParentNotFound
+		}
+		pid := uint(parent.ID)
+		parentID = &pid
+	}
+
+	existing, err := s.findDepartment(ctx, code, externalIDs)
+	if err != nil {
+		return false, err
+	}
+	placed := &models.Department{Type: departmentType(rec.Type)}
+	if existing != nil {
+		placed = existing
+		if rec.ParentCode == nil && !rec.MoveToRoot {
+			parentID = existing.ParentID
+		}
+		if !sameParent(existing.ParentID, parentID) {
+			if err := requireApproval(ctx, s.approvalMode, "move", uint(existing.ID)); err != nil {
+				return false, err
+			}
+		}
+	}
+	if err := checkDepartmentIdentifiers(ctx, s.deptRepo, uint(placed.ID), code, externalIDs); err != nil {
+		return false, err
+	}
+	if err := checkPlacement(ctx, s.deptRepo, s.types, placed, name, parentID, time.Time{}); err != nil {
+		return false, err
+	}
+
+	if existing == nil {
+		attrs, err := s.attrs.Validate(ctx, models.EntityDepartment, rec.Attributes)
+		if err != nil {
+			return false, err
+		}
+		dept := &models.Department{
+			Name:        name,
+			Type:        placed.Type,
+			ParentID:    parentID,
+			Code:        code,
+			ExternalIDs: externalIDs,
+			Attributes:  attrs,
+		}
+		if err := s.deptRepo.Create(ctx, dept); err != nil {
+			return false, fmt.Errorf("failed to create department: %w", err)
+		}
+		return true, nil
+	}
+
+	existing.Name = name
+	existing.ParentID = parentID
+	if code != nil {
+		existing.Code = code
+	}
+	existing.ExternalIDs = mergeExternalIDs(existing.ExternalIDs, externalIDs)
+	attrs, err := s.attrs.Validate(ctx, models.EntityDepartment, mergeAttributes(existing.Attributes, rec.Attributes))
+	if err != nil {
+		return false, err
+	}
+	existing.Attributes = attrs
+	if err := s.deptRepo.Update(ctx, existing); err != nil {
+		return false, fmt.Errorf("failed to update department: %w", err)
+	}
+	return false, nil
+}
+
+// upsertEmployee создаёт или обновляет сотрудника и сообщает, был ли он создан. Приём проверяется
+// по утверждённой численности подразделения, перевод в другое подразделение - как перевод по API:
+// уволенного сотрудника перевести нельзя. В мягком режиме возвращается предупреждение о превышении.
+func (s *ImpService) upsertEmployee(ctx context.Context, rec ImportEmployee) (bool, string, error) {
+	code, err := validateCodePtr(rec.Code)
+	if err != nil {
+		return false, "", err
+	}
+	externalIDs, err := ValidateExternalIDs(rec.ExternalIDs)
+	if err != nil {
+		return false, "", err
+	}
+	if code == nil && len(externalIDs) == 0 {
+		return false, "", apperrors.ErrImportKeyRequired
+	}
+	fullName, position, err := validateEmployeeFields(rec.FullName, rec.Position)
+	if err != nil {
+		return false, "", err
+	}
+
+	deptCode, err := ValidateCode(rec.DepartmentCode)
+	if err != nil {
+		return false, "", err
+	}
+	dept, err := s.deptRepo.GetByCode(ctx, deptCode)
+	if err != nil {
+		return false, "", err
+	}
+	if dept == nil {
+		return false, "", apperrors.ErrEmployeeDepartmentNotFound
+	}
+
+	existing, err := s.findEmployee(ctx, code, externalIDs)
+	if err != nil {
+		return false, "", err
+	}
+	var id uint
+	if existing != nil {
+		id = uint(existing.ID)
+	}
+	if err := checkEmployeeIdentifiers(ctx, s.empRepo, id, code, externalIDs); err != nil {
+		return false, "", err
+	}
+
+	var warning string
+	if existing == nil {
+		warning, err = s.guard.check(ctx, dept, false)
+	} else {
+		warning, err = validateTransfer(ctx, s.guard, existing, dept)
+	}
+	if err != nil {
+		return false, "", err
+	}
+
+	if existing == nil {
+		attrs, err := s.attrs.Validate(ctx, models.EntityEmployee, rec.Attributes)
+		if err != nil {
+			return false, "", err
+		}
+		emp := &models.Employee{
+			DepartmentID: dept.ID,
+			FullName:     fullName,
+			Position:     position,
+			HiredAt:      rec.HiredAt,
+			Code:         code,
+			ExternalIDs:  externalIDs,
+			Attributes:   attrs,
+		}
+		if err := s.empRepo.Create(ctx, emp); err != nil {
+			return false, "", fmt.Errorf("failed to create employee: %w", err)
+		}
+		return true, warning, nil
+	}
+
+	existing.DepartmentID = dept.ID
+	existing.FullName = fullName
+	existing.Position = position
+	if rec.HiredAt != nil {
+		existing.HiredAt = rec.HiredAt
+	}
+	if code != nil {
+		existing.Code = code
+	}
+	existing.ExternalIDs = mergeExternalIDs(existing.ExternalIDs, externalIDs)
+	attrs, err := s.attrs.Validate(ctx, models.EntityEmployee, mergeAttributes(existing.Attributes, rec.Attributes))
+	if err != nil {
+		return false, "", err
+	}
+	existing.Attributes = attrs
+	if err := s.empRepo.Update(ctx, existing); err != nil {
+		return false, "", fmt.Errorf("failed to update employee: %w", err)
+	}
+	return false, warning, nil
+}
+
+// findDepartment ищет подразделение по коду, а затем по внешним идентификаторам в порядке
+// названий систем. Если идентификаторы разных систем указывают на разные подразделения,
+// запись неоднозначна: возвращается ErrExternalIDConflict.
+func (s *ImpService) findDepartment(ctx context.Context, code *string, externalIDs models.ExternalIDs) (*models.Department, error) {
+	if code != nil {
+		dept, err := s.deptRepo.GetByCode(ctx, *code)
+		if err != nil || dept != nil {
+			return dept, err
+		}
+	}
+	var found *models.Department
+	var foundSystem string
+	for _, system := range externalIDSystems(externalIDs) {
+		dept, err := s.deptRepo.GetByExternalID(ctx, system, externalIDs[system])
+		if err != nil {
+			return nil, err
+		}
+		if dept == nil {
+			continue
+		}
+		if found == nil {
+			found, foundSystem = dept, system
+			continue
+		}
+		if dept.ID != found.ID {
+			return nil, fmt.Errorf("%w: %s and %s identify different departments (%d and %d)",
+				apperrors.ErrExternalIDConflict, foundSystem, system, found.ID, dept.ID)
+		}
+	}
+	return found, nil
+}
+
+// findEmployee ищет сотрудника по коду, а затем по внешним идентификаторам в порядке
+// названий систем. Если идентификаторы разных систем указывают на разных сотрудников,
+// запись неоднозначна: возвращается ErrExternalIDConflict.
+func (s *ImpService) findEmployee(ctx context.Context, code *string, externalIDs models.ExternalIDs) (*models.Employee, error) {
+	if code != nil {
+		emp, err := s.empRepo.GetByCode(ctx, *code)
+		if err != nil || emp != nil {
+			return emp, err
+		}
+	}
+	var found *models.Employee
+	var foundSystem string
+	for _, system := range externalIDSystems(externalIDs) {
+		emp, err := s.empRepo.GetByExternalID(ctx, system, externalIDs[system])
+		if err != nil {
+			return nil, err
+		}
+		if emp == nil {
+			continue
+		}
+		if found == nil {
+			found, foundSystem = emp, system
+			continue
+		}
+		if emp.ID != found.ID {
+			return nil, fmt.Errorf("%w: %s and %s identify different employees (%d and %d)",
+				apperrors.ErrExternalIDConflict, foundSystem, system, found.ID, emp.ID)
+		}
+	}
+	return found, nil
+}
+
+// externalIDSystems возвращает названия внешних систем по возрастанию, чтобы сопоставление
+// записи импорта не зависело от порядка обхода словаря.
+func externalIDSystems(externalIDs models.ExternalIDs) []string {
+	systems := make([]string, 0, len(externalIDs))
+	for system := range externalIDs {
+		systems = append(systems, system)
+	}
+	sort.Strings(systems)
+	return systems
+}
+
+// mergeExternalIDs дополняет существующие внешние идентификаторы новыми.
+func mergeExternalIDs(existing, update models.ExternalIDs) models.ExternalIDs {
+	merged := make(models.ExternalIDs, len(existing)+len(update))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range update {
+		merged[k] = v
+	}
+	return merged
+}
+
+// validateEmployeeFields проверяет и очищает ФИО и должность сотрудника.
+func validateEmployeeFields(fullName, position string) (string, string, error) {
+	cleanFullName := strings.TrimSpace(fullName)
+	if cleanFullName == "" || len(cleanFullName) > 200 {
+		return "", "", apperrors.ErrInvalidFullName
+	}
+	cleanPosition := strings.TrimSpace(position)
+	if cleanPosition == "" || len(cleanPosition) > 200 {
+		return "", "", apperrors.ErrInvalidPosition
+	}
+	return cleanFullName, cleanPosition, nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Вспомогательная функция для создания сервиса импорта с моками
+func setupImportService(t *testing.T) (*ImpService, *MockDepartmentRepo, *MockEmployeeRepo) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	service := NewImportService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitHard, ChangeApprovalOptional)
+	return service, mockDeptRepo, mockEmpRepo
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestImport_CreatesDepartmentAndUpdatesEmployee(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupImportService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	platform := &models.Department{ID: 5, Name: "Platform", ParentID: &parentID}
+	employee := &models.Employee{ID: 7, DepartmentID: 1, FullName: "Ivan Petrov", Position: "Engineer",
+		ExternalIDs: models.ExternalIDs{"hris": "E-7"}}
+
+	mockDeptRepo.On("GetByCode", ctx, "ENG").Return(&models.Department{ID: 1, Name: "Engineering"}, nil)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 1, Name: "Engineering"}, nil)
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT").Return(nil, nil).Times(2)
+	mockDeptRepo.On("GetByExternalID", ctx, "sap", "1001").Return(nil, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform", &parentID).Return(nil, nil)
+	mockDeptRepo.On("Create", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.Name == "Platform" && *d.ParentID == parentID && *d.Code == "ENG-PLT" && d.ExternalIDs["sap"] == "1001"
+	})).Return(nil)
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT").Return(platform, nil)
+
+	mockEmpRepo.On("GetByExternalID", ctx, "hris", "E-7").Return(employee, nil)
+	mockEmpRepo.On("Update", ctx, mock.MatchedBy(func(e *models.Employee) bool {
+		return e.ID == 7 && e.DepartmentID == 5 && e.Position == "Senior Engineer"
+	})).Return(nil)
+
+	result, err := service.Import(ctx,
+		[]ImportDepartment{{
+			Code:        strPtr("eng-plt"),
+			ExternalIDs: map[string]string{"sap": "1001"},
+			Name:        "Platform",
+			ParentCode:  strPtr("ENG"),
+		}},
+		[]ImportEmployee{{
+			ExternalIDs:    map[string]string{"hris": "E-7"},
+			DepartmentCode: "ENG-PLT",
+			FullName:       "Ivan Petrov",
+			Position:       "Senior Engineer",
+		}},
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportResult{DepartmentsCreated: 1, EmployeesUpdated: 1}, result)
+	mockDeptRepo.AssertExpectations(t)
+	mockEmpRepo.AssertExpectations(t)
+}
+
+func TestImport_KeyRequired(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+
+	result, err := service.Import(context.Background(), []ImportDepartment{{Name: "Platform"}}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrImportKeyRequired)
+	assert.Contains(t, err.Error(), "departments[0]")
+	assert.Nil(t, result)
+	mockDeptRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_ExternalIDsMatchDifferentDepartments(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByExternalID", ctx, "hris", "D-4").Return(&models.Department{ID: 4, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetByExternalID", ctx, "sap", "1001").Return(&models.Department{ID: 9, Name: "Platform Team"}, nil)
+
+	result, err := service.Import(ctx, []ImportDepartment{{
+		Name:        "Platform",
+		ExternalIDs: map[string]string{"sap": "1001", "hris": "D-4"},
+	}}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrExternalIDConflict)
+	assert.Contains(t, err.Error(), "hris and sap")
+	assert.Nil(t, result)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockDeptRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_ExternalIDsMatchDifferentEmployees(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupImportService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByCode", ctx, "OPS").Return(&models.Department{ID: 3, Name: "Operations"}, nil)
+	mockEmpRepo.On("GetByExternalID", ctx, "hris", "E-7").Return(&models.Employee{ID: 7, DepartmentID: 3}, nil)
+	mockEmpRepo.On("GetByExternalID", ctx, "sap", "5001").Return(&models.Employee{ID: 8, DepartmentID: 3}, nil)
+
+	result, err := service.Import(ctx, nil, []ImportEmployee{{
+		ExternalIDs:    map[string]string{"sap": "5001", "hris": "E-7"},
+		DepartmentCode: "OPS",
+		FullName:       "Anna Smirnova",
+		Position:       "Analyst",
+	}})
+
+	assert.ErrorIs(t, err, apperrors.ErrExternalIDConflict)
+	assert.Contains(t, err.Error(), "employees[0]")
+	assert.Nil(t, result)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockEmpRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_EmployeeDepartmentNotFound(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupImportService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByCode", ctx, "OPS").Return(nil, nil)
+
+	result, err := service.Import(ctx, nil, []ImportEmployee{{
+		Code:           strPtr("EMP-1"),
+		DepartmentCode: "OPS",
+		FullName:       "Anna Smirnova",
+		Position:       "Analyst",
+	}})
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeDepartmentNotFound)
+	assert.Contains(t, err.Error(), "employees[0]")
+	assert.Nil(t, result)
+	mockEmpRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_EmployeeHeadcountLimit(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupImportService(t)
+	ctx := context.Background()
+	mockVacancyRepo := service.guard.vacancyRepo.(*MockVacancyRepo)
+
+	full := &models.Department{ID: 3, Name: "Operations", HeadcountLimit: intPtr(2)}
+	mockDeptRepo.On("GetByCode", ctx, "OPS").Return(full, nil)
+	mockEmpRepo.On("GetByCode", ctx, "EMP-1").Return(nil, nil)
+	mockDeptRepo.On("Lock", ctx, uint(3)).Return(full, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(3)).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, uint(3)).Return(int64(1), nil)
+
+	result, err := service.Import(ctx, nil, []ImportEmployee{{
+		Code:           strPtr("EMP-1"),
+		DepartmentCode: "OPS",
+		FullName:       "Anna Smirnova",
+		Position:       "Analyst",
+	}})
+
+	assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+	assert.Nil(t, result)
+	mockEmpRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestImport_TerminatedEmployeeTransfer(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupImportService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByCode", ctx, "OPS").Return(&models.Department{ID: 3, Name: "Operations"}, nil)
+	mockEmpRepo.On("GetByCode", ctx, "EMP-1").
+		Return(&models.Employee{ID: 5, Code: strPtr("EMP-1"), DepartmentID: 2, Status: models.EmployeeTerminated}, nil)
+
+	result, err := service.Import(ctx, nil, []ImportEmployee{{
+		Code:           strPtr("EMP-1"),
+		DepartmentCode: "OPS",
+		FullName:       "Anna Smirnova",
+		Position:       "Analyst",
+	}})
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeTerminated)
+	assert.Nil(t, result)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestImport_UpdateKeepsParentWithoutParentCode(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	platform := &models.Department{ID: 5, Name: "Platform", ParentID: &parentID, Code: strPtr("ENG-PLT")}
+
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT").Return(platform, nil)
+	mockDeptRepo.On("GetByID", ctx, parentID).Return(&models.Department{ID: 1, Name: "Engineering"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform Team", &parentID).Return(nil, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(5), parentID).Return(false, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 5 && d.Name == "Platform Team" && d.ParentID != nil && *d.ParentID == parentID
+	})).Return(nil)
+
+	result, err := service.Import(ctx, []ImportDepartment{{Code: strPtr("ENG-PLT"), Name: "Platform Team"}}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ImportResult{DepartmentsUpdated: 1}, result)
+	mockDeptRepo.AssertExpectations(t)
+}
+
+func TestImport_MoveToRoot(t *testing.T) {
+	service, mockDeptRepo, _ := setupImportService(t)
+	ctx := context.Background()
+
+	parentID := uint(1)
+	platform := &models.Department{ID: 5, Name: "Platform", ParentID: &parentID, Code: strPtr("ENG-PLT")}
+
+	mockDeptRepo.On("GetByCode", ctx, "ENG-PLT").Return(platform, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 5 && d.ParentID == nil
+	})).Return(nil)
+
+	_, err := service.Import(ctx, []ImportDepartment{{Code: strPtr("ENG-PLT"), Name: "Platform", MoveToRoot: true}}, nil)
+	assert.NoError(t, err)
+	mockDeptRepo.AssertExpectations(t)
+
+	_, err = service.Import(ctx, []ImportDepartment{{Code: strPtr("ENG-PLT"), Name: "Platform", ParentCode: strPtr("ENG"),
+		MoveToRoot: true}}, nil)
+	assert.ErrorIs(t, err, apperrors.ErrImportParentAmbiguous)
+}
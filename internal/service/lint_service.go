@@ -0,0 +1,207 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// Уровни серьёзности замечаний отчёта о состоянии структуры.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Правила проверки структуры.
+const (
+	LintEmptyDepartment   = "empty_department"
+	LintSingleChildChain  = "single_child_chain"
+	LintTooDeep           = "too_deep"
+	LintWideSpan          = "wide_span"
+	LintNoHead            = "no_head"
+	LintDuplicateLikeName = "duplicate_like_name"
+)
+
+// severityRank задаёт порядок замечаний в отчёте: сначала самые серьёзные.
+var severityRank = map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// LintService определяет интерфейс отчёта о состоянии структуры,
+// который будет реализован сервисом и использован обработчиками HTTP и командой orglint.
+type LintService interface {
+	Report(ctx context.Context) (*LintReport, error)
+}
+
+// LintLimits задаёт пороги правил too_deep и wide_span.
+type LintLimits struct {
+	MaxDepth int `json:"max_depth"`
+	MaxSpan  int `json:"max_span"`
+}
+
+// LintReport содержит замечания по всей структуре и их количество по уровням серьёзности.
+type LintReport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Limits      LintLimits     `json:"limits"`
+	Summary     map[string]int `json:"summary"`
+	Findings    []LintFinding  `json:"findings"`
+}
+
+// LintFinding описывает одно замечание к подразделению.
+type LintFinding struct {
+	Rule           string `json:"rule"`
+	Severity       string `json:"severity"`
+	DepartmentID   int    `json:"department_id"`
+	DepartmentName string `json:"department_name"`
+	Message        string `json:"message"`
+	Link           string `json:"link"`
+}
+
+// LintSvc реализует проверку структуры на признаки неудачной организации:
+// пустые подразделения, цепочки из единственных дочерних подразделений, чрезмерную
+// глубину и охват управления, подразделения без руководителя и похожие названия.
+type LintSvc struct {
+	deptRepo DepartmentRepository
+	empRepo  EmployeeRepository
+	limits   LintLimits
+	now      func() time.Time
+}
+
+// NewLintService создаёт новый экземпляр сервиса отчёта о состоянии структуры.
+func NewLintService(deptRepo DepartmentRepository, empRepo EmployeeRepository, limits LintLimits) *LintSvc {
+	return &LintSvc{deptRepo: deptRepo, empRepo: empRepo, limits: limits, now: time.Now}
+}
+
+// Report проверяет всё дерево подразделений и возвращает замечания, упорядоченные
+// по серьёзности и идентификатору подразделения.
+func (s *LintSvc) Report(ctx context.Context) (*LintReport, error) {
+	depts, err := s.deptRepo.List(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := s.empRepo.List(ctx, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	headcount := make(map[int]int, len(depts))
+	for _, emp := range employedOnly(employees, nil) {
+		headcount[emp.DepartmentID]++
+	}
+	children := make(map[int][]int, len(depts))
+	byID := make(map[int]models.Department, len(depts))
+	for _, dept := range depts {
+		byID[dept.ID] = dept
+		if dept.ParentID != nil {
+			children[int(*dept.ParentID)] = append(children[int(*dept.ParentID)], dept.ID)
+		}
+	}
+	// Звено цепочки - подразделение без сотрудников с единственным дочерним.
+	isLink := func(id int) bool {
+		return headcount[id] == 0 && len(children[id]) == 1
+	}
+
+	var findings []LintFinding
+	add := func(dept models.Department, rule, severity, message string) {
+		findings = append(findings, LintFinding{Rule: rule, Severity: severity, DepartmentID: dept.ID,
+			DepartmentName: dept.Name, Message: message, Link: fmt.Sprintf("/departments/%d", dept.ID)})
+	}
+
+	// Список упорядочен по пути, поэтому глубина родителя вычислена раньше глубины потомка.
+	depth := make(map[int]int, len(depts))
+	for _, dept := range depts {
+		depth[dept.ID] = 1
+		if dept.ParentID != nil {
+			depth[dept.ID] = depth[int(*dept.ParentID)] + 1
+		}
+		span := headcount[dept.ID] + len(children[dept.ID])
+
+		if span == 0 {
+			add(dept, LintEmptyDepartment, SeverityWarning, "department has no employees and no sub-departments")
+		}
+		if isLink(dept.ID) && (dept.ParentID == nil || !isLink(int(*dept.ParentID))) {
+			chain := []string{}
+			for id := dept.ID; isLink(id); id = children[id][0] {
+				chain = append(chain, fmt.Sprint(children[id][0]))
+			}
+			add(dept, LintSingleChildChain, SeverityInfo, fmt.Sprintf(
+				"chain of %d single-child level(s) without employees: %d -> %s", len(chain), dept.ID, strings.Join(chain, " -> ")))
+		}
+		if depth[dept.ID] > s.limits.MaxDepth {
+			add(dept, LintTooDeep, SeverityWarning, fmt.Sprintf("depth %d exceeds limit %d", depth[dept.ID], s.limits.MaxDepth))
+		}
+		if span > s.limits.MaxSpan {
+			add(dept, LintWideSpan, SeverityWarning, fmt.Sprintf(
+				"span of control %d (%d employees, %d sub-departments) exceeds limit %d",
+				span, headcount[dept.ID], len(children[dept.ID]), s.limits.MaxSpan))
+		}
+		if dept.HeadID == nil {
+			add(dept, LintNoHead, SeverityInfo, "department has no head")
+		}
+	}
+
+	// Похожие названия: совпадают без учёта регистра, пробелов и алфавита записи
+	// (ключ транслитерации, вычисляемый базой данных), но записаны по-разному.
+	groups := make(map[string][]int)
+	for _, dept := range depts {
+		key := dept.SearchKey
+		if key == "" {
+			key = strings.ToLower(strings.Join(strings.Fields(dept.Name), " "))
+		}
+		groups[key] = append(groups[key], dept.ID)
+	}
+	for _, ids := range groups {
+		if len(ids) < 2 {
+			continue
+		}
+		for _, id := range ids {
+			var others []string
+			for _, other := range ids {
+				if other != id && byID[other].Name != byID[id].Name {
+					others = append(others, fmt.Sprintf("%q (%d)", byID[other].Name, other))
+				}
+			}
+			if len(others) > 0 {
+				add(byID[id], LintDuplicateLikeName, SeverityError,
+					"name differs only in case, whitespace or script from "+strings.Join(others, ", "))
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		return a.DepartmentID < b.DepartmentID
+	})
+	summary := map[string]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0}
+	for _, f := range findings {
+		summary[f.Severity]++
+	}
+	if findings == nil {
+		findings = []LintFinding{}
+	}
+	return &LintReport{GeneratedAt: s.now().UTC(), Limits: s.limits, Summary: summary, Findings: findings}, nil
+}
+
+// ValidSeverity сообщает, является ли значение известным уровнем серьёзности.
+func ValidSeverity(severity string) bool {
+	_, ok := severityRank[severity]
+	return ok
+}
+
+// FindingsAtLeast возвращает замечания уровня severity и серьёзнее.
+func (r *LintReport) FindingsAtLeast(severity string) []LintFinding {
+	findings := []LintFinding{}
+	for _, f := range r.Findings {
+		if severityRank[f.Severity] <= severityRank[severity] {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
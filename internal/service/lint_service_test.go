@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintReport(t *testing.T) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	service := NewLintService(mockDeptRepo, mockEmpRepo, LintLimits{MaxDepth: 3, MaxSpan: 2})
+	ctx := context.Background()
+
+	// HQ -> Sales (3 сотрудника), " sales " (пустое), Ops -> Core -> Platform (1 сотрудник).
+	mockDeptRepo.On("List", ctx, models.Attributes(nil), (*time.Time)(nil)).Return([]models.Department{
+		{ID: 1, Name: "HQ", HeadID: uintPtr(10)},
+		{ID: 2, Name: "Sales", ParentID: uintPtr(1), HeadID: uintPtr(11)},
+		{ID: 3, Name: " sales ", ParentID: uintPtr(1), HeadID: uintPtr(12)},
+		{ID: 4, Name: "Ops", ParentID: uintPtr(1), HeadID: uintPtr(13)},
+		{ID: 5, Name: "Core", ParentID: uintPtr(4), HeadID: uintPtr(14)},
+		{ID: 6, Name: "Platform", ParentID: uintPtr(5)},
+	}, nil)
+	mockEmpRepo.On("List", ctx, (*uint)(nil), models.Attributes(nil), (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 11, DepartmentID: 2}, {ID: 12, DepartmentID: 2}, {ID: 13, DepartmentID: 2}, {ID: 20, DepartmentID: 6},
+	}, nil)
+
+	report, err := service.Report(ctx)
+
+	assert.NoError(t, err)
+	type key struct {
+		rule string
+		id   int
+	}
+	got := map[key]string{}
+	for _, f := range report.Findings {
+		got[key{f.Rule, f.DepartmentID}] = f.Severity
+		assert.Equal(t, fmt.Sprintf("/departments/%d", f.DepartmentID), f.Link)
+	}
+	assert.Equal(t, map[key]string{
+		{LintDuplicateLikeName, 2}: SeverityError,
+		{LintDuplicateLikeName, 3}: SeverityError,
+		{LintWideSpan, 1}:          SeverityWarning,
+		{LintWideSpan, 2}:          SeverityWarning,
+		{LintEmptyDepartment, 3}:   SeverityWarning,
+		{LintTooDeep, 6}:           SeverityWarning,
+		{LintSingleChildChain, 4}:  SeverityInfo,
+		{LintNoHead, 6}:            SeverityInfo,
+	}, got)
+	assert.Equal(t, map[string]int{SeverityError: 2, SeverityWarning: 4, SeverityInfo: 2}, report.Summary)
+	assert.Equal(t, SeverityError, report.Findings[0].Severity)
+	assert.Len(t, report.FindingsAtLeast(SeverityWarning), 6)
+}
+
+func TestLintReport_DuplicateAcrossScripts(t *testing.T) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	service := NewLintService(mockDeptRepo, mockEmpRepo, LintLimits{MaxDepth: 3, MaxSpan: 10})
+	ctx := context.Background()
+
+	mockDeptRepo.On("List", ctx, models.Attributes(nil), (*time.Time)(nil)).Return([]models.Department{
+		{ID: 1, Name: "HQ", SearchKey: "hq", HeadID: uintPtr(10)},
+		{ID: 2, Name: "Отдел продаж", SearchKey: "otdel proda2", ParentID: uintPtr(1), HeadID: uintPtr(11)},
+		{ID: 3, Name: "Otdel prodazh", SearchKey: "otdel proda2", ParentID: uintPtr(1), HeadID: uintPtr(12)},
+	}, nil)
+	mockEmpRepo.On("List", ctx, (*uint)(nil), models.Attributes(nil), (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 11, DepartmentID: 2}, {ID: 12, DepartmentID: 3},
+	}, nil)
+
+	report, err := service.Report(ctx)
+
+	assert.NoError(t, err)
+	findings := report.FindingsAtLeast(SeverityError)
+	assert.Len(t, findings, 2)
+	for _, f := range findings {
+		assert.Equal(t, LintDuplicateLikeName, f.Rule)
+	}
+	assert.Contains(t, findings[0].Message, `"Otdel prodazh" (3)`)
+}
@@ -0,0 +1,351 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"golang.org/x/text/language"
+)
+
+// LocationRepository определяет интерфейс репозитория офисов и площадок.
+type LocationRepository interface {
+	Create(ctx context.Context, loc *models.Location) error
+	GetByID(ctx context.Context, id uint) (*models.Location, error)
+	GetByName(ctx context.Context, name string) (*models.Location, error)
+	List(ctx context.Context, country string) ([]models.Location, error)
+	Update(ctx context.Context, loc *models.Location) error
+	Delete(ctx context.Context, id uint) error
+	CountUsage(ctx context.Context, id uint) (int64, error)
+	ListEmployees(ctx context.Context, id uint) ([]models.Employee, error)
+	Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error)
+}
+
+// LocationService определяет интерфейс справочника мест и размещения подразделений
+// и сотрудников, который будет реализован сервисом и использован обработчиками HTTP.
+type LocationService interface {
+	Create(ctx context.Context, loc models.Location) (*models.Location, error)
+	Get(ctx context.Context, id uint) (*models.Location, error)
+	List(ctx context.Context, country string) ([]models.Location, error)
+	Update(ctx context.Context, id uint, loc models.Location) (*models.Location, error)
+	Delete(ctx context.Context, id uint) error
+	Employees(ctx context.Context, id uint) ([]models.Employee, error)
+	Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error)
+	SetDepartmentLocation(ctx context.Context, departmentID uint, locationID *uint) (*models.Department, error)
+	SetEmployeeLocation(ctx context.Context, employeeID uint, locationID *uint) (*models.Employee, error)
+	EmployeeLocation(ctx context.Context, employeeID uint) (*models.EmployeeLocation, error)
+	// Timezone возвращает часовой пояс действующего места сотрудника или UTC, если место не задано.
+	Timezone(ctx context.Context, employeeID uint) (*time.Location, error)
+}
+
+// LocationSvc реализует справочник мест, размещение подразделений и сотрудников
+// и отчёт о численности по местам.
+type LocationSvc struct {
+	locRepo     LocationRepository
+	deptRepo    DepartmentRepository
+	empRepo     EmployeeRepository
+	historyRepo HistoryRepository
+	tx          Transactor
+	clock       func() time.Time
+}
+
+// NewLocationService создаёт новый экземпляр сервиса мест.
+// clock - источник текущего времени: time.Now в приложении, управляемые часы в тестах.
+func NewLocationService(locRepo LocationRepository, deptRepo DepartmentRepository, empRepo EmployeeRepository,
+	historyRepo HistoryRepository, tx Transactor, clock func() time.Time) *LocationSvc {
+	return &LocationSvc{locRepo: locRepo, deptRepo: deptRepo, empRepo: empRepo, historyRepo: historyRepo, tx: tx, clock: clock}
+}
+
+// Create проверяет и сохраняет новое место.
+func (s *LocationSvc) Create(ctx context.Context, loc models.Location) (*models.Location, error) {
+	if err := validateLocation(&loc); err != nil {
+		return nil, err
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkName(ctx, 0, loc.Name); err != nil {
+			return err
+		}
+		err := s.locRepo.Create(ctx, &loc)
+		if errors.Is(err, apperrors.ErrUniqueViolation) {
+			return fmt.Errorf("%w: %q", apperrors.ErrLocationConflict, loc.Name)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
+
+// Get возвращает место по идентификатору.
+func (s *LocationSvc) Get(ctx context.Context, id uint) (*models.Location, error) {
+	loc, err := s.locRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrLocationNotFound, id)
+	}
+	return loc, nil
+}
+
+// List возвращает места; непустой country (код ISO 3166-1 alpha-2) оставляет места одной страны.
+func (s *LocationSvc) List(ctx context.Context, country string) ([]models.Location, error) {
+	return s.locRepo.List(ctx, strings.ToUpper(strings.TrimSpace(country)))
+}
+
+// Update заменяет название, адрес, страну и часовой пояс места.
+func (s *LocationSvc) Update(ctx context.Context, id uint, loc models.Location) (*models.Location, error) {
+	if err := validateLocation(&loc); err != nil {
+		return nil, err
+	}
+	var result *models.Location
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkName(ctx, id, loc.Name); err != nil {
+			return err
+		}
+		existing.Name = loc.Name
+		existing.Address = loc.Address
+		existing.Country = loc.Country
+		existing.Timezone = loc.Timezone
+		result = existing
+		return s.locRepo.Update(ctx, existing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete удаляет место, не заданное ни одному подразделению и сотруднику, включая уволенных.
+func (s *LocationSvc) Delete(ctx context.Context, id uint) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.Get(ctx, id); err != nil {
+			return err
+		}
+		count, err := s.locRepo.CountUsage(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %d department(s) and employee(s)", apperrors.ErrLocationInUse, count)
+		}
+		err = s.locRepo.Delete(ctx, id)
+		// Место могли назначить параллельно после проверки.
+		if errors.Is(err, apperrors.ErrForeignKeyViolation) {
+			return apperrors.ErrLocationInUse
+		}
+		return err
+	})
+}
+
+// Employees возвращает работающих сотрудников, которые работают в месте id:
+// заданном им самим или унаследованном от подразделения.
+func (s *LocationSvc) Employees(ctx context.Context, id uint) ([]models.Employee, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.locRepo.ListEmployees(ctx, id)
+}
+
+// Headcount возвращает численность работающих сотрудников по местам: всей организации
+// при departmentID = nil, иначе поддерева подразделения.
+func (s *LocationSvc) Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error) {
+	if departmentID != nil {
+		dept, err := s.deptRepo.GetByID(ctx, *departmentID)
+		if err != nil {
+			return nil, err
+		}
+		if dept == nil {
+			return nil, apperrors.ErrDepartmentNotFound
+		}
+	}
+	return s.locRepo.Headcount(ctx, departmentID)
+}
+
+// SetDepartmentLocation задаёт место подразделения; locationID = nil снимает его, и подразделение
+// наследует место ближайшего предка. Место действует для сотрудников без собственного места.
+func (s *LocationSvc) SetDepartmentLocation(ctx context.Context, departmentID uint, locationID *uint) (*models.Department, error) {
+	var dept *models.Department
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if dept, err = s.deptRepo.GetByID(ctx, departmentID); err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		if err := s.checkLocation(ctx, locationID); err != nil {
+			return err
+		}
+		previous := dept.LocationID
+		dept.LocationID = locationID
+		if err := s.deptRepo.Update(ctx, dept); err != nil {
+			return err
+		}
+		return writeHistory(ctx, s.historyRepo, departmentID, "location", map[string]any{"from": previous, "to": locationID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// SetEmployeeLocation задаёт сотруднику собственное место вместо места подразделения;
+// locationID = nil возвращает место подразделения.
+func (s *LocationSvc) SetEmployeeLocation(ctx context.Context, employeeID uint, locationID *uint) (*models.Employee, error) {
+	var emp *models.Employee
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if emp, err = s.empRepo.GetByID(ctx, employeeID); err != nil {
+			return err
+		}
+		if emp == nil {
+			return apperrors.ErrEmployeeNotFound
+		}
+		if err := s.checkLocation(ctx, locationID); err != nil {
+			return err
+		}
+		emp.LocationID = locationID
+		return s.empRepo.Update(ctx, emp)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// EmployeeLocation возвращает действующее место сотрудника: собственное, иначе его
+// подразделения или ближайшего предка, - и текущее время в часовом поясе места.
+func (s *LocationSvc) EmployeeLocation(ctx context.Context, employeeID uint) (*models.EmployeeLocation, error) {
+	emp, err := s.empRepo.GetByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if emp == nil {
+		return nil, apperrors.ErrEmployeeNotFound
+	}
+
+	result := &models.EmployeeLocation{EmployeeID: emp.ID}
+	locationID := emp.LocationID
+	if locationID != nil {
+		result.Source = models.LocationSourceEmployee
+	} else {
+		dept, err := s.departmentWithLocation(ctx, uint(emp.DepartmentID))
+		if err != nil {
+			return nil, err
+		}
+		if dept == nil {
+			return result, nil
+		}
+		result.Source = models.LocationSourceDepartment
+		result.DepartmentID = &dept.ID
+		locationID = dept.LocationID
+	}
+
+	if result.Location, err = s.Get(ctx, *locationID); err != nil {
+		return nil, err
+	}
+	tz, err := time.LoadLocation(result.Location.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("location %d: %w", result.Location.ID, err)
+	}
+	now := s.clock().In(tz)
+	result.LocalTime = &now
+	return result, nil
+}
+
+// Timezone возвращает часовой пояс действующего места сотрудника для планирования
+// встреч и событий; если место не задано, возвращается UTC.
+func (s *LocationSvc) Timezone(ctx context.Context, employeeID uint) (*time.Location, error) {
+	loc, err := s.EmployeeLocation(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	if loc.Location == nil {
+		return time.UTC, nil
+	}
+	return loc.LocalTime.Location(), nil
+}
+
+// departmentWithLocation возвращает подразделение id или ближайшего его предка, у которого
+// задано место, либо nil, если места нет ни у кого из них.
+func (s *LocationSvc) departmentWithLocation(ctx context.Context, id uint) (*models.Department, error) {
+	dept, err := s.deptRepo.GetByID(ctx, id)
+	if err != nil || dept == nil {
+		return nil, err
+	}
+	if dept.LocationID != nil {
+		return dept, nil
+	}
+	ancestors, err := s.deptRepo.GetAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if ancestors[i].LocationID != nil {
+			return &ancestors[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// checkLocation проверяет, что место locationID существует; nil допустим.
+func (s *LocationSvc) checkLocation(ctx context.Context, locationID *uint) error {
+	if locationID == nil {
+		return nil
+	}
+	_, err := s.Get(ctx, *locationID)
+	return err
+}
+
+// checkName проверяет, что название не занято другим местом без учёта регистра.
+func (s *LocationSvc) checkName(ctx context.Context, id uint, name string) error {
+	existing, err := s.locRepo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && uint(existing.ID) != id {
+		return fmt.Errorf("%w: %q (%d)", apperrors.ErrLocationConflict, existing.Name, existing.ID)
+	}
+	return nil
+}
+
+// validateLocation нормализует и проверяет поля места: страна приводится к коду
+// ISO 3166-1 alpha-2 в верхнем регистре, часовой пояс должен быть известен базе IANA.
+func validateLocation(loc *models.Location) error {
+	loc.Name = strings.Join(strings.Fields(loc.Name), " ")
+	if loc.Name == "" || utf8.RuneCountInString(loc.Name) > 200 {
+		return fmt.Errorf("%w: name must be non-empty and max 200 characters", apperrors.ErrInvalidLocation)
+	}
+	loc.Address = strings.TrimSpace(loc.Address)
+	if utf8.RuneCountInString(loc.Address) > 500 {
+		return fmt.Errorf("%w: address must be max 500 characters", apperrors.ErrInvalidLocation)
+	}
+
+	country := strings.TrimSpace(loc.Country)
+	region, err := language.ParseRegion(country)
+	if len(country) != 2 || err != nil || !region.IsCountry() {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code, got %q", apperrors.ErrInvalidLocation, loc.Country)
+	}
+	loc.Country = region.String()
+
+	loc.Timezone = strings.TrimSpace(loc.Timezone)
+	if loc.Timezone == "" || loc.Timezone == "Local" {
+		return fmt.Errorf("%w: timezone must be an IANA time zone name", apperrors.ErrInvalidLocation)
+	}
+	if _, err := time.LoadLocation(loc.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", apperrors.ErrInvalidLocation, loc.Timezone)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLocationRepo - мок для репозитория мест
+type MockLocationRepo struct {
+	mock.Mock
+}
+
+func (m *MockLocationRepo) Create(ctx context.Context, loc *models.Location) error {
+	args := m.Called(ctx, loc)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepo) GetByID(ctx context.Context, id uint) (*models.Location, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Location), args.Error(1)
+}
+
+func (m *MockLocationRepo) GetByName(ctx context.Context, name string) (*models.Location, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Location), args.Error(1)
+}
+
+func (m *MockLocationRepo) List(ctx context.Context, country string) ([]models.Location, error) {
+	args := m.Called(ctx, country)
+	return args.Get(0).([]models.Location), args.Error(1)
+}
+
+func (m *MockLocationRepo) Update(ctx context.Context, loc *models.Location) error {
+	args := m.Called(ctx, loc)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepo) CountUsage(ctx context.Context, id uint) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLocationRepo) ListEmployees(ctx context.Context, id uint) ([]models.Employee, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockLocationRepo) Headcount(ctx context.Context, departmentID *uint) ([]models.LocationHeadcount, error) {
+	args := m.Called(ctx, departmentID)
+	return args.Get(0).([]models.LocationHeadcount), args.Error(1)
+}
+
+// locationNow - текущее время управляемых часов в тестах мест.
+var locationNow = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+func setupLocationService(t *testing.T) (*LocationSvc, *MockLocationRepo, *MockDepartmentRepo, *MockEmployeeRepo, *MockHistoryRepo) {
+	mockLocRepo := new(MockLocationRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewLocationService(mockLocRepo, mockDeptRepo, mockEmpRepo, mockHistoryRepo, fakeTx{},
+		func() time.Time { return locationNow })
+	return service, mockLocRepo, mockDeptRepo, mockEmpRepo, mockHistoryRepo
+}
+
+func TestLocationCreate(t *testing.T) {
+	service, mockLocRepo, _, _, _ := setupLocationService(t)
+	ctx := context.Background()
+
+	mockLocRepo.On("GetByName", ctx, "Berlin Office").Return(nil, nil)
+	mockLocRepo.On("Create", ctx, mock.MatchedBy(func(loc *models.Location) bool {
+		return loc.Name == "Berlin Office" && loc.Country == "DE" && loc.Timezone == "Europe/Berlin"
+	})).Return(nil)
+
+	loc, err := service.Create(ctx, models.Location{
+		Name: " Berlin  Office ", Address: " Alexanderplatz 1 ", Country: "de", Timezone: "Europe/Berlin",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Alexanderplatz 1", loc.Address)
+	mockLocRepo.AssertExpectations(t)
+}
+
+func TestLocationCreate_Invalid(t *testing.T) {
+	service, _, _, _, _ := setupLocationService(t)
+	ctx := context.Background()
+
+	tests := []models.Location{
+		{Name: "  ", Country: "DE", Timezone: "Europe/Berlin"},
+		{Name: "Office", Country: "DEU", Timezone: "Europe/Berlin"},
+		{Name: "Office", Country: "EU", Timezone: "Europe/Berlin"},
+		{Name: "Office", Country: "DE", Timezone: ""},
+		{Name: "Office", Country: "DE", Timezone: "Local"},
+		{Name: "Office", Country: "DE", Timezone: "Europe/Atlantis"},
+	}
+	for _, loc := range tests {
+		_, err := service.Create(ctx, loc)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidLocation, "%+v", loc)
+	}
+}
+
+func TestLocationCreate_Conflict(t *testing.T) {
+	service, mockLocRepo, _, _, _ := setupLocationService(t)
+	ctx := context.Background()
+
+	mockLocRepo.On("GetByName", ctx, "berlin office").Return(&models.Location{ID: 1, Name: "Berlin Office"}, nil)
+
+	_, err := service.Create(ctx, models.Location{Name: "berlin office", Country: "DE", Timezone: "Europe/Berlin"})
+
+	assert.ErrorIs(t, err, apperrors.ErrLocationConflict)
+	mockLocRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestLocationDelete_InUse(t *testing.T) {
+	service, mockLocRepo, _, _, _ := setupLocationService(t)
+	ctx := context.Background()
+
+	mockLocRepo.On("GetByID", ctx, uint(1)).Return(&models.Location{ID: 1, Name: "Berlin Office"}, nil)
+	mockLocRepo.On("CountUsage", ctx, uint(1)).Return(int64(3), nil)
+
+	err := service.Delete(ctx, 1)
+
+	assert.ErrorIs(t, err, apperrors.ErrLocationInUse)
+	mockLocRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestSetDepartmentLocation(t *testing.T) {
+	service, mockLocRepo, mockDeptRepo, _, mockHistoryRepo := setupLocationService(t)
+	ctx := context.Background()
+	locationID := uint(2)
+
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(&models.Department{ID: 5, Name: "Platform"}, nil)
+	mockLocRepo.On("GetByID", ctx, uint(2)).Return(&models.Location{ID: 2, Name: "Berlin Office"}, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.LocationID != nil && *d.LocationID == 2
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(h *models.DepartmentHistory) bool {
+		return h.DepartmentID == 5 && h.Action == "location"
+	})).Return(nil)
+
+	dept, err := service.SetDepartmentLocation(ctx, 5, &locationID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), *dept.LocationID)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestSetEmployeeLocation_UnknownLocation(t *testing.T) {
+	service, mockLocRepo, _, mockEmpRepo, _ := setupLocationService(t)
+	ctx := context.Background()
+	locationID := uint(9)
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 5}, nil)
+	mockLocRepo.On("GetByID", ctx, uint(9)).Return(nil, nil)
+
+	_, err := service.SetEmployeeLocation(ctx, 7, &locationID)
+
+	assert.ErrorIs(t, err, apperrors.ErrLocationNotFound)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestEmployeeLocation_Override(t *testing.T) {
+	service, mockLocRepo, _, mockEmpRepo, _ := setupLocationService(t)
+	ctx := context.Background()
+	locationID := uint(3)
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 5, LocationID: &locationID}, nil)
+	mockLocRepo.On("GetByID", ctx, uint(3)).Return(&models.Location{ID: 3, Name: "Tokyo", Country: "JP", Timezone: "Asia/Tokyo"}, nil)
+
+	loc, err := service.EmployeeLocation(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.LocationSourceEmployee, loc.Source)
+	assert.Nil(t, loc.DepartmentID)
+	assert.Equal(t, 18, loc.LocalTime.Hour(), "09:00 UTC is 18:00 in Tokyo")
+}
+
+func TestEmployeeLocation_InheritedFromAncestor(t *testing.T) {
+	service, mockLocRepo, mockDeptRepo, mockEmpRepo, _ := setupLocationService(t)
+	ctx := context.Background()
+	rootLocation, divisionLocation := uint(1), uint(2)
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 5}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(&models.Department{ID: 5, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(5)).Return([]models.Department{
+		{ID: 1, Name: "Company", LocationID: &rootLocation},
+		{ID: 3, Name: "Engineering", LocationID: &divisionLocation},
+		{ID: 4, Name: "Backend"},
+	}, nil)
+	mockLocRepo.On("GetByID", ctx, uint(2)).Return(&models.Location{ID: 2, Name: "New York", Country: "US", Timezone: "America/New_York"}, nil)
+
+	loc, err := service.EmployeeLocation(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.LocationSourceDepartment, loc.Source)
+	assert.Equal(t, 3, *loc.DepartmentID, "the nearest ancestor with a location wins")
+	assert.Equal(t, "New York", loc.Location.Name)
+
+	tz, err := service.Timezone(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "America/New_York", tz.String())
+}
+
+func TestEmployeeLocation_None(t *testing.T) {
+	service, _, mockDeptRepo, mockEmpRepo, _ := setupLocationService(t)
+	ctx := context.Background()
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 5}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(&models.Department{ID: 5, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetAncestors", ctx, uint(5)).Return([]models.Department{}, nil)
+
+	loc, err := service.EmployeeLocation(ctx, 7)
+	assert.NoError(t, err)
+	assert.Nil(t, loc.Location)
+	assert.Nil(t, loc.LocalTime)
+
+	tz, err := service.Timezone(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, tz)
+}
+
+func TestLocationHeadcount_DepartmentNotFound(t *testing.T) {
+	service, mockLocRepo, mockDeptRepo, _, _ := setupLocationService(t)
+	ctx := context.Background()
+	departmentID := uint(42)
+
+	mockDeptRepo.On("GetByID", ctx, uint(42)).Return(nil, nil)
+
+	_, err := service.Headcount(ctx, &departmentID)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+	mockLocRepo.AssertNotCalled(t, "Headcount", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,152 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Классы символов, которые политика именования может разрешить в названиях подразделений.
+const (
+	NameCharsLetters     = "letters"
+	NameCharsDigits      = "digits"
+	NameCharsSpaces      = "spaces"
+	NameCharsPunctuation = "punctuation"
+	NameCharsSymbols     = "symbols"
+)
+
+// maxNameLength - длина колонки departments.name в символах.
+const maxNameLength = 200
+
+// NamingPolicy задаёт правила названий подразделений организации. Длина считается
+// в символах (рунах), а не в байтах. Пустой AllowedChars разрешает любые символы.
+type NamingPolicy struct {
+	// MinLength и MaxLength - допустимая длина названия в символах.
+	MinLength int
+	MaxLength int
+	// AllowedChars - разрешённые классы символов (NameChars*).
+	AllowedChars []string
+	// ForbiddenWords - слова, которые не могут входить в название, без учёта регистра.
+	ForbiddenWords []string
+}
+
+// DefaultNamingPolicy возвращает политику по умолчанию: от 1 до 200 символов, любые символы.
+func DefaultNamingPolicy() NamingPolicy {
+	return NamingPolicy{MinLength: 1, MaxLength: maxNameLength}
+}
+
+// Normalize приводит название к нормальной форме Unicode NFC, схлопывает пробельные
+// символы в один пробел, обрезает их по краям и проверяет результат по правилам политики.
+func (p NamingPolicy) Normalize(name string) (string, error) {
+	clean := canonicalName(name)
+	length := utf8.RuneCountInString(clean)
+	if length == 0 {
+		return "", nameError("name cannot be empty")
+	}
+	if length > p.MaxLength {
+		return "", nameError(fmt.Sprintf("name too long (max %d)", p.MaxLength))
+	}
+	if length < p.MinLength {
+		return "", nameError(fmt.Sprintf("name too short (min %d)", p.MinLength))
+	}
+
+	if len(p.AllowedChars) > 0 {
+		for _, r := range clean {
+			if !p.allowed(r) {
+				return "", nameError(fmt.Sprintf("character %q is not allowed (allowed: %s)", r, strings.Join(p.AllowedChars, ", ")))
+			}
+		}
+	}
+
+	words := strings.FieldsFunc(clean, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	for _, word := range words {
+		for _, forbidden := range p.ForbiddenWords {
+			if strings.EqualFold(word, forbidden) {
+				return "", nameError(fmt.Sprintf("name contains forbidden word %q", forbidden))
+			}
+		}
+	}
+	return clean, nil
+}
+
+// allowed сообщает, относится ли символ к одному из разрешённых классов.
+func (p NamingPolicy) allowed(r rune) bool {
+	for _, class := range p.AllowedChars {
+		switch class {
+		case NameCharsLetters:
+			// Диакритические знаки, не составленные NFC, считаются частью буквы.
+			if unicode.IsLetter(r) || unicode.IsMark(r) {
+				return true
+			}
+		case NameCharsDigits:
+			if unicode.IsDigit(r) {
+				return true
+			}
+		case NameCharsSpaces:
+			if r == ' ' {
+				return true
+			}
+		case NameCharsPunctuation:
+			if unicode.IsPunct(r) {
+				return true
+			}
+		case NameCharsSymbols:
+			if unicode.IsSymbol(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameError - нарушение политики именования. Текст ошибки не содержит префикса,
+// но errors.Is сопоставляет её с ErrInvalidDepartmentName.
+type nameError string
+
+func (e nameError) Error() string {
+	return string(e)
+}
+
+func (e nameError) Is(target error) bool {
+	return target == apperrors.ErrInvalidDepartmentName
+}
+
+// nameConflictError - совпадение названия с соседним подразделением. Текст ошибки
+// сохраняется, но errors.Is сопоставляет её с ErrDepartmentNameConflict.
+type nameConflictError string
+
+func (e nameConflictError) Error() string {
+	return string(e)
+}
+
+func (e nameConflictError) Is(target error) bool {
+	return target == apperrors.ErrDepartmentNameConflict
+}
+
+// canonicalName приводит название к NFC и схлопывает пробельные символы.
+func canonicalName(name string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+}
+
+// findSibling возвращает подразделение под parentID, кроме id, название которого совпадает
+// с name по ключу department_name_key (без учёта регистра и лишних пробелов). Проверка лишь
+// даёт понятную ошибку заранее: уникальность ключа гарантирует индекс в базе данных.
+func findSibling(ctx context.Context, repo DepartmentRepository, id uint, name string, parentID *uint) (*models.Department, error) {
+	sibling, err := repo.GetByNameAndParent(ctx, name, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if sibling != nil && uint(sibling.ID) != id {
+		return sibling, nil
+	}
+	return nil, nil
+}
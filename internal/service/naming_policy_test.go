@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNamingPolicyNormalize(t *testing.T) {
+	policy := DefaultNamingPolicy()
+
+	// Длина считается в символах: 200 кириллических букв занимают 400 байт.
+	name, err := policy.Normalize(strings.Repeat("ж", 200))
+	assert.NoError(t, err)
+	assert.Len(t, []rune(name), 200)
+	_, err = policy.Normalize(strings.Repeat("ж", 201))
+	assert.EqualError(t, err, "name too long (max 200)")
+
+	// Разложенная "й" (и + краткая) приводится к NFC, пробелы схлопываются.
+	name, err = policy.Normalize("  Отдел \tпродаж  Сибирский  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Отдел продаж Сибирский", name)
+
+	_, err = policy.Normalize(" \t ")
+	assert.EqualError(t, err, "name cannot be empty")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidDepartmentName)
+}
+
+func TestNamingPolicyRules(t *testing.T) {
+	policy := NamingPolicy{
+		MinLength:      3,
+		MaxLength:      50,
+		AllowedChars:   []string{NameCharsLetters, NameCharsDigits, NameCharsSpaces, NameCharsPunctuation},
+		ForbiddenWords: []string{"test", "временный"},
+	}
+
+	_, err := policy.Normalize("Отдел R&D-2")
+	assert.NoError(t, err, "& is punctuation")
+	_, err = policy.Normalize("IT")
+	assert.EqualError(t, err, "name too short (min 3)")
+	_, err = policy.Normalize("Sales $")
+	assert.ErrorContains(t, err, "is not allowed")
+	_, err = policy.Normalize("Sales TEST team")
+	assert.EqualError(t, err, `name contains forbidden word "test"`)
+	_, err = policy.Normalize("Временный штаб")
+	assert.ErrorContains(t, err, "forbidden word")
+	_, err = policy.Normalize("Testing")
+	assert.NoError(t, err, "only whole words are forbidden")
+}
+
+func TestCreate_CaseInsensitiveSibling(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewDepartmentService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo),
+		fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	parentID := uint(1)
+	sales := &models.Department{ID: 4, Name: "Sales", ParentID: &parentID}
+	mockDeptRepo.On("GetByNameAndParent", ctx, "sales", &parentID).Return(sales, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "SALES", &parentID).Return(sales, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Sales", ParentID: &parentID}, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.Name == "SALES"
+	})).Return(nil)
+
+	_, err := service.Create(ctx, "sales", &parentID, nil, nil)
+	assert.EqualError(t, err, "department with this name already exists the same parent")
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	mockDeptRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+
+	// Переименование с изменением только регистра не конфликтует с самим подразделением.
+	newName := "SALES"
+	dept, err := service.Update(ctx, 4, &newName, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "SALES", dept.Name)
+}
+
+func TestCreate_NameConflictRace(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewDepartmentService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo),
+		fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	// Проверка не нашла соседа, но параллельный запрос успел создать его раньше вставки.
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Sales", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("Create", ctx, mock.Anything).Return(apperrors.ErrDepartmentNameConflict)
+
+	_, err := service.Create(ctx, "Sales", nil, nil, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+}
+
+func TestImport_NameConflictIgnoresCase(t *testing.T) {
+	ctx := context.Background()
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewImportService(mockDeptRepo, new(MockEmployeeRepo), new(MockVacancyRepo), fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitHard, ChangeApprovalOptional)
+	mockDeptRepo.On("GetByCode", ctx, "OPS").Return(nil, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Operations", (*uint)(nil)).Return(&models.Department{ID: 2, Name: "OPERATIONS"}, nil)
+
+	_, err := service.Import(ctx, []ImportDepartment{{Code: strPtr("ops"), Name: " Operations "}}, nil)
+
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+}
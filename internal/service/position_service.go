@@ -0,0 +1,246 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// PositionRepository определяет интерфейс репозитория справочника должностей.
+type PositionRepository interface {
+	Create(ctx context.Context, pos *models.Position) error
+	GetByID(ctx context.Context, id uint) (*models.Position, error)
+	GetByTitle(ctx context.Context, title string) (*models.Position, error)
+	List(ctx context.Context, jobFamily string) ([]models.Position, error)
+	Update(ctx context.Context, pos *models.Position) error
+	Delete(ctx context.Context, id uint) error
+	CountUsage(ctx context.Context, id uint) (int64, error)
+	Merge(ctx context.Context, sourceID, targetID uint) error
+	Headcount(ctx context.Context, departmentID *uint) ([]models.PositionHeadcount, error)
+}
+
+// PositionService определяет интерфейс справочника должностей,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type PositionService interface {
+	Create(ctx context.Context, pos models.Position) (*models.Position, error)
+	Get(ctx context.Context, id uint) (*models.Position, error)
+	List(ctx context.Context, jobFamily string) ([]models.Position, error)
+	Update(ctx context.Context, id uint, pos models.Position) (*models.Position, error)
+	Delete(ctx context.Context, id uint) error
+	Merge(ctx context.Context, sourceID, targetID uint) (*models.Position, error)
+	JobFamilies(ctx context.Context, departmentID *uint) ([]models.JobFamilyHeadcount, error)
+}
+
+// PositionSvc реализует справочник должностей и отчёт о численности по профессиональным семействам.
+type PositionSvc struct {
+	posRepo  PositionRepository
+	deptRepo DepartmentRepository
+	tx       Transactor
+}
+
+// NewPositionService создаёт новый экземпляр сервиса справочника должностей.
+func NewPositionService(posRepo PositionRepository, deptRepo DepartmentRepository, tx Transactor) *PositionSvc {
+	return &PositionSvc{posRepo: posRepo, deptRepo: deptRepo, tx: tx}
+}
+
+// Create проверяет и сохраняет новую должность. Сотрудники, название должности которых
+// совпадает с ней без учёта регистра и пробелов, связываются с ней.
+func (s *PositionSvc) Create(ctx context.Context, pos models.Position) (*models.Position, error) {
+	if err := validatePosition(&pos); err != nil {
+		return nil, err
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkTitle(ctx, 0, pos.Title); err != nil {
+			return err
+		}
+		// Должность с тем же названием могла появиться после проверки в параллельном запросе.
+		err := s.posRepo.Create(ctx, &pos)
+		if errors.Is(err, apperrors.ErrUniqueViolation) {
+			return fmt.Errorf("%w: %q", apperrors.ErrPositionConflict, pos.Title)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pos, nil
+}
+
+// Get возвращает должность по идентификатору.
+func (s *PositionSvc) Get(ctx context.Context, id uint) (*models.Position, error) {
+	pos, err := s.posRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pos == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrPositionNotFound, id)
+	}
+	return pos, nil
+}
+
+// List возвращает должности справочника; непустой jobFamily оставляет должности одного семейства.
+func (s *PositionSvc) List(ctx context.Context, jobFamily string) ([]models.Position, error) {
+	return s.posRepo.List(ctx, strings.TrimSpace(jobFamily))
+}
+
+// Update заменяет название, семейство и грейд должности. Новое название получают
+// все сотрудники этой должности.
+func (s *PositionSvc) Update(ctx context.Context, id uint, pos models.Position) (*models.Position, error) {
+	if err := validatePosition(&pos); err != nil {
+		return nil, err
+	}
+	var result *models.Position
+	err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkTitle(ctx, id, pos.Title); err != nil {
+			return err
+		}
+		existing.Title = pos.Title
+		existing.JobFamily = pos.JobFamily
+		existing.Grade = pos.Grade
+		result = existing
+		return s.posRepo.Update(ctx, existing)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete удаляет должность, которая не назначена ни одному сотруднику, включая уволенных, и ни одной вакансии.
+func (s *PositionSvc) Delete(ctx context.Context, id uint) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.Get(ctx, id); err != nil {
+			return err
+		}
+		count, err := s.posRepo.CountUsage(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %d employee(s) and vacancies, merge it into another position instead", apperrors.ErrPositionInUse, count)
+		}
+		return s.posRepo.Delete(ctx, id)
+	})
+}
+
+// Merge переводит сотрудников и вакансии должности sourceID на должность targetID и удаляет sourceID.
+// Так сводятся разные названия одной должности, например "BE Developer" и "Backend Engineer".
+func (s *PositionSvc) Merge(ctx context.Context, sourceID, targetID uint) (*models.Position, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a position into itself", apperrors.ErrInvalidPositionEntry)
+	}
+	var target *models.Position
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.Get(ctx, sourceID); err != nil {
+			return err
+		}
+		var err error
+		if target, err = s.Get(ctx, targetID); err != nil {
+			return err
+		}
+		return s.posRepo.Merge(ctx, sourceID, targetID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// JobFamilies возвращает численность работающих сотрудников по профессиональным семействам
+// и их должностям: всей организации при departmentID = nil, иначе поддерева подразделения.
+// Семейства упорядочены по убыванию численности.
+func (s *PositionSvc) JobFamilies(ctx context.Context, departmentID *uint) ([]models.JobFamilyHeadcount, error) {
+	if departmentID != nil {
+		dept, err := s.deptRepo.GetByID(ctx, *departmentID)
+		if err != nil {
+			return nil, err
+		}
+		if dept == nil {
+			return nil, apperrors.ErrDepartmentNotFound
+		}
+	}
+	rows, err := s.posRepo.Headcount(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	families := []models.JobFamilyHeadcount{}
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.JobFamily]
+		if !ok {
+			i = len(families)
+			index[row.JobFamily] = i
+			families = append(families, models.JobFamilyHeadcount{JobFamily: row.JobFamily, Positions: []models.PositionHeadcount{}})
+		}
+		families[i].Headcount += row.Headcount
+		families[i].Positions = append(families[i].Positions, row)
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		a, b := families[i], families[j]
+		// Сотрудники без должности справочника (семейство "") всегда последние.
+		if (a.JobFamily == "") != (b.JobFamily == "") {
+			return b.JobFamily == ""
+		}
+		if a.Headcount != b.Headcount {
+			return a.Headcount > b.Headcount
+		}
+		return a.JobFamily < b.JobFamily
+	})
+	return families, nil
+}
+
+// resolvePosition находит должность справочника по идентификатору positionID либо
+// по названию title. Название, которого нет в справочнике, допускается: тогда
+// возвращается nil. Неизвестный positionID - ошибка ErrPositionNotFound.
+func resolvePosition(ctx context.Context, repo PositionRepository, title string, positionID *uint) (*models.Position, error) {
+	if positionID == nil {
+		return repo.GetByTitle(ctx, title)
+	}
+	pos, err := repo.GetByID(ctx, *positionID)
+	if err != nil {
+		return nil, err
+	}
+	if pos == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrPositionNotFound, *positionID)
+	}
+	return pos, nil
+}
+
+// checkTitle проверяет, что название не занято другой должностью, кроме id.
+func (s *PositionSvc) checkTitle(ctx context.Context, id uint, title string) error {
+	existing, err := s.posRepo.GetByTitle(ctx, title)
+	if err != nil {
+		return err
+	}
+	if existing != nil && uint(existing.ID) != id {
+		return fmt.Errorf("%w: %q (%d)", apperrors.ErrPositionConflict, existing.Title, existing.ID)
+	}
+	return nil
+}
+
+// validatePosition нормализует и проверяет поля должности.
+func validatePosition(pos *models.Position) error {
+	pos.Title = strings.Join(strings.Fields(pos.Title), " ")
+	if pos.Title == "" || len(pos.Title) > 200 {
+		return fmt.Errorf("%w: title must be non-empty and max 200 characters", apperrors.ErrInvalidPositionEntry)
+	}
+	pos.JobFamily = strings.TrimSpace(pos.JobFamily)
+	if len(pos.JobFamily) > 100 {
+		return fmt.Errorf("%w: job_family must be max 100 characters", apperrors.ErrInvalidPositionEntry)
+	}
+	if pos.Grade != nil && *pos.Grade < 1 {
+		return fmt.Errorf("%w: grade must be a positive integer", apperrors.ErrInvalidPositionEntry)
+	}
+	return nil
+}
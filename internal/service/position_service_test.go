@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPositionRepo - мок для репозитория справочника должностей
+type MockPositionRepo struct {
+	mock.Mock
+}
+
+func (m *MockPositionRepo) Create(ctx context.Context, pos *models.Position) error {
+	args := m.Called(ctx, pos)
+	return args.Error(0)
+}
+
+func (m *MockPositionRepo) GetByID(ctx context.Context, id uint) (*models.Position, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionRepo) GetByTitle(ctx context.Context, title string) (*models.Position, error) {
+	args := m.Called(ctx, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Position), args.Error(1)
+}
+
+func (m *MockPositionRepo) List(ctx context.Context, jobFamily string) ([]models.Position, error) {
+	args := m.Called(ctx, jobFamily)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Position), args.Error(1)
+}
+
+func (m *MockPositionRepo) Update(ctx context.Context, pos *models.Position) error {
+	args := m.Called(ctx, pos)
+	return args.Error(0)
+}
+
+func (m *MockPositionRepo) Delete(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPositionRepo) CountUsage(ctx context.Context, id uint) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPositionRepo) Merge(ctx context.Context, sourceID, targetID uint) error {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockPositionRepo) Headcount(ctx context.Context, departmentID *uint) ([]models.PositionHeadcount, error) {
+	args := m.Called(ctx, departmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PositionHeadcount), args.Error(1)
+}
+
+func setupPositionService(t *testing.T) (*PositionSvc, *MockPositionRepo, *MockDepartmentRepo) {
+	mockPosRepo := new(MockPositionRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	service := NewPositionService(mockPosRepo, mockDeptRepo, fakeTx{})
+	return service, mockPosRepo, mockDeptRepo
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestPositionCreate(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	mockPosRepo.On("GetByTitle", ctx, "Backend Engineer").Return(nil, nil)
+	mockPosRepo.On("Create", ctx, mock.MatchedBy(func(pos *models.Position) bool {
+		return pos.Title == "Backend Engineer" && pos.JobFamily == "Engineering" && *pos.Grade == 3
+	})).Return(nil)
+
+	pos, err := service.Create(ctx, models.Position{Title: "  Backend   Engineer ", JobFamily: " Engineering", Grade: intPtr(3)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", pos.Title)
+	assert.Equal(t, "Engineering", pos.JobFamily)
+	mockPosRepo.AssertExpectations(t)
+}
+
+func TestPositionCreate_Invalid(t *testing.T) {
+	service, _, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	_, err := service.Create(ctx, models.Position{Title: "   "})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidPositionEntry)
+
+	_, err = service.Create(ctx, models.Position{Title: "Backend Engineer", Grade: intPtr(0)})
+	assert.ErrorIs(t, err, apperrors.ErrInvalidPositionEntry)
+}
+
+func TestPositionCreate_Conflict(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	mockPosRepo.On("GetByTitle", ctx, "backend engineer").Return(&models.Position{ID: 1, Title: "Backend Engineer"}, nil)
+
+	_, err := service.Create(ctx, models.Position{Title: "backend engineer"})
+
+	assert.ErrorIs(t, err, apperrors.ErrPositionConflict)
+	mockPosRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPositionCreate_ConcurrentDuplicate(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	// Проверка не нашла должность, но уникальный индекс отклонил вставку параллельного дубликата.
+	mockPosRepo.On("GetByTitle", ctx, "Backend Engineer").Return(nil, nil)
+	mockPosRepo.On("Create", ctx, mock.Anything).
+		Return(&apperrors.DBError{Kind: apperrors.ErrUniqueViolation, Constraint: "idx_positions_title_key"})
+
+	_, err := service.Create(ctx, models.Position{Title: "Backend Engineer"})
+
+	assert.ErrorIs(t, err, apperrors.ErrPositionConflict)
+}
+
+func TestPositionUpdate_SameTitleDifferentCase(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	existing := &models.Position{ID: 1, Title: "backend engineer"}
+	mockPosRepo.On("GetByID", ctx, uint(1)).Return(existing, nil)
+	mockPosRepo.On("GetByTitle", ctx, "Backend Engineer").Return(existing, nil)
+	mockPosRepo.On("Update", ctx, existing).Return(nil)
+
+	pos, err := service.Update(ctx, 1, models.Position{Title: "Backend Engineer", JobFamily: "Engineering"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", pos.Title)
+	assert.Equal(t, "Engineering", pos.JobFamily)
+	mockPosRepo.AssertExpectations(t)
+}
+
+func TestPositionDelete_InUse(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	mockPosRepo.On("GetByID", ctx, uint(1)).Return(&models.Position{ID: 1, Title: "BE Developer"}, nil)
+	mockPosRepo.On("CountUsage", ctx, uint(1)).Return(int64(2), nil)
+
+	err := service.Delete(ctx, 1)
+
+	assert.ErrorIs(t, err, apperrors.ErrPositionInUse)
+	mockPosRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestPositionMerge(t *testing.T) {
+	service, mockPosRepo, _ := setupPositionService(t)
+	ctx := context.Background()
+
+	mockPosRepo.On("GetByID", ctx, uint(2)).Return(&models.Position{ID: 2, Title: "BE Developer"}, nil)
+	mockPosRepo.On("GetByID", ctx, uint(1)).Return(&models.Position{ID: 1, Title: "Backend Engineer"}, nil)
+	mockPosRepo.On("Merge", ctx, uint(2), uint(1)).Return(nil)
+
+	pos, err := service.Merge(ctx, 2, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", pos.Title)
+	mockPosRepo.AssertExpectations(t)
+
+	_, err = service.Merge(ctx, 1, 1)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidPositionEntry)
+
+	mockPosRepo.On("GetByID", ctx, uint(9)).Return(nil, nil)
+	_, err = service.Merge(ctx, 9, 1)
+	assert.ErrorIs(t, err, apperrors.ErrPositionNotFound)
+}
+
+func TestPositionJobFamilies(t *testing.T) {
+	service, mockPosRepo, mockDeptRepo := setupPositionService(t)
+	ctx := context.Background()
+	deptID := uint(5)
+
+	mockDeptRepo.On("GetByID", ctx, deptID).Return(&models.Department{ID: 5, Name: "R&D"}, nil)
+	mockPosRepo.On("Headcount", ctx, &deptID).Return([]models.PositionHeadcount{
+		{Title: "Office Dog", Headcount: 1},
+		{PositionID: intPtr(3), Title: "Recruiter", JobFamily: "HR", Headcount: 1},
+		{PositionID: intPtr(1), Title: "Backend Engineer", JobFamily: "Engineering", Grade: intPtr(2), Headcount: 4},
+		{PositionID: intPtr(2), Title: "Frontend Engineer", JobFamily: "Engineering", Headcount: 1},
+	}, nil)
+
+	families, err := service.JobFamilies(ctx, &deptID)
+
+	assert.NoError(t, err)
+	assert.Len(t, families, 3)
+	assert.Equal(t, "Engineering", families[0].JobFamily)
+	assert.Equal(t, 5, families[0].Headcount)
+	assert.Len(t, families[0].Positions, 2)
+	assert.Equal(t, "HR", families[1].JobFamily)
+	assert.Equal(t, "", families[2].JobFamily)
+
+	missing := uint(99)
+	mockDeptRepo.On("GetByID", ctx, missing).Return(nil, nil)
+	_, err = service.JobFamilies(ctx, &missing)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+}
@@ -0,0 +1,325 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// errReorgDryRun откатывает транзакцию пробного выполнения пакета при его проверке.
+var errReorgDryRun = errors.New("reorganization dry run")
+
+// ReorgRepository определяет интерфейс репозитория пакетов запланированных изменений.
+type ReorgRepository interface {
+	Create(ctx context.Context, batch *models.ReorgBatch) error
+	GetByID(ctx context.Context, id uint) (*models.ReorgBatch, error)
+	Lock(ctx context.Context, id uint) (*models.ReorgBatch, error)
+	List(ctx context.Context, status string) ([]models.ReorgBatch, error)
+	Due(ctx context.Context, now time.Time) ([]models.ReorgBatch, error)
+	Update(ctx context.Context, batch *models.ReorgBatch) error
+	MarkFailed(ctx context.Context, id uint, message string, at time.Time) (bool, error)
+}
+
+// ReorgService определяет интерфейс пакетов запланированных изменений структуры,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type ReorgService interface {
+	Submit(ctx context.Context, executeAt time.Time, ops []models.ReorgOperation) (*models.ReorgBatch, error)
+	Get(ctx context.Context, id uint) (*models.ReorgBatch, error)
+	List(ctx context.Context, status string) ([]models.ReorgBatch, error)
+	Cancel(ctx context.Context, id uint) (*models.ReorgBatch, error)
+}
+
+// ReorgSvc реализует пакеты запланированных изменений: проверку при создании,
+// атомарное выполнение по расписанию и отмену.
+type ReorgSvc struct {
+	reorgRepo    ReorgRepository
+	deptRepo     DepartmentRepository
+	empRepo      EmployeeRepository
+	departments  DepartmentService
+	types        DepartmentTypeRules
+	names        NamingPolicy
+	guard        headcountGuard
+	tx           Transactor
+	clock        func() time.Time
+	approvalMode string
+}
+
+// NewReorgService создаёт новый экземпляр сервиса пакетов изменений.
+// clock - источник текущего времени: time.Now в приложении, управляемые часы в тестах;
+// limitMode - режим проверки утверждённой численности подразделения при переводе,
+// approvalMode - режим согласования переносов и удалений подразделений.
+func NewReorgService(reorgRepo ReorgRepository, deptRepo DepartmentRepository, empRepo EmployeeRepository,
+	vacancyRepo VacancyRepository, departments DepartmentService, types DepartmentTypeRules, names NamingPolicy,
+	tx Transactor, clock func() time.Time, limitMode string, approvalMode string) *ReorgSvc {
+	return &ReorgSvc{reorgRepo: reorgRepo, deptRepo: deptRepo, empRepo: empRepo, departments: departments, types: types,
+		names: names, tx: tx, clock: clock, approvalMode: approvalMode,
+		guard: headcountGuard{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo, mode: limitMode}}
+}
+
+// Submit проверяет пакет пробным выполнением на текущей структуре с откатом
+// и сохраняет его в статусе pending для выполнения в момент executeAt.
+func (s *ReorgSvc) Submit(ctx context.Context, executeAt time.Time, ops []models.ReorgOperation) (*models.ReorgBatch, error) {
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("%w: operations must not be empty", apperrors.ErrInvalidReorgBatch)
+	}
+	if executeAt.Before(s.clock()) {
+		return nil, apperrors.ErrInvalidExecutionTime
+	}
+	for i, op := range ops {
+		if err := validateReorgOperation(op); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", apperrors.ErrInvalidReorgBatch, i+1, err)
+		}
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.execute(ctx, ops); err != nil {
+			return err
+		}
+		return errReorgDryRun
+	})
+	if !errors.Is(err, errReorgDryRun) {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrInvalidReorgBatch, err)
+	}
+
+	batch := &models.ReorgBatch{
+		Status:     models.ReorgStatusPending,
+		ExecuteAt:  executeAt.UTC(),
+		Operations: ops,
+	}
+	if err := s.reorgRepo.Create(ctx, batch); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// Get возвращает пакет изменений по идентификатору.
+func (s *ReorgSvc) Get(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	batch, err := s.reorgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, apperrors.ErrReorgBatchNotFound
+	}
+	return batch, nil
+}
+
+// List возвращает пакеты изменений, необязательно отфильтрованные по статусу.
+func (s *ReorgSvc) List(ctx context.Context, status string) ([]models.ReorgBatch, error) {
+	switch status {
+	case "", models.ReorgStatusPending, models.ReorgStatusApplied, models.ReorgStatusFailed, models.ReorgStatusCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", apperrors.ErrInvalidReorgBatch, status)
+	}
+	return s.reorgRepo.List(ctx, status)
+}
+
+// Cancel отменяет ожидающий выполнения пакет.
+func (s *ReorgSvc) Cancel(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	var result *models.ReorgBatch
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		batch, err := s.reorgRepo.Lock(ctx, id)
+		if err != nil {
+			return err
+		}
+		if batch == nil {
+			return apperrors.ErrReorgBatchNotFound
+		}
+		if batch.Status != models.ReorgStatusPending {
+			return fmt.Errorf("%w: status is %s", apperrors.ErrReorgBatchNotPending, batch.Status)
+		}
+		batch.Status = models.ReorgStatusCancelled
+		result = batch
+		return s.reorgRepo.Update(ctx, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExecuteDue выполняет пакеты, время которых наступило. Каждый пакет выполняется
+// в своей транзакции целиком или не выполняется вовсе; пакет с ошибкой помечается
+// как failed с её текстом. После временной ошибки БД пакет остаётся pending
+// и выполняется при следующей проверке. Возвращает количество выполненных пакетов и ошибки остальных.
+func (s *ReorgSvc) ExecuteDue(ctx context.Context) (int, error) {
+	now := s.clock()
+	batches, err := s.reorgRepo.Due(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	for i := range batches {
+		executed := false
+		err := s.tx.WithinRetryableTransaction(ctx, func(ctx context.Context) error {
+			executed = false
+			batch, err := s.reorgRepo.Lock(ctx, uint(batches[i].ID))
+			if err != nil {
+				return err
+			}
+			// Пакет могли отменить или выполнить после выборки.
+			if batch == nil || batch.Status != models.ReorgStatusPending {
+				return nil
+			}
+			if err := s.execute(ctx, batch.Operations); err != nil {
+				return err
+			}
+			batch.Status = models.ReorgStatusApplied
+			batch.ExecutedAt = &now
+			executed = true
+			return s.reorgRepo.Update(ctx, batch)
+		})
+		if err == nil {
+			if executed {
+				applied++
+			}
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("reorganization batch %d: %w", batches[i].ID, err))
+		if transientError(err) {
+			continue
+		}
+		if _, err := s.reorgRepo.MarkFailed(ctx, uint(batches[i].ID), err.Error(), now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// transientError сообщает, вызвана ли ошибка не содержимым пакета, а временным состоянием:
+// конфликтом или таймаутом транзакции либо отменой контекста. Такой пакет выполняется повторно.
+func transientError(err error) bool {
+	return errors.Is(err, apperrors.ErrSerializationFailure) ||
+		errors.Is(err, apperrors.ErrDeadlock) ||
+		errors.Is(err, apperrors.ErrStatementTimeout) ||
+		errors.Is(err, apperrors.ErrLockNotAvailable) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// execute последовательно выполняет операции пакета в транзакции из контекста.
+func (s *ReorgSvc) execute(ctx context.Context, ops []models.ReorgOperation) error {
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case models.ReorgOpMove:
+			err = s.move(ctx, op)
+		case models.ReorgOpRename:
+			err = s.rename(ctx, op)
+		case models.ReorgOpTransfer:
+			err = s.transfer(ctx, op)
+		case models.ReorgOpDelete:
+			err = s.departments.Delete(ctx, op.DepartmentID, op.Mode, op.ReassignTo)
+		}
+		if err != nil {
+			return fmt.Errorf("operation %d (%s): %w", i+1, op.Type, err)
+		}
+	}
+	return nil
+}
+
+// move переносит подразделение под нового родителя.
+func (s *ReorgSvc) move(ctx context.Context, op models.ReorgOperation) error {
+	dept, err := s.deptRepo.GetByID(ctx, op.DepartmentID)
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return apperrors.ErrDepartmentNotFound
+	}
+	parentID := op.ParentID
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if !sameParent(dept.ParentID, parentID) {
+		if err := requireApproval(ctx, s.approvalMode, "move", op.DepartmentID); err != nil {
+			return err
+		}
+	}
+	if err := checkPlacement(ctx, s.deptRepo, s.types, dept, dept.Name, parentID, time.Time{}); err != nil {
+		return err
+	}
+	dept.ParentID = parentID
+	return s.deptRepo.Update(ctx, dept)
+}
+
+// rename переименовывает подразделение.
+func (s *ReorgSvc) rename(ctx context.Context, op models.ReorgOperation) error {
+	dept, err := s.deptRepo.GetByID(ctx, op.DepartmentID)
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return apperrors.ErrDepartmentNotFound
+	}
+	name, err := s.names.Normalize(op.Name)
+	if err != nil {
+		return fmt.Errorf("%w: %v", apperrors.ErrInvalidDepartmentName, err)
+	}
+	if err := checkPlacement(ctx, s.deptRepo, s.types, dept, name, dept.ParentID, time.Time{}); err != nil {
+		return err
+	}
+	dept.Name = name
+	return s.deptRepo.Update(ctx, dept)
+}
+
+// transfer переводит сотрудника в другое подразделение с теми же проверками, что и перевод по API.
+// В мягком режиме превышение утверждённой численности допускается: у пакета нет ответа,
+// в котором его показать.
+func (s *ReorgSvc) transfer(ctx context.Context, op models.ReorgOperation) error {
+	emp, err := s.empRepo.GetByID(ctx, op.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if emp == nil {
+		return apperrors.ErrEmployeeNotFound
+	}
+	dept, err := s.deptRepo.GetByID(ctx, op.DepartmentID)
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return apperrors.ErrEmployeeDepartmentNotFound
+	}
+	if _, err := validateTransfer(ctx, s.guard, emp, dept); err != nil {
+		return err
+	}
+	emp.DepartmentID = int(op.DepartmentID)
+	return s.empRepo.Update(ctx, emp)
+}
+
+// validateReorgOperation проверяет, что у операции известный тип и заданы нужные ей поля.
+func validateReorgOperation(op models.ReorgOperation) error {
+	switch op.Type {
+	case models.ReorgOpMove:
+		if op.DepartmentID == 0 {
+			return errors.New("move requires department_id")
+		}
+	case models.ReorgOpRename:
+		if op.DepartmentID == 0 || op.Name == "" {
+			return errors.New("rename requires department_id and name")
+		}
+	case models.ReorgOpTransfer:
+		if op.EmployeeID == 0 || op.DepartmentID == 0 {
+			return errors.New("transfer requires employee_id and department_id")
+		}
+	case models.ReorgOpDelete:
+		if op.DepartmentID == 0 {
+			return errors.New("delete requires department_id")
+		}
+		if op.Mode != "cascade" && op.Mode != "reassign" {
+			return errors.New("delete requires mode cascade or reassign")
+		}
+	default:
+		return fmt.Errorf("unknown operation type %q", op.Type)
+	}
+	return nil
+}
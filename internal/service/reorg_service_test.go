@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReorgRepo - мок для репозитория пакетов изменений
+type MockReorgRepo struct {
+	mock.Mock
+}
+
+func (m *MockReorgRepo) Create(ctx context.Context, batch *models.ReorgBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockReorgRepo) GetByID(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgRepo) Lock(ctx context.Context, id uint) (*models.ReorgBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgRepo) List(ctx context.Context, status string) ([]models.ReorgBatch, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).([]models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgRepo) Due(ctx context.Context, now time.Time) ([]models.ReorgBatch, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]models.ReorgBatch), args.Error(1)
+}
+
+func (m *MockReorgRepo) Update(ctx context.Context, batch *models.ReorgBatch) error {
+	args := m.Called(ctx, batch)
+	return args.Error(0)
+}
+
+func (m *MockReorgRepo) MarkFailed(ctx context.Context, id uint, message string, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, message, at)
+	return args.Bool(0), args.Error(1)
+}
+
+// testClock - управляемые часы для сервиса пакетов изменений
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func setupReorgService(t *testing.T) (*ReorgSvc, *MockReorgRepo, *MockDepartmentRepo, *MockEmployeeRepo, *testClock) {
+	mockReorgRepo := new(MockReorgRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	clock := &testClock{now: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}
+	departments := NewDepartmentService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), noAssignments(), new(MockHistoryRepo), fakeSchema{}, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitSoft, ChangeApprovalOptional)
+	service := NewReorgService(mockReorgRepo, mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), departments, fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, clock.Now, HeadcountLimitHard, ChangeApprovalOptional)
+	return service, mockReorgRepo, mockDeptRepo, mockEmpRepo, clock
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+func TestReorgSubmit_Success(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, mockEmpRepo, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Platform", ParentID: uintPtr(1)}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2, Name: "Engineering"}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(4), uint(2)).Return(false, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Platform", uintPtr(2)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.Anything).Return(nil)
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1}, nil)
+	mockEmpRepo.On("Update", ctx, mock.Anything).Return(nil)
+	mockReorgRepo.On("Create", ctx, mock.MatchedBy(func(b *models.ReorgBatch) bool {
+		return b.Status == models.ReorgStatusPending && len(b.Operations) == 2
+	})).Return(nil)
+
+	executeAt := clock.now.Add(24 * time.Hour)
+	batch, err := service.Submit(ctx, executeAt, []models.ReorgOperation{
+		{Type: models.ReorgOpMove, DepartmentID: 4, ParentID: uintPtr(2)},
+		{Type: models.ReorgOpTransfer, EmployeeID: 7, DepartmentID: 2},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReorgStatusPending, batch.Status)
+	assert.True(t, executeAt.Equal(batch.ExecuteAt))
+	mockReorgRepo.AssertExpectations(t)
+}
+
+func TestReorgSubmit_ConflictWithCurrentTree(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Sales", (*uint)(nil)).Return(&models.Department{ID: 9, Name: "Sales"}, nil)
+
+	batch, err := service.Submit(ctx, clock.now.Add(time.Hour), []models.ReorgOperation{
+		{Type: models.ReorgOpRename, DepartmentID: 4, Name: "Sales"},
+	})
+
+	assert.Nil(t, batch)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidReorgBatch)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	mockReorgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReorgSubmit_HeadcountLimit(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, mockEmpRepo, clock := setupReorgService(t)
+	ctx := context.Background()
+	mockVacancyRepo := service.guard.vacancyRepo.(*MockVacancyRepo)
+
+	full := &models.Department{ID: 2, Name: "Engineering", HeadcountLimit: intPtr(1)}
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(full, nil)
+	mockDeptRepo.On("Lock", ctx, uint(2)).Return(full, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(2)).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, uint(2)).Return(int64(0), nil)
+
+	batch, err := service.Submit(ctx, clock.now.Add(time.Hour), []models.ReorgOperation{
+		{Type: models.ReorgOpTransfer, EmployeeID: 7, DepartmentID: 2},
+	})
+
+	assert.Nil(t, batch)
+	assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockReorgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReorgSubmit_TerminatedEmployee(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, mockEmpRepo, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).
+		Return(&models.Employee{ID: 7, DepartmentID: 1, Status: models.EmployeeTerminated}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(2)).Return(&models.Department{ID: 2, Name: "Engineering"}, nil)
+
+	batch, err := service.Submit(ctx, clock.now.Add(time.Hour), []models.ReorgOperation{
+		{Type: models.ReorgOpTransfer, EmployeeID: 7, DepartmentID: 2},
+	})
+
+	assert.Nil(t, batch)
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeTerminated)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockReorgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReorgSubmit_InvalidOperation(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	_, err := service.Submit(ctx, clock.now.Add(time.Hour), []models.ReorgOperation{
+		{Type: models.ReorgOpDelete, DepartmentID: 4, Mode: "archive"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidReorgBatch)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockReorgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReorgSubmit_PastExecutionTime(t *testing.T) {
+	service, _, _, _, clock := setupReorgService(t)
+
+	_, err := service.Submit(context.Background(), clock.now.Add(-time.Minute), []models.ReorgOperation{
+		{Type: models.ReorgOpRename, DepartmentID: 4, Name: "Core"},
+	})
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidExecutionTime)
+}
+
+func TestReorgExecuteDue_WaitsForExecutionTime(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	start := clock.now
+	executeAt := start.Add(2 * time.Hour)
+	batch := models.ReorgBatch{ID: 1, Status: models.ReorgStatusPending, ExecuteAt: executeAt,
+		Operations: models.ReorgOperations{{Type: models.ReorgOpRename, DepartmentID: 4, Name: "Core"}}}
+
+	mockReorgRepo.On("Due", ctx, start).Return([]models.ReorgBatch{}, nil).Once()
+	applied, err := service.ExecuteDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+
+	clock.Advance(2 * time.Hour)
+	mockReorgRepo.On("Due", ctx, executeAt).Return([]models.ReorgBatch{batch}, nil).Once()
+	locked := batch
+	mockReorgRepo.On("Lock", ctx, uint(1)).Return(&locked, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(&models.Department{ID: 4, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Core", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 4 && d.Name == "Core"
+	})).Return(nil)
+	mockReorgRepo.On("Update", ctx, mock.MatchedBy(func(b *models.ReorgBatch) bool {
+		return b.ID == 1 && b.Status == models.ReorgStatusApplied && b.ExecutedAt.Equal(executeAt)
+	})).Return(nil)
+
+	applied, err = service.ExecuteDue(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, applied)
+	mockDeptRepo.AssertExpectations(t)
+	mockReorgRepo.AssertExpectations(t)
+}
+
+func TestReorgExecuteDue_MarksFailedBatch(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	batch := models.ReorgBatch{ID: 2, Status: models.ReorgStatusPending, ExecuteAt: clock.now,
+		Operations: models.ReorgOperations{{Type: models.ReorgOpMove, DepartmentID: 4}}}
+	locked := batch
+
+	mockReorgRepo.On("Due", ctx, clock.now).Return([]models.ReorgBatch{batch}, nil)
+	mockReorgRepo.On("Lock", ctx, uint(2)).Return(&locked, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(4)).Return(nil, nil)
+	mockReorgRepo.On("MarkFailed", ctx, uint(2), mock.MatchedBy(func(message string) bool {
+		return message != ""
+	}), clock.now).Return(true, nil)
+
+	applied, err := service.ExecuteDue(ctx)
+
+	assert.Equal(t, 0, applied)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+	mockReorgRepo.AssertExpectations(t)
+	mockReorgRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestReorgExecuteDue_KeepsBatchPendingOnTransientError(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	batch := models.ReorgBatch{ID: 2, Status: models.ReorgStatusPending, ExecuteAt: clock.now,
+		Operations: models.ReorgOperations{{Type: models.ReorgOpRename, DepartmentID: 4, Name: "Core"}}}
+	locked := batch
+
+	mockReorgRepo.On("Due", ctx, clock.now).Return([]models.ReorgBatch{batch}, nil)
+	mockReorgRepo.On("Lock", ctx, uint(2)).Return(&locked, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(4)).
+		Return(nil, &apperrors.DBError{Kind: apperrors.ErrSerializationFailure})
+
+	applied, err := service.ExecuteDue(ctx)
+
+	assert.Equal(t, 0, applied)
+	assert.ErrorIs(t, err, apperrors.ErrSerializationFailure)
+	mockReorgRepo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockReorgRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestReorgExecuteDue_SkipsCancelledBatch(t *testing.T) {
+	service, mockReorgRepo, mockDeptRepo, _, clock := setupReorgService(t)
+	ctx := context.Background()
+
+	batch := models.ReorgBatch{ID: 3, Status: models.ReorgStatusPending, ExecuteAt: clock.now,
+		Operations: models.ReorgOperations{{Type: models.ReorgOpRename, DepartmentID: 4, Name: "Core"}}}
+
+	mockReorgRepo.On("Due", ctx, clock.now).Return([]models.ReorgBatch{batch}, nil)
+	mockReorgRepo.On("Lock", ctx, uint(3)).Return(&models.ReorgBatch{ID: 3, Status: models.ReorgStatusCancelled}, nil)
+
+	applied, err := service.ExecuteDue(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockReorgRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestReorgCancel(t *testing.T) {
+	service, mockReorgRepo, _, _, _ := setupReorgService(t)
+	ctx := context.Background()
+
+	mockReorgRepo.On("Lock", ctx, uint(1)).Return(&models.ReorgBatch{ID: 1, Status: models.ReorgStatusPending}, nil)
+	mockReorgRepo.On("Lock", ctx, uint(2)).Return(&models.ReorgBatch{ID: 2, Status: models.ReorgStatusApplied}, nil)
+	mockReorgRepo.On("Update", ctx, mock.MatchedBy(func(b *models.ReorgBatch) bool {
+		return b.ID == 1 && b.Status == models.ReorgStatusCancelled
+	})).Return(nil)
+
+	batch, err := service.Cancel(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReorgStatusCancelled, batch.Status)
+
+	_, err = service.Cancel(ctx, 2)
+	assert.True(t, errors.Is(err, apperrors.ErrReorgBatchNotPending))
+}
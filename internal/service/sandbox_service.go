@@ -0,0 +1,241 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// SandboxRepository определяет интерфейс репозитория песочниц.
+type SandboxRepository interface {
+	Create(ctx context.Context, sandbox *models.Sandbox) error
+	GetByID(ctx context.Context, id uint) (*models.Sandbox, error)
+	Lock(ctx context.Context, id uint) (*models.Sandbox, error)
+	Share(ctx context.Context, id uint) (*models.Sandbox, error)
+	List(ctx context.Context) ([]models.Sandbox, error)
+	Update(ctx context.Context, sandbox *models.Sandbox) error
+	Within(ctx context.Context, schema string, fn func(ctx context.Context) error) error
+	Departments(ctx context.Context, schema string, base bool) ([]models.Department, error)
+	Employees(ctx context.Context, schema string, base bool) ([]models.Employee, error)
+	Conflicts(ctx context.Context, schema string) (departments, employees []int, err error)
+	Promote(ctx context.Context, schema string) error
+	Drop(ctx context.Context, schema string) error
+}
+
+// SandboxService определяет интерфейс песочниц для пробной реорганизации,
+// который будет реализован сервисом и использован обработчиками HTTP и middleware.
+type SandboxService interface {
+	Create(ctx context.Context, name string) (*models.Sandbox, error)
+	Get(ctx context.Context, id uint) (*models.Sandbox, error)
+	List(ctx context.Context) ([]models.Sandbox, error)
+	Changes(ctx context.Context, id uint) (*SnapshotDiff, error)
+	Promote(ctx context.Context, id uint) (*SnapshotDiff, error)
+	Discard(ctx context.Context, id uint) (*models.Sandbox, error)
+	Within(ctx context.Context, id uint, fn func(ctx context.Context) error) error
+}
+
+// SandboxSvc реализует песочницы: запросы к подразделениям и сотрудникам с заголовком
+// X-Sandbox-ID выполняются обычными сервисами над копией структуры, а накопленные
+// изменения переносятся в рабочую структуру одной транзакцией или отбрасываются.
+type SandboxSvc struct {
+	sandboxRepo  SandboxRepository
+	tx           Transactor
+	now          func() time.Time
+	approvalMode string
+}
+
+// NewSandboxService создаёт новый экземпляр сервиса песочниц; approvalMode - режим согласования
+// переносов и удалений подразделений.
+func NewSandboxService(sandboxRepo SandboxRepository, tx Transactor, approvalMode string) *SandboxSvc {
+	return &SandboxSvc{sandboxRepo: sandboxRepo, tx: tx, now: time.Now, approvalMode: approvalMode}
+}
+
+// Create создаёт песочницу с копией текущей структуры.
+func (s *SandboxSvc) Create(ctx context.Context, name string) (*models.Sandbox, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 200 {
+		return nil, apperrors.ErrInvalidSandboxName
+	}
+
+	sandbox := &models.Sandbox{Name: name, Status: models.SandboxActive}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.sandboxRepo.Create(ctx, sandbox)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sandbox, nil
+}
+
+// Get возвращает песочницу по идентификатору.
+func (s *SandboxSvc) Get(ctx context.Context, id uint) (*models.Sandbox, error) {
+	sandbox, err := s.sandboxRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sandbox == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrSandboxNotFound, id)
+	}
+	return sandbox, nil
+}
+
+// List возвращает все песочницы.
+func (s *SandboxSvc) List(ctx context.Context) ([]models.Sandbox, error) {
+	return s.sandboxRepo.List(ctx)
+}
+
+// Changes возвращает изменения песочницы относительно структуры на момент её создания.
+func (s *SandboxSvc) Changes(ctx context.Context, id uint) (*SnapshotDiff, error) {
+	var diff *SnapshotDiff
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sandbox, err := s.active(s.sandboxRepo.Share(ctx, id))
+		if err != nil {
+			return err
+		}
+		diff, err = s.changes(ctx, sandbox.SchemaName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// Promote переносит изменения песочницы в рабочую структуру одной транзакцией и закрывает
+// песочницу. Если подразделения или сотрудники, изменённые в песочнице, после её создания
+// изменились и в рабочей структуре, перенос отклоняется целиком. В режиме обязательного
+// согласования песочница с переносами или удалениями подразделений не переносится.
+func (s *SandboxSvc) Promote(ctx context.Context, id uint) (*SnapshotDiff, error) {
+	var diff *SnapshotDiff
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sandbox, err := s.active(s.sandboxRepo.Lock(ctx, id))
+		if err != nil {
+			return err
+		}
+		departments, employees, err := s.sandboxRepo.Conflicts(ctx, sandbox.SchemaName)
+		if err != nil {
+			return err
+		}
+		if len(departments) > 0 || len(employees) > 0 {
+			return fmt.Errorf("%w: departments %v, employees %v", apperrors.ErrSandboxConflict, departments, employees)
+		}
+		if diff, err = s.changes(ctx, sandbox.SchemaName); err != nil {
+			return err
+		}
+		if err := s.checkApproval(ctx, diff); err != nil {
+			return err
+		}
+		if err := s.sandboxRepo.Promote(ctx, sandbox.SchemaName); err != nil {
+			return err
+		}
+		return s.close(ctx, sandbox, models.SandboxPromoted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// Discard закрывает песочницу без переноса изменений.
+func (s *SandboxSvc) Discard(ctx context.Context, id uint) (*models.Sandbox, error) {
+	var result *models.Sandbox
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sandbox, err := s.active(s.sandboxRepo.Lock(ctx, id))
+		if err != nil {
+			return err
+		}
+		result = sandbox
+		return s.close(ctx, sandbox, models.SandboxDiscarded)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Within выполняет fn в транзакции так, что репозитории, вызванные с переданным контекстом,
+// работают с данными открытой песочницы id. Строка песочницы заблокирована от изменения
+// до конца транзакции, поэтому перенос или закрытие не удалят схему во время запроса.
+// Ошибка fn откатывает все её изменения в песочнице.
+func (s *SandboxSvc) Within(ctx context.Context, id uint, fn func(ctx context.Context) error) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sandbox, err := s.active(s.sandboxRepo.Share(ctx, id))
+		if err != nil {
+			return err
+		}
+		return s.sandboxRepo.Within(ctx, sandbox.SchemaName, func(ctx context.Context) error {
+			return fn(withinSandbox(ctx))
+		})
+	})
+}
+
+// checkApproval отклоняет перенос песочницы, в которой подразделения перенесены или удалены,
+// если такие изменения требуют согласованной заявки.
+func (s *SandboxSvc) checkApproval(ctx context.Context, diff *SnapshotDiff) error {
+	for _, moved := range diff.MovedDepartments {
+		if err := requireApproval(ctx, s.approvalMode, "move", uint(moved.ID)); err != nil {
+			return err
+		}
+	}
+	for _, removed := range diff.RemovedDepartments {
+		if err := requireApproval(ctx, s.approvalMode, "delete", uint(removed.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// active проверяет, что песочница найдена и ещё открыта.
+func (s *SandboxSvc) active(sandbox *models.Sandbox, err error) (*models.Sandbox, error) {
+	if err != nil {
+		return nil, err
+	}
+	if sandbox == nil {
+		return nil, apperrors.ErrSandboxNotFound
+	}
+	if sandbox.Status != models.SandboxActive {
+		return nil, fmt.Errorf("%w: status is %s", apperrors.ErrSandboxClosed, sandbox.Status)
+	}
+	return sandbox, nil
+}
+
+// close удаляет схему песочницы и сохраняет её итоговый статус.
+func (s *SandboxSvc) close(ctx context.Context, sandbox *models.Sandbox, status string) error {
+	if err := s.sandboxRepo.Drop(ctx, sandbox.SchemaName); err != nil {
+		return err
+	}
+	now := s.now()
+	sandbox.Status = status
+	sandbox.ClosedAt = &now
+	return s.sandboxRepo.Update(ctx, sandbox)
+}
+
+// changes сравнивает текущие данные песочницы с их состоянием на момент её создания.
+func (s *SandboxSvc) changes(ctx context.Context, schema string) (*SnapshotDiff, error) {
+	var depts [2]map[int]models.Department
+	var emps [2]map[int]models.Employee
+	for i, base := range []bool{true, false} {
+		departments, err := s.sandboxRepo.Departments(ctx, schema, base)
+		if err != nil {
+			return nil, err
+		}
+		employees, err := s.sandboxRepo.Employees(ctx, schema, base)
+		if err != nil {
+			return nil, err
+		}
+		depts[i] = make(map[int]models.Department, len(departments))
+		for _, dept := range departments {
+			depts[i][dept.ID] = dept
+		}
+		emps[i] = make(map[int]models.Employee, len(employees))
+		for _, emp := range employees {
+			emps[i][emp.ID] = emp
+		}
+	}
+	return diffStructures(depts[0], depts[1], emps[0], emps[1]), nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSandboxRepo - мок для репозитория песочниц
+type MockSandboxRepo struct {
+	mock.Mock
+}
+
+func (m *MockSandboxRepo) Create(ctx context.Context, sandbox *models.Sandbox) error {
+	args := m.Called(ctx, sandbox)
+	if args.Error(0) == nil {
+		sandbox.ID = 1
+		sandbox.SchemaName = "sandbox_1"
+	}
+	return args.Error(0)
+}
+
+func (m *MockSandboxRepo) GetByID(ctx context.Context, id uint) (*models.Sandbox, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxRepo) Lock(ctx context.Context, id uint) (*models.Sandbox, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxRepo) Share(ctx context.Context, id uint) (*models.Sandbox, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxRepo) List(ctx context.Context) ([]models.Sandbox, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Sandbox), args.Error(1)
+}
+
+func (m *MockSandboxRepo) Update(ctx context.Context, sandbox *models.Sandbox) error {
+	args := m.Called(ctx, sandbox)
+	return args.Error(0)
+}
+
+func (m *MockSandboxRepo) Within(ctx context.Context, schema string, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx, schema)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+func (m *MockSandboxRepo) Departments(ctx context.Context, schema string, base bool) ([]models.Department, error) {
+	args := m.Called(ctx, schema, base)
+	return args.Get(0).([]models.Department), args.Error(1)
+}
+
+func (m *MockSandboxRepo) Employees(ctx context.Context, schema string, base bool) ([]models.Employee, error) {
+	args := m.Called(ctx, schema, base)
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *MockSandboxRepo) Conflicts(ctx context.Context, schema string) ([]int, []int, error) {
+	args := m.Called(ctx, schema)
+	return args.Get(0).([]int), args.Get(1).([]int), args.Error(2)
+}
+
+func (m *MockSandboxRepo) Promote(ctx context.Context, schema string) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func (m *MockSandboxRepo) Drop(ctx context.Context, schema string) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func setupSandboxService(t *testing.T) (*SandboxSvc, *MockSandboxRepo) {
+	mockRepo := new(MockSandboxRepo)
+	return NewSandboxService(mockRepo, fakeTx{}, ChangeApprovalOptional), mockRepo
+}
+
+// mockSandboxState задаёт исходное и текущее состояние песочницы sandbox_1:
+// подразделение 2 перенесено под 3, сотрудник 10 переведён в подразделение 3.
+func mockSandboxState(ctx context.Context, mockRepo *MockSandboxRepo) {
+	mockRepo.On("Departments", ctx, "sandbox_1", true).Return([]models.Department{
+		{ID: 1, Name: "HQ"}, {ID: 2, Name: "Sales", ParentID: uintPtr(1)}, {ID: 3, Name: "Ops", ParentID: uintPtr(1)},
+	}, nil)
+	mockRepo.On("Departments", ctx, "sandbox_1", false).Return([]models.Department{
+		{ID: 1, Name: "HQ"}, {ID: 2, Name: "Sales", ParentID: uintPtr(3)}, {ID: 3, Name: "Ops", ParentID: uintPtr(1)},
+	}, nil)
+	mockRepo.On("Employees", ctx, "sandbox_1", true).Return([]models.Employee{{ID: 10, DepartmentID: 2}}, nil)
+	mockRepo.On("Employees", ctx, "sandbox_1", false).Return([]models.Employee{{ID: 10, DepartmentID: 3}}, nil)
+}
+
+func TestSandboxCreate(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(s *models.Sandbox) bool {
+		return s.Name == "Q3 reorg" && s.Status == models.SandboxActive
+	})).Return(nil)
+
+	sandbox, err := service.Create(ctx, "  Q3 reorg ")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sandbox.ID)
+
+	_, err = service.Create(ctx, "   ")
+	assert.ErrorIs(t, err, apperrors.ErrInvalidSandboxName)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestSandboxWithin_ClosedSandbox(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Share", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxPromoted}, nil)
+	mockRepo.On("Share", ctx, uint(2)).Return(nil, nil)
+
+	called := false
+	err := service.Within(ctx, 1, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, apperrors.ErrSandboxClosed)
+
+	err = service.Within(ctx, 2, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, apperrors.ErrSandboxNotFound)
+	assert.False(t, called)
+	mockRepo.AssertNotCalled(t, "Within", mock.Anything, mock.Anything)
+}
+
+func TestSandboxWithin(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Share", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockRepo.On("Within", ctx, "sandbox_1").Return(nil)
+
+	err := service.Within(ctx, 1, func(ctx context.Context) error {
+		// Изменения в песочнице не требуют согласования.
+		return requireApproval(ctx, ChangeApprovalRequired, "move", 2)
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSandboxChanges(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Share", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockSandboxState(ctx, mockRepo)
+
+	diff, err := service.Changes(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []MovedDepartment{{ID: 2, Name: "Sales", OldParentID: uintPtr(1), NewParentID: uintPtr(3)}}, diff.MovedDepartments)
+	assert.Equal(t, []TransferredEmployee{{ID: 10, FromDepartmentID: 2, ToDepartmentID: 3}}, diff.TransferredEmployees)
+	assert.Empty(t, diff.AddedDepartments)
+	assert.Empty(t, diff.RemovedDepartments)
+}
+
+func TestSandboxPromote_Success(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Lock", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockRepo.On("Conflicts", ctx, "sandbox_1").Return([]int{}, []int{}, nil)
+	mockSandboxState(ctx, mockRepo)
+	mockRepo.On("Promote", ctx, "sandbox_1").Return(nil)
+	mockRepo.On("Drop", ctx, "sandbox_1").Return(nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(s *models.Sandbox) bool {
+		return s.Status == models.SandboxPromoted && s.ClosedAt != nil
+	})).Return(nil)
+
+	diff, err := service.Promote(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, diff.MovedDepartments, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSandboxPromote_Conflict(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Lock", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockRepo.On("Conflicts", ctx, "sandbox_1").Return([]int{2}, []int{}, nil)
+
+	diff, err := service.Promote(ctx, 1)
+
+	assert.Nil(t, diff)
+	assert.ErrorIs(t, err, apperrors.ErrSandboxConflict)
+	mockRepo.AssertNotCalled(t, "Promote", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Drop", mock.Anything, mock.Anything)
+}
+
+func TestSandboxPromote_MoveRequiresApproval(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	service.approvalMode = ChangeApprovalRequired
+	ctx := context.Background()
+
+	mockRepo.On("Lock", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockRepo.On("Conflicts", ctx, "sandbox_1").Return([]int{}, []int{}, nil)
+	mockSandboxState(ctx, mockRepo)
+
+	diff, err := service.Promote(ctx, 1)
+
+	assert.Nil(t, diff)
+	assert.ErrorIs(t, err, apperrors.ErrApprovalRequired)
+	mockRepo.AssertNotCalled(t, "Promote", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Drop", mock.Anything, mock.Anything)
+}
+
+func TestSandboxDiscard(t *testing.T) {
+	service, mockRepo := setupSandboxService(t)
+	ctx := context.Background()
+
+	mockRepo.On("Lock", ctx, uint(1)).Return(&models.Sandbox{ID: 1, SchemaName: "sandbox_1", Status: models.SandboxActive}, nil)
+	mockRepo.On("Drop", ctx, "sandbox_1").Return(nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(s *models.Sandbox) bool {
+		return s.Status == models.SandboxDiscarded
+	})).Return(nil)
+
+	sandbox, err := service.Discard(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SandboxDiscarded, sandbox.Status)
+	mockRepo.AssertNotCalled(t, "Promote", mock.Anything, mock.Anything)
+}
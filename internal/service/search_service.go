@@ -0,0 +1,90 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// Режимы поиска.
+const (
+	SearchModeFull         = "full"
+	SearchModeAutocomplete = "autocomplete"
+)
+
+// Ограничения запроса поиска.
+const (
+	maxSearchQueryLength     = 200
+	maxSearchLimit           = 50
+	defaultSearchLimit       = 20
+	defaultAutocompleteLimit = 10
+)
+
+// SearchRepository определяет интерфейс репозитория поиска.
+type SearchRepository interface {
+	Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error)
+	Autocomplete(ctx context.Context, words []string, limit int) ([]models.SearchHit, error)
+}
+
+// SearchService определяет интерфейс поиска сотрудников и подразделений,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type SearchService interface {
+	Search(ctx context.Context, query, mode string, limit int) ([]models.SearchHit, error)
+}
+
+// SearchSvc реализует поиск по именам и должностям сотрудников и названиям подразделений.
+type SearchSvc struct {
+	searchRepo SearchRepository
+}
+
+// NewSearchService создаёт новый экземпляр сервиса поиска.
+func NewSearchService(searchRepo SearchRepository) *SearchSvc {
+	return &SearchSvc{searchRepo: searchRepo}
+}
+
+// Search ищет по запросу query в режиме mode: full - полнотекстовый поиск с учётом опечаток
+// и путями подразделений, autocomplete - поиск по префиксам слов для подсказок при вводе.
+// limit = 0 означает значение по умолчанию для режима.
+func (s *SearchSvc) Search(ctx context.Context, query, mode string, limit int) ([]models.SearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: q must be non-empty and max %d characters", apperrors.ErrInvalidSearchQuery, maxSearchQueryLength)
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", apperrors.ErrInvalidSearchQuery, maxSearchLimit)
+	}
+
+	switch mode {
+	case "", SearchModeFull:
+		if limit == 0 {
+			limit = defaultSearchLimit
+		}
+		return s.searchRepo.Search(ctx, query, limit)
+	case SearchModeAutocomplete:
+		if limit == 0 {
+			limit = defaultAutocompleteLimit
+		}
+		words := searchWords(query)
+		if len(words) == 0 {
+			return []models.SearchHit{}, nil
+		}
+		return s.searchRepo.Autocomplete(ctx, words, limit)
+	default:
+		return nil, fmt.Errorf("%w: mode must be %s or %s", apperrors.ErrInvalidSearchQuery, SearchModeFull, SearchModeAutocomplete)
+	}
+}
+
+// searchWords разбивает запрос на слова для поиска по префиксам: "иван пет'" -> ["иван", "пет"].
+// В слова попадают только буквы и цифры, поэтому синтаксис tsquery, который репозиторий
+// строит из них, не может быть нарушен вводом пользователя.
+func searchWords(query string) []string {
+	return strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchRepo - мок для репозитория поиска
+type MockSearchRepo struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepo) Search(ctx context.Context, query string, limit int) ([]models.SearchHit, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).([]models.SearchHit), args.Error(1)
+}
+
+func (m *MockSearchRepo) Autocomplete(ctx context.Context, words []string, limit int) ([]models.SearchHit, error) {
+	args := m.Called(ctx, words, limit)
+	return args.Get(0).([]models.SearchHit), args.Error(1)
+}
+
+func TestSearch_FullDefaults(t *testing.T) {
+	mockRepo := new(MockSearchRepo)
+	service := NewSearchService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("Search", ctx, "Иван Петров", defaultSearchLimit).Return([]models.SearchHit{
+		{Type: models.SearchEmployee, ID: 7, Title: "Иван Петров", Path: models.DepartmentPath{"HQ", "Platform"}},
+	}, nil)
+
+	hits, err := service.Search(ctx, "  Иван Петров ", "", 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearch_Autocomplete(t *testing.T) {
+	mockRepo := new(MockSearchRepo)
+	service := NewSearchService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("Autocomplete", ctx, []string{"ив", "пет"}, defaultAutocompleteLimit).Return([]models.SearchHit{}, nil)
+
+	_, err := service.Search(ctx, "ив пет':*|", SearchModeAutocomplete, 0)
+	assert.NoError(t, err)
+
+	hits, err := service.Search(ctx, "!!!", SearchModeAutocomplete, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	mockRepo.AssertNumberOfCalls(t, "Autocomplete", 1)
+}
+
+func TestSearch_InvalidQuery(t *testing.T) {
+	service := NewSearchService(new(MockSearchRepo))
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		query, mode string
+		limit       int
+	}{
+		{query: "   "},
+		{query: strings.Repeat("я", maxSearchQueryLength+1)},
+		{query: "sales", mode: "regex"},
+		{query: "sales", limit: maxSearchLimit + 1},
+	} {
+		_, err := service.Search(ctx, tc.query, tc.mode, tc.limit)
+		assert.ErrorIs(t, err, apperrors.ErrInvalidSearchQuery)
+	}
+}
@@ -0,0 +1,263 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// SnapshotRepository определяет интерфейс репозитория снимков структуры.
+type SnapshotRepository interface {
+	Create(ctx context.Context, snapshot *models.Snapshot) error
+	GetByID(ctx context.Context, id uint) (*models.Snapshot, error)
+	List(ctx context.Context) ([]models.Snapshot, error)
+}
+
+// SnapshotService определяет интерфейс снимков структуры и их сравнения,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type SnapshotService interface {
+	Create(ctx context.Context, label string) (*models.Snapshot, error)
+	Get(ctx context.Context, id uint) (*models.Snapshot, error)
+	List(ctx context.Context) ([]models.Snapshot, error)
+	Diff(ctx context.Context, fromID, toID uint) (*SnapshotDiff, error)
+}
+
+// SnapshotDiff описывает структурные различия между двумя снимками
+// или между песочницей и её исходным состоянием.
+type SnapshotDiff struct {
+	From                 int                   `json:"from,omitempty"`
+	To                   int                   `json:"to,omitempty"`
+	AddedDepartments     []DepartmentRef       `json:"added_departments"`
+	RemovedDepartments   []DepartmentRef       `json:"removed_departments"`
+	RenamedDepartments   []RenamedDepartment   `json:"renamed_departments"`
+	MovedDepartments     []MovedDepartment     `json:"moved_departments"`
+	TransferredEmployees []TransferredEmployee `json:"transferred_employees"`
+}
+
+// DepartmentRef описывает подразделение, появившееся или исчезнувшее между снимками.
+type DepartmentRef struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// RenamedDepartment описывает переименование подразделения.
+type RenamedDepartment struct {
+	ID      int    `json:"id"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
+// MovedDepartment описывает перенос подразделения под другого родителя.
+type MovedDepartment struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	OldParentID *uint  `json:"old_parent_id"`
+	NewParentID *uint  `json:"new_parent_id"`
+}
+
+// TransferredEmployee описывает перевод сотрудника в другое подразделение.
+type TransferredEmployee struct {
+	ID               int    `json:"id"`
+	FullName         string `json:"full_name"`
+	FromDepartmentID int    `json:"from_department_id"`
+	ToDepartmentID   int    `json:"to_department_id"`
+}
+
+// SnapService реализует снимки структуры: сохранение дерева подразделений
+// с сотрудниками и сравнение двух снимков.
+type SnapService struct {
+	snapshotRepo SnapshotRepository
+	deptRepo     DepartmentRepository
+	empRepo      EmployeeRepository
+	tx           Transactor
+}
+
+// NewSnapshotService создаёт новый экземпляр сервиса снимков.
+func NewSnapshotService(snapshotRepo SnapshotRepository, deptRepo DepartmentRepository, empRepo EmployeeRepository,
+	tx Transactor) *SnapService {
+	return &SnapService{snapshotRepo: snapshotRepo, deptRepo: deptRepo, empRepo: empRepo, tx: tx}
+}
+
+// Create сохраняет снимок текущего дерева подразделений с сотрудниками.
+func (s *SnapService) Create(ctx context.Context, label string) (*models.Snapshot, error) {
+	label = strings.TrimSpace(label)
+	if len(label) > 200 {
+		return nil, apperrors.ErrInvalidSnapshotLabel
+	}
+
+	var snapshot *models.Snapshot
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		flat, err := s.deptRepo.List(ctx, nil, nil)
+		if err != nil {
+			return err
+		}
+		employees, err := s.empRepo.List(ctx, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		// Снимок фиксирует штат: уволенные сотрудники в него не попадают.
+		employees = employedOnly(employees, nil)
+
+		index := make(map[int]int, len(flat))
+		for i := range flat {
+			index[flat[i].ID] = i
+		}
+		for _, emp := range employees {
+			if i, ok := index[emp.DepartmentID]; ok {
+				flat[i].Employees = append(flat[i].Employees, emp)
+			}
+		}
+
+		roots := []models.Department{}
+		for _, dept := range flat {
+			if dept.ParentID == nil {
+				dept.Children = buildChildrenTree(flat, uint(dept.ID))
+				roots = append(roots, dept)
+			}
+		}
+
+		snapshot = &models.Snapshot{
+			Label: label,
+			Document: &models.SnapshotDocument{
+				FormatVersion: models.SnapshotFormatVersion,
+				Departments:   roots,
+			},
+			DepartmentCount: len(flat),
+			EmployeeCount:   len(employees),
+		}
+		return s.snapshotRepo.Create(ctx, snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Get возвращает снимок вместе с документом.
+func (s *SnapService) Get(ctx context.Context, id uint) (*models.Snapshot, error) {
+	snapshot, err := s.snapshotRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrSnapshotNotFound, id)
+	}
+	return snapshot, nil
+}
+
+// List возвращает снимки без документов.
+func (s *SnapService) List(ctx context.Context) ([]models.Snapshot, error) {
+	return s.snapshotRepo.List(ctx)
+}
+
+// Diff сравнивает снимок fromID со снимком toID.
+func (s *SnapService) Diff(ctx context.Context, fromID, toID uint) (*SnapshotDiff, error) {
+	from, err := s.Get(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.Get(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffDocuments(from.Document, to.Document)
+	diff.From = from.ID
+	diff.To = to.ID
+	return diff, nil
+}
+
+// diffDocuments сравнивает подразделения и назначения сотрудников двух документов.
+// Подразделения и сотрудники сопоставляются по идентификаторам.
+func diffDocuments(from, to *models.SnapshotDocument) *SnapshotDiff {
+	oldDepts, oldEmps := flattenSnapshot(from)
+	newDepts, newEmps := flattenSnapshot(to)
+	return diffStructures(oldDepts, newDepts, oldEmps, newEmps)
+}
+
+// diffStructures сравнивает два состояния структуры, заданные словарями
+// подразделений и сотрудников по идентификаторам.
+func diffStructures(oldDepts, newDepts map[int]models.Department, oldEmps, newEmps map[int]models.Employee) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		AddedDepartments:     []DepartmentRef{},
+		RemovedDepartments:   []DepartmentRef{},
+		RenamedDepartments:   []RenamedDepartment{},
+		MovedDepartments:     []MovedDepartment{},
+		TransferredEmployees: []TransferredEmployee{},
+	}
+	for _, id := range sortedKeys(newDepts) {
+		dept := newDepts[id]
+		old, ok := oldDepts[id]
+		if !ok {
+			diff.AddedDepartments = append(diff.AddedDepartments, DepartmentRef{ID: id, Name: dept.Name, ParentID: dept.ParentID})
+			continue
+		}
+		if old.Name != dept.Name {
+			diff.RenamedDepartments = append(diff.RenamedDepartments, RenamedDepartment{ID: id, OldName: old.Name, NewName: dept.Name})
+		}
+		if !sameParent(old.ParentID, dept.ParentID) {
+			diff.MovedDepartments = append(diff.MovedDepartments, MovedDepartment{ID: id, Name: dept.Name,
+				OldParentID: old.ParentID, NewParentID: dept.ParentID})
+		}
+	}
+	for _, id := range sortedKeys(oldDepts) {
+		if _, ok := newDepts[id]; !ok {
+			dept := oldDepts[id]
+			diff.RemovedDepartments = append(diff.RemovedDepartments, DepartmentRef{ID: id, Name: dept.Name, ParentID: dept.ParentID})
+		}
+	}
+	for _, id := range sortedKeys(newEmps) {
+		emp := newEmps[id]
+		if old, ok := oldEmps[id]; ok && old.DepartmentID != emp.DepartmentID {
+			diff.TransferredEmployees = append(diff.TransferredEmployees, TransferredEmployee{ID: id, FullName: emp.FullName,
+				FromDepartmentID: old.DepartmentID, ToDepartmentID: emp.DepartmentID})
+		}
+	}
+	return diff
+}
+
+// flattenSnapshot раскладывает дерево документа в словари подразделений и сотрудников по идентификаторам.
+func flattenSnapshot(doc *models.SnapshotDocument) (map[int]models.Department, map[int]models.Employee) {
+	depts := make(map[int]models.Department)
+	emps := make(map[int]models.Employee)
+	if doc == nil {
+		return depts, emps
+	}
+	var walk func(list []models.Department)
+	walk = func(list []models.Department) {
+		for _, dept := range list {
+			for _, emp := range dept.Employees {
+				emps[emp.ID] = emp
+			}
+			walk(dept.Children)
+			dept.Children = nil
+			dept.Employees = nil
+			depts[dept.ID] = dept
+		}
+	}
+	walk(doc.Departments)
+	return depts, emps
+}
+
+// sameParent сравнивает родителей подразделения; nil - корень.
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sortedKeys возвращает идентификаторы словаря по возрастанию.
+func sortedKeys[T any](m map[int]T) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
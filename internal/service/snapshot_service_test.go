@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSnapshotRepo - мок для репозитория снимков
+type MockSnapshotRepo struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotRepo) Create(ctx context.Context, snapshot *models.Snapshot) error {
+	args := m.Called(ctx, snapshot)
+	return args.Error(0)
+}
+
+func (m *MockSnapshotRepo) GetByID(ctx context.Context, id uint) (*models.Snapshot, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepo) List(ctx context.Context) ([]models.Snapshot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Snapshot), args.Error(1)
+}
+
+func setupSnapshotService(t *testing.T) (*SnapService, *MockSnapshotRepo, *MockDepartmentRepo, *MockEmployeeRepo) {
+	mockSnapshotRepo := new(MockSnapshotRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	service := NewSnapshotService(mockSnapshotRepo, mockDeptRepo, mockEmpRepo, fakeTx{})
+	return service, mockSnapshotRepo, mockDeptRepo, mockEmpRepo
+}
+
+func TestSnapshotCreate_BuildsTree(t *testing.T) {
+	service, mockSnapshotRepo, mockDeptRepo, mockEmpRepo := setupSnapshotService(t)
+	ctx := context.Background()
+
+	mockDeptRepo.On("List", ctx, models.Attributes(nil), (*time.Time)(nil)).Return([]models.Department{
+		{ID: 1, Name: "Company"},
+		{ID: 2, Name: "Engineering", ParentID: uintPtr(1)},
+		{ID: 4, Name: "Platform", ParentID: uintPtr(2)},
+		{ID: 5, Name: "Lab"},
+	}, nil)
+	mockEmpRepo.On("List", ctx, (*uint)(nil), models.Attributes(nil), (*time.Time)(nil)).Return([]models.Employee{
+		{ID: 10, DepartmentID: 4, FullName: "Ivan Petrov"},
+		{ID: 11, DepartmentID: 1, FullName: "Anna Smirnova"},
+	}, nil)
+	mockSnapshotRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	snapshot, err := service.Create(ctx, "  before reorg ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "before reorg", snapshot.Label)
+	assert.Equal(t, 4, snapshot.DepartmentCount)
+	assert.Equal(t, 2, snapshot.EmployeeCount)
+
+	doc := snapshot.Document
+	assert.Equal(t, models.SnapshotFormatVersion, doc.FormatVersion)
+	assert.Len(t, doc.Departments, 2)
+	company := doc.Departments[0]
+	assert.Equal(t, "Company", company.Name)
+	assert.Len(t, company.Employees, 1)
+	assert.Equal(t, "Platform", company.Children[0].Children[0].Name)
+	assert.Equal(t, 10, company.Children[0].Children[0].Employees[0].ID)
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	service, mockSnapshotRepo, _, _ := setupSnapshotService(t)
+	ctx := context.Background()
+
+	before := &models.Snapshot{ID: 1, Document: &models.SnapshotDocument{Departments: []models.Department{
+		{ID: 1, Name: "Company", Employees: []models.Employee{{ID: 11, DepartmentID: 1}}, Children: []models.Department{
+			{ID: 2, Name: "Engineering", ParentID: uintPtr(1), Children: []models.Department{
+				{ID: 4, Name: "Platform", ParentID: uintPtr(2), Employees: []models.Employee{{ID: 10, DepartmentID: 4, FullName: "Ivan Petrov"}}},
+			}},
+			{ID: 3, Name: "Sales", ParentID: uintPtr(1)},
+		}},
+	}}}
+	after := &models.Snapshot{ID: 2, Document: &models.SnapshotDocument{Departments: []models.Department{
+		{ID: 1, Name: "Company", Employees: []models.Employee{{ID: 11, DepartmentID: 1}}, Children: []models.Department{
+			{ID: 2, Name: "R&D", ParentID: uintPtr(1), Employees: []models.Employee{{ID: 10, DepartmentID: 2, FullName: "Ivan Petrov"}}},
+			{ID: 4, Name: "Platform", ParentID: uintPtr(1)},
+			{ID: 6, Name: "Support", ParentID: uintPtr(1)},
+		}},
+	}}}
+	mockSnapshotRepo.On("GetByID", ctx, uint(1)).Return(before, nil)
+	mockSnapshotRepo.On("GetByID", ctx, uint(2)).Return(after, nil)
+
+	diff, err := service.Diff(ctx, 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 2, diff.To)
+	assert.Equal(t, []DepartmentRef{{ID: 6, Name: "Support", ParentID: uintPtr(1)}}, diff.AddedDepartments)
+	assert.Equal(t, []DepartmentRef{{ID: 3, Name: "Sales", ParentID: uintPtr(1)}}, diff.RemovedDepartments)
+	assert.Equal(t, []RenamedDepartment{{ID: 2, OldName: "Engineering", NewName: "R&D"}}, diff.RenamedDepartments)
+	assert.Equal(t, []MovedDepartment{{ID: 4, Name: "Platform", OldParentID: uintPtr(2), NewParentID: uintPtr(1)}}, diff.MovedDepartments)
+	assert.Equal(t, []TransferredEmployee{{ID: 10, FullName: "Ivan Petrov", FromDepartmentID: 4, ToDepartmentID: 2}}, diff.TransferredEmployees)
+}
+
+func TestSnapshotDiff_NotFound(t *testing.T) {
+	service, mockSnapshotRepo, _, _ := setupSnapshotService(t)
+	ctx := context.Background()
+
+	mockSnapshotRepo.On("GetByID", ctx, uint(1)).Return(&models.Snapshot{ID: 1, Document: &models.SnapshotDocument{}}, nil)
+	mockSnapshotRepo.On("GetByID", ctx, uint(9)).Return(nil, nil)
+
+	_, err := service.Diff(ctx, 1, 9)
+
+	assert.ErrorIs(t, err, apperrors.ErrSnapshotNotFound)
+}
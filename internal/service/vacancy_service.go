@@ -0,0 +1,133 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// VacancyService определяет интерфейс вакансий,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type VacancyService interface {
+	Create(ctx context.Context, departmentID uint, position string, positionID *uint, targetStartDate *time.Time) (*models.Vacancy, error)
+	Get(ctx context.Context, id uint) (*models.Vacancy, error)
+	List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error)
+	Cancel(ctx context.Context, id uint) (*models.Vacancy, error)
+}
+
+// VacancySvc реализует открытие и закрытие вакансий с учётом утверждённой численности
+// подразделения. Вакансия заполняется при создании сотрудника (EmpService.Create).
+type VacancySvc struct {
+	vacancyRepo VacancyRepository
+	deptRepo    DepartmentRepository
+	posRepo     PositionRepository
+	guard       headcountGuard
+	tx          Transactor
+	now         func() time.Time
+}
+
+// NewVacancyService создаёт новый экземпляр сервиса вакансий; limitMode - режим проверки
+// утверждённой численности (HeadcountLimitSoft или HeadcountLimitHard).
+func NewVacancyService(vacancyRepo VacancyRepository, deptRepo DepartmentRepository, empRepo EmployeeRepository,
+	posRepo PositionRepository, tx Transactor, limitMode string) *VacancySvc {
+	return &VacancySvc{
+		vacancyRepo: vacancyRepo,
+		deptRepo:    deptRepo,
+		posRepo:     posRepo,
+		guard:       headcountGuard{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo, mode: limitMode},
+		tx:          tx,
+		now:         time.Now,
+	}
+}
+
+// Create открывает вакансию в подразделении. Должность задаётся идентификатором справочника
+// positionID либо названием position. Открытая вакансия занимает место в утверждённой
+// численности подразделения, поэтому проверяется так же, как приём сотрудника.
+func (s *VacancySvc) Create(ctx context.Context, departmentID uint, position string, positionID *uint,
+	targetStartDate *time.Time) (*models.Vacancy, error) {
+	vacancy := &models.Vacancy{
+		DepartmentID:    int(departmentID),
+		Position:        strings.TrimSpace(position),
+		TargetStartDate: targetStartDate,
+		Status:          models.VacancyOpen,
+	}
+	if positionID == nil && (vacancy.Position == "" || len(vacancy.Position) > 200) {
+		return nil, apperrors.ErrInvalidPosition
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		dept, err := s.deptRepo.GetByID(ctx, departmentID)
+		if err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		pos, err := resolvePosition(ctx, s.posRepo, vacancy.Position, positionID)
+		if err != nil {
+			return err
+		}
+		if pos != nil {
+			vacancy.Position = pos.Title
+			vacancy.PositionID = &pos.ID
+		}
+		if vacancy.Warning, err = s.guard.check(ctx, dept, false); err != nil {
+			return err
+		}
+		return s.vacancyRepo.Create(ctx, vacancy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vacancy, nil
+}
+
+// Get возвращает вакансию по идентификатору.
+func (s *VacancySvc) Get(ctx context.Context, id uint) (*models.Vacancy, error) {
+	vacancy, err := s.vacancyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if vacancy == nil {
+		return nil, fmt.Errorf("%w: %d", apperrors.ErrVacancyNotFound, id)
+	}
+	return vacancy, nil
+}
+
+// List возвращает вакансии, при заданном departmentID - только этого отдела,
+// при непустом status - только с этим статусом.
+func (s *VacancySvc) List(ctx context.Context, departmentID *uint, status string) ([]models.Vacancy, error) {
+	switch status {
+	case "", models.VacancyOpen, models.VacancyFilled, models.VacancyCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", apperrors.ErrInvalidVacancy, status)
+	}
+	return s.vacancyRepo.List(ctx, departmentID, status)
+}
+
+// Cancel закрывает открытую вакансию без приёма сотрудника и освобождает её место.
+func (s *VacancySvc) Cancel(ctx context.Context, id uint) (*models.Vacancy, error) {
+	var vacancy *models.Vacancy
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if vacancy, err = s.Get(ctx, id); err != nil {
+			return err
+		}
+		if vacancy.Status != models.VacancyOpen {
+			return fmt.Errorf("%w: status is %s", apperrors.ErrVacancyNotOpen, vacancy.Status)
+		}
+		now := s.now()
+		vacancy.Status = models.VacancyCancelled
+		vacancy.ClosedAt = &now
+		return s.vacancyRepo.Update(ctx, vacancy)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vacancy, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupVacancyService(t *testing.T, mode string) (*VacancySvc, *MockVacancyRepo, *MockDepartmentRepo, *MockEmployeeRepo) {
+	mockVacancyRepo := new(MockVacancyRepo)
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockPosRepo := new(MockPositionRepo)
+	mockPosRepo.On("GetByTitle", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	service := NewVacancyService(mockVacancyRepo, mockDeptRepo, mockEmpRepo, mockPosRepo, fakeTx{}, mode)
+	return service, mockVacancyRepo, mockDeptRepo, mockEmpRepo
+}
+
+func TestVacancyCreate_HeadcountLimit(t *testing.T) {
+	ctx := context.Background()
+	dept := &models.Department{ID: 1, Name: "Dev", HeadcountLimit: intPtr(2)}
+
+	// Один работающий сотрудник и одна открытая вакансия занимают оба места.
+	for _, mode := range []string{HeadcountLimitSoft, HeadcountLimitHard} {
+		service, mockVacancyRepo, mockDeptRepo, mockEmpRepo := setupVacancyService(t, mode)
+		mockDeptRepo.On("GetByID", ctx, uint(1)).Return(dept, nil)
+		mockDeptRepo.On("Lock", ctx, uint(1)).Return(dept, nil).Maybe()
+		mockEmpRepo.On("CountEmployed", ctx, uint(1)).Return(int64(1), nil)
+		mockVacancyRepo.On("CountOpen", ctx, uint(1)).Return(int64(1), nil)
+		mockVacancyRepo.On("Create", ctx, mock.Anything).Return(nil).Maybe()
+
+		vacancy, err := service.Create(ctx, 1, " Developer ", nil, nil)
+		if mode == HeadcountLimitHard {
+			assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+			assert.Nil(t, vacancy)
+			mockDeptRepo.AssertCalled(t, "Lock", ctx, uint(1))
+			mockVacancyRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+			continue
+		}
+		mockDeptRepo.AssertNotCalled(t, "Lock", ctx, uint(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "Developer", vacancy.Position)
+		assert.Equal(t, models.VacancyOpen, vacancy.Status)
+		assert.Contains(t, vacancy.Warning, "2 of 2 budgeted seats")
+	}
+}
+
+func TestVacancyCreate_UnderLimit(t *testing.T) {
+	ctx := context.Background()
+	service, mockVacancyRepo, mockDeptRepo, mockEmpRepo := setupVacancyService(t, HeadcountLimitHard)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, HeadcountLimit: intPtr(3)}, nil)
+	mockDeptRepo.On("Lock", ctx, uint(1)).Return(&models.Department{ID: 1, HeadcountLimit: intPtr(3)}, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(1)).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, uint(1)).Return(int64(1), nil)
+	mockVacancyRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	vacancy, err := service.Create(ctx, 1, "Developer", nil, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, vacancy.Warning)
+}
+
+func TestVacancyCancel(t *testing.T) {
+	ctx := context.Background()
+	service, mockVacancyRepo, _, _ := setupVacancyService(t, HeadcountLimitSoft)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	mockVacancyRepo.On("GetByID", ctx, uint(5)).Return(&models.Vacancy{ID: 5, Status: models.VacancyOpen}, nil).Once()
+	mockVacancyRepo.On("Update", ctx, mock.MatchedBy(func(v *models.Vacancy) bool {
+		return v.Status == models.VacancyCancelled && v.ClosedAt.Equal(now)
+	})).Return(nil)
+
+	vacancy, err := service.Cancel(ctx, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.VacancyCancelled, vacancy.Status)
+
+	mockVacancyRepo.On("GetByID", ctx, uint(5)).Return(&models.Vacancy{ID: 5, Status: models.VacancyFilled}, nil)
+	_, err = service.Cancel(ctx, 5)
+	assert.ErrorIs(t, err, apperrors.ErrVacancyNotOpen)
+
+	mockVacancyRepo.On("GetByID", ctx, uint(6)).Return(nil, nil)
+	_, err = service.Cancel(ctx, 6)
+	assert.ErrorIs(t, err, apperrors.ErrVacancyNotFound)
+}
+
+func TestVacancyList_InvalidStatus(t *testing.T) {
+	service, _, _, _ := setupVacancyService(t, HeadcountLimitSoft)
+
+	_, err := service.List(context.Background(), nil, "closed")
+
+	assert.ErrorIs(t, err, apperrors.ErrInvalidVacancy)
+}
+
+func TestEmployeeCreate_FillsVacancy(t *testing.T) {
+	ctx := context.Background()
+	mockEmpRepo := new(MockEmployeeRepoForService)
+	mockDeptRepo := new(MockDepartmentRepoForEmployee)
+	mockPosRepo := new(MockPositionRepo)
+	mockVacancyRepo := new(MockVacancyRepo)
+	service := NewEmpService(mockEmpRepo, mockDeptRepo, mockPosRepo, mockVacancyRepo, fakeSchema{}, fakeTx{}, HeadcountLimitHard)
+
+	// Отдел заполнен, но приём на открытую вакансию численность не меняет.
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, HeadcountLimit: intPtr(1)}, nil)
+	mockVacancyRepo.On("GetByID", ctx, uint(5)).
+		Return(&models.Vacancy{ID: 5, DepartmentID: 1, Position: "Backend Engineer", PositionID: intPtr(7), Status: models.VacancyOpen}, nil)
+	mockPosRepo.On("GetByID", ctx, uint(7)).Return(&models.Position{ID: 7, Title: "Backend Engineer"}, nil)
+	mockEmpRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Employee).ID = 42
+	}).Return(nil)
+	mockVacancyRepo.On("Update", ctx, mock.MatchedBy(func(v *models.Vacancy) bool {
+		return v.Status == models.VacancyFilled && *v.EmployeeID == 42 && v.ClosedAt != nil
+	})).Return(nil)
+
+	emp, err := service.Create(ctx, 1, "John Doe", "", nil, uintPtr(5), nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", emp.Position)
+	assert.Equal(t, 7, *emp.PositionID)
+	mockVacancyRepo.AssertExpectations(t)
+}
+
+func TestEmployeeCreate_VacancyChecks(t *testing.T) {
+	ctx := context.Background()
+	mockEmpRepo := new(MockEmployeeRepoForService)
+	mockDeptRepo := new(MockDepartmentRepoForEmployee)
+	mockVacancyRepo := new(MockVacancyRepo)
+	service := NewEmpService(mockEmpRepo, mockDeptRepo, new(MockPositionRepo), mockVacancyRepo, fakeSchema{}, fakeTx{}, HeadcountLimitSoft)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1}, nil)
+	mockVacancyRepo.On("GetByID", ctx, uint(5)).Return(&models.Vacancy{ID: 5, DepartmentID: 2, Status: models.VacancyOpen}, nil)
+	mockVacancyRepo.On("GetByID", ctx, uint(6)).Return(&models.Vacancy{ID: 6, DepartmentID: 1, Status: models.VacancyCancelled}, nil)
+	mockVacancyRepo.On("GetByID", ctx, uint(7)).Return(nil, nil)
+
+	_, err := service.Create(ctx, 1, "John Doe", "Developer", nil, uintPtr(5), nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidVacancy)
+	_, err = service.Create(ctx, 1, "John Doe", "Developer", nil, uintPtr(6), nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrVacancyNotOpen)
+	_, err = service.Create(ctx, 1, "John Doe", "Developer", nil, uintPtr(7), nil, nil)
+	assert.ErrorIs(t, err, apperrors.ErrVacancyNotFound)
+	mockEmpRepo.AssertNotCalled(t, "Create", ctx, mock.Anything)
+}
+
+func TestDepartmentHeadcountBudget_Rollup(t *testing.T) {
+	ctx := context.Background()
+	service, mockDeptRepo, _ := setupDepartmentService(t)
+	mockDeptRepo.On("HeadcountBudget", ctx, uint(1)).Return([]models.HeadcountBudget{
+		{DepartmentID: 1, Name: "Root", Budgeted: intPtr(2), Actual: 2},
+		{DepartmentID: 2, Name: "Dev", ParentID: uintPtr(1), Budgeted: intPtr(5), Actual: 3, Open: 1},
+		{DepartmentID: 3, Name: "Backend", ParentID: uintPtr(2), Actual: 4, Open: 2},
+		{DepartmentID: 4, Name: "QA", ParentID: uintPtr(1), Budgeted: intPtr(1), Actual: 1},
+	}, nil)
+	mockDeptRepo.On("HeadcountBudget", ctx, uint(9)).Return([]models.HeadcountBudget{}, nil)
+
+	budget, err := service.HeadcountBudget(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, budget.TotalBudgeted)
+	assert.Equal(t, 10, budget.TotalActual)
+	assert.Equal(t, 3, budget.TotalOpen)
+	assert.Equal(t, 1, budget.UnbudgetedDepartments)
+	assert.Len(t, budget.Children, 2)
+	assert.Equal(t, 7, budget.Children[0].TotalActual)
+	assert.Equal(t, 5, budget.Children[0].TotalBudgeted)
+
+	_, err = service.HeadcountBudget(ctx, 9)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNotFound)
+}
+
+func TestDepartmentSetHeadcountLimit(t *testing.T) {
+	ctx := context.Background()
+	service, mockDeptRepo, _, mockHistoryRepo := setupMergeService(t)
+	mockDeptRepo.On("GetByID", ctx, uint(1)).Return(&models.Department{ID: 1, Name: "Dev"}, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.HeadcountLimit != nil && *d.HeadcountLimit == 4
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(entry *models.DepartmentHistory) bool {
+		return entry.Action == "headcount_limit"
+	})).Return(nil)
+
+	dept, err := service.SetHeadcountLimit(ctx, 1, intPtr(4))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, *dept.HeadcountLimit)
+
+	_, err = service.SetHeadcountLimit(ctx, 1, intPtr(-1))
+	assert.ErrorIs(t, err, apperrors.ErrInvalidHeadcountLimit)
+}
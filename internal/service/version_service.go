@@ -0,0 +1,381 @@
+// Package service содержит бизнес-логику приложения.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+)
+
+// VersionRepository определяет интерфейс репозитория версий подразделений и сотрудников.
+type VersionRepository interface {
+	ScheduleDepartment(ctx context.Context, id uint, from time.Time, name string, parentID *uint) error
+	ScheduleEmployee(ctx context.Context, id uint, from time.Time, departmentID uint) error
+	Today(ctx context.Context) (time.Time, error)
+	DueDepartmentVersions(ctx context.Context) ([]models.DepartmentVersion, error)
+	DueEmployeeVersions(ctx context.Context) ([]models.EmployeeVersion, error)
+	DiscardDepartmentVersion(ctx context.Context, version *models.DepartmentVersion) error
+	DiscardEmployeeVersion(ctx context.Context, version *models.EmployeeVersion) error
+}
+
+// VersionService определяет интерфейс изменений структуры с датой вступления в силу,
+// который будет реализован сервисом и использован обработчиками HTTP.
+type VersionService interface {
+	ScheduleDepartmentChange(ctx context.Context, id uint, name *string, parentID *uint, effectiveFrom time.Time) (*models.Department, error)
+	TransferEmployee(ctx context.Context, id, departmentID uint, effectiveFrom *time.Time) (*models.Employee, error)
+}
+
+// VerService реализует изменения структуры с датой вступления в силу и их применение
+// к текущим записям при наступлении даты. Сегодняшняя дата берётся из БД, как в триггерах версий.
+type VerService struct {
+	deptRepo     DepartmentRepository
+	empRepo      EmployeeRepository
+	versionRepo  VersionRepository
+	historyRepo  HistoryRepository
+	types        DepartmentTypeRules
+	names        NamingPolicy
+	guard        headcountGuard
+	tx           Transactor
+	approvalMode string
+}
+
+// NewVersionService создаёт новый экземпляр сервиса версий; limitMode - режим проверки
+// утверждённой численности подразделения при переводе (HeadcountLimitSoft или HeadcountLimitHard),
+// approvalMode - режим согласования переносов подразделений.
+func NewVersionService(deptRepo DepartmentRepository, empRepo EmployeeRepository, vacancyRepo VacancyRepository,
+	versionRepo VersionRepository, historyRepo HistoryRepository, types DepartmentTypeRules, names NamingPolicy,
+	tx Transactor, limitMode string, approvalMode string) *VerService {
+	return &VerService{deptRepo: deptRepo, empRepo: empRepo, versionRepo: versionRepo, historyRepo: historyRepo,
+		types: types, names: names, tx: tx, approvalMode: approvalMode,
+		guard: headcountGuard{deptRepo: deptRepo, empRepo: empRepo, vacancyRepo: vacancyRepo, mode: limitMode}}
+}
+
+// ScheduleDepartmentChange переименовывает и/или переносит подразделение с даты effectiveFrom.
+// Изменение с сегодняшней датой применяется сразу, с будущей - при наступлении даты.
+// Возвращает подразделение в состоянии на effectiveFrom.
+func (s *VerService) ScheduleDepartmentChange(ctx context.Context, id uint, name *string, parentID *uint,
+	effectiveFrom time.Time) (*models.Department, error) {
+	if name == nil && parentID == nil {
+		return nil, apperrors.ErrEmptyScheduledChange
+	}
+	today, err := s.versionRepo.Today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := effectiveDate(today, &effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Department
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		dept, err := s.deptRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if dept == nil {
+			return apperrors.ErrDepartmentNotFound
+		}
+		state, err := s.deptRepo.GetAsOf(ctx, id, from)
+		if err != nil {
+			return err
+		}
+		if state == nil {
+			state = dept
+		}
+
+		newName := state.Name
+		if name != nil {
+			clean, err := s.names.Normalize(*name)
+			if err != nil {
+				return fmt.Errorf("%w: %v", apperrors.ErrInvalidDepartmentName, err)
+			}
+			newName = clean
+		}
+		newParent := state.ParentID
+		if parentID != nil {
+			newParent = parentID
+			if *parentID == 0 {
+				newParent = nil
+			}
+		}
+		if !sameParent(state.ParentID, newParent) {
+			if err := requireApproval(ctx, s.approvalMode, "move", id); err != nil {
+				return err
+			}
+		}
+		if err := checkPlacement(ctx, s.deptRepo, s.types, dept, newName, newParent, from); err != nil {
+			return err
+		}
+
+		if from.Equal(today) {
+			dept.Name = newName
+			dept.ParentID = newParent
+			if err := s.deptRepo.Update(ctx, dept); err != nil {
+				return err
+			}
+		} else if err := s.versionRepo.ScheduleDepartment(ctx, id, from, newName, newParent); err != nil {
+			return err
+		}
+
+		result = dept
+		result.Name = newName
+		result.ParentID = newParent
+		return writeHistory(ctx, s.historyRepo, id, "schedule_change", map[string]any{
+			"name":           newName,
+			"parent_id":      newParent,
+			"effective_from": from.Format(time.DateOnly),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TransferEmployee переводит сотрудника в отдел departmentID с даты effectiveFrom
+// (по умолчанию - сегодня). Уволенного сотрудника перевести нельзя, перевод в другой отдел
+// проверяется по его утверждённой численности. Возвращает сотрудника в состоянии на эту дату.
+func (s *VerService) TransferEmployee(ctx context.Context, id, departmentID uint, effectiveFrom *time.Time) (*models.Employee, error) {
+	today, err := s.versionRepo.Today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := effectiveDate(today, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Employee
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		emp, err := s.empRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if emp == nil {
+			return apperrors.ErrEmployeeNotFound
+		}
+		warning, err := s.checkTransfer(ctx, emp, departmentID)
+		if err != nil {
+			return err
+		}
+
+		if from.Equal(today) {
+			emp.DepartmentID = int(departmentID)
+			if err := s.empRepo.Update(ctx, emp); err != nil {
+				return err
+			}
+		} else if err := s.versionRepo.ScheduleEmployee(ctx, id, from, departmentID); err != nil {
+			return err
+		}
+		result = emp
+		result.DepartmentID = int(departmentID)
+		result.Warning = warning
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ApplyDue применяет к текущим записям версии, дата вступления в силу которых наступила.
+// Версия, которую нельзя применить (например, из-за конфликта имён), отбрасывается
+// с записью в журнал. Возвращает количество применённых версий и ошибки неприменённых.
+func (s *VerService) ApplyDue(ctx context.Context) (int, error) {
+	applied := 0
+	var errs []error
+
+	deptVersions, err := s.versionRepo.DueDepartmentVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deptVersions {
+		v := &deptVersions[i]
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.applyDepartmentVersion(ctx, v)
+		})
+		if err == nil {
+			applied++
+			continue
+		}
+		errs = append(errs, fmt.Errorf("department %d change from %s: %w", v.DepartmentID, v.ValidFrom.Format(time.DateOnly), err))
+		if err := s.discard(ctx, uint(v.DepartmentID), map[string]any{
+			"name": v.Name, "parent_id": v.ParentID, "effective_from": v.ValidFrom.Format(time.DateOnly), "error": err.Error(),
+		}, func(ctx context.Context) error {
+			return s.versionRepo.DiscardDepartmentVersion(ctx, v)
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	empVersions, err := s.versionRepo.DueEmployeeVersions(ctx)
+	if err != nil {
+		return applied, errors.Join(append(errs, err)...)
+	}
+	for i := range empVersions {
+		v := &empVersions[i]
+		err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.applyEmployeeVersion(ctx, v)
+		})
+		if err == nil {
+			applied++
+			continue
+		}
+		errs = append(errs, fmt.Errorf("employee %d transfer from %s: %w", v.EmployeeID, v.ValidFrom.Format(time.DateOnly), err))
+		if err := s.discard(ctx, uint(v.DepartmentID), map[string]any{
+			"employee_id": v.EmployeeID, "effective_from": v.ValidFrom.Format(time.DateOnly), "error": err.Error(),
+		}, func(ctx context.Context) error {
+			return s.versionRepo.DiscardEmployeeVersion(ctx, v)
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return applied, errors.Join(errs...)
+}
+
+// applyDepartmentVersion переносит название и родителя из версии в текущую запись подразделения.
+func (s *VerService) applyDepartmentVersion(ctx context.Context, v *models.DepartmentVersion) error {
+	dept, err := s.deptRepo.GetByID(ctx, uint(v.DepartmentID))
+	if err != nil {
+		return err
+	}
+	if dept == nil {
+		return apperrors.ErrDepartmentNotFound
+	}
+	if err := checkPlacement(ctx, s.deptRepo, s.types, dept, v.Name, v.ParentID, time.Time{}); err != nil {
+		return err
+	}
+	dept.Name = v.Name
+	dept.ParentID = v.ParentID
+	return s.deptRepo.Update(ctx, dept)
+}
+
+// applyEmployeeVersion переносит отдел, ФИО и должность из версии в текущую запись сотрудника.
+// Перевод проверяется так же, как при назначении: состояние могло измениться с тех пор.
+func (s *VerService) applyEmployeeVersion(ctx context.Context, v *models.EmployeeVersion) error {
+	emp, err := s.empRepo.GetByID(ctx, uint(v.EmployeeID))
+	if err != nil {
+		return err
+	}
+	if emp == nil {
+		return apperrors.ErrEmployeeNotFound
+	}
+	if _, err := s.checkTransfer(ctx, emp, uint(v.DepartmentID)); err != nil {
+		return err
+	}
+	emp.DepartmentID = v.DepartmentID
+	emp.FullName = v.FullName
+	emp.Position = v.Position
+	return s.empRepo.Update(ctx, emp)
+}
+
+// checkTransfer проверяет перевод сотрудника emp в отдел departmentID: отдел существует
+// и переводу не мешают статус сотрудника и утверждённая численность (см. validateTransfer).
+func (s *VerService) checkTransfer(ctx context.Context, emp *models.Employee, departmentID uint) (string, error) {
+	dept, err := s.deptRepo.GetByID(ctx, departmentID)
+	if err != nil {
+		return "", err
+	}
+	if dept == nil {
+		return "", apperrors.ErrEmployeeDepartmentNotFound
+	}
+	return validateTransfer(ctx, s.guard, emp, dept)
+}
+
+// validateTransfer проверяет перевод сотрудника emp в другой отдел dept: сотрудник не уволен,
+// а в новом отделе есть место по утверждённой численности. Общая проверка для перевода
+// по API, отложенных переводов, пакетов реорганизации и импорта. Возвращает предупреждение
+// о превышении в мягком режиме.
+func validateTransfer(ctx context.Context, guard headcountGuard, emp *models.Employee, dept *models.Department) (string, error) {
+	if emp.DepartmentID == dept.ID {
+		return "", nil
+	}
+	if emp.Status == models.EmployeeTerminated {
+		return "", apperrors.ErrEmployeeTerminated
+	}
+	return guard.check(ctx, dept, false)
+}
+
+// discard отбрасывает неприменимую версию и записывает причину в журнал подразделения.
+func (s *VerService) discard(ctx context.Context, departmentID uint, details map[string]any, fn func(ctx context.Context) error) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return writeHistory(ctx, s.historyRepo, departmentID, "scheduled_change_failed", details)
+	})
+}
+
+// checkPlacement проверяет, что подразделение dept можно поставить под родителя parentID
+// с названием name: родитель существует (на дату from, если она задана), не является самим
+// подразделением или его потомком, тип подразделения допускает такого родителя (или корень),
+// а среди соседей нет подразделения с тем же ключом названия. Подразделение с нулевым ID
+// ещё не создано и не может оказаться предком родителя.
+func checkPlacement(ctx context.Context, deptRepo DepartmentRepository, types DepartmentTypeRules,
+	dept *models.Department, name string, parentID *uint, from time.Time) error {
+	id := uint(dept.ID)
+	var parent *models.Department
+	if parentID != nil {
+		if *parentID == id {
+			return apperrors.ErrSelfParent
+		}
+		var err error
+		if from.IsZero() {
+			parent, err = deptRepo.GetByID(ctx, *parentID)
+		} else {
+			parent, err = deptRepo.GetAsOf(ctx, *parentID, from)
+		}
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return apperrors.ErrParentNotFound
+		}
+		if id != 0 {
+			isDescendant, err := deptRepo.IsDescendant(ctx, id, *parentID)
+			if err != nil {
+				return err
+			}
+			if isDescendant {
+				return apperrors.ErrCycleDetected
+			}
+		}
+	}
+	if err := types.CheckPlacement(ctx, dept.Type, parent, nil); err != nil {
+		return err
+	}
+
+	sibling, err := findSibling(ctx, deptRepo, id, name, parentID)
+	if err != nil {
+		return err
+	}
+	if sibling != nil {
+		return apperrors.ErrDepartmentNameConflict
+	}
+	return nil
+}
+
+// effectiveDate возвращает дату вступления изменения в силу: сегодня (today), если она не задана.
+// Даты в прошлом не допускаются.
+func effectiveDate(today time.Time, effectiveFrom *time.Time) (time.Time, error) {
+	today = dateOf(today)
+	if effectiveFrom == nil {
+		return today, nil
+	}
+	from := dateOf(*effectiveFrom)
+	if from.Before(today) {
+		return time.Time{}, apperrors.ErrInvalidEffectiveDate
+	}
+	return from, nil
+}
+
+// dateOf отбрасывает время, оставляя календарную дату.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
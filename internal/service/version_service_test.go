@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apperrors "github.com/NailUsmanov/api_organization/internal/errors"
+	"github.com/NailUsmanov/api_organization/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVersionRepo - мок для репозитория версий
+type MockVersionRepo struct {
+	mock.Mock
+}
+
+func (m *MockVersionRepo) ScheduleDepartment(ctx context.Context, id uint, from time.Time, name string, parentID *uint) error {
+	args := m.Called(ctx, id, from, name, parentID)
+	return args.Error(0)
+}
+
+func (m *MockVersionRepo) ScheduleEmployee(ctx context.Context, id uint, from time.Time, departmentID uint) error {
+	args := m.Called(ctx, id, from, departmentID)
+	return args.Error(0)
+}
+
+func (m *MockVersionRepo) Today(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockVersionRepo) DueDepartmentVersions(ctx context.Context) ([]models.DepartmentVersion, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.DepartmentVersion), args.Error(1)
+}
+
+func (m *MockVersionRepo) DueEmployeeVersions(ctx context.Context) ([]models.EmployeeVersion, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.EmployeeVersion), args.Error(1)
+}
+
+func (m *MockVersionRepo) DiscardDepartmentVersion(ctx context.Context, version *models.DepartmentVersion) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+func (m *MockVersionRepo) DiscardEmployeeVersion(ctx context.Context, version *models.EmployeeVersion) error {
+	args := m.Called(ctx, version)
+	return args.Error(0)
+}
+
+// versionToday - текущая дата сервиса версий в тестах
+var versionToday = time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+func setupVersionService(t *testing.T) (*VerService, *MockDepartmentRepo, *MockEmployeeRepo, *MockVersionRepo, *MockHistoryRepo) {
+	mockDeptRepo := new(MockDepartmentRepo)
+	mockEmpRepo := new(MockEmployeeRepo)
+	mockVersionRepo := new(MockVersionRepo)
+	mockHistoryRepo := new(MockHistoryRepo)
+	service := NewVersionService(mockDeptRepo, mockEmpRepo, new(MockVacancyRepo), mockVersionRepo, mockHistoryRepo,
+		fakeTypes{}, DefaultNamingPolicy(), fakeTx{}, HeadcountLimitHard, ChangeApprovalOptional)
+	mockVersionRepo.On("Today", mock.Anything).Return(versionToday, nil).Maybe()
+	return service, mockDeptRepo, mockEmpRepo, mockVersionRepo, mockHistoryRepo
+}
+
+func TestScheduleDepartmentChange_Future(t *testing.T) {
+	service, mockDeptRepo, _, mockVersionRepo, mockHistoryRepo := setupVersionService(t)
+	ctx := context.Background()
+
+	from := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	newParentID := uint(2)
+	dept := &models.Department{ID: 5, Name: "Platform"}
+
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(dept, nil)
+	mockDeptRepo.On("GetAsOf", ctx, uint(5), from).Return(&models.Department{ID: 5, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetAsOf", ctx, newParentID, from).Return(&models.Department{ID: 2, Name: "Engineering"}, nil)
+	mockDeptRepo.On("IsDescendant", ctx, uint(5), newParentID).Return(false, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Core Platform", &newParentID).Return(nil, nil)
+	mockVersionRepo.On("ScheduleDepartment", ctx, uint(5), from, "Core Platform", &newParentID).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(e *models.DepartmentHistory) bool {
+		return e.DepartmentID == 5 && e.Action == "schedule_change"
+	})).Return(nil)
+
+	name := "Core Platform"
+	result, err := service.ScheduleDepartmentChange(ctx, 5, &name, &newParentID, from)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Core Platform", result.Name)
+	assert.Equal(t, newParentID, *result.ParentID)
+	mockDeptRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockVersionRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestScheduleDepartmentChange_TodayAppliesImmediately(t *testing.T) {
+	service, mockDeptRepo, _, mockVersionRepo, mockHistoryRepo := setupVersionService(t)
+	ctx := context.Background()
+
+	dept := &models.Department{ID: 5, Name: "Platform"}
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(dept, nil)
+	mockDeptRepo.On("GetAsOf", ctx, uint(5), versionToday).Return(&models.Department{ID: 5, Name: "Platform"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Core", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 5 && d.Name == "Core"
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	name := "Core"
+	_, err := service.ScheduleDepartmentChange(ctx, 5, &name, nil, versionToday)
+
+	assert.NoError(t, err)
+	mockDeptRepo.AssertExpectations(t)
+	mockVersionRepo.AssertNotCalled(t, "ScheduleDepartment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestScheduleDepartmentChange_PastDate(t *testing.T) {
+	service, mockDeptRepo, _, _, _ := setupVersionService(t)
+	ctx := context.Background()
+
+	name := "Core"
+	result, err := service.ScheduleDepartmentChange(ctx, 5, &name, nil, versionToday.AddDate(0, 0, -1))
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, apperrors.ErrInvalidEffectiveDate)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestTransferEmployee_Future(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockVersionRepo, _ := setupVersionService(t)
+	ctx := context.Background()
+
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(3)).Return(&models.Department{ID: 3}, nil)
+	mockVersionRepo.On("ScheduleEmployee", ctx, uint(7), from, uint(3)).Return(nil)
+
+	emp, err := service.TransferEmployee(ctx, 7, 3, &from)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, emp.DepartmentID)
+	mockEmpRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockVersionRepo.AssertExpectations(t)
+}
+
+func TestTransferEmployee_Terminated(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockVersionRepo, _ := setupVersionService(t)
+	ctx := context.Background()
+
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1, Status: models.EmployeeTerminated}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(3)).Return(&models.Department{ID: 3}, nil)
+
+	_, err := service.TransferEmployee(ctx, 7, 3, &from)
+
+	assert.ErrorIs(t, err, apperrors.ErrEmployeeTerminated)
+	mockVersionRepo.AssertNotCalled(t, "ScheduleEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferEmployee_HeadcountLimit(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockVersionRepo, _ := setupVersionService(t)
+	ctx := context.Background()
+	mockVacancyRepo := service.guard.vacancyRepo.(*MockVacancyRepo)
+
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1}, nil)
+	mockDeptRepo.On("GetByID", ctx, uint(3)).Return(&models.Department{ID: 3, HeadcountLimit: intPtr(1)}, nil)
+	mockDeptRepo.On("Lock", ctx, uint(3)).Return(&models.Department{ID: 3, HeadcountLimit: intPtr(1)}, nil)
+	mockEmpRepo.On("CountEmployed", ctx, uint(3)).Return(int64(1), nil)
+	mockVacancyRepo.On("CountOpen", ctx, uint(3)).Return(int64(0), nil)
+
+	_, err := service.TransferEmployee(ctx, 7, 3, &from)
+
+	assert.ErrorIs(t, err, apperrors.ErrHeadcountLimitExceeded)
+	mockVersionRepo.AssertNotCalled(t, "ScheduleEmployee", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyDue_DiscardsConflictingChange(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo, mockVersionRepo, mockHistoryRepo := setupVersionService(t)
+	ctx := context.Background()
+
+	conflicting := models.DepartmentVersion{ID: 11, DepartmentID: 5, Name: "Sales", ValidFrom: versionToday}
+	renamed := models.DepartmentVersion{ID: 12, DepartmentID: 6, Name: "Support", ValidFrom: versionToday}
+	transfer := models.EmployeeVersion{ID: 21, EmployeeID: 7, DepartmentID: 6, FullName: "Ivan Petrov",
+		Position: "Engineer", ValidFrom: versionToday}
+
+	mockVersionRepo.On("DueDepartmentVersions", ctx).Return([]models.DepartmentVersion{conflicting, renamed}, nil)
+	mockVersionRepo.On("DueEmployeeVersions", ctx).Return([]models.EmployeeVersion{transfer}, nil)
+
+	mockDeptRepo.On("GetByID", ctx, uint(5)).Return(&models.Department{ID: 5, Name: "Marketing"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Sales", (*uint)(nil)).Return(&models.Department{ID: 9, Name: "Sales"}, nil)
+	mockVersionRepo.On("DiscardDepartmentVersion", ctx, mock.MatchedBy(func(v *models.DepartmentVersion) bool {
+		return v.ID == 11
+	})).Return(nil)
+	mockHistoryRepo.On("Create", ctx, mock.MatchedBy(func(e *models.DepartmentHistory) bool {
+		return e.DepartmentID == 5 && e.Action == "scheduled_change_failed"
+	})).Return(nil)
+
+	mockDeptRepo.On("GetByID", ctx, uint(6)).Return(&models.Department{ID: 6, Name: "Helpdesk"}, nil)
+	mockDeptRepo.On("GetByNameAndParent", ctx, "Support", (*uint)(nil)).Return(nil, nil)
+	mockDeptRepo.On("Update", ctx, mock.MatchedBy(func(d *models.Department) bool {
+		return d.ID == 6 && d.Name == "Support"
+	})).Return(nil)
+
+	mockEmpRepo.On("GetByID", ctx, uint(7)).Return(&models.Employee{ID: 7, DepartmentID: 1}, nil)
+	mockEmpRepo.On("Update", ctx, mock.MatchedBy(func(e *models.Employee) bool {
+		return e.ID == 7 && e.DepartmentID == 6
+	})).Return(nil)
+
+	applied, err := service.ApplyDue(ctx)
+
+	assert.Equal(t, 2, applied)
+	assert.ErrorIs(t, err, apperrors.ErrDepartmentNameConflict)
+	mockDeptRepo.AssertExpectations(t)
+	mockEmpRepo.AssertExpectations(t)
+	mockVersionRepo.AssertExpectations(t)
+	mockHistoryRepo.AssertExpectations(t)
+}
+
+func TestGetByID_AsOf(t *testing.T) {
+	service, mockDeptRepo, mockEmpRepo := setupDepartmentService(t)
+	ctx := context.Background()
+
+	asOf := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockDeptRepo.On("GetAsOf", ctx, uint(1), asOf).Return(&models.Department{ID: 1, Name: "Old Name"}, nil)
+	mockEmpRepo.On("ListByDepartment", ctx, uint(1), "created_at", &asOf).Return([]models.Employee{{ID: 3}}, nil)
+	mockDeptRepo.On("GetSubTree", ctx, uint(1), 2, &asOf).Return([]models.Department{}, nil)
+
+	dept, err := service.GetByID(ctx, 1, 2, true, &asOf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Old Name", dept.Name)
+	assert.Len(t, dept.Employees, 1)
+	mockDeptRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE department_history (
+    id            SERIAL PRIMARY KEY,
+    department_id INT NOT NULL,
+    action        VARCHAR(50) NOT NULL,
+    details       JSONB NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_department_history_department_id ON department_history(department_id);
+
+-- +goose Down
+DROP TABLE department_history;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE vacancies (
+    id            SERIAL PRIMARY KEY,
+    department_id INT NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    position      VARCHAR(200) NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_vacancies_department_id ON vacancies(department_id);
+
+-- +goose Down
+DROP TABLE vacancies;
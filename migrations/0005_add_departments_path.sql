@@ -0,0 +1,67 @@
+-- +goose Up
+-- Материализованный путь вида /1/5/12/ из идентификаторов предков и самого подразделения.
+-- Сортировка "C" нужна, чтобы поддерево занимало непрерывный диапазон в btree-индексе.
+ALTER TABLE departments ADD COLUMN path TEXT COLLATE "C";
+
+WITH RECURSIVE tree AS (
+    SELECT id, '/' || id || '/' AS path
+    FROM departments
+    WHERE parent_id IS NULL
+    UNION ALL
+    SELECT d.id, t.path || d.id || '/'
+    FROM departments d
+    INNER JOIN tree t ON d.parent_id = t.id
+)
+UPDATE departments d SET path = tree.path FROM tree WHERE d.id = tree.id;
+
+ALTER TABLE departments ALTER COLUMN path SET NOT NULL;
+CREATE UNIQUE INDEX idx_departments_path ON departments (path);
+
+-- +goose StatementBegin
+CREATE FUNCTION departments_set_path() RETURNS trigger AS $$
+DECLARE
+    parent_path TEXT;
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        NEW.path := '/' || NEW.id || '/';
+        RETURN NEW;
+    END IF;
+
+    SELECT path INTO parent_path FROM departments WHERE id = NEW.parent_id;
+    IF parent_path LIKE '%/' || NEW.id || '/%' THEN
+        RAISE EXCEPTION 'cannot move department % to its own descendant', NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    NEW.path := parent_path || NEW.id || '/';
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION departments_move_subtree() RETURNS trigger AS $$
+BEGIN
+    IF NEW.path IS DISTINCT FROM OLD.path THEN
+        UPDATE departments
+        SET path = NEW.path || substr(path, length(OLD.path) + 1)
+        WHERE path > OLD.path AND path < OLD.path || '~';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER departments_path_insert BEFORE INSERT ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_set_path();
+CREATE TRIGGER departments_path_update BEFORE UPDATE OF parent_id ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_set_path();
+CREATE TRIGGER departments_path_move AFTER UPDATE OF parent_id ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_move_subtree();
+
+-- +goose Down
+DROP TRIGGER departments_path_move ON departments;
+DROP TRIGGER departments_path_update ON departments;
+DROP TRIGGER departments_path_insert ON departments;
+DROP FUNCTION departments_move_subtree();
+DROP FUNCTION departments_set_path();
+ALTER TABLE departments DROP COLUMN path;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE departments ADD COLUMN sort_order INT NOT NULL DEFAULT 0;
+
+UPDATE departments d SET sort_order = o.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY id) AS rn
+    FROM departments
+) o
+WHERE d.id = o.id;
+
+CREATE INDEX idx_departments_parent_sort_order ON departments (parent_id, sort_order);
+
+-- +goose Down
+DROP INDEX idx_departments_parent_sort_order;
+ALTER TABLE departments DROP COLUMN sort_order;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE departments ADD COLUMN code VARCHAR(50);
+ALTER TABLE departments ADD COLUMN external_ids JSONB NOT NULL DEFAULT '{}';
+CREATE UNIQUE INDEX idx_departments_code ON departments (code) WHERE code IS NOT NULL;
+CREATE INDEX idx_departments_external_ids ON departments USING GIN (external_ids jsonb_path_ops);
+
+ALTER TABLE employees ADD COLUMN code VARCHAR(50);
+ALTER TABLE employees ADD COLUMN external_ids JSONB NOT NULL DEFAULT '{}';
+CREATE UNIQUE INDEX idx_employees_code ON employees (code) WHERE code IS NOT NULL;
+CREATE INDEX idx_employees_external_ids ON employees USING GIN (external_ids jsonb_path_ops);
+
+-- +goose Down
+DROP INDEX idx_employees_external_ids;
+DROP INDEX idx_employees_code;
+ALTER TABLE employees DROP COLUMN external_ids;
+ALTER TABLE employees DROP COLUMN code;
+
+DROP INDEX idx_departments_external_ids;
+DROP INDEX idx_departments_code;
+ALTER TABLE departments DROP COLUMN external_ids;
+ALTER TABLE departments DROP COLUMN code;
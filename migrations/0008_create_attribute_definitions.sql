@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE attribute_definitions (
+    id          SERIAL PRIMARY KEY,
+    entity      VARCHAR(20) NOT NULL CHECK (entity IN ('department', 'employee')),
+    name        VARCHAR(50) NOT NULL,
+    type        VARCHAR(20) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'date', 'enum')),
+    required    BOOLEAN NOT NULL DEFAULT FALSE,
+    enum_values JSONB NOT NULL DEFAULT '[]',
+    pattern     VARCHAR(500) NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_attribute_entity_name ON attribute_definitions (entity, name);
+
+ALTER TABLE departments ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX idx_departments_attributes ON departments USING GIN (attributes jsonb_path_ops);
+
+ALTER TABLE employees ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+CREATE INDEX idx_employees_attributes ON employees USING GIN (attributes jsonb_path_ops);
+
+-- +goose Down
+DROP INDEX idx_employees_attributes;
+ALTER TABLE employees DROP COLUMN attributes;
+DROP INDEX idx_departments_attributes;
+ALTER TABLE departments DROP COLUMN attributes;
+DROP TABLE attribute_definitions;
@@ -0,0 +1,152 @@
+-- +goose Up
+-- Версии подразделений и сотрудников с периодом действия [valid_from, valid_to).
+-- Текущие изменения записываются триггерами, будущие - сервисом; valid_to = NULL - версия действует бессрочно.
+CREATE TABLE department_versions (
+    id            SERIAL PRIMARY KEY,
+    department_id INT NOT NULL,
+    name          VARCHAR(200) NOT NULL,
+    parent_id     INT,
+    valid_from    DATE NOT NULL,
+    valid_to      DATE,
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE UNIQUE INDEX idx_department_versions_from ON department_versions (department_id, valid_from);
+CREATE INDEX idx_department_versions_parent ON department_versions (parent_id);
+
+CREATE TABLE employee_versions (
+    id            SERIAL PRIMARY KEY,
+    employee_id   INT NOT NULL,
+    department_id INT NOT NULL,
+    full_name     VARCHAR(200) NOT NULL,
+    position      VARCHAR(200) NOT NULL,
+    valid_from    DATE NOT NULL,
+    valid_to      DATE,
+    CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE UNIQUE INDEX idx_employee_versions_from ON employee_versions (employee_id, valid_from);
+CREATE INDEX idx_employee_versions_department ON employee_versions (department_id);
+
+INSERT INTO department_versions (department_id, name, parent_id, valid_from)
+SELECT id, name, parent_id, created_at::date FROM departments;
+
+INSERT INTO employee_versions (employee_id, department_id, full_name, position, valid_from)
+SELECT id, department_id, full_name, position, LEAST(hired_at, created_at::date) FROM employees;
+
+-- +goose StatementBegin
+-- department_versions_set задаёт состояние подразделения начиная с p_from до начала следующей версии.
+CREATE FUNCTION department_versions_set(p_department_id INT, p_from DATE, p_name VARCHAR, p_parent_id INT) RETURNS void AS $$
+DECLARE
+    cur department_versions%ROWTYPE;
+BEGIN
+    SELECT * INTO cur FROM department_versions
+    WHERE department_id = p_department_id AND valid_from <= p_from AND (valid_to IS NULL OR valid_to > p_from)
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        INSERT INTO department_versions (department_id, name, parent_id, valid_from, valid_to)
+        VALUES (p_department_id, p_name, p_parent_id, p_from,
+            (SELECT min(valid_from) FROM department_versions WHERE department_id = p_department_id AND valid_from > p_from));
+    ELSIF cur.name = p_name AND cur.parent_id IS NOT DISTINCT FROM p_parent_id THEN
+        RETURN;
+    ELSIF cur.valid_from = p_from THEN
+        UPDATE department_versions SET name = p_name, parent_id = p_parent_id WHERE id = cur.id;
+    ELSE
+        UPDATE department_versions SET valid_to = p_from WHERE id = cur.id;
+        INSERT INTO department_versions (department_id, name, parent_id, valid_from, valid_to)
+        VALUES (p_department_id, p_name, p_parent_id, p_from, cur.valid_to);
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION departments_track_version() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM department_versions WHERE department_id = OLD.id AND valid_from >= CURRENT_DATE;
+        UPDATE department_versions SET valid_to = CURRENT_DATE
+        WHERE department_id = OLD.id AND (valid_to IS NULL OR valid_to > CURRENT_DATE);
+        RETURN NULL;
+    END IF;
+    PERFORM department_versions_set(NEW.id, CURRENT_DATE, NEW.name, NEW.parent_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- employee_versions_set задаёт назначение сотрудника начиная с p_from до начала следующей версии.
+CREATE FUNCTION employee_versions_set(p_employee_id INT, p_from DATE, p_department_id INT, p_full_name VARCHAR, p_position VARCHAR)
+RETURNS void AS $$
+DECLARE
+    cur employee_versions%ROWTYPE;
+BEGIN
+    SELECT * INTO cur FROM employee_versions
+    WHERE employee_id = p_employee_id AND valid_from <= p_from AND (valid_to IS NULL OR valid_to > p_from)
+    FOR UPDATE;
+
+    IF NOT FOUND THEN
+        INSERT INTO employee_versions (employee_id, department_id, full_name, position, valid_from, valid_to)
+        VALUES (p_employee_id, p_department_id, p_full_name, p_position, p_from,
+            (SELECT min(valid_from) FROM employee_versions WHERE employee_id = p_employee_id AND valid_from > p_from));
+    ELSIF cur.department_id = p_department_id AND cur.full_name = p_full_name AND cur.position = p_position THEN
+        RETURN;
+    ELSIF cur.valid_from = p_from THEN
+        UPDATE employee_versions SET department_id = p_department_id, full_name = p_full_name, position = p_position
+        WHERE id = cur.id;
+    ELSE
+        UPDATE employee_versions SET valid_to = p_from WHERE id = cur.id;
+        INSERT INTO employee_versions (employee_id, department_id, full_name, position, valid_from, valid_to)
+        VALUES (p_employee_id, p_department_id, p_full_name, p_position, p_from, cur.valid_to);
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION employees_track_version() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM employee_versions WHERE employee_id = OLD.id AND valid_from >= CURRENT_DATE;
+        UPDATE employee_versions SET valid_to = CURRENT_DATE
+        WHERE employee_id = OLD.id AND (valid_to IS NULL OR valid_to > CURRENT_DATE);
+        RETURN NULL;
+    END IF;
+    -- Новый сотрудник числится в отделе с даты найма, если она в прошлом.
+    PERFORM employee_versions_set(NEW.id,
+        CASE WHEN TG_OP = 'INSERT' THEN LEAST(NEW.hired_at, CURRENT_DATE) ELSE CURRENT_DATE END,
+        NEW.department_id, NEW.full_name, NEW.position);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER departments_version_insert AFTER INSERT ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_track_version();
+CREATE TRIGGER departments_version_update AFTER UPDATE OF name, parent_id ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_track_version();
+CREATE TRIGGER departments_version_delete AFTER DELETE ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_track_version();
+
+CREATE TRIGGER employees_version_insert AFTER INSERT ON employees
+    FOR EACH ROW EXECUTE FUNCTION employees_track_version();
+CREATE TRIGGER employees_version_update AFTER UPDATE OF department_id, full_name, position ON employees
+    FOR EACH ROW EXECUTE FUNCTION employees_track_version();
+CREATE TRIGGER employees_version_delete AFTER DELETE ON employees
+    FOR EACH ROW EXECUTE FUNCTION employees_track_version();
+
+-- +goose Down
+DROP TRIGGER employees_version_delete ON employees;
+DROP TRIGGER employees_version_update ON employees;
+DROP TRIGGER employees_version_insert ON employees;
+DROP TRIGGER departments_version_delete ON departments;
+DROP TRIGGER departments_version_update ON departments;
+DROP TRIGGER departments_version_insert ON departments;
+DROP FUNCTION employees_track_version();
+DROP FUNCTION employee_versions_set(INT, DATE, INT, VARCHAR, VARCHAR);
+DROP FUNCTION departments_track_version();
+DROP FUNCTION department_versions_set(INT, DATE, VARCHAR, INT);
+DROP TABLE employee_versions;
+DROP TABLE department_versions;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE reorg_batches (
+    id          SERIAL PRIMARY KEY,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'failed', 'cancelled')),
+    execute_at  TIMESTAMP NOT NULL,
+    operations  JSONB NOT NULL,
+    error       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    executed_at TIMESTAMP
+);
+
+CREATE INDEX idx_reorg_batches_due ON reorg_batches (execute_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE reorg_batches;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE departments ADD COLUMN head_id INT REFERENCES employees(id) ON DELETE SET NULL;
+
+CREATE TABLE change_requests (
+    id            SERIAL PRIMARY KEY,
+    operation     VARCHAR(20) NOT NULL CHECK (operation IN ('move', 'delete')),
+    department_id INT NOT NULL,
+    payload       JSONB NOT NULL DEFAULT '{}',
+    requested_by  INT NOT NULL,
+    approver_rule VARCHAR(50) NOT NULL,
+    approver_id   INT,
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by    INT,
+    decided_at    TIMESTAMP,
+    comment       TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_change_requests_status ON change_requests (status);
+
+-- +goose Down
+DROP TABLE change_requests;
+ALTER TABLE departments DROP COLUMN head_id;
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE snapshots (
+    id               SERIAL PRIMARY KEY,
+    label            VARCHAR(200) NOT NULL DEFAULT '',
+    document         JSONB NOT NULL,
+    department_count INT NOT NULL,
+    employee_count   INT NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Снимки неизменяемы: документ фиксирует структуру на момент создания.
+-- +goose StatementBegin
+CREATE FUNCTION snapshots_forbid_update() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'snapshot % is immutable', OLD.id;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER snapshots_immutable
+    BEFORE UPDATE ON snapshots
+    FOR EACH ROW EXECUTE FUNCTION snapshots_forbid_update();
+
+-- +goose Down
+DROP TABLE snapshots;
+DROP FUNCTION snapshots_forbid_update();
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Песочница хранит копии рабочих таблиц в отдельной схеме schema_name.
+-- Схема удаляется при переносе изменений или отказе от песочницы.
+CREATE TABLE sandboxes (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(200) NOT NULL,
+    schema_name VARCHAR(63) NOT NULL DEFAULT '',
+    status      VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    closed_at   TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE sandboxes;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Полнотекстовый поиск использует конфигурацию simple: имена и названия не приводятся
+-- к основе слова. Выражения индексов совпадают с выражениями запросов SearchRepo.
+CREATE INDEX idx_employees_search ON employees
+    USING GIN (to_tsvector('simple', full_name || ' ' || position));
+CREATE INDEX idx_employees_full_name_trgm ON employees USING GIN (full_name gin_trgm_ops);
+CREATE INDEX idx_employees_position_trgm ON employees USING GIN (position gin_trgm_ops);
+
+CREATE INDEX idx_departments_search ON departments USING GIN (to_tsvector('simple', name));
+CREATE INDEX idx_departments_name_trgm ON departments USING GIN (name gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_departments_name_trgm;
+DROP INDEX idx_departments_search;
+DROP INDEX idx_employees_position_trgm;
+DROP INDEX idx_employees_full_name_trgm;
+DROP INDEX idx_employees_search;
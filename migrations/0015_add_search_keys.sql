@@ -0,0 +1,99 @@
+-- +goose Up
+-- translit_key приводит имя, записанное кириллицей или латиницей, к общему ключу поиска.
+-- Кириллица транслитерируется по ГОСТ 7.79 (система Б), ISO 9 с диакритикой приводится
+-- к той же записи, затем неформальные варианты сводятся к одному: kh/h, ts/tz/cz/c,
+-- shch/shh/sch, yu/ju/iu, ya/ja/ia, yo/jo и ye/je как e, y/j как i, x как ks,
+-- повторяющиеся буквы - одна. Буквы, записываемые несколькими латинскими, в ключе
+-- заменяются цифрами, чтобы последующие замены их не затрагивали, поэтому ключ служит
+-- только для сравнения: "Иван Петров", "Ivan Petrov" и "IVAN  PETROV" дают ключ "ivan petrov",
+-- "Михаил Щукин" и "Mikhail Schukin" - "mi5ail 1ukin".
+-- +goose StatementBegin
+CREATE FUNCTION translit_key(input TEXT) RETURNS TEXT AS $$
+DECLARE
+    s TEXT := lower(input);
+BEGIN
+    s := replace(s, 'щ', 'shch');
+    s := replace(s, 'ж', 'zh');
+    s := replace(s, 'х', 'kh');
+    s := replace(s, 'ц', 'ts');
+    s := replace(s, 'ч', 'ch');
+    s := replace(s, 'ш', 'sh');
+    s := replace(s, 'ю', 'yu');
+    s := replace(s, 'я', 'ya');
+    s := replace(s, 'ё', 'yo');
+    s := translate(s, 'абвгдезийклмнопрстуфыэіїєґўъь', 'abvgdezijklmnoprstufyeiiegu');
+    s := replace(s, 'ŝ', 'shch');
+    s := replace(s, 'ž', 'zh');
+    s := replace(s, 'č', 'ch');
+    s := replace(s, 'š', 'sh');
+    s := replace(s, 'û', 'yu');
+    s := replace(s, 'â', 'ya');
+    s := replace(s, 'ë', 'yo');
+    s := translate(s, 'èéì', 'eei');
+    s := regexp_replace(s, '[''`’ʼʹʺ′″]', '', 'g');
+
+    s := regexp_replace(s, 'shch|shh|sch', '1', 'g');
+    s := replace(s, 'zh', '2');
+    s := replace(s, 'ch', '3');
+    s := replace(s, 'sh', '4');
+    s := regexp_replace(s, 'kh|h', '5', 'g');
+    s := regexp_replace(s, 'ts|tz|cz|c', '6', 'g');
+    s := replace(s, 'x', 'ks');
+    s := replace(s, 'iya', 'ia');
+    s := regexp_replace(s, '[yji]u', '7', 'g');
+    s := regexp_replace(s, '[yji]a', '8', 'g');
+    s := regexp_replace(s, '[yj][oe]', 'e', 'g');
+    s := translate(s, 'yjw', 'iiv');
+
+    s := regexp_replace(s, '[^a-z0-9]+', ' ', 'g');
+    s := regexp_replace(s, '([a-z])\1+', '\1', 'g');
+    RETURN btrim(s);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT PARALLEL SAFE;
+-- +goose StatementEnd
+
+-- Ключи хранятся в столбцах, а не вычисляются в индексах, чтобы по ним можно было
+-- и искать, и сравнивать записи между собой без повторного вычисления.
+ALTER TABLE employees ADD COLUMN search_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE departments ADD COLUMN search_key TEXT NOT NULL DEFAULT '';
+UPDATE employees SET search_key = translit_key(full_name);
+UPDATE departments SET search_key = translit_key(name);
+
+-- +goose StatementBegin
+CREATE FUNCTION employees_set_search_key() RETURNS trigger AS $$
+BEGIN
+    NEW.search_key := translit_key(NEW.full_name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION departments_set_search_key() RETURNS trigger AS $$
+BEGIN
+    NEW.search_key := translit_key(NEW.name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER employees_search_key BEFORE INSERT OR UPDATE OF full_name, search_key ON employees
+    FOR EACH ROW EXECUTE FUNCTION employees_set_search_key();
+CREATE TRIGGER departments_search_key BEFORE INSERT OR UPDATE OF name, search_key ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_set_search_key();
+
+CREATE INDEX idx_employees_search_key ON employees (search_key);
+CREATE INDEX idx_employees_search_key_trgm ON employees USING GIN (search_key gin_trgm_ops);
+CREATE INDEX idx_employees_search_key_fts ON employees USING GIN (to_tsvector('simple', search_key));
+CREATE INDEX idx_departments_search_key ON departments (search_key);
+CREATE INDEX idx_departments_search_key_trgm ON departments USING GIN (search_key gin_trgm_ops);
+CREATE INDEX idx_departments_search_key_fts ON departments USING GIN (to_tsvector('simple', search_key));
+
+-- +goose Down
+DROP TRIGGER departments_search_key ON departments;
+DROP TRIGGER employees_search_key ON employees;
+DROP FUNCTION departments_set_search_key();
+DROP FUNCTION employees_set_search_key();
+ALTER TABLE departments DROP COLUMN search_key;
+ALTER TABLE employees DROP COLUMN search_key;
+DROP FUNCTION translit_key(TEXT);
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Журнал слияния дубликатов сотрудников. Записи не ссылаются на сотрудников внешними
+-- ключами: удалённый дубликат остаётся в журнале вместе со своим последним состоянием,
+-- а его версии в employee_versions сохраняются под прежним идентификатором.
+CREATE TABLE employee_merges (
+    id         SERIAL PRIMARY KEY,
+    source_id  INT NOT NULL,
+    target_id  INT NOT NULL,
+    source     JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_employee_merges_source ON employee_merges (source_id);
+CREATE INDEX idx_employee_merges_target ON employee_merges (target_id);
+
+-- +goose Down
+DROP TABLE employee_merges;
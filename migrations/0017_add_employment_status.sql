@@ -0,0 +1,21 @@
+-- +goose Up
+-- Статус занятости: уволенные сотрудники остаются в базе, но не учитываются
+-- в численности и составе подразделений.
+ALTER TABLE employees ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'on_leave', 'terminated'));
+ALTER TABLE employees ADD COLUMN terminated_at DATE;
+ALTER TABLE employees ADD COLUMN termination_reason VARCHAR(500) NOT NULL DEFAULT '';
+ALTER TABLE employees ADD CONSTRAINT employees_terminated_at_check
+    CHECK ((status = 'terminated') = (terminated_at IS NOT NULL));
+ALTER TABLE employees ADD CONSTRAINT employees_terminated_after_hire_check
+    CHECK (terminated_at IS NULL OR hired_at IS NULL OR terminated_at >= hired_at);
+
+CREATE INDEX idx_employees_status ON employees (status) WHERE status <> 'active';
+
+-- +goose Down
+DROP INDEX idx_employees_status;
+ALTER TABLE employees DROP CONSTRAINT employees_terminated_after_hire_check;
+ALTER TABLE employees DROP CONSTRAINT employees_terminated_at_check;
+ALTER TABLE employees DROP COLUMN termination_reason;
+ALTER TABLE employees DROP COLUMN terminated_at;
+ALTER TABLE employees DROP COLUMN status;
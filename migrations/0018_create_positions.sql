@@ -0,0 +1,61 @@
+-- +goose Up
+-- position_key приводит название должности к ключу сравнения: без учёта регистра
+-- и лишних пробелов, поэтому "Backend Engineer" и " backend  engineer" - одна должность.
+-- +goose StatementBegin
+CREATE FUNCTION position_key(title TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(btrim(title), '\s+', ' ', 'g'));
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+-- +goose StatementEnd
+
+CREATE TABLE positions (
+    id         SERIAL PRIMARY KEY,
+    title      VARCHAR(200) NOT NULL,
+    job_family VARCHAR(100) NOT NULL DEFAULT '',
+    grade      INT CHECK (grade > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_positions_title_key ON positions (position_key(title));
+CREATE INDEX idx_positions_job_family ON positions (job_family);
+
+-- Существующие названия сводятся к записям справочника по ключу; название записи -
+-- самое частое написание среди сотрудников.
+INSERT INTO positions (title)
+SELECT mode() WITHIN GROUP (ORDER BY btrim(position))
+FROM employees
+WHERE btrim(position) <> ''
+GROUP BY position_key(position)
+ORDER BY 1;
+
+ALTER TABLE employees ADD COLUMN position_id INT REFERENCES positions(id) ON DELETE RESTRICT;
+CREATE INDEX idx_employees_position_id ON employees (position_id);
+
+UPDATE employees e SET position_id = p.id, position = p.title
+FROM positions p
+WHERE position_key(e.position) = position_key(p.title);
+
+-- Название должности сотрудника остаётся в employees.position и согласуется со справочником:
+-- при заданной должности справочника берётся её название, при заданном только названии
+-- сотрудник связывается с должностью справочника с тем же ключом, если она есть.
+-- +goose StatementBegin
+CREATE FUNCTION employees_set_position() RETURNS trigger AS $$
+BEGIN
+    IF NEW.position_id IS NOT NULL AND (TG_OP = 'INSERT' OR NEW.position_id IS DISTINCT FROM OLD.position_id) THEN
+        SELECT p.title INTO NEW.position FROM positions p WHERE p.id = NEW.position_id;
+    ELSIF TG_OP = 'INSERT' OR NEW.position IS DISTINCT FROM OLD.position THEN
+        SELECT p.id INTO NEW.position_id FROM positions p WHERE position_key(p.title) = position_key(NEW.position);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER employees_position BEFORE INSERT OR UPDATE OF position, position_id ON employees
+    FOR EACH ROW EXECUTE FUNCTION employees_set_position();
+
+-- +goose Down
+DROP TRIGGER employees_position ON employees;
+DROP FUNCTION employees_set_position();
+ALTER TABLE employees DROP COLUMN position_id;
+DROP TABLE positions;
+DROP FUNCTION position_key(TEXT);
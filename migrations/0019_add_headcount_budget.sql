@@ -0,0 +1,33 @@
+-- +goose Up
+-- Утверждённая численность подразделения: сколько сотрудников и открытых вакансий
+-- может быть в нём самом. NULL - численность не ограничена.
+ALTER TABLE departments ADD COLUMN headcount_limit INT CHECK (headcount_limit >= 0);
+
+-- Вакансии получают должность справочника, плановую дату выхода и статус.
+-- Существующие вакансии (копии должностей при клонировании) считаются открытыми.
+ALTER TABLE vacancies ADD COLUMN position_id INT REFERENCES positions(id) ON DELETE RESTRICT;
+ALTER TABLE vacancies ADD COLUMN target_start_date DATE;
+ALTER TABLE vacancies ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'filled', 'cancelled'));
+ALTER TABLE vacancies ADD COLUMN employee_id INT REFERENCES employees(id) ON DELETE SET NULL;
+ALTER TABLE vacancies ADD COLUMN closed_at TIMESTAMP;
+ALTER TABLE vacancies ADD CONSTRAINT vacancies_closed_at_check
+    CHECK ((status = 'open') = (closed_at IS NULL));
+
+UPDATE vacancies v SET position_id = p.id, position = p.title
+FROM positions p
+WHERE position_key(v.position) = position_key(p.title);
+
+CREATE INDEX idx_vacancies_open ON vacancies (department_id) WHERE status = 'open';
+CREATE UNIQUE INDEX idx_vacancies_employee_id ON vacancies (employee_id);
+
+-- +goose Down
+DROP INDEX idx_vacancies_employee_id;
+DROP INDEX idx_vacancies_open;
+ALTER TABLE vacancies DROP CONSTRAINT vacancies_closed_at_check;
+ALTER TABLE vacancies DROP COLUMN closed_at;
+ALTER TABLE vacancies DROP COLUMN employee_id;
+ALTER TABLE vacancies DROP COLUMN status;
+ALTER TABLE vacancies DROP COLUMN target_start_date;
+ALTER TABLE vacancies DROP COLUMN position_id;
+ALTER TABLE departments DROP COLUMN headcount_limit;
@@ -0,0 +1,57 @@
+-- +goose Up
+-- Дополнительные назначения сотрудников (проекты, гильдии) помимо основного подразделения
+-- employees.department_id: роль и доля занятости в процентах.
+CREATE TABLE employee_assignments (
+    id            SERIAL PRIMARY KEY,
+    employee_id   INT NOT NULL REFERENCES employees(id) ON DELETE CASCADE,
+    department_id INT NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    role          VARCHAR(100) NOT NULL DEFAULT '',
+    allocation    INT NOT NULL CHECK (allocation BETWEEN 1 AND 100),
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_employee_assignments_employee_department ON employee_assignments (employee_id, department_id);
+CREATE INDEX idx_employee_assignments_department_id ON employee_assignments (department_id);
+
+-- Сумма долей дополнительных назначений сотрудника не больше 100%. Строка сотрудника
+-- блокируется, чтобы одновременные назначения одного сотрудника проверялись по очереди.
+-- +goose StatementBegin
+CREATE FUNCTION employee_assignments_check_allocation() RETURNS trigger AS $$
+DECLARE
+    total INT;
+BEGIN
+    PERFORM 1 FROM employees WHERE id = NEW.employee_id FOR UPDATE;
+    SELECT COALESCE(sum(allocation), 0) INTO total
+    FROM employee_assignments WHERE employee_id = NEW.employee_id;
+    IF total > 100 THEN
+        RAISE EXCEPTION 'total allocation of employee % is % percent, max 100', NEW.employee_id, total
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER employee_assignments_allocation AFTER INSERT OR UPDATE OF employee_id, allocation
+    ON employee_assignments FOR EACH ROW EXECUTE FUNCTION employee_assignments_check_allocation();
+
+-- Сотрудник, переведённый в подразделение своего дополнительного назначения,
+-- становится его основным членом, и назначение удаляется.
+-- +goose StatementBegin
+CREATE FUNCTION employees_drop_home_assignment() RETURNS trigger AS $$
+BEGIN
+    DELETE FROM employee_assignments WHERE employee_id = NEW.id AND department_id = NEW.department_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER employees_home_assignment AFTER UPDATE OF department_id ON employees
+    FOR EACH ROW WHEN (NEW.department_id IS DISTINCT FROM OLD.department_id)
+    EXECUTE FUNCTION employees_drop_home_assignment();
+
+-- +goose Down
+DROP TRIGGER employees_home_assignment ON employees;
+DROP FUNCTION employees_drop_home_assignment();
+DROP TABLE employee_assignments;
+DROP FUNCTION employee_assignments_check_allocation();
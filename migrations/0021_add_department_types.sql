@@ -0,0 +1,76 @@
+-- +goose Up
+-- Типы подразделений (компания, дивизион, отдел, команда, ...) и правила вложенности:
+-- allow_root разрешает подразделению типа быть корнем, parent_types перечисляет типы,
+-- под которыми оно может находиться.
+CREATE TABLE department_types (
+    name         VARCHAR(50) PRIMARY KEY,
+    allow_root   BOOLEAN NOT NULL DEFAULT FALSE,
+    parent_types JSONB NOT NULL DEFAULT '[]',
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO department_types (name, allow_root, parent_types) VALUES
+    ('company', TRUE, '[]'),
+    ('division', FALSE, '["company"]'),
+    ('department', FALSE, '["company", "division"]'),
+    ('team', FALSE, '["division", "department"]');
+
+-- Подразделения без типа правилами не ограничиваются.
+ALTER TABLE departments ADD COLUMN type VARCHAR(50) REFERENCES department_types(name);
+CREATE INDEX idx_departments_type ON departments (type);
+
+-- Правила вложенности проверяются при любом изменении родителя или типа, включая
+-- перемещения реорганизаций, слияния, импорт и применение песочниц.
+-- +goose StatementBegin
+CREATE FUNCTION departments_check_type() RETURNS trigger AS $$
+DECLARE
+    rule        department_types%ROWTYPE;
+    parent_type VARCHAR(50);
+    child_type  VARCHAR(50);
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.parent_id IS NOT DISTINCT FROM OLD.parent_id
+        AND NEW.type IS NOT DISTINCT FROM OLD.type THEN
+        RETURN NEW;
+    END IF;
+    IF NEW.type IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT * INTO rule FROM department_types WHERE name = NEW.type;
+    IF NEW.parent_id IS NULL THEN
+        IF NOT rule.allow_root THEN
+            RAISE EXCEPTION 'department of type % cannot be a root department', NEW.type
+                USING ERRCODE = 'check_violation';
+        END IF;
+    ELSE
+        SELECT type INTO parent_type FROM departments WHERE id = NEW.parent_id;
+        IF parent_type IS NOT NULL AND NOT jsonb_exists(rule.parent_types, parent_type) THEN
+            RAISE EXCEPTION 'department of type % cannot be placed under type %', NEW.type, parent_type
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND NEW.type IS DISTINCT FROM OLD.type THEN
+        SELECT c.type INTO child_type
+        FROM departments c
+        INNER JOIN department_types t ON t.name = c.type
+        WHERE c.parent_id = NEW.id AND NOT jsonb_exists(t.parent_types, NEW.type)
+        LIMIT 1;
+        IF FOUND THEN
+            RAISE EXCEPTION 'department of type % cannot be placed under type %', child_type, NEW.type
+                USING ERRCODE = 'check_violation';
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER departments_type_rule BEFORE INSERT OR UPDATE OF parent_id, type ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_check_type();
+
+-- +goose Down
+DROP TRIGGER departments_type_rule ON departments;
+DROP FUNCTION departments_check_type();
+ALTER TABLE departments DROP COLUMN type;
+DROP TABLE department_types;
@@ -0,0 +1,48 @@
+-- +goose Up
+-- department_name_key приводит название подразделения к ключу сравнения: NFC, без учёта
+-- регистра и лишних пробелов, поэтому "Sales" и " sales " под одним родителем - одно название.
+-- +goose StatementBegin
+CREATE FUNCTION department_name_key(name TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g'));
+$$ LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE;
+-- +goose StatementEnd
+
+ALTER TABLE departments ADD COLUMN name_key TEXT;
+
+-- +goose StatementBegin
+CREATE FUNCTION departments_set_name_key() RETURNS trigger AS $$
+BEGIN
+    NEW.name_key := department_name_key(NEW.name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER departments_name_key BEFORE INSERT OR UPDATE OF name, name_key ON departments
+    FOR EACH ROW EXECUTE FUNCTION departments_set_name_key();
+
+UPDATE departments SET name_key = department_name_key(name);
+
+-- Соседние подразделения, названия которых совпадают по ключу, получают суффикс с id:
+-- первое по id сохраняет название, остальные переименовываются.
+UPDATE departments d
+SET name = left(d.name, 190) || ' (' || d.id || ')'
+FROM departments o
+WHERE o.parent_id IS NOT DISTINCT FROM d.parent_id AND o.name_key = d.name_key AND o.id < d.id;
+
+ALTER TABLE departments ALTER COLUMN name_key SET NOT NULL;
+
+DROP INDEX unique_name_per_parent_not_null;
+DROP INDEX unique_name_for_root;
+CREATE UNIQUE INDEX idx_departments_parent_name_key ON departments (parent_id, name_key) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_departments_root_name_key ON departments (name_key) WHERE parent_id IS NULL;
+
+-- +goose Down
+DROP INDEX idx_departments_root_name_key;
+DROP INDEX idx_departments_parent_name_key;
+CREATE UNIQUE INDEX unique_name_per_parent_not_null ON departments (parent_id, name) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX unique_name_for_root ON departments (name) WHERE parent_id IS NULL;
+DROP TRIGGER departments_name_key ON departments;
+DROP FUNCTION departments_set_name_key();
+ALTER TABLE departments DROP COLUMN name_key;
+DROP FUNCTION department_name_key(TEXT);
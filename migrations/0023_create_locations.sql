@@ -0,0 +1,25 @@
+-- +goose Up
+-- Офисы и площадки: страна - код ISO 3166-1 alpha-2, часовой пояс - имя из базы IANA
+-- (например, Europe/Moscow). Названия уникальны без учёта регистра.
+CREATE TABLE locations (
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(200) NOT NULL,
+    address    VARCHAR(500) NOT NULL DEFAULT '',
+    country    CHAR(2) NOT NULL,
+    timezone   VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_locations_name ON locations (lower(name));
+
+-- Место подразделения действует для его сотрудников и вложенных подразделений без своего места;
+-- место сотрудника заменяет место подразделения. Используемое место удалить нельзя.
+ALTER TABLE departments ADD COLUMN location_id INT REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE employees ADD COLUMN location_id INT REFERENCES locations(id) ON DELETE RESTRICT;
+CREATE INDEX idx_departments_location_id ON departments (location_id);
+CREATE INDEX idx_employees_location_id ON employees (location_id);
+
+-- +goose Down
+ALTER TABLE employees DROP COLUMN location_id;
+ALTER TABLE departments DROP COLUMN location_id;
+DROP TABLE locations;